@@ -5,21 +5,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/metrics"
 	"github.com/kubex-ecosystem/analyzer/internal/types"
+	"github.com/kubex-ecosystem/analyzer/internal/webhook"
 )
 
 // MetricsAPI handles standardized metrics API endpoints
 type MetricsAPI struct {
-	doraCalculator *metrics.EnhancedDORACalculator
-	chiCalculator  *metrics.CHICalculator
-	aiCalculator   *metrics.AIMetricsCalculator
-	cache          *metrics.CacheMiddleware
-	timeUtils      *metrics.TimeUtils
+	doraCalculator  *metrics.EnhancedDORACalculator
+	chiCalculator   *metrics.CHICalculator
+	aiCalculator    *metrics.AIMetricsCalculator
+	cache           *metrics.CacheMiddleware
+	timeUtils       *metrics.TimeUtils
+	testReports     *metrics.TestReportStore
+	testReportKeys  map[string]string // API key -> CI system allowed to upload test reports
+	aiTelemetry     *metrics.AITelemetryStore
+	aiTelemetryKeys map[string]string // API key -> user allowed to post AI telemetry; "*" posts for anyone
+
+	// DORA calculators keyed by repository host (e.g. gitlab.com)
+	hostCalculators map[string]*metrics.EnhancedDORACalculator
 }
 
 // NewMetricsAPI creates a new metrics API handler
@@ -38,12 +47,47 @@ func NewMetricsAPI(
 	}
 }
 
+// SetTestReportStore sets where uploaded JUnit reports are kept for flaky test detection.
+// apiKeys maps each key accepted for uploads to its CI system; without keys, uploads are rejected.
+func (m *MetricsAPI) SetTestReportStore(store *metrics.TestReportStore, apiKeys map[string]string) {
+	m.testReports = store
+	m.testReportKeys = apiKeys
+}
+
+// SetAITelemetryStore sets where ingested AI assistant telemetry is kept. apiKeys maps each
+// key accepted for posting telemetry to its user, as in HEARTBEAT_API_KEYS; a user key posts
+// only its own events, a team key ("*") those of any user. Without keys, posts are rejected.
+func (m *MetricsAPI) SetAITelemetryStore(store *metrics.AITelemetryStore, apiKeys map[string]string) {
+	m.aiTelemetry = store
+	m.aiTelemetryKeys = apiKeys
+}
+
+// RegisterHost routes requests for repositories on host, named by the host query parameter,
+// to a dedicated DORA calculator
+func (m *MetricsAPI) RegisterHost(host string, dora *metrics.EnhancedDORACalculator) {
+	if m.hostCalculators == nil {
+		m.hostCalculators = make(map[string]*metrics.EnhancedDORACalculator)
+	}
+	m.hostCalculators[strings.ToLower(host)] = dora
+}
+
+// doraCalculatorFor selects the DORA calculator for a repository by its clone URL host
+func (m *MetricsAPI) doraCalculatorFor(repo types.Repository) *metrics.EnhancedDORACalculator {
+	if parsed, err := url.Parse(repo.CloneURL); err == nil && repo.CloneURL != "" {
+		if dora, ok := m.hostCalculators[strings.ToLower(parsed.Hostname())]; ok {
+			return dora
+		}
+	}
+	return m.doraCalculator
+}
+
 // RegisterMetricsRoutes registers all standardized metrics API routes
 func (m *MetricsAPI) RegisterMetricsRoutes(mux *http.ServeMux) {
 	// DORA metrics endpoints
 	mux.HandleFunc("/api/metrics/dora", m.handleDORAMetrics)
 	mux.HandleFunc("/api/metrics/dora/timeseries", m.handleDORATimeSeries)
 	mux.HandleFunc("/api/metrics/dora/trends", m.handleDORATrends)
+	mux.HandleFunc("/api/metrics/tests/junit", m.handleJUnitUpload)
 
 	// CHI metrics endpoints
 	mux.HandleFunc("/api/metrics/chi", m.handleCHIMetrics)
@@ -54,6 +98,7 @@ func (m *MetricsAPI) RegisterMetricsRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/metrics/hir", m.handleHIRMetrics)
 	mux.HandleFunc("/api/metrics/ai", m.handleAIMetrics)
 	mux.HandleFunc("/api/metrics/ai/tools", m.handleAIToolsBreakdown)
+	mux.HandleFunc("/api/metrics/ai/telemetry", m.handleAITelemetry)
 
 	// Aggregated metrics endpoints
 	mux.HandleFunc("/api/metrics/aggregated", m.handleAggregatedMetrics)
@@ -82,7 +127,7 @@ func (m *MetricsAPI) handleDORAMetrics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metrics, err := m.doraCalculator.Calculate(r.Context(), request)
+	metrics, err := m.doraCalculatorFor(request.Repository).Calculate(r.Context(), request)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to calculate DORA metrics: %v", err), http.StatusInternalServerError)
 		return
@@ -108,7 +153,7 @@ func (m *MetricsAPI) handleDORATimeSeries(w http.ResponseWriter, r *http.Request
 		request.Granularity = "day"
 	}
 
-	metrics, err := m.doraCalculator.Calculate(r.Context(), request)
+	metrics, err := m.doraCalculatorFor(request.Repository).Calculate(r.Context(), request)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to calculate DORA time series: %v", err), http.StatusInternalServerError)
 		return
@@ -137,7 +182,7 @@ func (m *MetricsAPI) handleDORATrends(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metrics, err := m.doraCalculator.Calculate(r.Context(), request)
+	metrics, err := m.doraCalculatorFor(request.Repository).Calculate(r.Context(), request)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to calculate DORA trends: %v", err), http.StatusInternalServerError)
 		return
@@ -154,6 +199,147 @@ func (m *MetricsAPI) handleDORATrends(w http.ResponseWriter, r *http.Request) {
 	m.writeJSONResponse(w, response)
 }
 
+// handleJUnitUpload stores a JUnit XML report of a CI run, identified by repo, sha and workflow.
+// It takes a TEST_REPORT_API_KEYS key.
+func (m *MetricsAPI) handleJUnitUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if m.testReports == nil {
+		http.Error(w, "Test reports not available", http.StatusServiceUnavailable)
+		return
+	}
+	if _, ok := webhook.AuthenticateAPIKey(r, m.testReportKeys); !ok {
+		http.Error(w, "Invalid API key", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	run := metrics.TestRun{
+		Repository: query.Get("repo"),
+		Workflow:   query.Get("workflow"),
+		SHA:        query.Get("sha"),
+		CreatedAt:  time.Now(),
+	}
+	if len(strings.Split(run.Repository, "/")) != 2 || run.SHA == "" {
+		http.Error(w, "repo (owner/name) and sha parameters are required", http.StatusBadRequest)
+		return
+	}
+	if runID := query.Get("run_id"); runID != "" {
+		if parsed, err := strconv.Atoi(runID); err == nil {
+			run.RunID = parsed
+		}
+	}
+
+	results, err := metrics.ParseJUnitXML(http.MaxBytesReader(w, r.Body, 32<<20))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JUnit report: %v", err), http.StatusBadRequest)
+		return
+	}
+	run.Results = results
+
+	if err := m.testReports.Add(run); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to store test report: %v", err), http.StatusBadRequest)
+		return
+	}
+	if m.cache != nil {
+		m.cache.InvalidateRepositoryCache(r.Context(), run.Repository)
+	}
+
+	m.writeJSONResponse(w, map[string]interface{}{
+		"repository": run.Repository,
+		"sha":        run.SHA,
+		"tests":      len(results),
+	})
+}
+
+// handleAITelemetry ingests AI assistant telemetry as JSONL (POST, with a HEARTBEAT_API_KEYS
+// key) and summarizes it by user, repository and period (GET with user, repo, since and until
+// in RFC 3339)
+func (m *MetricsAPI) handleAITelemetry(w http.ResponseWriter, r *http.Request) {
+	if m.aiTelemetry == nil {
+		http.Error(w, "AI telemetry not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		user, ok := webhook.AuthenticateAPIKey(r, m.aiTelemetryKeys)
+		if !ok {
+			http.Error(w, "Invalid API key", http.StatusUnauthorized)
+			return
+		}
+		events, err := metrics.ParseAIAssistEvents(http.MaxBytesReader(w, r.Body, 32<<20))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid telemetry: %v", err), http.StatusBadRequest)
+			return
+		}
+		if user != webhook.HeartbeatTeamUser {
+			for _, event := range events {
+				if !strings.EqualFold(event.User, user) {
+					http.Error(w, fmt.Sprintf("Key of %s cannot post telemetry of %s", user, event.User), http.StatusForbidden)
+					return
+				}
+			}
+		}
+		if err := m.aiTelemetry.Add(events...); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to store telemetry: %v", err), http.StatusBadRequest)
+			return
+		}
+		if m.cache != nil {
+			repositories := make(map[string]bool)
+			for _, event := range events {
+				if !repositories[event.Repository] {
+					repositories[event.Repository] = true
+					m.cache.InvalidateRepositoryCache(r.Context(), event.Repository)
+				}
+			}
+		}
+		m.writeJSONResponse(w, map[string]interface{}{
+			"events": len(events),
+		})
+
+	case http.MethodGet:
+		query := r.URL.Query()
+		telemetryQuery := metrics.AITelemetryQuery{
+			User:       query.Get("user"),
+			Repository: query.Get("repo"),
+		}
+		for name, target := range map[string]*time.Time{"since": &telemetryQuery.Since, "until": &telemetryQuery.Until} {
+			if value := query.Get(name); value != "" {
+				parsed, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Invalid %s: %v", name, err), http.StatusBadRequest)
+					return
+				}
+				*target = parsed
+			}
+		}
+
+		events := m.aiTelemetry.Query(telemetryQuery)
+		byUser := make(map[string][]metrics.AIAssistEvent)
+		for _, event := range events {
+			byUser[event.User] = append(byUser[event.User], event)
+		}
+		users := make(map[string]metrics.AIAssistData, len(byUser))
+		for user, userEvents := range byUser {
+			users[user] = metrics.SummarizeAIAssistEvents(userEvents)
+		}
+
+		m.writeJSONResponse(w, map[string]interface{}{
+			"query":  telemetryQuery,
+			"events": len(events),
+			"total":  metrics.SummarizeAIAssistEvents(events),
+			"users":  users,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // CHI metrics handlers
 
 func (m *MetricsAPI) handleCHIMetrics(w http.ResponseWriter, r *http.Request) {
@@ -254,11 +440,8 @@ func (m *MetricsAPI) handleAIMetrics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get user parameter for AI metrics
+	// AI metrics of one user, or of every user when empty
 	user := r.URL.Query().Get("user")
-	if user == "" {
-		user = "default"
-	}
 
 	// Get period in days
 	periodDays := 30
@@ -268,7 +451,7 @@ func (m *MetricsAPI) handleAIMetrics(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	aiMetrics, err := m.aiCalculator.Calculate(r.Context(), request.Repository, user, periodDays)
+	aiMetrics, err := m.aiCalculator.CalculateEnhanced(r.Context(), request.Repository, user, periodDays)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to calculate AI metrics: %v", err), http.StatusInternalServerError)
 		return
@@ -289,24 +472,22 @@ func (m *MetricsAPI) handleAIToolsBreakdown(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Return AI tools breakdown
+	user := r.URL.Query().Get("user")
+	periodDays := 30
+	if periodStr := r.URL.Query().Get("period_days"); periodStr != "" {
+		if parsed, err := strconv.Atoi(periodStr); err == nil {
+			periodDays = parsed
+		}
+	}
+
+	breakdown, err := m.aiCalculator.ToolBreakdown(r.Context(), request.Repository, user, periodDays)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to calculate AI tools breakdown: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	response := map[string]interface{}{
-		"ai_tools_breakdown": []interface{}{
-			map[string]interface{}{
-				"tool_name": "github_copilot",
-				"usage_hours": 120.5,
-				"acceptance_rate": 0.75,
-				"lines_generated": 5420,
-				"lines_accepted": 4065,
-			},
-			map[string]interface{}{
-				"tool_name": "chatgpt",
-				"usage_hours": 45.2,
-				"acceptance_rate": 0.85,
-				"lines_generated": 1230,
-				"lines_accepted": 1045,
-			},
-		},
+		"ai_tools_breakdown": breakdown,
 		"repository": request.Repository,
 		"time_range": request.TimeRange,
 		"generated_at": time.Now(),
@@ -342,18 +523,43 @@ func (m *MetricsAPI) handleAggregatedMetrics(w http.ResponseWriter, r *http.Requ
 		http.Error(w, fmt.Sprintf("Invalid time range: %v", err), http.StatusBadRequest)
 		return
 	}
+	host := r.URL.Query().Get("host")
+
+	// DORA metrics of each repository; repositories that fail are reported and left out
+	byRepository := make(map[string]*metrics.EnhancedDORAMetrics, len(repositories))
+	failures := make(map[string]string)
+	for _, repo := range repositories {
+		repo = strings.TrimSpace(repo)
+		parts := strings.Split(repo, "/")
+		if len(parts) != 2 {
+			http.Error(w, fmt.Sprintf("repository %q must be in format 'owner/name'", repo), http.StatusBadRequest)
+			return
+		}
+
+		repository := hostedRepository(parts[0], parts[1], host)
+		dora, err := m.doraCalculatorFor(repository).Calculate(r.Context(), metrics.MetricsRequest{
+			Repository:  repository,
+			TimeRange:   timeRange,
+			Granularity: "day",
+			UseCache:    true,
+		})
+		if err != nil {
+			failures[repo] = err.Error()
+			continue
+		}
+		byRepository[repo] = dora
+	}
+
+	aggregatedDORA := metrics.AggregateDORAMetrics(byRepository)
+	organizationalHealth := metrics.OrganizationalHealth{}
+	if len(byRepository) > 0 {
+		organizationalHealth.DeliveryMaturity = m.doraCalculator.Benchmarks().ClassifyDeliveryMaturity(aggregatedDORA)
+	}
 
-	// This would use a cross-repository aggregation service
+	// CHI and AI aggregates would use a cross-repository aggregation service
 	response := map[string]interface{}{
 		"aggregated_metrics": map[string]interface{}{
-			"dora": map[string]interface{}{
-				"mean_lead_time_p95_hours": 24.5,
-				"mean_deployment_frequency_per_week": 3.2,
-				"mean_change_fail_rate_pct": 8.5,
-				"mean_mttr_hours": 2.1,
-				"total_deployments": 156,
-				"total_incidents": 12,
-			},
+			"dora": aggregatedDORA,
 			"chi": map[string]interface{}{
 				"mean_chi_score": 78,
 				"mean_duplication_pct": 12.3,
@@ -367,6 +573,8 @@ func (m *MetricsAPI) handleAggregatedMetrics(w http.ResponseWriter, r *http.Requ
 				"organizational_ai_adoption": 0.82,
 			},
 		},
+		"organizational_health": organizationalHealth,
+		"failed_repositories": failures,
 		"repositories": repositories,
 		"time_range": timeRange,
 		"generated_at": time.Now(),
@@ -500,6 +708,10 @@ func (m *MetricsAPI) handleMetricsInfo(w http.ResponseWriter, r *http.Request) {
 			"timezones": true,
 			"aggregation": true,
 			"time_series": true,
+			"environment_breakdown": true,
+			"working_hours": true,
+			"flaky_tests": m.testReports != nil,
+			"ai_telemetry": m.aiTelemetry != nil,
 		},
 		"limits": map[string]interface{}{
 			"max_time_range_days": 365,
@@ -527,11 +739,7 @@ func (m *MetricsAPI) parseMetricsRequest(r *http.Request) (metrics.MetricsReques
 		return metrics.MetricsRequest{}, fmt.Errorf("repo must be in format 'owner/name'")
 	}
 
-	repository := types.Repository{
-		Owner:    parts[0],
-		Name:     parts[1],
-		FullName: repo,
-	}
+	repository := hostedRepository(parts[0], parts[1], query.Get("host"))
 
 	// Parse time range
 	timeRange, err := m.parseTimeRange(r)
@@ -554,15 +762,48 @@ func (m *MetricsAPI) parseMetricsRequest(r *http.Request) (metrics.MetricsReques
 		}
 	}
 
+	// Production environments as comma-separated glob patterns, e.g. "prod,prod-*"
+	var productionEnvironments []string
+	if envs := query.Get("production_environments"); envs != "" {
+		for _, env := range strings.Split(envs, ",") {
+			if env = strings.TrimSpace(env); env != "" {
+				productionEnvironments = append(productionEnvironments, env)
+			}
+		}
+		if err := metrics.ValidateEnvironmentPatterns(productionEnvironments); err != nil {
+			return metrics.MetricsRequest{}, err
+		}
+	}
+
+	// Working hours of the repository's team, or of the team named in the query
+	workingHours := query.Get("working_hours") == "true"
+	team := strings.TrimSpace(query.Get("team"))
+
 	return metrics.MetricsRequest{
-		Repository:  repository,
-		TimeRange:   timeRange,
-		Granularity: granularity,
-		UseCache:    useCache,
-		CacheTTL:    cacheTTL,
+		Repository:             repository,
+		TimeRange:              timeRange,
+		Granularity:            granularity,
+		UseCache:               useCache,
+		CacheTTL:               cacheTTL,
+		ProductionEnvironments: productionEnvironments,
+		WorkingHours:           workingHours,
+		Team:                   team,
 	}, nil
 }
 
+// hostedRepository names the repository owner/name on host; github.com when host is empty
+func hostedRepository(owner, name, host string) types.Repository {
+	repository := types.Repository{
+		Owner:    owner,
+		Name:     name,
+		FullName: owner + "/" + name,
+	}
+	if host = strings.TrimSpace(host); host != "" {
+		repository.CloneURL = fmt.Sprintf("https://%s/%s.git", host, repository.FullName)
+	}
+	return repository
+}
+
 func (m *MetricsAPI) parseTimeRange(r *http.Request) (metrics.TimeRange, error) {
 	query := r.URL.Query()
 
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/services"
+	"gopkg.in/yaml.v3"
+)
+
+// MetricsConfig holds configuration for repository intelligence metrics, read from the
+// YAML file named by METRICS_CONFIG
+type MetricsConfig struct {
+	RepoPath       string         `yaml:"repo_path"`  // Local clone analyzed for commits and code health
+	Repository     string         `yaml:"repository"` // "owner/name" of the clone; webhook events of other repositories get no CHI analysis
+	Incidents      IncidentConfig `yaml:"incidents"`
+	BenchmarksFile string         `yaml:"benchmarks_file"` // DORA band thresholds; State of DevOps when empty
+	Hosts          []HostConfig   `yaml:"hosts"`           // Instances other than GitHub whose repositories get DORA metrics
+
+	// Teams measured in working hours, and the holiday calendars those hours skip
+	Teams    []metrics.TeamConfig  `yaml:"teams"`
+	Holidays metrics.HolidayConfig `yaml:"holidays"`
+
+	TestReports TestReportConfig  `yaml:"test_reports"` // JUnit reports for flaky test detection
+	AITelemetry AITelemetryConfig `yaml:"ai_telemetry"` // AI assistant telemetry ingested at /api/metrics/ai/telemetry
+
+	AIQuality       metrics.AIQualityConfig `yaml:"ai_quality"`        // Follow-up windows of the AI vs human quality comparison
+	AIDetectionFile string                  `yaml:"ai_detection_file"` // Rules marking commits AI-assisted, by tool; built-in rules when empty
+
+	Contributors metrics.ContributorConfig   `yaml:"contributors"` // History, onboarding and retention windows of contributor metrics
+	Community    metrics.CommunityBenchmarks `yaml:"community"`    // Community health targets; defaults when unset
+
+	StalePRs  services.StalePRConfig    `yaml:"stale_prs"` // Digest of stale pull requests; off without repositories
+	Anomalies AnomalyNotificationConfig `yaml:"anomalies"` // Notifications of anomalies found in webhook events
+}
+
+// AnomalyNotificationConfig sends warning and critical anomalies found when analyzing
+// webhook events to a notification channel, once per anomaly
+type AnomalyNotificationConfig struct {
+	Channel   string `yaml:"channel"` // Notification type, e.g. "discord", "email"; off when empty
+	Recipient string `yaml:"recipient"`
+}
+
+// HostConfig points at a GitLab, Gitea, Forgejo or Bitbucket instance whose repositories
+// are measured through its API instead of GitHub's. The access token is read from the
+// environment variable named by TokenEnv.
+type HostConfig struct {
+	Type     string `yaml:"type"`      // "gitlab", "gitea" (also Forgejo) or "bitbucket"
+	BaseURL  string `yaml:"base_url"`  // API URL, e.g. https://gitlab.example.com/api/v4; the public instance when empty
+	TokenEnv string `yaml:"token_env"` // GITLAB_TOKEN, GITEA_TOKEN or BITBUCKET_TOKEN when empty
+	Username string `yaml:"username"`  // Bitbucket user whose app password is the token; a bearer token when empty
+}
+
+// hostTokenEnvs are the default token variables of each host type
+var hostTokenEnvs = map[string]string{
+	"gitlab":    "GITLAB_TOKEN",
+	"gitea":     "GITEA_TOKEN",
+	"bitbucket": "BITBUCKET_TOKEN",
+}
+
+// Token returns the access token of the host
+func (h HostConfig) Token() string {
+	if h.TokenEnv != "" {
+		return os.Getenv(h.TokenEnv)
+	}
+	return os.Getenv(hostTokenEnvs[h.Type])
+}
+
+// TestReportConfig keeps JUnit reports of CI runs for flaky test detection. Reports are
+// uploaded to /api/metrics/tests/junit; Files loads reports archived by CI at startup.
+type TestReportConfig struct {
+	Limit int                 `yaml:"limit"` // Test runs kept per repository; 1000 when 0
+	Files []JUnitReportConfig `yaml:"files"`
+}
+
+// AITelemetryConfig keeps AI assistant telemetry across restarts
+type AITelemetryConfig struct {
+	Limit   int    `yaml:"limit"`    // Events kept per repository; 100000 when 0
+	LogFile string `yaml:"log_file"` // JSONL file holding the ingested events; memory only when empty
+}
+
+// JUnitReportConfig names the JUnit XML reports of one CI run
+type JUnitReportConfig struct {
+	Repository string    `yaml:"repository"` // "owner/name"
+	Workflow   string    `yaml:"workflow"`
+	SHA        string    `yaml:"sha"`
+	CreatedAt  time.Time `yaml:"created_at"` // When the run happened; load time when empty
+	Paths      []string  `yaml:"paths"`      // Globs allowed
+}
+
+// IncidentConfig selects the sources of production incidents used for MTTR and change
+// failure rate. Incidents posted to the incident webhook are always used.
+type IncidentConfig struct {
+	GitHubLabels    []string           `yaml:"github_labels"`    // Labels of GitHub issues that are incidents
+	Jira            JiraIncidentConfig `yaml:"jira"`             // Jira project of incident issues
+	Files           []string           `yaml:"files"`            // PagerDuty or Opsgenie JSON exports; globs allowed
+	FilesRepository string             `yaml:"files_repository"` // "owner/name" the exports apply to; all when empty
+}
+
+// JiraIncidentConfig points at the Jira project holding incidents. The API token is read
+// from the environment variable named by TokenEnv.
+type JiraIncidentConfig struct {
+	BaseURL    string   `yaml:"base_url"`
+	Username   string   `yaml:"username"`
+	TokenEnv   string   `yaml:"token_env"`
+	Project    string   `yaml:"project"`
+	IssueTypes []string `yaml:"issue_types"`
+	// Component or label names to the "owner/name" their incidents apply to; all when empty
+	Repositories map[string]string `yaml:"repositories"`
+}
+
+// Enabled reports whether a Jira project is configured
+func (j JiraIncidentConfig) Enabled() bool {
+	return j.BaseURL != "" && j.Project != ""
+}
+
+// Token returns the Jira API token, from JIRA_API_TOKEN unless TokenEnv names another variable
+func (j JiraIncidentConfig) Token() string {
+	if j.TokenEnv != "" {
+		return os.Getenv(j.TokenEnv)
+	}
+	return os.Getenv("JIRA_API_TOKEN")
+}
+
+// GetMetricsConfig returns metrics configuration from the file named by METRICS_CONFIG,
+// or the defaults when it is not set
+func GetMetricsConfig() (MetricsConfig, error) {
+	config := MetricsConfig{RepoPath: "."}
+
+	path := os.Getenv("METRICS_CONFIG")
+	if path == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read metrics config %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse metrics config %s: %w", path, err)
+	}
+	if config.RepoPath == "" {
+		config.RepoPath = "."
+	}
+	return config, nil
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/config"
@@ -14,7 +16,9 @@ import (
 	"github.com/kubex-ecosystem/analyzer/internal/gateway/middleware"
 	"github.com/kubex-ecosystem/analyzer/internal/gateway/registry"
 	"github.com/kubex-ecosystem/analyzer/internal/handlers/lookatni"
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
 	"github.com/kubex-ecosystem/analyzer/internal/scorecard"
+	"github.com/kubex-ecosystem/analyzer/internal/services"
 	providers "github.com/kubex-ecosystem/analyzer/internal/types"
 	"github.com/kubex-ecosystem/analyzer/internal/web"
 	"github.com/kubex-ecosystem/analyzer/internal/webhook"
@@ -24,12 +28,18 @@ import (
 type httpHandlers struct {
 	registry             *registry.Registry
 	productionMiddleware *middleware.ProductionMiddleware
-	engine               *scorecard.Engine    // Repository Intelligence engine
-	lookAtniHandler      *lookatni.Handler    // LookAtni integration
-	webhookHandler       *webhook.HTTPHandler // Meta-recursive webhook handler
-	healthEngine         *health.Engine       // AI Provider health monitoring
+	engine               *scorecard.Engine         // Repository Intelligence engine
+	lookAtniHandler      *lookatni.Handler         // LookAtni integration
+	webhookHandler       *webhook.HTTPHandler      // Meta-recursive webhook handler
+	incidentHandler      *webhook.IncidentHandler  // Generic incident webhook
+	incidentStore        *metrics.IncidentStore    // Incidents for MTTR/CFR
+	heartbeatHandler     *webhook.HeartbeatHandler // WakaTime-compatible heartbeat receiver
+	heartbeatKeys        map[string]string         // HEARTBEAT_API_KEYS, also required to post AI telemetry and snooze stale pull requests
+	heartbeatStore       *metrics.HeartbeatStore   // Editor coding time for HIR
+	healthEngine         *health.Engine            // AI Provider health monitoring
 	healthRegistry       *health.ProberRegistry
-	healthScheduler      *health.Scheduler // Background health checks
+	healthScheduler      *health.Scheduler       // Background health checks
+	daemon               *services.DaemonService // Stale pull request digest; nil when not configured
 }
 
 // WireHTTP sets up HTTP routes
@@ -38,8 +48,38 @@ func WireHTTP(mux *http.ServeMux, reg *registry.Registry, prodMiddleware *middle
 	workDir := "./lookatni_workspace" // TODO: Make configurable
 	lookAtniHandler := lookatni.NewHandler(workDir)
 
-	// Initialize webhook handler (mock for now - TODO: implement real actors)
-	webhookHandler := webhook.NewHTTPHandler(nil) // TODO: Initialize with real handler
+	// Initialize incident webhook (feeds MTTR and change failure rate)
+	incidentStore := metrics.NewIncidentStore()
+	incidentToken := os.Getenv("INCIDENT_WEBHOOK_TOKEN")
+	incidentHandler := webhook.NewIncidentHandler(incidentStore, incidentToken)
+
+	// Initialize WakaTime-compatible heartbeat receiver (keeps editor activity on-prem)
+	heartbeatStore := metrics.NewHeartbeatStore(0)
+	heartbeatKeys := webhook.ParseHeartbeatAPIKeys(os.Getenv("HEARTBEAT_API_KEYS"))
+	heartbeatHandler := webhook.NewHeartbeatHandler(heartbeatStore, heartbeatKeys)
+
+	// Initialize Repository Intelligence from the metrics config (GitHub-hosted repositories)
+	var engine *scorecard.Engine
+	metricsConfig, err := config.GetMetricsConfig()
+	if err != nil {
+		log.Printf("⚠️  Failed to load metrics config: %v", err)
+	}
+	var daemon *services.DaemonService
+	wiring, err := newMetricsWiring(metricsConfig, reg.GetConfig(), incidentStore, heartbeatStore, heartbeatKeys)
+	if err != nil {
+		log.Printf("⚠️  Repository Intelligence disabled: %v", err)
+	} else {
+		engine = wiring.engine
+		daemon = wiring.daemon
+		wiring.api.RegisterMetricsRoutes(mux)
+	}
+
+	// Initialize webhook handler: events are analyzed for DORA and CHI anomalies
+	// (TODO: implement recommender and executor actors)
+	webhookHandler := webhook.NewHTTPHandler(nil)
+	if wiring != nil {
+		webhookHandler = webhook.NewHTTPHandler(wiring.events)
+	}
 
 	// Initialize AI Provider Health Monitoring
 	healthStore := health.NewStore()
@@ -67,12 +107,18 @@ func WireHTTP(mux *http.ServeMux, reg *registry.Registry, prodMiddleware *middle
 	h := &httpHandlers{
 		registry:             reg,
 		productionMiddleware: prodMiddleware,
-		engine:               nil, // TODO: Initialize scorecard engine with real clients
+		engine:               engine,
 		lookAtniHandler:      lookAtniHandler,
 		webhookHandler:       webhookHandler,
+		incidentHandler:      incidentHandler,
+		incidentStore:        incidentStore,
+		heartbeatHandler:     heartbeatHandler,
+		heartbeatKeys:        heartbeatKeys,
+		heartbeatStore:       heartbeatStore,
 		healthEngine:         healthEngine,
 		healthRegistry:       healthRegistry,
 		healthScheduler:      healthScheduler,
+		daemon:               daemon,
 	}
 
 	// Web Interface - Frontend embarcado! 🚀
@@ -99,6 +145,7 @@ func WireHTTP(mux *http.ServeMux, reg *registry.Registry, prodMiddleware *middle
 	mux.HandleFunc("/api/v1/scorecard/advice", h.handleScorecardAdvice)
 	mux.HandleFunc("/api/v1/metrics/ai", h.handleAIMetrics)
 	mux.HandleFunc("/api/v1/health", h.handleRepositoryHealth)
+	mux.HandleFunc("/api/v1/stale-prs/snooze", h.handleSnoozeStalePR)
 
 	// AI Provider Health Monitoring - ARQUITETURA QUE NÃO SE SABOTA! 🔥
 	health.RegisterRoutes(mux, h.healthEngine, h.healthRegistry)
@@ -115,6 +162,33 @@ func WireHTTP(mux *http.ServeMux, reg *registry.Registry, prodMiddleware *middle
 	// Meta-Recursive Webhook endpoints - INSANIDADE RACIONAL! 🔄
 	mux.HandleFunc("/v1/webhooks", h.webhookHandler.HandleWebhook)
 	mux.HandleFunc("/v1/webhooks/health", h.webhookHandler.HealthCheck)
+	mux.HandleFunc("/v1/webhooks/incidents", h.incidentHandler.HandleIncidentWebhook)
+	if incidentToken == "" {
+		log.Println("⚠️  INCIDENT_WEBHOOK_TOKEN not set - incident webhook rejects all requests")
+	}
+
+	// Signed webhooks of the configured Gitea/Forgejo and Bitbucket hosts
+	if wiring != nil {
+		for _, host := range wiring.hosts {
+			switch host.source {
+			case "gitea":
+				mux.HandleFunc("/v1/webhooks/gitea", webhook.NewGiteaHandler(host.webhookSecret, wiring.events).HandleGiteaWebhook)
+			case "bitbucket":
+				mux.HandleFunc("/v1/webhooks/bitbucket", webhook.NewBitbucketHandler(host.webhookSecret, wiring.events).HandleBitbucketWebhook)
+			default:
+				continue
+			}
+			if host.webhookSecret == "" {
+				log.Printf("⚠️  %s_WEBHOOK_SECRET not set - /v1/webhooks/%s rejects all requests", strings.ToUpper(host.source), host.source)
+			}
+		}
+	}
+
+	// WakaTime-compatible heartbeats - editor plugins use api_url = <gateway>/api/v1
+	mux.HandleFunc("/api/v1/users/", h.heartbeatHandler.HandleUsers)
+	if len(heartbeatKeys) == 0 {
+		log.Println("⚠️  HEARTBEAT_API_KEYS not set - heartbeat receiver, AI telemetry posts and stale PR snoozes reject all requests")
+	}
 
 	log.Println("✅ LookAtni integration enabled - Code extraction and navigation ready!")
 	log.Println("🔄 Meta-recursive webhook system enabled")
@@ -556,6 +630,56 @@ func (h *httpHandlers) handleRepositoryHealth(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(health)
 }
 
+// handleSnoozeStalePR handles POST /api/v1/stale-prs/snooze, leaving a pull request out of
+// stale digests for a duration such as "72h", up to 30 days. It takes a HEARTBEAT_API_KEYS key.
+func (h *httpHandlers) handleSnoozeStalePR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := webhook.AuthenticateAPIKey(r, h.heartbeatKeys)
+	if !ok {
+		http.Error(w, "Invalid API key", http.StatusUnauthorized)
+		return
+	}
+	if h.daemon == nil {
+		http.Error(w, "Stale PR digest is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req struct {
+		Repository string `json:"repository"` // "owner/name"
+		Number     int    `json:"number"`
+		Duration   string `json:"duration"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration <= 0 || duration > services.MaxSnoozeDuration {
+		http.Error(w, "duration must be a positive duration such as 72h, up to 720h", http.StatusBadRequest)
+		return
+	}
+	if req.Repository == "" || req.Number <= 0 {
+		http.Error(w, "repository and number are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.daemon.SnoozePullRequest(req.Repository, req.Number, duration); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to snooze pull request: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"repository": req.Repository,
+		"number":     req.Number,
+		"until":      time.Now().Add(duration),
+		"snoozed_by": user,
+	})
+}
+
 // handleSchedulerStats returns health scheduler statistics
 func (h *httpHandlers) handleSchedulerStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
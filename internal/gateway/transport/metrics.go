@@ -0,0 +1,379 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/api"
+	"github.com/kubex-ecosystem/analyzer/internal/config"
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/repositories"
+	"github.com/kubex-ecosystem/analyzer/internal/scorecard"
+	"github.com/kubex-ecosystem/analyzer/internal/services"
+	"github.com/kubex-ecosystem/analyzer/internal/services/bitbucket"
+	"github.com/kubex-ecosystem/analyzer/internal/services/gitea"
+	"github.com/kubex-ecosystem/analyzer/internal/services/github"
+	"github.com/kubex-ecosystem/analyzer/internal/services/gitlab"
+	providers "github.com/kubex-ecosystem/analyzer/internal/types"
+	"github.com/kubex-ecosystem/analyzer/internal/webhook"
+)
+
+// metricsWiring holds the Repository Intelligence engine and metrics API built from config
+type metricsWiring struct {
+	engine *scorecard.Engine
+	api    *api.MetricsAPI
+	events *webhook.Handler        // Anomaly detection for webhook events
+	hosts  []hostDORA              // Configured hosts other than GitHub
+	daemon *services.DaemonService // Stale pull request digest and anomaly notifications; nil when neither is configured
+}
+
+// newMetricsWiring builds the scorecard engine and the metrics API for repositories hosted
+// on GitHub and on config.Hosts, analyzing commits and code health of the local clone at
+// config.RepoPath.
+// Incidents received by the incident webhook are read from incidents, and coding time from
+// editor heartbeats unless WAKATIME_API_KEY selects wakatime.com, or the WakaTime-compatible
+// API at WAKATIME_API_URL. DORA bands come from
+// config.BenchmarksFile when set; team working hours skip the configured holidays. Uploaded
+// and configured JUnit reports feed flaky test detection, and AI assistant telemetry posted
+// with heartbeatKeys feeds AI metrics. Contributor onboarding and retention follow the clone's commits and the
+// GitHub pull requests over config.Contributors.HistoryDays, and community health is rated
+// against config.Community. The stale pull request digest watches config.StalePRs.Repositories,
+// measuring review waits in team working hours. Webhook events are analyzed for DORA
+// anomalies, and for CHI anomalies when they come from config.Repository; the digest and
+// the anomalies in config.Anomalies are sent through the notification provider of gateway.
+func newMetricsWiring(cfg config.MetricsConfig, gateway providers.Config, incidents *metrics.IncidentStore, heartbeats *metrics.HeartbeatStore, heartbeatKeys map[string]string) (*metricsWiring, error) {
+	service, err := github.NewServiceFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub service: %w", err)
+	}
+	githubClient := repositories.NewGitHubClientFromService(service)
+	gitClient := repositories.NewGitClient(cfg.RepoPath)
+	sources := incidentSources(cfg.Incidents, service, incidents)
+
+	benchmarks := metrics.DefaultDORABenchmarks()
+	if cfg.BenchmarksFile != "" {
+		benchmarks, err = metrics.LoadDORABenchmarks(cfg.BenchmarksFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The git client and the AI calculator share one rule set so both agree on AI commits
+	detection := metrics.DefaultAIDetectionRules()
+	if cfg.AIDetectionFile != "" {
+		detection, err = metrics.LoadAIDetectionRules(cfg.AIDetectionFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	gitClient.SetAIDetectionRules(detection)
+
+	testReports, err := loadTestReports(cfg.TestReports)
+	if err != nil {
+		return nil, err
+	}
+
+	aiTelemetry := metrics.NewAITelemetryStore(cfg.AITelemetry.Limit)
+	if cfg.AITelemetry.LogFile != "" {
+		if err := aiTelemetry.SetLogFile(cfg.AITelemetry.LogFile); err != nil {
+			return nil, err
+		}
+	}
+
+	holidays := cfg.Holidays
+	if err := holidays.LoadCalendars(); err != nil {
+		return nil, err
+	}
+	if err := metrics.ValidateTeams(cfg.Teams); err != nil {
+		return nil, err
+	}
+	if err := holidays.Validate(cfg.Teams); err != nil {
+		return nil, err
+	}
+
+	dora := metrics.NewDORACalculator(githubClient, nil)
+	dora.SetGitClient(gitClient)
+	dora.SetTeams(cfg.Teams)
+	dora.SetHolidays(holidays)
+	enhancedDORA := metrics.NewEnhancedDORACalculator(githubClient, nil, nil, metrics.DORAConfig{
+		Benchmarks: benchmarks,
+		Teams:      cfg.Teams,
+		Holidays:   holidays,
+	})
+	enhancedDORA.SetGitClient(gitClient)
+	for _, source := range sources {
+		dora.AddIncidentSource(source)
+		enhancedDORA.AddIncidentSource(source)
+	}
+	dora.SetTestRunSource(testReports)
+	enhancedDORA.SetTestRunSource(testReports)
+
+	// Repositories on the configured hosts read pull requests, deployments and pipelines from
+	// their own API; the local clone and GitHub issues only describe GitHub repositories
+	hosts, err := hostCalculators(cfg.Hosts, sources, metrics.DORAConfig{
+		Benchmarks: benchmarks,
+		Teams:      cfg.Teams,
+		Holidays:   holidays,
+	}, testReports)
+	if err != nil {
+		return nil, err
+	}
+
+	chi := metrics.NewCHICalculator(cfg.RepoPath)
+	var wakatime metrics.WakaTimeClient = heartbeats
+	if apiKey := os.Getenv("WAKATIME_API_KEY"); apiKey != "" {
+		wakatime = repositories.NewWakaTimeClient(apiKey)
+		if baseURL := os.Getenv("WAKATIME_API_URL"); baseURL != "" {
+			wakatime = repositories.NewWakaTimeClientWithBaseURL(apiKey, baseURL)
+		}
+	}
+	ai := metrics.NewAIMetricsCalculator(wakatime, gitClient, repositories.NewIDEClient(aiTelemetry))
+	ai.SetAIDetectionRules(detection)
+	ai.SetQualityConfig(cfg.AIQuality)
+
+	// CHI measurements of scorecards and webhook analyses feed CHI forecasts and anomalies
+	chiHistory := metrics.NewCHIHistoryStore(0)
+	engine := scorecard.NewEngine(dora, chi, ai)
+	for _, host := range hosts {
+		engine.RegisterHost(host.host, host.dora)
+	}
+	engine.SetCHIHistory(chiHistory)
+	engine.SetFileHistoryClient(gitClient)
+	engine.SetContributorHistory(gitClient, githubClient, cfg.Contributors)
+	engine.SetCommunityClient(metrics.NewGraphQLClient(service, service.GraphQLURL()), cfg.Community)
+	if err := engine.SetBenchmarks(benchmarks); err != nil {
+		return nil, err
+	}
+
+	cache := metrics.NewCacheMiddleware(metrics.NewMetricsCache(metrics.CacheConfig{}))
+	metricsAPI := api.NewMetricsAPI(enhancedDORA, chi, ai, cache)
+	testReportKeys := webhook.ParseHeartbeatAPIKeys(os.Getenv("TEST_REPORT_API_KEYS"))
+	if len(testReportKeys) == 0 {
+		log.Println("⚠️  TEST_REPORT_API_KEYS not set - JUnit uploads to /api/metrics/tests/junit are rejected")
+	}
+	metricsAPI.SetTestReportStore(testReports, testReportKeys)
+	metricsAPI.SetAITelemetryStore(aiTelemetry, heartbeatKeys)
+	for _, host := range hosts {
+		metricsAPI.RegisterHost(host.host, host.enhanced)
+	}
+
+	var stalePRs *services.StalePRMonitor
+	if len(cfg.StalePRs.Repositories) > 0 {
+		stalePRConfig := cfg.StalePRs
+		stalePRConfig.Teams = cfg.Teams
+		stalePRConfig.Holidays = holidays
+		stalePRs, err = services.NewStalePRMonitor(service, stalePRConfig, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	analyzer := webhook.NewMetricsAnalyzer(enhancedDORA, chi, chiHistory)
+	analyzer.SetCHIRepository(cfg.Repository)
+	for _, host := range hosts {
+		analyzer.RegisterSource(host.source, host.enhanced)
+	}
+	if cfg.Repository == "" {
+		log.Printf("⚠️  Metrics repository not set - webhook events get no CHI analysis of the clone at %s", cfg.RepoPath)
+	}
+	events := webhook.NewHandler(nil, analyzer, nil, nil)
+
+	var daemon *services.DaemonService
+	if stalePRs != nil || cfg.Anomalies.Channel != "" {
+		daemon, err = startNotificationDaemon(gateway, stalePRs)
+		if err != nil {
+			log.Printf("⚠️  Stale PR digest and anomaly notifications disabled: %v", err)
+		}
+	}
+	if daemon != nil && cfg.Anomalies.Channel != "" {
+		events.SetNotifier(daemon, cfg.Anomalies.Channel, cfg.Anomalies.Recipient)
+	} else if cfg.Anomalies.Channel == "" {
+		log.Printf("⚠️  Anomaly notifications not configured - anomalies found in webhook events are not sent")
+	}
+
+	return &metricsWiring{
+		engine: engine,
+		api:    metricsAPI,
+		events: events,
+		hosts:  hosts,
+		daemon: daemon,
+	}, nil
+}
+
+// hostDORA holds the DORA calculators of the repositories on a host other than GitHub
+type hostDORA struct {
+	host          string // e.g. gitlab.example.com
+	source        string // Source of the host's webhook events, e.g. "gitlab"
+	webhookSecret string // Secret signing the host's webhooks; from GITEA_WEBHOOK_SECRET or BITBUCKET_WEBHOOK_SECRET
+	dora          *metrics.DORACalculator
+	enhanced      *metrics.EnhancedDORACalculator
+}
+
+// hostCalculators builds the DORA calculators of each configured host, with the incident
+// sources that are not tied to GitHub and the teams and holidays of doraConfig
+func hostCalculators(hosts []config.HostConfig, sources []metrics.IncidentSource, doraConfig metrics.DORAConfig, testRuns metrics.TestRunSource) ([]hostDORA, error) {
+	calculators := make([]hostDORA, 0, len(hosts))
+	seen := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		// Webhook events only name their source, so each type has one instance
+		if seen[host.Type] {
+			return nil, fmt.Errorf("only one %s host is supported", host.Type)
+		}
+		seen[host.Type] = true
+
+		calculator, err := newHostDORA(host, doraConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		calculator.dora.SetTestRunSource(testRuns)
+		calculator.enhanced.SetTestRunSource(testRuns)
+		for _, source := range sources {
+			if _, ok := source.(*repositories.GitHubIssueIncidentSource); ok {
+				continue
+			}
+			calculator.dora.AddIncidentSource(source)
+			calculator.enhanced.AddIncidentSource(source)
+		}
+
+		log.Printf("🔀 Repositories on %s use the %s API for DORA metrics", calculator.host, host.Type)
+		calculators = append(calculators, calculator)
+	}
+	return calculators, nil
+}
+
+// newHostDORA creates the API client of a host and DORA calculators reading from it
+func newHostDORA(host config.HostConfig, doraConfig metrics.DORAConfig) (hostDORA, error) {
+	var client metrics.GitHubClient
+	var hostname, webhookSecret string
+	switch host.Type {
+	case "gitlab":
+		gitlabConfig, err := gitlab.LoadConfig()
+		if err != nil {
+			return hostDORA{}, err
+		}
+		if host.BaseURL != "" {
+			gitlabConfig.BaseURL = host.BaseURL
+		}
+		gitlabConfig.Token = host.Token()
+		service, err := gitlab.NewService(gitlabConfig)
+		if err != nil {
+			return hostDORA{}, fmt.Errorf("failed to create GitLab service for %s: %w", gitlabConfig.BaseURL, err)
+		}
+		client, hostname = service, service.Host()
+	case "gitea":
+		giteaConfig, err := gitea.LoadConfig()
+		if err != nil {
+			return hostDORA{}, err
+		}
+		if host.BaseURL != "" {
+			giteaConfig.BaseURL = host.BaseURL
+		}
+		giteaConfig.Token = host.Token()
+		service, err := gitea.NewService(giteaConfig)
+		if err != nil {
+			return hostDORA{}, fmt.Errorf("failed to create Gitea service for %s: %w", giteaConfig.BaseURL, err)
+		}
+		client, hostname, webhookSecret = service, service.Host(), giteaConfig.WebhookSecret
+	case "bitbucket":
+		bitbucketConfig, err := bitbucket.LoadConfig()
+		if err != nil {
+			return hostDORA{}, err
+		}
+		if host.BaseURL != "" {
+			bitbucketConfig.BaseURL = host.BaseURL
+		}
+		if host.Username != "" {
+			bitbucketConfig.Token = ""
+			bitbucketConfig.Username, bitbucketConfig.AppPassword = host.Username, host.Token()
+		} else {
+			bitbucketConfig.Token = host.Token()
+		}
+		service, err := bitbucket.NewService(bitbucketConfig)
+		if err != nil {
+			return hostDORA{}, fmt.Errorf("failed to create Bitbucket service for %s: %w", bitbucketConfig.BaseURL, err)
+		}
+		client, hostname, webhookSecret = service, service.Host(), bitbucketConfig.WebhookSecret
+	default:
+		return hostDORA{}, fmt.Errorf("unsupported host type %q: expected gitlab, gitea or bitbucket", host.Type)
+	}
+
+	dora := metrics.NewDORACalculator(client, nil)
+	dora.SetTeams(doraConfig.Teams)
+	dora.SetHolidays(doraConfig.Holidays)
+	return hostDORA{
+		host:          hostname,
+		source:        host.Type,
+		webhookSecret: webhookSecret,
+		dora:          dora,
+		enhanced:      metrics.NewEnhancedDORACalculator(client, nil, nil, doraConfig),
+	}, nil
+}
+
+// startNotificationDaemon starts the daemon sending notifications through the notification
+// provider of the gateway config, and the stale pull request digest unless monitor is nil
+func startNotificationDaemon(cfg providers.Config, monitor *services.StalePRMonitor) (*services.DaemonService, error) {
+	// The notification service fills in defaults, so it gets its own copy
+	defaults := providers.DefaultsConfig{}
+	if cfg.Defaults != nil {
+		defaults = *cfg.Defaults
+	}
+	cfg.Defaults = &defaults
+
+	daemon := services.NewDaemonService(&cfg)
+	if monitor != nil {
+		daemon.SetStalePRMonitor(monitor)
+	}
+	if err := daemon.Start(); err != nil {
+		return nil, err
+	}
+	return daemon, nil
+}
+
+// loadTestReports creates the store of JUnit reports, loaded with the reports archived by CI
+func loadTestReports(cfg config.TestReportConfig) (*metrics.TestReportStore, error) {
+	store := metrics.NewTestReportStore(cfg.Limit)
+	for _, report := range cfg.Files {
+		run, err := metrics.LoadJUnitFiles(metrics.TestRun{
+			Repository: report.Repository,
+			Workflow:   report.Workflow,
+			SHA:        report.SHA,
+			CreatedAt:  report.CreatedAt,
+		}, report.Paths...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JUnit reports of %s@%s: %w", report.Repository, report.SHA, err)
+		}
+		if err := store.Add(run); err != nil {
+			return nil, fmt.Errorf("failed to load JUnit reports of %s@%s: %w", report.Repository, report.SHA, err)
+		}
+	}
+	return store, nil
+}
+
+// incidentSources lists the configured incident sources, always including the webhook store.
+// The store alone only takes over MTTR and change failure rate once it holds incidents.
+func incidentSources(cfg config.IncidentConfig, service *github.Service, incidents *metrics.IncidentStore) []metrics.IncidentSource {
+	sources := []metrics.IncidentSource{incidents}
+	if len(cfg.GitHubLabels) > 0 {
+		sources = append(sources, repositories.NewGitHubIssueIncidentSource(service, cfg.GitHubLabels))
+	}
+	if cfg.Jira.Enabled() {
+		jira := repositories.NewJiraClient(cfg.Jira.BaseURL, cfg.Jira.Username, cfg.Jira.Token())
+		sources = append(sources, repositories.NewJiraIncidentSource(jira, cfg.Jira.Project, cfg.Jira.IssueTypes, cfg.Jira.Repositories))
+	}
+	if len(cfg.Files) > 0 {
+		sources = append(sources, repositories.NewFileIncidentSource(cfg.Files, cfg.FilesRepository))
+	}
+
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.Name())
+	}
+	log.Printf("🚨 Incident sources for MTTR and change failure rate: %v", names)
+	if len(sources) == 1 {
+		log.Printf("⚠️  No incident source configured: MTTR and change failure rate use workflow and deployment heuristics until incidents are posted to /v1/webhooks/incidents")
+	}
+	return sources
+}
@@ -0,0 +1,95 @@
+// Package metrics - Cross-repository aggregation of DORA metrics
+package metrics
+
+import (
+	"sort"
+)
+
+// AggregateDORAMetrics combines the DORA metrics of several repositories, keyed by "owner/name".
+// Lead time and MTTR of zero mean no data, so means and percentiles of those metrics leave the
+// repository out. The best and worst repositories are ranked by overall performance band.
+func AggregateDORAMetrics(byRepository map[string]*EnhancedDORAMetrics) AggregatedDORAMetrics {
+	var aggregated AggregatedDORAMetrics
+	var leadTimes, leadTimesP95, frequencies, failRates, mttrs []float64
+
+	repositories := make([]string, 0, len(byRepository))
+	for repository, dora := range byRepository {
+		if dora == nil {
+			continue
+		}
+		repositories = append(repositories, repository)
+
+		aggregated.TotalDeployments += dora.TotalDeployments
+		aggregated.TotalIncidents += dora.IncidentCount
+		frequencies = append(frequencies, dora.DeploymentFrequencyWeek)
+		failRates = append(failRates, dora.ChangeFailRatePercent)
+		if dora.LeadTimeP50Hours > 0 {
+			leadTimes = append(leadTimes, dora.LeadTimeP50Hours)
+		}
+		if dora.LeadTimeP95Hours > 0 {
+			leadTimesP95 = append(leadTimesP95, dora.LeadTimeP95Hours)
+		}
+		if dora.MTTRHours > 0 {
+			mttrs = append(mttrs, dora.MTTRHours)
+		}
+	}
+	if len(repositories) == 0 {
+		return aggregated
+	}
+
+	aggregated.MeanLeadTimeP95Hours = meanOf(leadTimesP95)
+	aggregated.MeanDeploymentFrequencyWeek = meanOf(frequencies)
+	aggregated.MeanChangeFailRatePercent = meanOf(failRates)
+	aggregated.MeanMTTRHours = meanOf(mttrs)
+
+	for _, values := range [][]float64{leadTimes, frequencies, failRates, mttrs} {
+		sort.Float64s(values)
+	}
+	aggregated.Percentiles = DORAPercentiles{
+		LeadTimeP50:       percentile(leadTimes, 0.50),
+		LeadTimeP75:       percentile(leadTimes, 0.75),
+		LeadTimeP90:       percentile(leadTimes, 0.90),
+		LeadTimeP95:       percentile(leadTimes, 0.95),
+		DeployFreqP50:     percentile(frequencies, 0.50),
+		DeployFreqP75:     percentile(frequencies, 0.75),
+		DeployFreqP90:     percentile(frequencies, 0.90),
+		ChangeFailRateP50: percentile(failRates, 0.50),
+		ChangeFailRateP75: percentile(failRates, 0.75),
+		MTTRP50:           percentile(mttrs, 0.50),
+		MTTRP75:           percentile(mttrs, 0.75),
+	}
+
+	// Rank by overall band, then by deployment frequency; unclassified repositories rank last
+	rank := func(repository string) int {
+		if band := byRepository[repository].Performance.Overall; band != "" {
+			return bandRank(band)
+		}
+		return len(bandOrder)
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		ri, rj := rank(repositories[i]), rank(repositories[j])
+		if ri != rj {
+			return ri < rj
+		}
+		fi, fj := byRepository[repositories[i]].DeploymentFrequencyWeek, byRepository[repositories[j]].DeploymentFrequencyWeek
+		if fi != fj {
+			return fi > fj
+		}
+		return repositories[i] < repositories[j]
+	})
+	aggregated.BestPerformingRepo = repositories[0]
+	aggregated.WorstPerformingRepo = repositories[len(repositories)-1]
+	return aggregated
+}
+
+// meanOf returns the arithmetic mean of values, or 0 when there are none
+func meanOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,100 @@
+// Package metrics - Line-level AI attribution from git blame
+package metrics
+
+import (
+	"context"
+	"path"
+	"sort"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// maxAttributedFiles caps the files listed in a line attribution report
+const maxAttributedFiles = 100
+
+// BlameHunk is a run of lines in the current tree last changed by one commit
+type BlameHunk struct {
+	File       string `json:"file"`
+	SHA        string `json:"sha"`
+	Author     string `json:"author"`
+	Lines      int    `json:"lines"`
+	AIAssisted bool   `json:"ai_assisted"`
+	AIProvider string `json:"ai_provider,omitempty"`
+}
+
+// LineAttributionClient blames the current tree of a repository, marking lines of AI-assisted
+// commits. A GitClient that also implements it adds line attribution to AI metrics.
+type LineAttributionClient interface {
+	GetLineAttribution(ctx context.Context, owner, repo string) ([]BlameHunk, error)
+}
+
+// CalculateLineAttribution computes the share of surviving lines written by AI-assisted
+// commits, overall and per file, package (directory), author and AI tool. Returns nil
+// without blamed lines.
+func CalculateLineAttribution(hunks []BlameHunk) *types.AILineAttribution {
+	files := make(map[string]*types.AttributionShare)
+	packages := make(map[string]*types.AttributionShare)
+	authors := make(map[string]*types.AttributionShare)
+	providers := make(map[string]*types.AttributionShare)
+	attribution := &types.AILineAttribution{}
+
+	add := func(shares map[string]*types.AttributionShare, name string, hunk BlameHunk) {
+		share := shares[name]
+		if share == nil {
+			share = &types.AttributionShare{Name: name}
+			shares[name] = share
+		}
+		share.Lines += hunk.Lines
+		if hunk.AIAssisted {
+			share.AILines += hunk.Lines
+		}
+	}
+
+	for _, hunk := range hunks {
+		if hunk.Lines <= 0 {
+			continue
+		}
+		attribution.TotalLines += hunk.Lines
+		add(files, hunk.File, hunk)
+		add(packages, path.Dir(hunk.File), hunk)
+		add(authors, firstNonEmpty(hunk.Author, "unknown"), hunk)
+		if hunk.AIAssisted {
+			attribution.AILines += hunk.Lines
+			add(providers, firstNonEmpty(hunk.AIProvider, UnknownAITool), hunk)
+		}
+	}
+	if attribution.TotalLines == 0 {
+		return nil
+	}
+
+	attribution.AISharePercent = float64(attribution.AILines) / float64(attribution.TotalLines) * 100
+	attribution.Files = sortedShares(files)
+	if len(attribution.Files) > maxAttributedFiles {
+		attribution.Files = attribution.Files[:maxAttributedFiles]
+	}
+	attribution.Packages = sortedShares(packages)
+	attribution.Authors = sortedShares(authors)
+	attribution.Providers = sortedShares(providers)
+	return attribution
+}
+
+// sortedShares computes share percentages and orders by AI lines, then lines, then name
+func sortedShares(shares map[string]*types.AttributionShare) []types.AttributionShare {
+	sorted := make([]types.AttributionShare, 0, len(shares))
+	for _, share := range shares {
+		if share.Lines > 0 {
+			share.AISharePercent = float64(share.AILines) / float64(share.Lines) * 100
+		}
+		sorted = append(sorted, *share)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].AILines != sorted[j].AILines {
+			return sorted[i].AILines > sorted[j].AILines
+		}
+		if sorted[i].Lines != sorted[j].Lines {
+			return sorted[i].Lines > sorted[j].Lines
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,277 @@
+// Package metrics - Configurable AI tool detection rules
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownAITool identifies AI assistance that no rule names a tool for
+const UnknownAITool = "unknown-ai"
+
+// AIToolRule maps the marks an AI tool leaves on commits to the tool's identity. Message,
+// co-author and branch patterns are case-insensitive regular expressions; trailers are keys
+// whose presence marks the tool, or rules it out when set to "false", "no", "none" or "0".
+type AIToolRule struct {
+	Tool            string   `json:"tool" yaml:"tool"`                                             // Identity reported for matching commits, e.g. "github-copilot"
+	Aliases         []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`                   // Other names of the tool in telemetry, e.g. "copilot"
+	MessagePatterns []string `json:"message_patterns,omitempty" yaml:"message_patterns,omitempty"` // Matched against the full commit message
+	CoAuthors       []string `json:"co_authors,omitempty" yaml:"co_authors,omitempty"`             // Matched against "Name <email>" of Co-authored-by trailers
+	Trailers        []string `json:"trailers,omitempty" yaml:"trailers,omitempty"`                 // Trailer keys, e.g. "Copilot-Assisted"
+	Branches        []string `json:"branches,omitempty" yaml:"branches,omitempty"`                 // Matched against the branch a commit was made on
+}
+
+// AIDetectionRules decides which commits are AI-assisted and by which tool. Rules are tried
+// in order and the first match wins, so tool-specific rules go before generic ones.
+type AIDetectionRules struct {
+	tools []compiledAIToolRule
+}
+
+// compiledAIToolRule is an AIToolRule with its patterns compiled
+type compiledAIToolRule struct {
+	rule      AIToolRule
+	messages  []*regexp.Regexp
+	coAuthors []*regexp.Regexp
+	branches  []*regexp.Regexp
+}
+
+// aiDetectionFile is the YAML (or JSON) form of a rule set
+type aiDetectionFile struct {
+	IncludeDefaults bool         `json:"include_defaults" yaml:"include_defaults"` // Try the default rules after the file's own
+	Tools           []AIToolRule `json:"tools" yaml:"tools"`
+}
+
+// agentSignature starts a message line an assistant signs its commits with, such as
+// "Generated with Claude Code" or "🤖 Created by Copilot"
+const agentSignature = `(?m)^\W*(generated|created|written|authored) (with|by|using) \[?`
+
+// DefaultAIToolRules returns rules for common assistants followed by generic AI markers. They
+// only match the trailers, signatures, bot co-authors and branches the assistants leave, not a
+// mention of a tool in an ordinary message.
+func DefaultAIToolRules() []AIToolRule {
+	return []AIToolRule{
+		{
+			Tool:            "github-copilot",
+			Aliases:         []string{"copilot", "github_copilot", "gh-copilot"},
+			MessagePatterns: []string{agentSignature + `(gh |github )?copilot\b`},
+			CoAuthors:       []string{`[<+]copilot@(users\.noreply\.)?github\.com>`},
+			Trailers:        []string{"Copilot-Assisted"},
+			Branches:        []string{`^copilot/`},
+		},
+		{
+			Tool:            "amazon-codewhisperer",
+			Aliases:         []string{"codewhisperer", "amazon-q", "amazon_q"},
+			MessagePatterns: []string{agentSignature + `(amazon )?(codewhisperer|q developer)\b`},
+			CoAuthors:       []string{`^amazon (codewhisperer|q)( developer)? <`},
+			Trailers:        []string{"Codewhisperer-Assisted"},
+		},
+		{
+			Tool:            "codeium",
+			Aliases:         []string{"windsurf"},
+			MessagePatterns: []string{agentSignature + `(codeium|windsurf)\b`},
+			CoAuthors:       []string{`^(codeium|windsurf)( cascade)? <`},
+		},
+		{
+			Tool:            "tabnine",
+			MessagePatterns: []string{agentSignature + `tabnine\b`},
+			CoAuthors:       []string{`^tabnine <`},
+		},
+		{
+			Tool:            "cursor",
+			Aliases:         []string{"cursor-ai"},
+			MessagePatterns: []string{agentSignature + `cursor\b`},
+			CoAuthors:       []string{`@cursor\.(com|sh)>`},
+			Branches:        []string{`^cursor/`},
+		},
+		{
+			Tool:            "claude",
+			Aliases:         []string{"claude-code", "anthropic"},
+			MessagePatterns: []string{agentSignature + `claude\b`},
+			CoAuthors:       []string{`<noreply@anthropic\.com>`},
+			Branches:        []string{`^claude/`},
+		},
+		{
+			Tool:            "chatgpt",
+			Aliases:         []string{"openai", "codex"},
+			MessagePatterns: []string{agentSignature + `(chatgpt|(openai )?codex)\b`},
+			CoAuthors:       []string{`^(chatgpt|codex)\b[^<]*<[^>]*(noreply|bot)[^>]*>`},
+			Branches:        []string{`^codex/`},
+		},
+		{
+			Tool:            "aider",
+			MessagePatterns: []string{`^aider: `},
+			CoAuthors:       []string{`@aider\.chat>`},
+		},
+		{
+			Tool:            UnknownAITool,
+			MessagePatterns: []string{`^\[ai[- ]?(assisted|generated)\]`},
+			CoAuthors:       []string{`^ai[- ]assistant <`},
+			Trailers:        []string{"AI-Assisted", "AI-Generated", "Generated-By", "Assisted-By"},
+		},
+	}
+}
+
+// defaultAIDetectionRules is used when no rule set is configured
+var defaultAIDetectionRules = mustAIDetectionRules(DefaultAIToolRules())
+
+// DefaultAIDetectionRules returns the default rule set
+func DefaultAIDetectionRules() *AIDetectionRules {
+	return defaultAIDetectionRules
+}
+
+// NewAIDetectionRules compiles a rule set
+func NewAIDetectionRules(tools []AIToolRule) (*AIDetectionRules, error) {
+	rules := &AIDetectionRules{}
+	for _, tool := range tools {
+		if tool.Tool == "" {
+			return nil, fmt.Errorf("AI tool rule requires a tool name")
+		}
+		compiled := compiledAIToolRule{rule: tool}
+		var err error
+		if compiled.messages, err = compileAIPatterns(tool.Tool, tool.MessagePatterns); err != nil {
+			return nil, err
+		}
+		if compiled.coAuthors, err = compileAIPatterns(tool.Tool, tool.CoAuthors); err != nil {
+			return nil, err
+		}
+		if compiled.branches, err = compileAIPatterns(tool.Tool, tool.Branches); err != nil {
+			return nil, err
+		}
+		rules.tools = append(rules.tools, compiled)
+	}
+	return rules, nil
+}
+
+// mustAIDetectionRules compiles a rule set known to be valid
+func mustAIDetectionRules(tools []AIToolRule) *AIDetectionRules {
+	rules, err := NewAIDetectionRules(tools)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// compileAIPatterns compiles case-insensitive patterns of a tool rule
+func compileAIPatterns(tool string, patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q for AI tool %s: %w", pattern, tool, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// LoadAIDetectionRules reads a rule set from a YAML (or JSON) file
+func LoadAIDetectionRules(path string) (*AIDetectionRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AI detection rules %s: %w", path, err)
+	}
+	return ParseAIDetectionRules(data)
+}
+
+// ParseAIDetectionRules parses a rule set of the form
+//
+//	include_defaults: true
+//	tools:
+//	  - tool: internal-assistant
+//	    aliases: [assistant]
+//	    message_patterns: ['\[assistant\]']
+//	    co_authors: ['assistant@example\.com']
+//	    trailers: [Assistant-Session]
+//	    branches: ['^assistant/']
+func ParseAIDetectionRules(data []byte) (*AIDetectionRules, error) {
+	var file aiDetectionFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse AI detection rules: %w", err)
+	}
+
+	tools := file.Tools
+	if file.IncludeDefaults {
+		tools = append(tools, DefaultAIToolRules()...)
+	}
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("AI detection rules define no tools")
+	}
+	return NewAIDetectionRules(tools)
+}
+
+// Detect returns the AI tool that assisted a commit, from its message, trailers, co-authors
+// and branch. A nil rule set uses the defaults.
+func (r *AIDetectionRules) Detect(commit Commit) (string, bool) {
+	if r == nil {
+		r = defaultAIDetectionRules
+	}
+	for _, tool := range r.tools {
+		if tool.matches(commit) {
+			return tool.rule.Tool, true
+		}
+	}
+	return "", false
+}
+
+// ToolName maps a tool name or alias, as reported by telemetry or a commit, to the identity of
+// its rule. Names without a rule are returned lowercased.
+func (r *AIDetectionRules) ToolName(name string) string {
+	if r == nil {
+		r = defaultAIDetectionRules
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return UnknownAITool
+	}
+	for _, tool := range r.tools {
+		if strings.EqualFold(tool.rule.Tool, name) {
+			return tool.rule.Tool
+		}
+		for _, alias := range tool.rule.Aliases {
+			if strings.EqualFold(alias, name) {
+				return tool.rule.Tool
+			}
+		}
+	}
+	return name
+}
+
+// matches checks a commit against every kind of mark of the rule. A trailer of the rule set
+// to a negative value rules the tool out, even if the message mentions it.
+func (t compiledAIToolRule) matches(commit Commit) bool {
+	for key, value := range commit.Trailers {
+		for _, trailer := range t.rule.Trailers {
+			if !strings.EqualFold(key, trailer) {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "false", "no", "none", "0":
+				return false
+			}
+			return true
+		}
+	}
+	for _, coAuthor := range commit.CoAuthoredBy {
+		if matchesAny(t.coAuthors, coAuthor) {
+			return true
+		}
+	}
+	if commit.Branch != "" && matchesAny(t.branches, commit.Branch) {
+		return true
+	}
+	return matchesAny(t.messages, commit.Message)
+}
+
+// matchesAny reports whether any pattern matches text
+func matchesAny(patterns []*regexp.Regexp, text string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
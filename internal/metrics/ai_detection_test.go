@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+func TestDefaultAIDetectionRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		commit Commit
+		tool   string
+	}{
+		{"agent signature", Commit{Message: "Add parser\n\n🤖 Generated with [Claude Code](https://claude.com/claude-code)"}, "claude"},
+		{"agent co-author", Commit{CoAuthoredBy: []string{"Claude <noreply@anthropic.com>"}}, "claude"},
+		{"copilot agent", Commit{CoAuthoredBy: []string{"Copilot <175728472+Copilot@users.noreply.github.com>"}}, "github-copilot"},
+		{"cursor agent", Commit{CoAuthoredBy: []string{"Cursor Agent <cursoragent@cursor.com>"}}, "cursor"},
+		{"aider", Commit{Message: "aider: Add retries", CoAuthoredBy: []string{"aider (gpt-4o) <noreply@aider.chat>"}}, "aider"},
+		{"agent branch", Commit{Message: "Fix tests", Branch: "codex/fix-tests"}, "chatgpt"},
+		{"trailer", Commit{Trailers: map[string]string{"AI-Assisted": "true"}}, UnknownAITool},
+		{"subject tag", Commit{Message: "[AI-generated] Add fixtures"}, UnknownAITool},
+
+		// Mentions of a tool or of AI in an ordinary commit
+		{"tool in subject", Commit{Message: "Fix claude provider timeout"}, ""},
+		{"tool in body", Commit{Message: "Add cursor pagination\n\nThe cursor is opaque, like codex and copilot suggested."}, ""},
+		{"AI in subject", Commit{Message: "Route requests with AI gateway fallback"}, ""},
+		{"AI-generated feature", Commit{Message: "Fix AI-generated summaries\n\nGenerated-by trailers were ignored"}, ""},
+		{"namesake co-authors", Commit{CoAuthoredBy: []string{"Claude Monet <claude@example.com>", "Codex Team <codex@acme.io>", "Cursor Smith <cursor@acme.io>"}}, ""},
+		{"negative trailer", Commit{Trailers: map[string]string{"AI-Assisted": "no"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, ok := DefaultAIDetectionRules().Detect(tt.commit)
+			if tool != tt.tool || ok != (tt.tool != "") {
+				t.Errorf("Detect = %q, %v, expected %q", tool, ok, tt.tool)
+			}
+		})
+	}
+}
+
+func TestParseAIDetectionRules(t *testing.T) {
+	rules, err := ParseAIDetectionRules([]byte(`
+include_defaults: true
+tools:
+  - tool: internal-assistant
+    aliases: [assistant]
+    message_patterns: ['\[assistant\]']
+    trailers: [Assistant-Session]
+`))
+	if err != nil {
+		t.Fatalf("ParseAIDetectionRules failed: %v", err)
+	}
+	if tool, _ := rules.Detect(Commit{Message: "[assistant] Add retries"}); tool != "internal-assistant" {
+		t.Errorf("Expected the file's own tool, got %q", tool)
+	}
+	if tool, _ := rules.Detect(Commit{Branch: "claude/add-retries"}); tool != "claude" {
+		t.Errorf("Expected the default rules after the file's own, got %q", tool)
+	}
+	if name := rules.ToolName("Assistant"); name != "internal-assistant" {
+		t.Errorf("Expected the alias mapped to its tool, got %q", name)
+	}
+
+	if _, err := ParseAIDetectionRules([]byte("tools: []")); err == nil {
+		t.Error("Expected an error for rules without tools")
+	}
+	if _, err := ParseAIDetectionRules([]byte("tools: [{tool: x, message_patterns: ['(']}]")); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+// fakeCommitClient serves the same commits for every repository
+type fakeCommitClient []Commit
+
+func (f fakeCommitClient) GetCommits(ctx context.Context, owner, repo string, since time.Time) ([]Commit, error) {
+	return f, nil
+}
+
+func TestToolBreakdownOfUser(t *testing.T) {
+	now := time.Now()
+	copilot := []string{"Copilot <175728472+Copilot@users.noreply.github.com>"}
+	commits := fakeCommitClient{
+		{SHA: "a", Author: "Alice Doe", Email: "123+alice@users.noreply.github.com", Additions: 10, CoAuthoredBy: copilot},
+		{SHA: "b", Author: "alice", Additions: 5, Branch: "claude/retries"},
+		{SHA: "c", Author: "Bob", Email: "bob@acme.io", Additions: 40, CoAuthoredBy: copilot},
+	}
+	store := NewAITelemetryStore(0)
+	if err := store.Add(
+		AIAssistEvent{Type: AIEventSession, User: "alice", Repository: "acme/api", Provider: "copilot", Timestamp: now.Add(-time.Hour), DurationSeconds: 3600},
+		AIAssistEvent{Type: AIEventSession, User: "alice", Repository: "other/api", Provider: "cursor", Timestamp: now.Add(-time.Hour), DurationSeconds: 3600},
+		AIAssistEvent{Type: AIEventSession, User: "bob", Repository: "acme/api", Provider: "copilot", Timestamp: now.Add(-time.Hour), DurationSeconds: 7200},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	calculator := NewAIMetricsCalculator(fakeWakaTimeClient{}, commits, store)
+	repo := types.Repository{Owner: "acme", Name: "api"}
+	breakdown, err := calculator.ToolBreakdown(context.Background(), repo, "alice", 30)
+	if err != nil {
+		t.Fatalf("ToolBreakdown failed: %v", err)
+	}
+	// Alice's commits and acme/api telemetry only
+	if len(breakdown) != 2 {
+		t.Fatalf("Expected copilot and claude, got %+v", breakdown)
+	}
+	if copilot := breakdown[0]; copilot.ToolName != "github-copilot" || copilot.Commits != 1 || copilot.LinesAccepted != 10 || copilot.UsageHours != 1 {
+		t.Errorf("Unexpected copilot usage %+v", copilot)
+	}
+	if claude := breakdown[1]; claude.ToolName != "claude" || claude.Commits != 1 || claude.UsageHours != 0 {
+		t.Errorf("Unexpected claude usage %+v", claude)
+	}
+
+	// Every user when empty
+	breakdown, _ = calculator.ToolBreakdown(context.Background(), repo, "", 30)
+	if len(breakdown) != 2 || breakdown[0].Commits != 2 || breakdown[0].UsageHours != 3 {
+		t.Errorf("Expected the commits and hours of alice and bob, got %+v", breakdown)
+	}
+}
+
+func TestAuthoredBy(t *testing.T) {
+	commit := Commit{Author: "Alice Doe", Email: "123+Alice@users.noreply.github.com"}
+	for user, expected := range map[string]bool{
+		"alice doe": true, "alice": true, "123+alice@users.noreply.github.com": true, "bob": false, "": false,
+	} {
+		if got := authoredBy(commit, user); got != expected {
+			t.Errorf("authoredBy(%q) = %v, expected %v", user, got, expected)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,6 +16,8 @@ type AIMetricsCalculator struct {
 	wakatimeClient WakaTimeClient
 	gitClient      GitClient
 	ideClient      IDEClient
+	qualityConfig  AIQualityConfig
+	detection      *AIDetectionRules
 }
 
 // WakaTimeClient interface for time tracking data
@@ -32,6 +35,12 @@ type IDEClient interface {
 	GetAIAssistData(ctx context.Context, user, repo string, since time.Time) (*AIAssistData, error)
 }
 
+// AIToolTelemetryClient reports IDE telemetry per AI tool. An IDEClient that also implements it
+// adds usage hours, acceptance rates and generated lines to the AI tool breakdown.
+type AIToolTelemetryClient interface {
+	GetAIAssistDataByTool(ctx context.Context, user, repo string, since time.Time) (map[string]*AIAssistData, error)
+}
+
 // CodingTime represents time tracking data from WakaTime
 type CodingTime struct {
 	TotalHours  float64        `json:"total_hours"`
@@ -56,14 +65,17 @@ type Commit struct {
 	SHA       string    `json:"sha"`
 	Message   string    `json:"message"`
 	Author    string    `json:"author"`
+	Email     string    `json:"email,omitempty"` // Author email, when the source reports it
 	Date      time.Time `json:"date"`
 	Files     []string  `json:"files"`
 	Additions int       `json:"additions"`
 	Deletions int       `json:"deletions"`
 	// AI assistance indicators
-	CoAuthoredBy []string `json:"co_authored_by"`
-	AIAssisted   bool     `json:"ai_assisted"`
-	AIProvider   string   `json:"ai_provider"`
+	CoAuthoredBy []string          `json:"co_authored_by"`     // "Name <email>" of Co-authored-by trailers
+	Trailers     map[string]string `json:"trailers,omitempty"` // Trailers of the message, last value per key
+	Branch       string            `json:"branch,omitempty"`   // Branch the commit was made on, when known
+	AIAssisted   bool              `json:"ai_assisted"`
+	AIProvider   string            `json:"ai_provider"`
 }
 
 // AIAssistData represents AI assistance data from IDE
@@ -82,29 +94,84 @@ func NewAIMetricsCalculator(wakatime WakaTimeClient, git GitClient, ide IDEClien
 		wakatimeClient: wakatime,
 		gitClient:      git,
 		ideClient:      ide,
+		qualityConfig:  DefaultAIQualityConfig(),
+		detection:      DefaultAIDetectionRules(),
 	}
 }
 
+// SetAIDetectionRules sets the rules deciding which commits are AI-assisted and by which tool.
+// Use the same rules as the git client so both agree.
+func (a *AIMetricsCalculator) SetAIDetectionRules(rules *AIDetectionRules) {
+	a.detection = rules
+}
+
+// SetQualityConfig sets the follow-up windows of the AI vs human quality comparison
+func (a *AIMetricsCalculator) SetQualityConfig(config AIQualityConfig) {
+	a.qualityConfig = config
+}
+
 // Calculate computes AI impact metrics for a repository
 func (a *AIMetricsCalculator) Calculate(ctx context.Context, repo types.Repository, user string, periodDays int) (*types.AIMetrics, error) {
-	since := time.Now().AddDate(0, 0, -periodDays)
+	aiMetrics, _, err := a.calculate(ctx, repo, user, periodDays, time.Now())
+	return aiMetrics, err
+}
+
+// CalculateEnhanced computes AI impact metrics along with the code quality impact of the
+// period's AI-assisted commits and the human vs AI contributions
+func (a *AIMetricsCalculator) CalculateEnhanced(ctx context.Context, repo types.Repository, user string, periodDays int) (*EnhancedAIMetrics, error) {
+	now := time.Now()
+	aiMetrics, commits, err := a.calculate(ctx, repo, user, periodDays, now)
+	if err != nil {
+		return nil, err
+	}
+
+	shas := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		shas = append(shas, commit.SHA)
+	}
+	var hunks map[string][]DiffHunk
+	if client, ok := a.gitClient.(CommitHunkClient); ok && len(shas) > 0 {
+		hunks, err = client.GetCommitHunks(ctx, repo.Owner, repo.Name, shas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit hunks: %w", err)
+		}
+	}
+	var deltas map[string]CodeDelta
+	if client, ok := a.gitClient.(CommitCodeDeltaClient); ok && len(shas) > 0 {
+		deltas, err = client.GetCommitCodeDeltas(ctx, repo.Owner, repo.Name, shas)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit code deltas: %w", err)
+		}
+	}
+
+	enhanced := &EnhancedAIMetrics{
+		AIMetrics: *aiMetrics,
+		TimeRange: TimeRange{Start: now.AddDate(0, 0, -periodDays), End: now},
+	}
+	enhanced.CodeQualityImpact, enhanced.HumanVsAIContributions = CompareAIQuality(commits, a.isAIAssisted, hunks, deltas, a.qualityConfig, now)
+	return enhanced, nil
+}
+
+// calculate computes AI impact metrics for the period ending at now, along with its commits
+func (a *AIMetricsCalculator) calculate(ctx context.Context, repo types.Repository, user string, periodDays int, now time.Time) (*types.AIMetrics, []Commit, error) {
+	since := now.AddDate(0, 0, -periodDays)
 
 	// Get time tracking data
 	codingTime, err := a.wakatimeClient.GetCodingTime(ctx, user, repo.Name, since)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get coding time: %w", err)
+		return nil, nil, fmt.Errorf("failed to get coding time: %w", err)
 	}
 
 	// Get commit data
 	commits, err := a.gitClient.GetCommits(ctx, repo.Owner, repo.Name, since)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get commits: %w", err)
+		return nil, nil, fmt.Errorf("failed to get commits: %w", err)
 	}
 
 	// Get AI assistance data
-	aiData, err := a.ideClient.GetAIAssistData(ctx, user, repo.Name, since)
+	aiData, err := a.ideClient.GetAIAssistData(ctx, user, repositoryFullName(repo), since)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get AI assist data: %w", err)
+		return nil, nil, fmt.Errorf("failed to get AI assist data: %w", err)
 	}
 
 	// Calculate metrics
@@ -112,15 +179,114 @@ func (a *AIMetricsCalculator) Calculate(ctx context.Context, repo types.Reposito
 	aac := a.calculateAAC(commits)
 	tph := a.calculateTPH(commits, codingTime)
 
-	return &types.AIMetrics{
+	aiMetrics := &types.AIMetrics{
 		HIR:          hir,
 		AAC:          aac,
 		TPH:          tph,
 		HumanHours:   codingTime.CodingHours - aiData.TimeWithAI,
 		AIHours:      aiData.TimeWithAI,
 		Period:       periodDays,
-		CalculatedAt: time.Now(),
-	}, nil
+		CalculatedAt: now,
+	}
+
+	// Line attribution weighs AI assistance by the lines that survive in the tree
+	if client, ok := a.gitClient.(LineAttributionClient); ok {
+		hunks, err := client.GetLineAttribution(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get line attribution: %w", err)
+		}
+		aiMetrics.LineAttribution = CalculateLineAttribution(hunks)
+	}
+
+	return aiMetrics, commits, nil
+}
+
+// ToolBreakdown reports usage per AI tool, of one user or of every user when empty. Commits and
+// the lines they added are attributed to tools by the detection rules; usage hours, acceptance
+// rate and generated lines come from IDE telemetry when the IDE client reports per tool, with
+// provider names mapped to rule tools. Tools are ordered by commits, then usage hours.
+func (a *AIMetricsCalculator) ToolBreakdown(ctx context.Context, repo types.Repository, user string, periodDays int) ([]AIToolUsage, error) {
+	since := time.Now().AddDate(0, 0, -periodDays)
+
+	commits, err := a.gitClient.GetCommits(ctx, repo.Owner, repo.Name, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	tools := make(map[string]*AIToolUsage)
+	usage := func(name string) *AIToolUsage {
+		tool := tools[name]
+		if tool == nil {
+			tool = &AIToolUsage{ToolName: name}
+			tools[name] = tool
+		}
+		return tool
+	}
+
+	for _, commit := range commits {
+		if user != "" && !authoredBy(commit, user) {
+			continue
+		}
+		if name, ok := a.aiTool(commit); ok {
+			tool := usage(name)
+			tool.Commits++
+			tool.LinesAccepted += commit.Additions
+		}
+	}
+
+	if client, ok := a.ideClient.(AIToolTelemetryClient); ok {
+		byTool, err := client.GetAIAssistDataByTool(ctx, user, repositoryFullName(repo), since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get AI assist data per tool: %w", err)
+		}
+		suggestions := make(map[string][2]int) // Shown and accepted per tool
+		for provider, data := range byTool {
+			name := a.detection.ToolName(provider)
+			tool := usage(name)
+			tool.UsageHours += data.TimeWithAI
+			tool.LinesGenerated += data.LinesGenerated
+			counts := suggestions[name]
+			counts[0] += data.TotalSuggestions
+			counts[1] += data.AcceptedSuggestions
+			suggestions[name] = counts
+		}
+		for name, counts := range suggestions {
+			if counts[0] > 0 {
+				tools[name].AcceptanceRate = float64(counts[1]) / float64(counts[0])
+			}
+		}
+	}
+
+	breakdown := make([]AIToolUsage, 0, len(tools))
+	for _, tool := range tools {
+		breakdown = append(breakdown, *tool)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Commits != breakdown[j].Commits {
+			return breakdown[i].Commits > breakdown[j].Commits
+		}
+		if breakdown[i].UsageHours != breakdown[j].UsageHours {
+			return breakdown[i].UsageHours > breakdown[j].UsageHours
+		}
+		return breakdown[i].ToolName < breakdown[j].ToolName
+	})
+	return breakdown, nil
+}
+
+// authoredBy reports whether a commit is by user, matched against the author name, email or
+// the user part of the email, such as "alice" of "123+alice@users.noreply.github.com"
+func authoredBy(commit Commit, user string) bool {
+	if strings.EqualFold(commit.Author, user) || strings.EqualFold(commit.Email, user) {
+		return true
+	}
+	local, _, ok := strings.Cut(commit.Email, "@")
+	if !ok {
+		return false
+	}
+	if _, login, noreply := strings.Cut(local, "+"); noreply {
+		local = login
+	}
+	return strings.EqualFold(local, user)
 }
 
 // calculateHIR calculates Human Input Ratio (0.0-1.0)
@@ -188,58 +354,17 @@ func (a *AIMetricsCalculator) calculateTPH(commits []Commit, codingTime *CodingT
 
 // isAIAssisted determines if a commit was AI-assisted
 func (a *AIMetricsCalculator) isAIAssisted(commit Commit) bool {
-	// Check explicit AI assisted flag
-	if commit.AIAssisted {
-		return true
-	}
-
-	// Check co-authored-by patterns
-	for _, coAuthor := range commit.CoAuthoredBy {
-		if a.isAICoAuthor(coAuthor) {
-			return true
-		}
-	}
-
-	// Check commit message patterns
-	aiPatterns := []string{
-		"copilot",
-		"codewhisperer",
-		"codeium",
-		"ai-assisted",
-		"ai-generated",
-		"with ai",
-		"assisted by",
-	}
-
-	message := strings.ToLower(commit.Message)
-	for _, pattern := range aiPatterns {
-		if strings.Contains(message, pattern) {
-			return true
-		}
-	}
-
-	return false
+	_, ok := a.aiTool(commit)
+	return ok
 }
 
-// isAICoAuthor checks if a co-author is an AI system
-func (a *AIMetricsCalculator) isAICoAuthor(coAuthor string) bool {
-	aiCoAuthors := []string{
-		"github-copilot",
-		"copilot",
-		"codewhisperer",
-		"codeium",
-		"tabnine",
-		"ai-assistant",
-	}
-
-	coAuthorLower := strings.ToLower(coAuthor)
-	for _, ai := range aiCoAuthors {
-		if strings.Contains(coAuthorLower, ai) {
-			return true
-		}
+// aiTool returns the AI tool of a commit: the provider set by the git client, or else the
+// tool detected by the rules
+func (a *AIMetricsCalculator) aiTool(commit Commit) (string, bool) {
+	if commit.AIAssisted {
+		return a.detection.ToolName(commit.AIProvider), true
 	}
-
-	return false
+	return a.detection.Detect(commit)
 }
 
 // AnalyzeAIImpact provides insights on AI usage patterns
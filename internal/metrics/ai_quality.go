@@ -0,0 +1,310 @@
+// Package metrics - Quality outcomes of AI-assisted vs human commits
+package metrics
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	significanceLevel   = 0.05
+	minSignificanceSize = 5 // Smaller groups are never reported as significant
+)
+
+// fixMessagePattern matches commit subjects of bug fixes and hotfixes
+var fixMessagePattern = regexp.MustCompile(`(?i)^(hot)?fix(es|ed)?\b|^(hot)?fix[(:!]|\bhotfix\b`)
+
+// AIQualityConfig tunes the AI vs human quality comparison
+type AIQualityConfig struct {
+	WindowDays int `json:"window_days" yaml:"window_days"` // Days after a commit in which a revert or fix counts against it
+	ReworkDays int `json:"rework_days" yaml:"rework_days"` // Days after a commit in which changing its lines again is rework
+}
+
+// DefaultAIQualityConfig follows commits up for 14 days, and their lines for 7
+func DefaultAIQualityConfig() AIQualityConfig {
+	return AIQualityConfig{WindowDays: 14, ReworkDays: 7}
+}
+
+// CodeDelta is how a commit changed the complexity and duplication of the files it touched
+type CodeDelta struct {
+	ComplexityDelta      int `json:"complexity_delta"`       // Cyclomatic complexity after minus before
+	DuplicatedLinesDelta int `json:"duplicated_lines_delta"` // Lines in duplicated blocks after minus before
+}
+
+// CommitCodeDeltaClient measures code deltas of commits. A GitClient that also implements it
+// adds complexity and duplication to the AI quality comparison.
+type CommitCodeDeltaClient interface {
+	GetCommitCodeDeltas(ctx context.Context, owner, repo string, shas []string) (map[string]CodeDelta, error)
+}
+
+// DiffHunk is a block of lines a commit changed in a file, against its first parent. A hunk
+// that only adds lines has OldLines 0 and OldStart at the line it adds after; one that only
+// removes lines has NewLines 0.
+type DiffHunk struct {
+	File     string `json:"file"`
+	OldStart int    `json:"old_start"`
+	OldLines int    `json:"old_lines"`
+	NewStart int    `json:"new_start"`
+	NewLines int    `json:"new_lines"`
+}
+
+// CommitHunkClient lists the changed lines of commits. A GitClient that also implements it adds
+// hotfix and rework rates to the AI quality comparison.
+type CommitHunkClient interface {
+	GetCommitHunks(ctx context.Context, owner, repo string, shas []string) (map[string][]DiffHunk, error)
+}
+
+// followUpOutcomes records what happened to a commit within the follow-up windows
+type followUpOutcomes struct {
+	reverted, hotfixed, reworked bool
+}
+
+// CompareAIQuality compares AI-assisted and human commits: how often they are reverted within
+// the window, how often a fix or any other commit changes their lines again, and how much
+// complexity and duplication they add. Outcome rates only use commits whose window ended
+// before now, and hotfix and rework rates only commits with known hunks. Rates are compared
+// with a two-proportion z-test and deltas with a Mann-Whitney U test. Test coverage, review
+// pass rate and vulnerabilities are not measured from git history and stay zero.
+func CompareAIQuality(commits []Commit, isAI func(Commit) bool, hunks map[string][]DiffHunk, deltas map[string]CodeDelta, config AIQualityConfig, now time.Time) (CodeQualityImpact, HumanVsAIContributions) {
+	defaults := DefaultAIQualityConfig()
+	if config.WindowDays <= 0 {
+		config.WindowDays = defaults.WindowDays
+	}
+	if config.ReworkDays <= 0 {
+		config.ReworkDays = defaults.ReworkDays
+	}
+	window := time.Duration(config.WindowDays) * 24 * time.Hour
+	reworkWindow := time.Duration(config.ReworkDays) * 24 * time.Hour
+
+	sorted := append([]Commit(nil), commits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	impact := CodeQualityImpact{WindowDays: config.WindowDays, ReworkDays: config.ReworkDays}
+	var contributions HumanVsAIContributions
+	var rates [2][3]struct{ hits, total int } // [human, ai][revert, hotfix, rework]
+	var complexity, duplication [2][]float64
+	chi := &CHICalculator{}
+
+	for i, commit := range sorted {
+		if isRevertMessage(commit.Message) {
+			continue
+		}
+		group := 0
+		commitsOf, linesOf, testsOf, fixesOf := &contributions.HumanCommits, &contributions.HumanLinesAdded,
+			&contributions.HumanTestsWritten, &contributions.HumanBugsFixed
+		if isAI(commit) {
+			group = 1
+			commitsOf, linesOf, testsOf, fixesOf = &contributions.AIAssistedCommits, &contributions.AILinesAdded,
+				&contributions.AITestsWritten, &contributions.AIBugsFixed
+		}
+		*commitsOf++
+		*linesOf += commit.Additions
+		for _, file := range commit.Files {
+			if chi.isTestFile(file) {
+				*testsOf++
+			}
+		}
+		if fixMessagePattern.MatchString(firstLine(commit.Message)) {
+			*fixesOf++
+		}
+
+		if delta, ok := deltas[commit.SHA]; ok {
+			complexity[group] = append(complexity[group], float64(delta.ComplexityDelta))
+			duplication[group] = append(duplication[group], float64(delta.DuplicatedLinesDelta))
+		}
+
+		if now.Sub(commit.Date) < window {
+			continue
+		}
+		_, knownHunks := hunks[commit.SHA]
+		outcomes := followUps(commit, sorted[i+1:], hunks, window, reworkWindow)
+		for measure, hit := range []bool{outcomes.reverted, outcomes.hotfixed, outcomes.reworked} {
+			// Fixes and rework need the changed lines of the commit
+			if measure > 0 && !knownHunks {
+				continue
+			}
+			rates[group][measure].total++
+			if hit {
+				rates[group][measure].hits++
+			}
+		}
+	}
+
+	for measure, name := range []string{"revert_rate", "hotfix_rate", "rework_rate"} {
+		human, ai := rates[0][measure], rates[1][measure]
+		comparison := QualityComparison{
+			Metric:      name,
+			Human:       percentOf(human.hits, human.total),
+			AI:          percentOf(ai.hits, ai.total),
+			HumanSample: human.total,
+			AISample:    ai.total,
+			Test:        "two_proportion_z",
+			PValue:      twoProportionPValue(ai.hits, ai.total, human.hits, human.total),
+		}
+		impact.Comparisons = append(impact.Comparisons, finishComparison(comparison))
+	}
+	for _, measure := range []struct {
+		name    string
+		samples [2][]float64
+	}{{"complexity_delta", complexity}, {"duplication_delta", duplication}} {
+		human := append([]float64(nil), measure.samples[0]...)
+		ai := append([]float64(nil), measure.samples[1]...)
+		sort.Float64s(human)
+		sort.Float64s(ai)
+		comparison := QualityComparison{
+			Metric:      measure.name,
+			Human:       percentile(human, 0.50),
+			AI:          percentile(ai, 0.50),
+			HumanSample: len(human),
+			AISample:    len(ai),
+			Test:        "mann_whitney_u",
+			PValue:      mannWhitneyPValue(ai, human),
+		}
+		impact.Comparisons = append(impact.Comparisons, finishComparison(comparison))
+	}
+
+	hotfix, rework, complexityDelta := impact.Comparisons[1], impact.Comparisons[2], impact.Comparisons[3]
+	impact.BugDensityReduction = -hotfix.Difference
+	impact.RefactoringFrequency = rework.Difference
+	impact.CodeComplexityChange = complexityDelta.Difference
+
+	return impact, contributions
+}
+
+// followUps checks later commits for a revert of the commit within the window, and for
+// changes to its lines: by a fix within the window, by any commit within the rework window.
+// Lines are matched on line numbers, so edits in between that shift them make this an
+// approximation.
+func followUps(commit Commit, later []Commit, hunks map[string][]DiffHunk, window, reworkWindow time.Duration) followUpOutcomes {
+	var outcomes followUpOutcomes
+	subject := firstLine(commit.Message)
+	changed := make(map[string][]DiffHunk)
+	for _, hunk := range hunks[commit.SHA] {
+		changed[hunk.File] = append(changed[hunk.File], hunk)
+	}
+
+	for _, next := range later {
+		elapsed := next.Date.Sub(commit.Date)
+		if elapsed > window {
+			break
+		}
+		if isRevertMessage(next.Message) {
+			if match := revertedSHAPattern.FindStringSubmatch(next.Message); match != nil && strings.HasPrefix(commit.SHA, match[1]) {
+				outcomes.reverted = true
+			} else if subject != "" && revertedSubject(next.Message) == subject {
+				outcomes.reverted = true
+			}
+			continue
+		}
+		if !changesLines(changed, hunks[next.SHA]) {
+			continue
+		}
+		if elapsed <= reworkWindow {
+			outcomes.reworked = true
+		}
+		if fixMessagePattern.MatchString(firstLine(next.Message)) {
+			outcomes.hotfixed = true
+		}
+	}
+	return outcomes
+}
+
+// changesLines reports whether the old side of a later commit's hunks overlaps lines a commit
+// left in its files. Lines a commit removed are represented by the two lines around them; a
+// later insertion only counts between two lines of the commit.
+func changesLines(changed map[string][]DiffHunk, later []DiffHunk) bool {
+	for _, next := range later {
+		for _, hunk := range changed[next.File] {
+			start, end := hunk.NewStart, hunk.NewStart+hunk.NewLines-1
+			if hunk.NewLines == 0 {
+				end = start + 1
+			}
+			if next.OldLines == 0 {
+				if start <= next.OldStart && next.OldStart+1 <= end {
+					return true
+				}
+				continue
+			}
+			if next.OldStart <= end && start <= next.OldStart+next.OldLines-1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// finishComparison fills the difference and significance of a comparison
+func finishComparison(comparison QualityComparison) QualityComparison {
+	comparison.Difference = comparison.AI - comparison.Human
+	comparison.Significant = comparison.PValue < significanceLevel &&
+		comparison.HumanSample >= minSignificanceSize && comparison.AISample >= minSignificanceSize
+	return comparison
+}
+
+// percentOf returns hits as a percent of total, 0 without a total
+func percentOf(hits, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total) * 100
+}
+
+// twoProportionPValue returns the two-sided p-value of a z-test for equal proportions.
+// Returns 1 when either group is empty or both proportions are 0 or 1.
+func twoProportionPValue(hits1, n1, hits2, n2 int) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	p1, p2 := float64(hits1)/float64(n1), float64(hits2)/float64(n2)
+	pooled := float64(hits1+hits2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 1
+	}
+	return normalTwoSidedPValue((p1 - p2) / se)
+}
+
+// mannWhitneyPValue returns the two-sided p-value of a Mann-Whitney U test, using the normal
+// approximation with a tie correction. Returns 1 when either sample is empty or all values tie.
+func mannWhitneyPValue(xs, ys []float64) float64 {
+	n1, n2 := float64(len(xs)), float64(len(ys))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	combined := append(append([]float64(nil), xs...), ys...)
+	ranked := ranks(combined)
+
+	rankSum := 0.0
+	for i := range xs {
+		rankSum += ranked[i]
+	}
+	u := rankSum - n1*(n1+1)/2
+
+	// Tie correction: sum of t^3 - t over groups of tied values
+	counts := make(map[float64]int)
+	for _, value := range combined {
+		counts[value]++
+	}
+	ties := 0.0
+	for _, count := range counts {
+		t := float64(count)
+		ties += t*t*t - t
+	}
+	n := n1 + n2
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+	if n < 2 || variance <= 0 {
+		return 1
+	}
+	return normalTwoSidedPValue((u - n1*n2/2) / math.Sqrt(variance))
+}
+
+// normalTwoSidedPValue returns P(|Z| >= |z|) for a standard normal Z
+func normalTwoSidedPValue(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTwoProportionPValue(t *testing.T) {
+	// 30% vs 15% of 100 commits each: z = 2.54
+	if p := twoProportionPValue(30, 100, 15, 100); math.Abs(p-0.01109) > 0.0001 {
+		t.Errorf("Expected p = 0.0111, got %.5f", p)
+	}
+	if p := twoProportionPValue(15, 100, 30, 100); math.Abs(p-0.01109) > 0.0001 {
+		t.Errorf("Expected a two-sided p-value regardless of order, got %.5f", p)
+	}
+	if p := twoProportionPValue(5, 50, 5, 50); p != 1 {
+		t.Errorf("Expected p = 1 for equal proportions, got %.5f", p)
+	}
+	for _, tt := range [][4]int{{0, 0, 3, 10}, {0, 10, 0, 10}, {10, 10, 5, 5}} {
+		if p := twoProportionPValue(tt[0], tt[1], tt[2], tt[3]); p != 1 {
+			t.Errorf("Expected p = 1 for %v, got %.5f", tt, p)
+		}
+	}
+}
+
+func TestMannWhitneyPValue(t *testing.T) {
+	// No overlap between 5 and 5 values: U = 0, z = -2.61
+	low, high := []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}
+	if p := mannWhitneyPValue(low, high); math.Abs(p-0.00902) > 0.0001 {
+		t.Errorf("Expected p = 0.0090, got %.5f", p)
+	}
+	if p := mannWhitneyPValue(high, low); math.Abs(p-0.00902) > 0.0001 {
+		t.Errorf("Expected a two-sided p-value regardless of order, got %.5f", p)
+	}
+
+	// Ties shrink the variance, so the same shift is more significant
+	tiedLow, tiedHigh := []float64{1, 1, 1, 2, 2}, []float64{3, 3, 4, 4, 4}
+	if p := mannWhitneyPValue(tiedLow, tiedHigh); p >= 0.00902 {
+		t.Errorf("Expected ties to lower the p-value below 0.0090, got %.5f", p)
+	}
+	if p := mannWhitneyPValue([]float64{1, 2, 3}, []float64{1, 2, 3}); math.Abs(p-1) > 1e-9 {
+		t.Errorf("Expected p = 1 for identical samples, got %.5f", p)
+	}
+	if p := mannWhitneyPValue([]float64{4, 4}, []float64{4, 4, 4}); p != 1 {
+		t.Errorf("Expected p = 1 when every value ties, got %.5f", p)
+	}
+	if p := mannWhitneyPValue(nil, high); p != 1 {
+		t.Errorf("Expected p = 1 for an empty sample, got %.5f", p)
+	}
+}
+
+func TestCompareAIQuality(t *testing.T) {
+	day := func(days float64) time.Time { return at(days * 24) }
+	commits := []Commit{
+		{SHA: "a1", Message: "Add parser", Date: day(0), Additions: 50, Files: []string{"parser.go", "parser_test.go"}},
+		{SHA: "a2", Message: "Add config", Date: day(1), Additions: 10, Files: []string{"config.go"}},
+		{SHA: "h1", Message: "Add lexer", Date: day(1), Additions: 20, Files: []string{"lexer.go"}},
+		{SHA: "h2", Message: "Add docs", Date: day(2), Additions: 5, Files: []string{"README.md"}},
+		{SHA: "x1", Message: "Tweak parser", Date: day(2), Files: []string{"parser.go"}},
+		{SHA: "x3", Message: "Reformat lexer", Date: day(3), Files: []string{"lexer.go"}},
+		{SHA: "r1", Message: "Revert \"Add docs\"", Date: day(4), Files: []string{"README.md"}},
+		{SHA: "x2", Message: "fix: lexer crash", Date: day(10), Files: []string{"lexer.go"}},
+		// Its window has not ended yet
+		{SHA: "n1", Message: "Add server", Date: day(25), Additions: 30, Files: []string{"server.go"}},
+	}
+	hunks := map[string][]DiffHunk{
+		"a1": {{File: "parser.go", NewStart: 1, NewLines: 40}, {File: "parser_test.go", NewStart: 1, NewLines: 10}},
+		// a2 has no known hunks
+		"h1": {{File: "lexer.go", NewStart: 1, NewLines: 20}},
+		"h2": {{File: "README.md", NewStart: 1, NewLines: 5}},
+		"x1": {{File: "parser.go", OldStart: 5, OldLines: 2, NewStart: 5, NewLines: 2}},
+		// Other lines of the lexer, then an insertion right after its last line
+		"x3": {{File: "lexer.go", OldStart: 30, OldLines: 2, NewStart: 30, NewLines: 2}, {File: "lexer.go", OldStart: 20, NewStart: 21, NewLines: 3}},
+		"r1": {{File: "README.md", OldStart: 1, OldLines: 5}},
+		// A fix of lexer lines after the rework window
+		"x2": {{File: "lexer.go", OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 1}},
+		"n1": {{File: "server.go", NewStart: 1, NewLines: 30}},
+	}
+	deltas := map[string]CodeDelta{"a1": {ComplexityDelta: 5}, "h1": {ComplexityDelta: 1}}
+	isAI := func(commit Commit) bool { return commit.SHA == "a1" || commit.SHA == "a2" }
+
+	impact, contributions := CompareAIQuality(commits, isAI, hunks, deltas, AIQualityConfig{}, day(30))
+
+	expectedContributions := HumanVsAIContributions{
+		HumanCommits: 6, AIAssistedCommits: 2, HumanLinesAdded: 55, AILinesAdded: 60,
+		AITestsWritten: 1, HumanBugsFixed: 1,
+	}
+	if contributions != expectedContributions {
+		t.Errorf("Contributions = %+v, expected %+v", contributions, expectedContributions)
+	}
+
+	if impact.WindowDays != 14 || impact.ReworkDays != 7 || len(impact.Comparisons) != 5 {
+		t.Fatalf("Unexpected impact %+v", impact)
+	}
+	revert, hotfix, rework := impact.Comparisons[0], impact.Comparisons[1], impact.Comparisons[2]
+	// h2 reverted, of the 5 human commits whose window ended; neither AI commit
+	if revert.Human != 20 || revert.AI != 0 || revert.HumanSample != 5 || revert.AISample != 2 {
+		t.Errorf("Unexpected revert rate %+v", revert)
+	}
+	// Only h1 is fixed; a2 has no hunks to match
+	if hotfix.Human != 20 || hotfix.AI != 0 || hotfix.AISample != 1 {
+		t.Errorf("Unexpected hotfix rate %+v", hotfix)
+	}
+	// Only a1 is reworked: x3 changes other lines and x2 comes after the rework window
+	if rework.Human != 0 || rework.AI != 100 || rework.Significant {
+		t.Errorf("Unexpected rework rate %+v", rework)
+	}
+	if impact.BugDensityReduction != 20 || impact.RefactoringFrequency != 100 || impact.CodeComplexityChange != 4 {
+		t.Errorf("Unexpected code quality impact %+v", impact)
+	}
+
+	if impact, contributions := CompareAIQuality(nil, isAI, nil, nil, AIQualityConfig{}, day(30)); contributions.HumanCommits != 0 || impact.Comparisons[0].PValue != 1 {
+		t.Errorf("Expected an empty comparison without commits, got %+v", impact)
+	}
+}
+
+func TestChangesLines(t *testing.T) {
+	changed := map[string][]DiffHunk{
+		"a.go": {{File: "a.go", NewStart: 10, NewLines: 3}},
+		"b.go": {{File: "b.go", OldStart: 4, OldLines: 2, NewStart: 3}}, // Lines removed after line 3
+	}
+	tests := []struct {
+		name     string
+		later    DiffHunk
+		expected bool
+	}{
+		{"same lines", DiffHunk{File: "a.go", OldStart: 12, OldLines: 4}, true},
+		{"lines before", DiffHunk{File: "a.go", OldStart: 5, OldLines: 5}, false},
+		{"insertion inside", DiffHunk{File: "a.go", OldStart: 10}, true},
+		{"insertion after the last line", DiffHunk{File: "a.go", OldStart: 12}, false},
+		{"other file", DiffHunk{File: "c.go", OldStart: 10, OldLines: 1}, false},
+		{"line around a removal", DiffHunk{File: "b.go", OldStart: 4, OldLines: 1}, true},
+		{"insertion where lines were removed", DiffHunk{File: "b.go", OldStart: 3}, true},
+	}
+	for _, tt := range tests {
+		if got := changesLines(changed, []DiffHunk{tt.later}); got != tt.expected {
+			t.Errorf("%s: changesLines = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
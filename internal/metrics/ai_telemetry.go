@@ -0,0 +1,350 @@
+// Package metrics - AI assistant telemetry ingestion and queries
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AI assistant telemetry event types
+const (
+	AIEventSuggestionShown    = "suggestion_shown"
+	AIEventSuggestionAccepted = "suggestion_accepted"
+	AIEventSession            = "session"
+)
+
+// AIAssistEvent is one line of AI assistant telemetry in JSONL form:
+//
+//	{"type":"suggestion_shown","user":"alice","repository":"owner/repo","provider":"copilot","timestamp":"2025-01-06T10:00:00Z"}
+//	{"type":"suggestion_accepted","user":"alice","repository":"owner/repo","provider":"copilot","timestamp":"2025-01-06T10:00:05Z","lines":4}
+//	{"type":"session","user":"alice","repository":"owner/repo","provider":"copilot","timestamp":"2025-01-06T09:30:00Z","duration_seconds":1800}
+//
+// Exporters that roll events up may set count on suggestion events; it defaults to 1.
+// Lines are the lines inserted by accepted suggestions, and duration_seconds the time
+// spent coding with the assistant active in a session starting at timestamp.
+type AIAssistEvent struct {
+	Type            string    `json:"type"`
+	User            string    `json:"user"`
+	Repository      string    `json:"repository"` // "owner/name"
+	Provider        string    `json:"provider"`   // copilot, codewhisperer, codeium, cursor, etc.
+	Timestamp       time.Time `json:"timestamp"`
+	Count           int       `json:"count,omitempty"`
+	Lines           int       `json:"lines,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+}
+
+// AITelemetryQuery selects telemetry events. Empty fields match everything; Until is exclusive.
+type AITelemetryQuery struct {
+	User       string    `json:"user,omitempty"`
+	Repository string    `json:"repository,omitempty"` // "owner/name", or a bare name matching any owner
+	Since      time.Time `json:"since,omitempty"`
+	Until      time.Time `json:"until,omitempty"`
+}
+
+// validate checks the required fields of an event and fills the default count
+func (e *AIAssistEvent) validate() error {
+	switch e.Type {
+	case AIEventSuggestionShown, AIEventSuggestionAccepted, AIEventSession:
+	default:
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+	if e.User == "" || e.Repository == "" || e.Timestamp.IsZero() {
+		return fmt.Errorf("event requires user, repository and timestamp")
+	}
+	if e.Count < 0 || e.Lines < 0 || e.DurationSeconds < 0 {
+		return fmt.Errorf("count, lines and duration_seconds must not be negative")
+	}
+	if e.Count == 0 && e.Type != AIEventSession {
+		e.Count = 1
+	}
+	return nil
+}
+
+// ParseAIAssistEvents reads AI assistant telemetry in JSONL form, one event per line.
+// Blank lines are skipped; the first invalid line fails the whole input.
+func ParseAIAssistEvents(r io.Reader) ([]AIAssistEvent, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var events []AIAssistEvent
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var event AIAssistEvent
+		if err := json.Unmarshal([]byte(text), &event); err != nil {
+			return nil, fmt.Errorf("line %d: failed to parse event: %w", line, err)
+		}
+		if err := event.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read telemetry: %w", err)
+	}
+	return events, nil
+}
+
+// LoadAITelemetryFiles imports JSONL telemetry files into a store. Paths may be glob
+// patterns such as "telemetry/*.jsonl". Returns the number of events imported.
+func LoadAITelemetryFiles(store *AITelemetryStore, paths ...string) (int, error) {
+	imported := 0
+	for _, pattern := range paths {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return imported, fmt.Errorf("invalid telemetry file pattern %s: %w", pattern, err)
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return imported, fmt.Errorf("failed to open telemetry file %s: %w", file, err)
+			}
+			events, err := ParseAIAssistEvents(f)
+			f.Close()
+			if err != nil {
+				return imported, fmt.Errorf("%s: %w", file, err)
+			}
+			if err := store.Add(events...); err != nil {
+				return imported, fmt.Errorf("%s: %w", file, err)
+			}
+			imported += len(events)
+		}
+	}
+	return imported, nil
+}
+
+// AITelemetryStore keeps AI assistant telemetry per repository in memory, and in a JSONL
+// log file when one is set
+type AITelemetryStore struct {
+	mu      sync.RWMutex
+	events  map[string][]AIAssistEvent
+	limit   int
+	logFile string
+}
+
+// NewAITelemetryStore creates a store keeping up to limit events per repository
+func NewAITelemetryStore(limit int) *AITelemetryStore {
+	if limit <= 0 {
+		limit = 100000
+	}
+	return &AITelemetryStore{
+		events: make(map[string][]AIAssistEvent),
+		limit:  limit,
+	}
+}
+
+// Add validates and stores events. Nothing is stored when any event is invalid.
+func (s *AITelemetryStore) Add(events ...AIAssistEvent) error {
+	for i := range events {
+		if err := events[i].validate(); err != nil {
+			return fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.logFile != "" {
+		if err := appendAIAssistEvents(s.logFile, events); err != nil {
+			return err
+		}
+	}
+	s.store(events)
+	return nil
+}
+
+// SetLogFile keeps telemetry across restarts in a JSONL file of the ingestion format. Events
+// already in the file are loaded, and the file is rewritten with only the events the store
+// keeps; events added later are appended to it.
+func (s *AITelemetryStore) SetLogFile(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to open telemetry log %s: %w", path, err)
+	}
+	if err == nil {
+		events, err := ParseAIAssistEvents(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		s.store(events)
+	}
+
+	var kept []AIAssistEvent
+	for _, stored := range s.events {
+		kept = append(kept, stored...)
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Timestamp.Before(kept[j].Timestamp)
+	})
+	if err := writeAIAssistEvents(path, kept); err != nil {
+		return err
+	}
+	s.logFile = path
+	return nil
+}
+
+// store keeps events, dropping the oldest of a repository beyond the limit. Callers hold s.mu.
+func (s *AITelemetryStore) store(events []AIAssistEvent) {
+	for _, event := range events {
+		key := strings.ToLower(event.Repository)
+		stored := append(s.events[key], event)
+		if len(stored) > s.limit {
+			stored = stored[len(stored)-s.limit:]
+		}
+		s.events[key] = stored
+	}
+}
+
+// appendAIAssistEvents appends events to a JSONL log file
+func appendAIAssistEvents(path string, events []AIAssistEvent) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry log %s: %w", path, err)
+	}
+	if err := encodeAIAssistEvents(f, events); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write telemetry log %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write telemetry log %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeAIAssistEvents replaces a JSONL log file with events
+func writeAIAssistEvents(path string, events []AIAssistEvent) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ai-telemetry-*")
+	if err != nil {
+		return fmt.Errorf("failed to write telemetry log %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := encodeAIAssistEvents(tmp, events); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write telemetry log %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write telemetry log %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write telemetry log %s: %w", path, err)
+	}
+	return nil
+}
+
+// encodeAIAssistEvents writes events as JSONL, one event per line
+func encodeAIAssistEvents(w io.Writer, events []AIAssistEvent) error {
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns the events matching a query, oldest first
+func (s *AITelemetryStore) Query(query AITelemetryQuery) []AIAssistEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []AIAssistEvent
+	for repository, stored := range s.events {
+		if !matchesRepository(repository, query.Repository) {
+			continue
+		}
+		for _, event := range stored {
+			if query.User != "" && !strings.EqualFold(event.User, query.User) {
+				continue
+			}
+			if event.Timestamp.Before(query.Since) || (!query.Until.IsZero() && !event.Timestamp.Before(query.Until)) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// GetAIAssistData implements IDEClient. An empty user covers every user of the repository.
+func (s *AITelemetryStore) GetAIAssistData(ctx context.Context, user, repo string, since time.Time) (*AIAssistData, error) {
+	data := SummarizeAIAssistEvents(s.Query(AITelemetryQuery{User: user, Repository: repo, Since: since}))
+	return &data, nil
+}
+
+// GetAIAssistDataByTool implements AIToolTelemetryClient, summarizing events per provider
+func (s *AITelemetryStore) GetAIAssistDataByTool(ctx context.Context, user, repo string, since time.Time) (map[string]*AIAssistData, error) {
+	byProvider := make(map[string][]AIAssistEvent)
+	for _, event := range s.Query(AITelemetryQuery{User: user, Repository: repo, Since: since}) {
+		byProvider[event.Provider] = append(byProvider[event.Provider], event)
+	}
+
+	data := make(map[string]*AIAssistData, len(byProvider))
+	for provider, events := range byProvider {
+		summary := SummarizeAIAssistEvents(events)
+		summary.Provider = provider
+		data[provider] = &summary
+	}
+	return data, nil
+}
+
+// SummarizeAIAssistEvents totals suggestions, accepted lines and session time of events.
+// The provider is the one with the most accepted suggestions.
+func SummarizeAIAssistEvents(events []AIAssistEvent) AIAssistData {
+	var data AIAssistData
+	seconds := 0.0
+	accepted := make(map[string]int)
+	for _, event := range events {
+		switch event.Type {
+		case AIEventSuggestionShown:
+			data.TotalSuggestions += event.Count
+		case AIEventSuggestionAccepted:
+			data.AcceptedSuggestions += event.Count
+			data.LinesGenerated += event.Lines
+			accepted[event.Provider] += event.Count
+		case AIEventSession:
+			seconds += event.DurationSeconds
+		}
+	}
+
+	// An accepted suggestion was shown even when the exporter only reports acceptances
+	data.TotalSuggestions = max(data.TotalSuggestions, data.AcceptedSuggestions)
+	if data.TotalSuggestions > 0 {
+		data.AcceptanceRate = float64(data.AcceptedSuggestions) / float64(data.TotalSuggestions)
+	}
+	data.TimeWithAI = seconds / 3600
+
+	for provider, count := range accepted {
+		if count > accepted[data.Provider] || (count == accepted[data.Provider] && provider < data.Provider) {
+			data.Provider = provider
+		}
+	}
+	return data
+}
+
+// matchesRepository reports whether a stored "owner/name" key matches a full or bare repository name
+func matchesRepository(key, repository string) bool {
+	if repository == "" {
+		return true
+	}
+	repository = strings.ToLower(repository)
+	return key == repository || (!strings.Contains(repository, "/") && strings.HasSuffix(key, "/"+repository))
+}
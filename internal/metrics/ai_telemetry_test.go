@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+const aiTelemetryJSONL = `{"type":"suggestion_shown","user":"alice","repository":"acme/api","provider":"copilot","timestamp":"2025-03-03T09:00:00Z","count":10}
+{"type":"suggestion_accepted","user":"alice","repository":"acme/api","provider":"copilot","timestamp":"2025-03-03T09:01:00Z","count":4,"lines":12}
+
+{"type":"suggestion_accepted","user":"bob","repository":"acme/api","provider":"cursor","timestamp":"2025-03-03T10:00:00Z","lines":3}
+{"type":"session","user":"bob","repository":"acme/api","provider":"cursor","timestamp":"2025-03-03T11:00:00Z","duration_seconds":5400}
+{"type":"session","user":"carol","repository":"other/api","provider":"copilot","timestamp":"2025-03-03T11:00:00Z","duration_seconds":3600}
+`
+
+func TestParseAIAssistEvents(t *testing.T) {
+	events, err := ParseAIAssistEvents(strings.NewReader(aiTelemetryJSONL))
+	if err != nil {
+		t.Fatalf("ParseAIAssistEvents failed: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events, blank line skipped, got %d", len(events))
+	}
+	if events[2].Count != 1 {
+		t.Errorf("Expected a suggestion without count to count once, got %d", events[2].Count)
+	}
+
+	invalid := []struct {
+		name  string
+		input string
+		line  string
+	}{
+		{"not JSON", "{\"type\":\"session\"", "line 1"},
+		{"unknown type", `{"type":"keystroke","user":"a","repository":"o/r","timestamp":"2025-03-03T09:00:00Z"}`, "unknown event type"},
+		{"missing user", `{"type":"session","repository":"o/r","timestamp":"2025-03-03T09:00:00Z"}`, "requires user"},
+		{"negative lines", `{"type":"suggestion_accepted","user":"a","repository":"o/r","timestamp":"2025-03-03T09:00:00Z","lines":-1}`, "must not be negative"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAIAssistEvents(strings.NewReader(tt.input)); err == nil || !strings.Contains(err.Error(), tt.line) {
+				t.Errorf("Expected an error containing %q, got %v", tt.line, err)
+			}
+		})
+	}
+}
+
+func TestAITelemetryStoreQuery(t *testing.T) {
+	events, err := ParseAIAssistEvents(strings.NewReader(aiTelemetryJSONL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewAITelemetryStore(0)
+	if err := store.Add(events...); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// Same-named repositories of other owners stay apart
+	data, _ := store.GetAIAssistData(context.Background(), "", "Acme/API", baseTime)
+	if data.TotalSuggestions != 10 || data.AcceptedSuggestions != 5 || data.LinesGenerated != 15 || data.TimeWithAI != 1.5 {
+		t.Errorf("Unexpected summary of acme/api for every user %+v", data)
+	}
+	if data.Provider != "copilot" {
+		t.Errorf("Expected copilot with the most accepted suggestions, got %q", data.Provider)
+	}
+
+	data, _ = store.GetAIAssistData(context.Background(), "alice", "acme/api", baseTime)
+	if data.AcceptanceRate != 0.4 || data.TimeWithAI != 0 {
+		t.Errorf("Unexpected summary of alice %+v", data)
+	}
+
+	// A bare name still matches every owner
+	if events := store.Query(AITelemetryQuery{Repository: "api"}); len(events) != 5 {
+		t.Errorf("Expected the 5 events of both api repositories, got %d", len(events))
+	}
+	if events := store.Query(AITelemetryQuery{Since: at(1), Until: at(2)}); len(events) != 1 || events[0].User != "bob" {
+		t.Errorf("Expected bob's accepted suggestion between 10:00 and 11:00, got %+v", events)
+	}
+
+	byTool, _ := store.GetAIAssistDataByTool(context.Background(), "", "acme/api", baseTime)
+	if len(byTool) != 2 || byTool["cursor"].TimeWithAI != 1.5 || byTool["copilot"].LinesGenerated != 12 {
+		t.Errorf("Unexpected summary by tool %+v", byTool)
+	}
+
+	if err := store.Add(AIAssistEvent{Type: AIEventSession}); err == nil {
+		t.Error("Expected an error for an invalid event")
+	}
+}
+
+func TestAITelemetryStoreLogFile(t *testing.T) {
+	events, err := ParseAIAssistEvents(strings.NewReader(aiTelemetryJSONL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+
+	store := NewAITelemetryStore(0)
+	if err := store.SetLogFile(path); err != nil {
+		t.Fatalf("SetLogFile failed: %v", err)
+	}
+	if err := store.Add(events[:3]...); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add(events[3:]...); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// A restarted store reads back every event
+	restarted := NewAITelemetryStore(0)
+	if err := restarted.SetLogFile(path); err != nil {
+		t.Fatalf("SetLogFile failed: %v", err)
+	}
+	if got := restarted.Query(AITelemetryQuery{}); len(got) != 5 {
+		t.Errorf("Expected 5 events after a restart, got %d", len(got))
+	}
+
+	// The log keeps only what a smaller store retains
+	limited := NewAITelemetryStore(1)
+	if err := limited.SetLogFile(path); err != nil {
+		t.Fatalf("SetLogFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Expected the log compacted to the last event of each repository, got %d lines", lines)
+	}
+
+	if err := os.WriteFile(path, []byte("not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAITelemetryStore(0).SetLogFile(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Expected an error naming the invalid log, got %v", err)
+	}
+}
+
+type fakeWakaTimeClient struct {
+	hours float64
+}
+
+func (f fakeWakaTimeClient) GetCodingTime(ctx context.Context, user, repo string, since time.Time) (*CodingTime, error) {
+	return &CodingTime{CodingHours: f.hours}, nil
+}
+
+type fakeGitClient struct{}
+
+func (fakeGitClient) GetCommits(ctx context.Context, owner, repo string, since time.Time) ([]Commit, error) {
+	return nil, nil
+}
+
+func TestAIMetricsCalculatorReadsTelemetryOfOwnerAndName(t *testing.T) {
+	now := time.Now()
+	store := NewAITelemetryStore(0)
+	if err := store.Add(
+		AIAssistEvent{Type: AIEventSession, User: "alice", Repository: "acme/api", Timestamp: now.Add(-time.Hour), DurationSeconds: 3 * 3600},
+		AIAssistEvent{Type: AIEventSession, User: "bob", Repository: "acme/api", Timestamp: now.Add(-time.Hour), DurationSeconds: 3600},
+		AIAssistEvent{Type: AIEventSession, User: "carol", Repository: "other/api", Timestamp: now.Add(-time.Hour), DurationSeconds: 4 * 3600},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	calculator := NewAIMetricsCalculator(fakeWakaTimeClient{hours: 10}, fakeGitClient{}, store)
+	aiMetrics, err := calculator.Calculate(context.Background(), types.Repository{Owner: "acme", Name: "api"}, "", 30)
+	if err != nil {
+		t.Fatalf("Calculate failed: %v", err)
+	}
+	// Every user of acme/api, none of other/api
+	if aiMetrics.AIHours != 4 || aiMetrics.HumanHours != 6 || aiMetrics.HIR != 0.6 {
+		t.Errorf("Expected 4 AI hours of 10, got %+v", aiMetrics)
+	}
+}
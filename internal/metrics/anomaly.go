@@ -0,0 +1,341 @@
+// Package metrics - Statistical anomaly detection over DORA and CHI time series
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Anomaly kinds
+const (
+	AnomalySpike      = "spike"       // Single point above the EWMA baseline
+	AnomalyDrop       = "drop"        // Single point below the EWMA baseline
+	AnomalyLevelShift = "level_shift" // Sustained change in the series mean
+)
+
+// Anomaly severities, matching webhook insight severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// AnomalyConfig tunes anomaly detection
+type AnomalyConfig struct {
+	EWMAAlpha            float64 `json:"ewma_alpha"`             // Smoothing factor of the baseline, 0-1
+	ZScoreThreshold      float64 `json:"z_score_threshold"`      // Deviations from the baseline flagged as spikes or drops
+	MinHistory           int     `json:"min_history"`            // Points needed before scoring against the baseline
+	ChangePointThreshold float64 `json:"change_point_threshold"` // Welch t statistic flagged as a level shift
+	MinSegment           int     `json:"min_segment"`            // Minimum points on each side of a change point
+}
+
+// DefaultAnomalyConfig returns detection settings suited to daily series
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		EWMAAlpha:            0.3,
+		ZScoreThreshold:      3.0,
+		MinHistory:           7,
+		ChangePointThreshold: 4.0,
+		MinSegment:           5,
+	}
+}
+
+// withDefaults fills unset settings from DefaultAnomalyConfig
+func (c AnomalyConfig) withDefaults() AnomalyConfig {
+	defaults := DefaultAnomalyConfig()
+	if c.EWMAAlpha <= 0 || c.EWMAAlpha > 1 {
+		c.EWMAAlpha = defaults.EWMAAlpha
+	}
+	if c.ZScoreThreshold <= 0 {
+		c.ZScoreThreshold = defaults.ZScoreThreshold
+	}
+	if c.MinHistory <= 0 {
+		c.MinHistory = defaults.MinHistory
+	}
+	if c.ChangePointThreshold <= 0 {
+		c.ChangePointThreshold = defaults.ChangePointThreshold
+	}
+	if c.MinSegment <= 0 {
+		c.MinSegment = defaults.MinSegment
+	}
+	return c
+}
+
+// Anomaly is an unexpected point or level shift in a metric series
+type Anomaly struct {
+	Source     string    `json:"source"` // "dora", "chi"
+	Metric     string    `json:"metric"`
+	Kind       string    `json:"kind"` // "spike", "drop", "level_shift"
+	Timestamp  time.Time `json:"timestamp"`
+	Value      float64   `json:"value"`    // Observed value, or the mean after a level shift
+	Expected   float64   `json:"expected"` // Baseline value, or the mean before a level shift
+	Score      float64   `json:"score"`    // z-score or change-point t statistic
+	Regression bool      `json:"regression"`
+	Severity   string    `json:"severity"` // "info", "warning", "critical"
+	Confidence float64   `json:"confidence"`
+}
+
+// SeriesPoint is a single observation of a metric series
+type SeriesPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// DetectDORAAnomalies looks for spikes, drops and level shifts in a DORA time series.
+// Days without merges or recoveries are skipped for lead time and recovery time.
+func DetectDORAAnomalies(points []DORATimeSeriesPoint, config AnomalyConfig) []Anomaly {
+	var leadTime, deployments, failures, failureRate, recovery []SeriesPoint
+	for _, point := range points {
+		if point.LeadTimeHours > 0 {
+			leadTime = append(leadTime, SeriesPoint{point.Timestamp, point.LeadTimeHours})
+		}
+		deployments = append(deployments, SeriesPoint{point.Timestamp, float64(point.DeploymentCount)})
+		failures = append(failures, SeriesPoint{point.Timestamp, float64(point.FailureCount)})
+		if point.DeploymentCount > 0 {
+			failureRate = append(failureRate, SeriesPoint{point.Timestamp, point.ChangeFailureRate})
+		}
+		if point.RecoveryTimeHours > 0 {
+			recovery = append(recovery, SeriesPoint{point.Timestamp, point.RecoveryTimeHours})
+		}
+	}
+
+	var anomalies []Anomaly
+	anomalies = append(anomalies, DetectSeriesAnomalies("dora", "lead_time_hours", leadTime, true, config)...)
+	anomalies = append(anomalies, DetectSeriesAnomalies("dora", "deployment_count", deployments, false, config)...)
+	anomalies = append(anomalies, DetectSeriesAnomalies("dora", "failure_count", failures, true, config)...)
+	anomalies = append(anomalies, DetectSeriesAnomalies("dora", "change_failure_rate", failureRate, true, config)...)
+	anomalies = append(anomalies, DetectSeriesAnomalies("dora", "recovery_time_hours", recovery, true, config)...)
+	sortAnomalies(anomalies)
+	return anomalies
+}
+
+// DetectCHIAnomalies looks for spikes, drops and level shifts in successive CHI measurements
+func DetectCHIAnomalies(history []types.CHIMetrics, config AnomalyConfig) []Anomaly {
+	sorted := append([]types.CHIMetrics(nil), history...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CalculatedAt.Before(sorted[j].CalculatedAt)
+	})
+
+	series := []struct {
+		metric        string
+		higherIsWorse bool
+		value         func(types.CHIMetrics) float64
+	}{
+		{"chi_score", false, func(m types.CHIMetrics) float64 { return float64(m.Score) }},
+		{"duplication_pct", true, func(m types.CHIMetrics) float64 { return m.DuplicationPercent }},
+		{"cyclomatic_avg", true, func(m types.CHIMetrics) float64 { return m.CyclomaticComplexity }},
+		{"test_coverage_pct", false, func(m types.CHIMetrics) float64 { return m.TestCoverage }},
+		{"maintainability_index", false, func(m types.CHIMetrics) float64 { return m.MaintainabilityIndex }},
+		{"technical_debt_hours", true, func(m types.CHIMetrics) float64 { return m.TechnicalDebt }},
+	}
+
+	var anomalies []Anomaly
+	for _, s := range series {
+		points := make([]SeriesPoint, 0, len(sorted))
+		for _, chi := range sorted {
+			points = append(points, SeriesPoint{chi.CalculatedAt, s.value(chi)})
+		}
+		anomalies = append(anomalies, DetectSeriesAnomalies("chi", s.metric, points, s.higherIsWorse, config)...)
+	}
+	sortAnomalies(anomalies)
+	return anomalies
+}
+
+// DetectSeriesAnomalies runs EWMA z-score and change-point detection over one series.
+// higherIsWorse tells whether an increase is a regression.
+func DetectSeriesAnomalies(source, metric string, points []SeriesPoint, higherIsWorse bool, config AnomalyConfig) []Anomaly {
+	config = config.withDefaults()
+
+	var anomalies []Anomaly
+	for _, anomaly := range ewmaAnomalies(points, config) {
+		anomalies = append(anomalies, finishAnomaly(source, metric, anomaly, higherIsWorse, config))
+	}
+	for _, anomaly := range changePoints(points, 0, len(points), config, 0) {
+		anomalies = append(anomalies, finishAnomaly(source, metric, anomaly, higherIsWorse, config))
+	}
+	return anomalies
+}
+
+// ewmaAnomalies scores each point against the exponentially weighted mean and variance
+// of the points before it
+func ewmaAnomalies(points []SeriesPoint, config AnomalyConfig) []Anomaly {
+	if len(points) <= config.MinHistory {
+		return nil
+	}
+
+	var anomalies []Anomaly
+	mean := points[0].Value
+	variance := 0.0
+	for i := 1; i < len(points); i++ {
+		value := points[i].Value
+		if i >= config.MinHistory {
+			z := (value - mean) / stdFloor(math.Sqrt(variance), mean)
+			if math.Abs(z) >= config.ZScoreThreshold {
+				kind := AnomalySpike
+				if z < 0 {
+					kind = AnomalyDrop
+				}
+				historyFactor := math.Min(1, float64(i)/float64(2*config.MinHistory))
+				anomalies = append(anomalies, Anomaly{
+					Kind:       kind,
+					Timestamp:  points[i].Timestamp,
+					Value:      value,
+					Expected:   mean,
+					Score:      z,
+					Confidence: math.Min(0.99, math.Erf(math.Abs(z)/math.Sqrt2)) * historyFactor,
+				})
+			}
+		}
+
+		diff := value - mean
+		mean += config.EWMAAlpha * diff
+		variance = (1 - config.EWMAAlpha) * (variance + config.EWMAAlpha*diff*diff)
+	}
+	return anomalies
+}
+
+// changePoints finds level shifts by binary segmentation: the split of points[lo:hi]
+// with the largest Welch t statistic is kept when it passes the threshold, then each
+// side is searched again
+func changePoints(points []SeriesPoint, lo, hi int, config AnomalyConfig, depth int) []Anomaly {
+	if depth >= 3 || hi-lo < 2*config.MinSegment {
+		return nil
+	}
+
+	bestT := 0.0
+	bestSplit := -1
+	var bestBefore, bestAfter float64
+	for split := lo + config.MinSegment; split <= hi-config.MinSegment; split++ {
+		before, beforeVar := meanVariance(points[lo:split])
+		after, afterVar := meanVariance(points[split:hi])
+		stderr := math.Sqrt(beforeVar/float64(split-lo) + afterVar/float64(hi-split))
+		t := math.Abs(after-before) / stdFloor(stderr, before)
+		if t > bestT {
+			bestT, bestSplit, bestBefore, bestAfter = t, split, before, after
+		}
+	}
+
+	if bestSplit < 0 || bestT < config.ChangePointThreshold {
+		return nil
+	}
+
+	anomalies := []Anomaly{{
+		Kind:       AnomalyLevelShift,
+		Timestamp:  points[bestSplit].Timestamp,
+		Value:      bestAfter,
+		Expected:   bestBefore,
+		Score:      bestT,
+		Confidence: math.Min(0.99, 0.5+0.5*(1-math.Exp(-(bestT-config.ChangePointThreshold)))),
+	}}
+	anomalies = append(anomalies, changePoints(points, lo, bestSplit, config, depth+1)...)
+	anomalies = append(anomalies, changePoints(points, bestSplit, hi, config, depth+1)...)
+	return anomalies
+}
+
+// finishAnomaly sets source, metric, direction and severity. Improvements are informational;
+// regressions are critical beyond twice the threshold or a 50% level shift.
+func finishAnomaly(source, metric string, anomaly Anomaly, higherIsWorse bool, config AnomalyConfig) Anomaly {
+	anomaly.Source = source
+	anomaly.Metric = metric
+	anomaly.Regression = (anomaly.Value > anomaly.Expected) == higherIsWorse
+	anomaly.Confidence = math.Round(anomaly.Confidence*100) / 100
+
+	switch {
+	case !anomaly.Regression:
+		anomaly.Severity = SeverityInfo
+	case anomaly.Kind == AnomalyLevelShift:
+		anomaly.Severity = SeverityWarning
+		if math.Abs(anomaly.Value-anomaly.Expected) >= 0.5*math.Abs(anomaly.Expected) {
+			anomaly.Severity = SeverityCritical
+		}
+	case math.Abs(anomaly.Score) >= 2*config.ZScoreThreshold:
+		anomaly.Severity = SeverityCritical
+	default:
+		anomaly.Severity = SeverityWarning
+	}
+	return anomaly
+}
+
+// meanVariance returns the mean and sample variance of a series
+func meanVariance(points []SeriesPoint) (float64, float64) {
+	if len(points) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, point := range points {
+		sum += point.Value
+	}
+	mean := sum / float64(len(points))
+	if len(points) < 2 {
+		return mean, 0
+	}
+	squares := 0.0
+	for _, point := range points {
+		squares += (point.Value - mean) * (point.Value - mean)
+	}
+	return mean, squares / float64(len(points)-1)
+}
+
+// stdFloor keeps a deviation away from zero so flat series do not flag every small change.
+// The floor is 10% of the baseline, or 0.1 for baselines near zero.
+func stdFloor(std, baseline float64) float64 {
+	return math.Max(std, math.Max(0.1*math.Abs(baseline), 0.1))
+}
+
+// sortAnomalies orders anomalies by time, then metric
+func sortAnomalies(anomalies []Anomaly) {
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		if !anomalies[i].Timestamp.Equal(anomalies[j].Timestamp) {
+			return anomalies[i].Timestamp.Before(anomalies[j].Timestamp)
+		}
+		return anomalies[i].Metric < anomalies[j].Metric
+	})
+}
+
+// CHIHistoryStore keeps recent CHI measurements per repository in memory
+type CHIHistoryStore struct {
+	mu      sync.RWMutex
+	history map[string][]types.CHIMetrics
+	limit   int
+}
+
+// NewCHIHistoryStore creates a store keeping up to limit measurements per repository
+func NewCHIHistoryStore(limit int) *CHIHistoryStore {
+	if limit <= 0 {
+		limit = 365
+	}
+	return &CHIHistoryStore{
+		history: make(map[string][]types.CHIMetrics),
+		limit:   limit,
+	}
+}
+
+// Record appends a CHI measurement for a repository ("owner/name")
+func (s *CHIHistoryStore) Record(repository string, chi types.CHIMetrics) {
+	if chi.CalculatedAt.IsZero() {
+		chi.CalculatedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(repository)
+	history := append(s.history[key], chi)
+	if len(history) > s.limit {
+		history = history[len(history)-s.limit:]
+	}
+	s.history[key] = history
+}
+
+// History returns the CHI measurements of a repository, oldest first
+func (s *CHIHistoryStore) History(repository string) []types.CHIMetrics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]types.CHIMetrics(nil), s.history[strings.ToLower(repository)]...)
+}
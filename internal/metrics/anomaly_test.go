@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+func TestDetectDORAAnomalies_LeadTimeRegression(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var points []DORATimeSeriesPoint
+	for i := 0; i < 30; i++ {
+		leadTime := 10.0 + float64(i%3)
+		if i == 20 {
+			leadTime = 80
+		}
+		points = append(points, DORATimeSeriesPoint{
+			Timestamp:       start.AddDate(0, 0, i),
+			LeadTimeHours:   leadTime,
+			DeploymentCount: 3,
+		})
+	}
+
+	anomalies := DetectDORAAnomalies(points, DefaultAnomalyConfig())
+
+	var found *Anomaly
+	for i := range anomalies {
+		if anomalies[i].Metric == "lead_time_hours" && anomalies[i].Kind == AnomalySpike {
+			found = &anomalies[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("expected a lead time spike, got %+v", anomalies)
+	}
+	if !found.Timestamp.Equal(start.AddDate(0, 0, 20)) {
+		t.Errorf("expected spike on day 20, got %s", found.Timestamp)
+	}
+	if !found.Regression || found.Severity != SeverityCritical {
+		t.Errorf("expected critical regression, got regression=%v severity=%s", found.Regression, found.Severity)
+	}
+	if found.Confidence <= 0.5 || found.Confidence > 1 {
+		t.Errorf("unexpected confidence %.2f", found.Confidence)
+	}
+}
+
+func TestDetectCHIAnomalies_CoverageLevelShift(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var history []types.CHIMetrics
+	for i := 0; i < 20; i++ {
+		coverage := 80.0 + float64(i%2)
+		if i >= 10 {
+			coverage = 35.0 + float64(i%2)
+		}
+		history = append(history, types.CHIMetrics{
+			Score:        75,
+			TestCoverage: coverage,
+			CalculatedAt: start.AddDate(0, 0, i),
+		})
+	}
+
+	anomalies := DetectCHIAnomalies(history, DefaultAnomalyConfig())
+
+	for _, anomaly := range anomalies {
+		if anomaly.Metric == "test_coverage_pct" && anomaly.Kind == AnomalyLevelShift {
+			if !anomaly.Timestamp.Equal(start.AddDate(0, 0, 10)) {
+				t.Errorf("expected shift on day 10, got %s", anomaly.Timestamp)
+			}
+			if anomaly.Severity != SeverityCritical {
+				t.Errorf("expected critical severity, got %s", anomaly.Severity)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a test coverage level shift, got %+v", anomalies)
+}
@@ -0,0 +1,226 @@
+// Package metrics - DORA performance band classification against benchmark tables
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// bandOrder lists performance bands from best to worst
+var bandOrder = []string{types.BandElite, types.BandHigh, types.BandMedium, types.BandLow}
+
+// BandThresholds holds the boundary values of the elite, high and medium bands for one metric.
+// Values past the medium boundary are low.
+type BandThresholds struct {
+	Elite  float64 `json:"elite" yaml:"elite"`
+	High   float64 `json:"high" yaml:"high"`
+	Medium float64 `json:"medium" yaml:"medium"`
+}
+
+// DORABenchmarks is a benchmark table for classifying DORA metrics into performance bands.
+// Lead time, change failure rate and MTTR are upper bounds; deployment frequency is a lower bound.
+type DORABenchmarks struct {
+	Name                    string         `json:"name" yaml:"name"`
+	LeadTimeHours           BandThresholds `json:"lead_time_hours" yaml:"lead_time_hours"`                     // Commit to production, P50
+	DeploymentFrequencyWeek BandThresholds `json:"deployment_frequency_week" yaml:"deployment_frequency_week"` // Production deployments per week
+	ChangeFailRatePercent   BandThresholds `json:"change_fail_rate_pct" yaml:"change_fail_rate_pct"`
+	MTTRHours               BandThresholds `json:"mttr_hours" yaml:"mttr_hours"`
+}
+
+// DefaultDORABenchmarks returns the State of DevOps performance bands: lead time under a day,
+// a week and a month; deployments daily or more, weekly and monthly; change failure rate
+// of 5%, 10% and 15%; and recovery within an hour, a day and a week.
+func DefaultDORABenchmarks() DORABenchmarks {
+	return DORABenchmarks{
+		Name:                    "state-of-devops",
+		LeadTimeHours:           BandThresholds{Elite: 24, High: 24 * 7, Medium: 24 * 30},
+		DeploymentFrequencyWeek: BandThresholds{Elite: 7, High: 1, Medium: 12.0 / 52},
+		ChangeFailRatePercent:   BandThresholds{Elite: 5, High: 10, Medium: 15},
+		MTTRHours:               BandThresholds{Elite: 1, High: 24, Medium: 24 * 7},
+	}
+}
+
+// LoadDORABenchmarks reads a benchmark table from a YAML (or JSON) file.
+// Thresholds left out of the file keep their State of DevOps defaults.
+func LoadDORABenchmarks(path string) (DORABenchmarks, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DORABenchmarks{}, fmt.Errorf("failed to read benchmarks file %s: %w", path, err)
+	}
+
+	var benchmarks DORABenchmarks
+	if err := yaml.Unmarshal(data, &benchmarks); err != nil {
+		return DORABenchmarks{}, fmt.Errorf("failed to parse benchmarks file: %w", err)
+	}
+
+	benchmarks = benchmarks.WithDefaults()
+	if err := benchmarks.Validate(); err != nil {
+		return DORABenchmarks{}, err
+	}
+	return benchmarks, nil
+}
+
+// WithDefaults fills unset thresholds from the State of DevOps defaults
+func (b DORABenchmarks) WithDefaults() DORABenchmarks {
+	defaults := DefaultDORABenchmarks()
+	if b == (DORABenchmarks{}) {
+		return defaults
+	}
+	if b.Name == "" {
+		b.Name = "custom"
+	}
+	b.LeadTimeHours = b.LeadTimeHours.withDefaults(defaults.LeadTimeHours)
+	b.DeploymentFrequencyWeek = b.DeploymentFrequencyWeek.withDefaults(defaults.DeploymentFrequencyWeek)
+	b.ChangeFailRatePercent = b.ChangeFailRatePercent.withDefaults(defaults.ChangeFailRatePercent)
+	b.MTTRHours = b.MTTRHours.withDefaults(defaults.MTTRHours)
+	return b
+}
+
+// Validate checks that every metric's thresholds are ordered from elite to medium
+func (b DORABenchmarks) Validate() error {
+	checks := []struct {
+		name           string
+		thresholds     BandThresholds
+		higherIsBetter bool
+	}{
+		{"lead_time_hours", b.LeadTimeHours, false},
+		{"deployment_frequency_week", b.DeploymentFrequencyWeek, true},
+		{"change_fail_rate_pct", b.ChangeFailRatePercent, false},
+		{"mttr_hours", b.MTTRHours, false},
+	}
+
+	for _, check := range checks {
+		t := check.thresholds
+		ordered := t.Elite <= t.High && t.High <= t.Medium
+		if check.higherIsBetter {
+			ordered = t.Elite >= t.High && t.High >= t.Medium
+		}
+		if !ordered {
+			return fmt.Errorf("invalid %s benchmark: elite %.2f, high %.2f and medium %.2f are out of order",
+				check.name, t.Elite, t.High, t.Medium)
+		}
+	}
+	return nil
+}
+
+// Classify places each DORA metric in a performance band and derives the overall profile.
+// Lead time and MTTR of zero mean no data and are left out of the overall profile.
+func (b DORABenchmarks) Classify(dora types.DORAMetrics) types.DORAPerformance {
+	positions := []types.DORABandPosition{
+		classifyMetric("lead_time", "hours", dora.LeadTimeP50Hours, b.LeadTimeHours, false, dora.LeadTimeP50Hours > 0),
+		classifyMetric("deployment_frequency", "deployments/week", dora.DeploymentFrequencyWeek, b.DeploymentFrequencyWeek, true, true),
+		classifyMetric("change_fail_rate", "percent", dora.ChangeFailRatePercent, b.ChangeFailRatePercent, false, true),
+		classifyMetric("mttr", "hours", dora.MTTRHours, b.MTTRHours, false, dora.MTTRHours > 0),
+	}
+
+	overall := overallBand(positions)
+	return types.DORAPerformance{
+		Overall:   overall,
+		NextBand:  nextBand(overall),
+		Benchmark: b.Name,
+		Metrics:   positions,
+	}
+}
+
+// ClassifyDeliveryMaturity returns the overall DORA band of aggregated repository metrics,
+// for OrganizationalHealth.DeliveryMaturity. Lead time is the median of repository P50s; without
+// it lead time is left unmeasured, since the P95 would rank against the P50 thresholds.
+func (b DORABenchmarks) ClassifyDeliveryMaturity(dora AggregatedDORAMetrics) string {
+	return b.Classify(types.DORAMetrics{
+		LeadTimeP50Hours:        dora.Percentiles.LeadTimeP50,
+		DeploymentFrequencyWeek: dora.MeanDeploymentFrequencyWeek,
+		ChangeFailRatePercent:   dora.MeanChangeFailRatePercent,
+		MTTRHours:               dora.MeanMTTRHours,
+	}).Overall
+}
+
+// withDefaults replaces an entirely unset threshold set with the defaults
+func (t BandThresholds) withDefaults(defaults BandThresholds) BandThresholds {
+	if t == (BandThresholds{}) {
+		return defaults
+	}
+	return t
+}
+
+// bound returns the boundary of a band; low has none
+func (t BandThresholds) bound(band string) float64 {
+	switch band {
+	case types.BandElite:
+		return t.Elite
+	case types.BandHigh:
+		return t.High
+	default:
+		return t.Medium
+	}
+}
+
+// classifyMetric finds the best band whose boundary the value meets and the gap to the band above it
+func classifyMetric(metric, unit string, value float64, thresholds BandThresholds, higherIsBetter, measured bool) types.DORABandPosition {
+	position := types.DORABandPosition{
+		Metric: metric,
+		Value:  value,
+		Unit:   unit,
+	}
+	if !measured {
+		return position
+	}
+
+	position.Band = types.BandLow
+	for _, band := range bandOrder[:len(bandOrder)-1] {
+		bound := thresholds.bound(band)
+		if (higherIsBetter && value >= bound) || (!higherIsBetter && value <= bound) {
+			position.Band = band
+			break
+		}
+	}
+
+	if next := nextBand(position.Band); next != "" {
+		position.NextBand = next
+		position.NextBandThreshold = thresholds.bound(next)
+		position.DistanceToNextBand = math.Abs(value - position.NextBandThreshold)
+	}
+	return position
+}
+
+// overallBand averages the band ranks of measured metrics, rounding halves towards the worse band
+func overallBand(positions []types.DORABandPosition) string {
+	total := 0
+	measured := 0
+	for _, position := range positions {
+		if position.Band == "" {
+			continue
+		}
+		total += bandRank(position.Band)
+		measured++
+	}
+	if measured == 0 {
+		return ""
+	}
+	return bandOrder[int(math.Floor(float64(total)/float64(measured)+0.5))]
+}
+
+// bandRank returns the position of a band in bandOrder, 0 being elite
+func bandRank(band string) int {
+	for i, b := range bandOrder {
+		if b == band {
+			return i
+		}
+	}
+	return len(bandOrder) - 1
+}
+
+// nextBand returns the band above the given one, or "" for elite and unknown bands
+func nextBand(band string) string {
+	if band == "" {
+		return ""
+	}
+	rank := bandRank(band)
+	if rank == 0 {
+		return ""
+	}
+	return bandOrder[rank-1]
+}
@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+func TestDORABenchmarksClassify(t *testing.T) {
+	performance := DefaultDORABenchmarks().Classify(types.DORAMetrics{
+		LeadTimeP50Hours:        10,
+		DeploymentFrequencyWeek: 3,
+		ChangeFailRatePercent:   12,
+		MTTRHours:               200,
+	})
+
+	expected := map[string]struct {
+		band     string
+		next     string
+		distance float64
+	}{
+		"lead_time":            {types.BandElite, "", 0},
+		"deployment_frequency": {types.BandHigh, types.BandElite, 4},
+		"change_fail_rate":     {types.BandMedium, types.BandHigh, 2},
+		"mttr":                 {types.BandLow, types.BandMedium, 32},
+	}
+	if len(performance.Metrics) != len(expected) {
+		t.Fatalf("Expected %d metric positions, got %+v", len(expected), performance.Metrics)
+	}
+	for _, position := range performance.Metrics {
+		want := expected[position.Metric]
+		if position.Band != want.band || position.NextBand != want.next || math.Abs(position.DistanceToNextBand-want.distance) > 1e-9 {
+			t.Errorf("%s: got band %q, next %q at distance %.2f, expected %q, %q at %.2f",
+				position.Metric, position.Band, position.NextBand, position.DistanceToNextBand, want.band, want.next, want.distance)
+		}
+	}
+
+	// Ranks 0, 1, 2 and 3 average 1.5, which rounds towards the worse band
+	if performance.Overall != types.BandMedium || performance.NextBand != types.BandHigh {
+		t.Errorf("Expected overall medium with high next, got %q and %q", performance.Overall, performance.NextBand)
+	}
+	if performance.Benchmark != "state-of-devops" {
+		t.Errorf("Expected the default benchmark name, got %q", performance.Benchmark)
+	}
+}
+
+func TestDORABenchmarksClassifyUnmeasured(t *testing.T) {
+	performance := DefaultDORABenchmarks().Classify(types.DORAMetrics{
+		DeploymentFrequencyWeek: 10,
+		ChangeFailRatePercent:   3,
+	})
+
+	for _, position := range performance.Metrics {
+		measured := position.Metric == "deployment_frequency" || position.Metric == "change_fail_rate"
+		if measured != (position.Band != "") {
+			t.Errorf("%s: unexpected band %q", position.Metric, position.Band)
+		}
+	}
+	if performance.Overall != types.BandElite {
+		t.Errorf("Expected lead time and MTTR without data to be left out of the overall band, got %q", performance.Overall)
+	}
+}
+
+func TestOverallBand(t *testing.T) {
+	tests := []struct {
+		name     string
+		bands    []string
+		expected string
+	}{
+		{"none measured", []string{"", ""}, ""},
+		{"single band", []string{types.BandHigh}, types.BandHigh},
+		{"half rounds to worse", []string{types.BandElite, types.BandHigh}, types.BandHigh},
+		{"elite and low", []string{types.BandElite, types.BandLow}, types.BandMedium},
+		{"below half rounds to better", []string{types.BandElite, types.BandElite, types.BandHigh}, types.BandElite},
+		{"unmeasured ignored", []string{types.BandLow, ""}, types.BandLow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions := make([]types.DORABandPosition, 0, len(tt.bands))
+			for _, band := range tt.bands {
+				positions = append(positions, types.DORABandPosition{Band: band})
+			}
+			if got := overallBand(positions); got != tt.expected {
+				t.Errorf("overallBand(%v) = %q, expected %q", tt.bands, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDORABenchmarksValidate(t *testing.T) {
+	if err := DefaultDORABenchmarks().Validate(); err != nil {
+		t.Fatalf("Expected the defaults to be valid, got %v", err)
+	}
+
+	leadTime := DefaultDORABenchmarks()
+	leadTime.LeadTimeHours = BandThresholds{Elite: 48, High: 24, Medium: 720}
+	if err := leadTime.Validate(); err == nil {
+		t.Error("Expected an error for lead time thresholds out of order")
+	}
+
+	// Deployment frequency is a lower bound, so its thresholds descend
+	frequency := DefaultDORABenchmarks()
+	frequency.DeploymentFrequencyWeek = BandThresholds{Elite: 1, High: 7, Medium: 12}
+	if err := frequency.Validate(); err == nil {
+		t.Error("Expected an error for ascending deployment frequency thresholds")
+	}
+}
+
+func TestLoadDORABenchmarks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "benchmarks.yml")
+	content := "name: platform\nmttr_hours: {elite: 0.5, high: 4, medium: 48}\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	benchmarks, err := LoadDORABenchmarks(path)
+	if err != nil {
+		t.Fatalf("LoadDORABenchmarks failed: %v", err)
+	}
+	if benchmarks.Name != "platform" || benchmarks.MTTRHours != (BandThresholds{Elite: 0.5, High: 4, Medium: 48}) {
+		t.Errorf("Expected the configured name and MTTR thresholds, got %+v", benchmarks)
+	}
+	if benchmarks.LeadTimeHours != DefaultDORABenchmarks().LeadTimeHours {
+		t.Errorf("Expected lead time thresholds left out to keep their defaults, got %+v", benchmarks.LeadTimeHours)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yml")
+	if err := os.WriteFile(invalid, []byte("change_fail_rate_pct: {elite: 20, high: 10, medium: 5}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDORABenchmarks(invalid); err == nil {
+		t.Error("Expected an error for thresholds out of order")
+	}
+	if _, err := LoadDORABenchmarks(filepath.Join(dir, "missing.yml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestClassifyDeliveryMaturityWithoutLeadTimeP50(t *testing.T) {
+	maturity := DefaultDORABenchmarks().ClassifyDeliveryMaturity(AggregatedDORAMetrics{
+		MeanLeadTimeP95Hours:        2000,
+		MeanDeploymentFrequencyWeek: 10,
+		MeanChangeFailRatePercent:   2,
+		MeanMTTRHours:               0.5,
+	})
+	if maturity != types.BandElite {
+		t.Errorf("Expected lead time without a P50 to stay unmeasured rather than ranking the P95, got %q", maturity)
+	}
+}
+
+func TestAggregateDORAMetrics(t *testing.T) {
+	repository := func(leadP50, leadP95, frequency, failRate, mttr float64, deployments, incidents int, band string) *EnhancedDORAMetrics {
+		return &EnhancedDORAMetrics{
+			DORAMetrics: types.DORAMetrics{
+				LeadTimeP50Hours:        leadP50,
+				LeadTimeP95Hours:        leadP95,
+				DeploymentFrequencyWeek: frequency,
+				ChangeFailRatePercent:   failRate,
+				MTTRHours:               mttr,
+			},
+			TotalDeployments: deployments,
+			IncidentCount:    incidents,
+			Performance:      types.DORAPerformance{Overall: band},
+		}
+	}
+
+	aggregated := AggregateDORAMetrics(map[string]*EnhancedDORAMetrics{
+		"acme/api":    repository(10, 30, 8, 2, 0.5, 30, 1, types.BandElite),
+		"acme/legacy": repository(0, 0, 1, 20, 0, 4, 2, types.BandLow),
+		"acme/web":    repository(100, 300, 2, 10, 10, 8, 0, types.BandHigh),
+		"acme/failed": nil,
+	})
+
+	if aggregated.TotalDeployments != 42 || aggregated.TotalIncidents != 3 {
+		t.Errorf("Expected 42 deployments and 3 incidents, got %d and %d", aggregated.TotalDeployments, aggregated.TotalIncidents)
+	}
+	// Repositories without lead time or MTTR data are left out of those means
+	if aggregated.MeanLeadTimeP95Hours != 165 || aggregated.MeanMTTRHours != 5.25 {
+		t.Errorf("Expected mean lead time P95 165h and MTTR 5.25h, got %.2f and %.2f", aggregated.MeanLeadTimeP95Hours, aggregated.MeanMTTRHours)
+	}
+	if math.Abs(aggregated.MeanDeploymentFrequencyWeek-11.0/3) > 1e-9 || aggregated.MeanChangeFailRatePercent != 32.0/3 {
+		t.Errorf("Unexpected frequency and failure rate means: %.2f and %.2f", aggregated.MeanDeploymentFrequencyWeek, aggregated.MeanChangeFailRatePercent)
+	}
+	if aggregated.Percentiles.LeadTimeP50 != 10 || aggregated.Percentiles.LeadTimeP95 != 100 {
+		t.Errorf("Expected lead time percentiles over measured repositories, got %+v", aggregated.Percentiles)
+	}
+	if aggregated.BestPerformingRepo != "acme/api" || aggregated.WorstPerformingRepo != "acme/legacy" {
+		t.Errorf("Expected acme/api best and acme/legacy worst, got %q and %q", aggregated.BestPerformingRepo, aggregated.WorstPerformingRepo)
+	}
+
+	if empty := AggregateDORAMetrics(nil); empty != (AggregatedDORAMetrics{}) {
+		t.Errorf("Expected no aggregate without repositories, got %+v", empty)
+	}
+}
@@ -89,11 +89,17 @@ func (mc *MetricsCache) GenerateCacheKey(metricType string, request MetricsReque
 		Repository  string        `json:"repository"`
 		TimeRange   TimeRange     `json:"time_range"`
 		Granularity string        `json:"granularity"`
+		Production  []string      `json:"production,omitempty"`
+		WorkingHours bool         `json:"working_hours,omitempty"`
+		Team        string        `json:"team,omitempty"`
 	}{
 		Type:        metricType,
 		Repository:  fmt.Sprintf("%s/%s", request.Repository.Owner, request.Repository.Name),
 		TimeRange:   request.TimeRange,
 		Granularity: request.Granularity,
+		Production:  request.ProductionEnvironments,
+		WorkingHours: request.WorkingHours,
+		Team:        request.Team,
 	}
 
 	keyJSON, _ := json.Marshal(keyData)
@@ -0,0 +1,310 @@
+// Package metrics - Change failure attribution via reverts, hotfixes, rollbacks and incidents
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change failure causes
+const (
+	ChangeFailureRevert   = "revert"
+	ChangeFailureHotfix   = "hotfix"
+	ChangeFailureRollback = "rollback"
+	ChangeFailureIncident = "incident"
+)
+
+var revertedSHAPattern = regexp.MustCompile(`(?i)this reverts commit ([0-9a-f]{7,40})`)
+
+// ChangeFailureRules configures how follow-up changes are recognized as failure signals
+type ChangeFailureRules struct {
+	HotfixLabels         []string      `json:"hotfix_labels"`
+	HotfixBranchPrefixes []string      `json:"hotfix_branch_prefixes"`
+	AttributionWindow    time.Duration `json:"attribution_window"` // Max time between a deployment and an unlinked hotfix or revert
+}
+
+// DefaultChangeFailureRules returns the default hotfix conventions and a 7 day attribution window
+func DefaultChangeFailureRules() ChangeFailureRules {
+	return ChangeFailureRules{
+		HotfixLabels:         []string{"hotfix"},
+		HotfixBranchPrefixes: []string{"hotfix/", "hotfix-"},
+		AttributionWindow:    7 * 24 * time.Hour,
+	}
+}
+
+// ChangeFailure links a deployment to the failure signal that followed it
+type ChangeFailure struct {
+	DeploymentID  int       `json:"deployment_id,omitempty"`
+	DeploymentSHA string    `json:"deployment_sha"`
+	Environment   string    `json:"environment,omitempty"`
+	DeployedAt    time.Time `json:"deployed_at"`
+	Cause         string    `json:"cause"`     // "revert", "hotfix", "rollback", "incident"
+	Reference     string    `json:"reference"` // Revert commit, hotfix PR, rollback deployment or incident ID
+	Description   string    `json:"description"`
+	DetectedAt    time.Time `json:"detected_at"`
+}
+
+// attributeChangeFailures links each failure signal to the deployment that caused it.
+// Reverts and incidents that name a commit or deployment are matched directly;
+// otherwise signals are attributed to the latest successful deployment before them.
+func attributeChangeFailures(deployments []Deployment, prs []PullRequest, commits []Commit, incidents []Incident, rules ChangeFailureRules) []ChangeFailure {
+	successful := successfulDeployments(deployments)
+	if len(successful) == 0 {
+		return nil
+	}
+
+	var failures []ChangeFailure
+	seen := make(map[string]bool)
+	add := func(index int, cause, reference, description string, detectedAt time.Time) {
+		if index < 0 {
+			return
+		}
+		deployment := successful[index]
+		key := deploymentKey(deployment) + "|" + cause + "|" + reference
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		failures = append(failures, ChangeFailure{
+			DeploymentID:  deployment.ID,
+			DeploymentSHA: deployment.SHA,
+			Environment:   deployment.Environment,
+			DeployedAt:    deployment.CreatedAt,
+			Cause:         cause,
+			Reference:     reference,
+			Description:   description,
+			DetectedAt:    detectedAt,
+		})
+	}
+
+	// Revert commits, linked through the reverted SHA or subject when possible
+	for _, commit := range commits {
+		if !isRevertMessage(commit.Message) {
+			continue
+		}
+		index := findRevertedDeployment(successful, commits, prs, commit.Message, commit.Date)
+		if index < 0 {
+			index = deploymentBefore(successful, commit.Date, rules.AttributionWindow)
+		}
+		add(index, ChangeFailureRevert, shortSHA(commit.SHA), firstLine(commit.Message), commit.Date)
+	}
+
+	for _, pr := range prs {
+		if pr.MergedAt == nil {
+			continue
+		}
+
+		// Revert PRs created by the GitHub "Revert" button
+		if isRevertMessage(pr.Title) || strings.HasPrefix(pr.HeadBranch, "revert-") {
+			index := findRevertedDeployment(successful, commits, prs, pr.Title, *pr.MergedAt)
+			if index < 0 {
+				index = deploymentBefore(successful, *pr.MergedAt, rules.AttributionWindow)
+			}
+			add(index, ChangeFailureRevert, fmt.Sprintf("#%d", pr.Number), pr.Title, *pr.MergedAt)
+			continue
+		}
+
+		if isHotfix(pr, rules) {
+			index := deploymentBefore(successful, *pr.MergedAt, rules.AttributionWindow)
+			add(index, ChangeFailureHotfix, fmt.Sprintf("#%d", pr.Number), pr.Title, *pr.MergedAt)
+		}
+	}
+
+	// Rollbacks: a deployment that returns an environment to an earlier SHA
+	// marks the deployment it replaced as failed
+	lastByEnv := make(map[string]int)
+	deployedSHAs := make(map[string][]string)
+	for i, deployment := range successful {
+		env := deployment.Environment
+		if prev, ok := lastByEnv[env]; ok && !shaMatches(successful[prev].SHA, deployment.SHA) {
+			for _, sha := range deployedSHAs[env] {
+				if shaMatches(sha, deployment.SHA) {
+					description := fmt.Sprintf("%s rolled back to %s", env, shortSHA(deployment.SHA))
+					add(prev, ChangeFailureRollback, fmt.Sprintf("deployment-%d", deployment.ID), description, deployment.CreatedAt)
+					break
+				}
+			}
+		}
+		lastByEnv[env] = i
+		deployedSHAs[env] = append(deployedSHAs[env], deployment.SHA)
+	}
+
+	for _, incident := range incidents {
+		index := -1
+		if incident.DeploymentSHA != "" {
+			index = deploymentWithSHA(successful, incident.DeploymentSHA)
+		}
+		if index < 0 {
+			index = deploymentBefore(successful, incident.OpenedAt, 0)
+		}
+		add(index, ChangeFailureIncident, incident.Source+":"+incident.ID, incident.Title, incident.OpenedAt)
+	}
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].DeployedAt.Before(failures[j].DeployedAt)
+	})
+
+	return failures
+}
+
+// calculateAttributedChangeFailureRate calculates the percentage of successful deployments with at least one attributed failure
+func calculateAttributedChangeFailureRate(deployments []Deployment, failures []ChangeFailure) float64 {
+	successful := successfulDeployments(deployments)
+	if len(successful) == 0 {
+		return 0
+	}
+
+	failed := make(map[string]bool)
+	for _, failure := range failures {
+		failed[failureDeploymentKey(failure)] = true
+	}
+
+	return float64(len(failed)) / float64(len(successful)) * 100
+}
+
+// successfulDeployments returns deployments that reached their environment, oldest first.
+// Inactive deployments succeeded and were later superseded.
+func successfulDeployments(deployments []Deployment) []Deployment {
+	successful := make([]Deployment, 0, len(deployments))
+	for _, deployment := range deployments {
+		switch deployment.State {
+		case "success", "active", "inactive":
+			successful = append(successful, deployment)
+		}
+	}
+	sort.SliceStable(successful, func(i, j int) bool {
+		return successful[i].CreatedAt.Before(successful[j].CreatedAt)
+	})
+	return successful
+}
+
+// findRevertedDeployment finds the deployment that shipped the change undone by a revert
+func findRevertedDeployment(successful []Deployment, commits []Commit, prs []PullRequest, message string, revertedAt time.Time) int {
+	if match := revertedSHAPattern.FindStringSubmatch(message); match != nil {
+		if index := deploymentWithSHA(successful, match[1]); index >= 0 {
+			return index
+		}
+		for _, commit := range commits {
+			if shaMatches(commit.SHA, match[1]) {
+				return deploymentAfter(successful, commit.Date, revertedAt)
+			}
+		}
+	}
+
+	subject := revertedSubject(message)
+	if subject == "" {
+		return -1
+	}
+	for _, commit := range commits {
+		if firstLine(commit.Message) == subject && commit.Date.Before(revertedAt) {
+			return deploymentAfter(successful, commit.Date, revertedAt)
+		}
+	}
+	for _, pr := range prs {
+		if pr.MergedAt != nil && pr.Title == subject && pr.MergedAt.Before(revertedAt) {
+			return deploymentAfter(successful, *pr.MergedAt, revertedAt)
+		}
+	}
+
+	return -1
+}
+
+// deploymentWithSHA returns the latest deployment of the given SHA
+func deploymentWithSHA(successful []Deployment, sha string) int {
+	index := -1
+	for i, deployment := range successful {
+		if shaMatches(deployment.SHA, sha) {
+			index = i
+		}
+	}
+	return index
+}
+
+// deploymentBefore returns the latest deployment started before t, optionally within window
+func deploymentBefore(successful []Deployment, t time.Time, window time.Duration) int {
+	index := -1
+	for i, deployment := range successful {
+		if deployment.CreatedAt.After(t) {
+			break
+		}
+		index = i
+	}
+	if index >= 0 && window > 0 && t.Sub(successful[index].CreatedAt) > window {
+		return -1
+	}
+	return index
+}
+
+// deploymentAfter returns the first deployment started between from and until
+func deploymentAfter(successful []Deployment, from, until time.Time) int {
+	for i, deployment := range successful {
+		if deployment.CreatedAt.Before(from) {
+			continue
+		}
+		if deployment.CreatedAt.After(until) {
+			break
+		}
+		return i
+	}
+	return -1
+}
+
+// isRevertMessage reports whether a commit message or PR title is a revert
+func isRevertMessage(message string) bool {
+	return strings.HasPrefix(firstLine(message), "Revert \"") || revertedSHAPattern.MatchString(message)
+}
+
+// revertedSubject extracts the original subject from `Revert "subject"`
+func revertedSubject(message string) string {
+	line := firstLine(message)
+	if !strings.HasPrefix(line, "Revert \"") {
+		return ""
+	}
+	line = strings.TrimPrefix(line, "Revert \"")
+	if end := strings.LastIndex(line, "\""); end >= 0 {
+		line = line[:end]
+	}
+	return line
+}
+
+// isHotfix reports whether a pull request is a hotfix by label or branch prefix
+func isHotfix(pr PullRequest, rules ChangeFailureRules) bool {
+	for _, label := range pr.Labels {
+		for _, hotfix := range rules.HotfixLabels {
+			if strings.EqualFold(label, hotfix) {
+				return true
+			}
+		}
+	}
+	branch := strings.ToLower(pr.HeadBranch)
+	for _, prefix := range rules.HotfixBranchPrefixes {
+		if prefix != "" && strings.HasPrefix(branch, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// failureDeploymentKey identifies the deployment a failure is attributed to
+func failureDeploymentKey(failure ChangeFailure) string {
+	return deploymentKey(Deployment{ID: failure.DeploymentID, SHA: failure.DeploymentSHA})
+}
+
+// firstLine returns the first line of a commit message
+func firstLine(message string) string {
+	if idx := strings.IndexByte(message, '\n'); idx >= 0 {
+		return strings.TrimSpace(message[:idx])
+	}
+	return strings.TrimSpace(message)
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
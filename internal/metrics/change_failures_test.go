@@ -0,0 +1,254 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+var baseTime = time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC) // A Monday
+
+// at returns baseTime plus hours
+func at(hours float64) time.Time {
+	return baseTime.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+// timePtr returns a pointer to t
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func testDeployments() []Deployment {
+	return []Deployment{
+		{ID: 1, Environment: "production", State: "success", SHA: "aaaaaaa1111", CreatedAt: at(0)},
+		{ID: 2, Environment: "production", State: "success", SHA: "bbbbbbb2222", CreatedAt: at(24)},
+		{ID: 3, Environment: "production", State: "failure", SHA: "ccccccc3333", CreatedAt: at(30)},
+		{ID: 4, Environment: "production", State: "inactive", SHA: "ddddddd4444", CreatedAt: at(48)},
+	}
+}
+
+func TestAttributeRevertBySHA(t *testing.T) {
+	commits := []Commit{
+		{SHA: "eeeeeee5555", Message: "Revert \"Add cache\"\n\nThis reverts commit bbbbbbb2222.", Date: at(60)},
+	}
+
+	failures := attributeChangeFailures(testDeployments(), nil, commits, nil, DefaultChangeFailureRules())
+	if len(failures) != 1 {
+		t.Fatalf("Expected 1 failure, got %d: %+v", len(failures), failures)
+	}
+	failure := failures[0]
+	if failure.DeploymentID != 2 || failure.Cause != ChangeFailureRevert || failure.Reference != "eeeeeee" {
+		t.Errorf("Expected the revert to be attributed to deployment 2, got %+v", failure)
+	}
+	if failure.Description != "Revert \"Add cache\"" {
+		t.Errorf("Expected the subject as description, got %q", failure.Description)
+	}
+}
+
+func TestAttributeRevertBySubject(t *testing.T) {
+	commits := []Commit{
+		{SHA: "1111111aaaa", Message: "Add search", Date: at(30)},
+		{SHA: "2222222bbbb", Message: "Revert \"Add search\"", Date: at(50)},
+	}
+
+	// The original commit landed after deployment 2, so deployment 4 shipped it
+	failures := attributeChangeFailures(testDeployments(), nil, commits, nil, DefaultChangeFailureRules())
+	if len(failures) != 1 || failures[0].DeploymentID != 4 {
+		t.Fatalf("Expected the revert to be attributed to deployment 4, got %+v", failures)
+	}
+}
+
+func TestAttributeRevertPullRequest(t *testing.T) {
+	prs := []PullRequest{
+		{Number: 10, Title: "Add search", MergedAt: timePtr(at(20))},
+		{Number: 11, Title: "Revert \"Add search\"", HeadBranch: "revert-10-search", MergedAt: timePtr(at(40))},
+	}
+
+	failures := attributeChangeFailures(testDeployments(), prs, nil, nil, DefaultChangeFailureRules())
+	if len(failures) != 1 {
+		t.Fatalf("Expected 1 failure, got %d: %+v", len(failures), failures)
+	}
+	if failures[0].DeploymentID != 2 || failures[0].Reference != "#11" {
+		t.Errorf("Expected revert PR #11 attributed to deployment 2, got %+v", failures[0])
+	}
+}
+
+func TestAttributeHotfixWindow(t *testing.T) {
+	rules := DefaultChangeFailureRules()
+	rules.AttributionWindow = 12 * time.Hour
+
+	tests := []struct {
+		name       string
+		pr         PullRequest
+		deployment int // 0 when not attributed
+	}{
+		{"label inside window", PullRequest{Number: 1, Labels: []string{"HotFix"}, MergedAt: timePtr(at(30))}, 2},
+		{"branch prefix", PullRequest{Number: 2, HeadBranch: "Hotfix/login", MergedAt: timePtr(at(5))}, 1},
+		{"exactly at window edge", PullRequest{Number: 3, Labels: []string{"hotfix"}, MergedAt: timePtr(at(36))}, 2},
+		{"just past window", PullRequest{Number: 4, Labels: []string{"hotfix"}, MergedAt: timePtr(at(36.1))}, 0},
+		{"before any deployment", PullRequest{Number: 5, Labels: []string{"hotfix"}, MergedAt: timePtr(at(-1))}, 0},
+		{"not merged", PullRequest{Number: 6, Labels: []string{"hotfix"}}, 0},
+		{"not a hotfix", PullRequest{Number: 7, HeadBranch: "feature/hotfix", MergedAt: timePtr(at(30))}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := attributeChangeFailures(testDeployments(), []PullRequest{tt.pr}, nil, nil, rules)
+			if tt.deployment == 0 {
+				if len(failures) != 0 {
+					t.Errorf("Expected no failure, got %+v", failures)
+				}
+				return
+			}
+			if len(failures) != 1 || failures[0].DeploymentID != tt.deployment || failures[0].Cause != ChangeFailureHotfix {
+				t.Errorf("Expected a hotfix attributed to deployment %d, got %+v", tt.deployment, failures)
+			}
+		})
+	}
+}
+
+func TestAttributeRollback(t *testing.T) {
+	deployments := []Deployment{
+		{ID: 1, Environment: "production", State: "success", SHA: "aaaaaaa", CreatedAt: at(0)},
+		{ID: 2, Environment: "production", State: "success", SHA: "bbbbbbb", CreatedAt: at(10)},
+		{ID: 3, Environment: "staging", State: "success", SHA: "aaaaaaa", CreatedAt: at(11)},
+		{ID: 4, Environment: "production", State: "success", SHA: "aaaaaaa", CreatedAt: at(12)},
+	}
+
+	failures := attributeChangeFailures(deployments, nil, nil, nil, DefaultChangeFailureRules())
+	if len(failures) != 1 {
+		t.Fatalf("Expected 1 rollback, got %+v", failures)
+	}
+	if failures[0].DeploymentID != 2 || failures[0].Cause != ChangeFailureRollback || failures[0].Reference != "deployment-4" {
+		t.Errorf("Expected deployment 2 rolled back by deployment 4, got %+v", failures[0])
+	}
+}
+
+func TestAttributeIncidents(t *testing.T) {
+	incidents := []Incident{
+		{ID: "INC-1", Source: "webhook", DeploymentSHA: "aaaaaaa", OpenedAt: at(100)}, // Named deployment wins over timing
+		{ID: "INC-2", Source: "webhook", OpenedAt: at(200)},                           // No window for incidents
+		{ID: "INC-3", Source: "webhook", OpenedAt: at(-5)},                            // Before any deployment
+	}
+
+	failures := attributeChangeFailures(testDeployments(), nil, nil, incidents, DefaultChangeFailureRules())
+	if len(failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %+v", failures)
+	}
+	if failures[0].DeploymentID != 1 || failures[0].Reference != "webhook:INC-1" {
+		t.Errorf("Expected INC-1 on deployment 1, got %+v", failures[0])
+	}
+	if failures[1].DeploymentID != 4 || failures[1].Reference != "webhook:INC-2" {
+		t.Errorf("Expected INC-2 on deployment 4, got %+v", failures[1])
+	}
+}
+
+func TestAttributedChangeFailureRate(t *testing.T) {
+	deployments := testDeployments()
+	commits := []Commit{
+		{SHA: "1111111", Message: "This reverts commit bbbbbbb2222.", Date: at(25)},
+		{SHA: "2222222", Message: "This reverts commit bbbbbbb2222.", Date: at(26)},
+	}
+	prs := []PullRequest{{Number: 1, Labels: []string{"hotfix"}, MergedAt: timePtr(at(26))}}
+
+	failures := attributeChangeFailures(deployments, prs, commits, nil, DefaultChangeFailureRules())
+	if len(failures) != 3 {
+		t.Fatalf("Expected 3 failure signals, got %+v", failures)
+	}
+
+	// Three signals on one of three successful deployments
+	rate := calculateAttributedChangeFailureRate(deployments, failures)
+	if rate < 33.3 || rate > 33.4 {
+		t.Errorf("Expected a change failure rate of 33.3%%, got %.2f", rate)
+	}
+}
+
+// doraTestClient serves fixed repository data
+type doraTestClient struct {
+	prs         []PullRequest
+	deployments []Deployment
+	runs        []WorkflowRun
+}
+
+func (c *doraTestClient) GetPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]PullRequest, error) {
+	return c.prs, nil
+}
+
+func (c *doraTestClient) GetDeployments(ctx context.Context, owner, repo string, since time.Time) ([]Deployment, error) {
+	return c.deployments, nil
+}
+
+func (c *doraTestClient) GetWorkflowRuns(ctx context.Context, owner, repo string, since time.Time) ([]WorkflowRun, error) {
+	return c.runs, nil
+}
+
+// failingGitClient fails every commit query
+type failingGitClient struct{}
+
+func (failingGitClient) GetCommits(ctx context.Context, owner, repo string, since time.Time) ([]Commit, error) {
+	return nil, errors.New("repository unavailable")
+}
+
+func TestDORACalculatorDegradesWithoutCommits(t *testing.T) {
+	now := time.Now()
+	client := &doraTestClient{
+		deployments: []Deployment{
+			{ID: 1, Environment: "production", State: "success", SHA: "aaaaaaa", CreatedAt: now.Add(-72 * time.Hour)},
+			{ID: 2, Environment: "production", State: "success", SHA: "bbbbbbb", CreatedAt: now.Add(-48 * time.Hour)},
+		},
+		prs: []PullRequest{
+			{Number: 1, Labels: []string{"hotfix"}, CreatedAt: now.Add(-47 * time.Hour), MergedAt: timePtr(now.Add(-46 * time.Hour))},
+		},
+	}
+	dora := NewDORACalculator(client, nil)
+	dora.SetGitClient(failingGitClient{})
+
+	metrics, err := dora.Calculate(context.Background(), types.Repository{Owner: "test-owner", Name: "test-repo"}, 30)
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if metrics.ChangeFailRatePercent != 50 {
+		t.Errorf("Expected the hotfix to still count, got change failure rate %.1f", metrics.ChangeFailRatePercent)
+	}
+	if len(metrics.Warnings) != 1 {
+		t.Errorf("Expected a commit history warning, got %v", metrics.Warnings)
+	}
+}
+
+func TestDORACalculatorIncidentModeNeedsIncidents(t *testing.T) {
+	now := time.Now()
+	client := &doraTestClient{runs: []WorkflowRun{
+		{Status: "completed", Conclusion: "failure", CreatedAt: now.Add(-4 * time.Hour), UpdatedAt: now.Add(-4 * time.Hour)},
+		{Status: "completed", Conclusion: "success", CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)},
+	}}
+	store := NewIncidentStore()
+	dora := NewDORACalculator(client, nil)
+	dora.AddIncidentSource(store)
+	repo := types.Repository{Owner: "acme", Name: "api"}
+
+	// An empty store, or one with incidents of other repositories, keeps the workflow heuristics
+	if err := store.Record(Incident{ID: "1", Repository: "acme/web", OpenedAt: now.Add(-3 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	metrics, err := dora.Calculate(context.Background(), repo, 30)
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if metrics.ChangeFailRatePercent != 50 || math.Abs(metrics.MTTRHours-2) > 0.01 {
+		t.Errorf("Expected the workflow heuristics, got change failure rate %.1f and MTTR %.2f", metrics.ChangeFailRatePercent, metrics.MTTRHours)
+	}
+
+	if err := store.Record(Incident{ID: "2", Repository: "acme/api", OpenedAt: now.Add(-3 * time.Hour), ResolvedAt: timePtr(now.Add(-2 * time.Hour))}); err != nil {
+		t.Fatal(err)
+	}
+	metrics, err = dora.Calculate(context.Background(), repo, 30)
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if metrics.ChangeFailRatePercent != 0 || math.Abs(metrics.MTTRHours-1) > 0.01 {
+		t.Errorf("Expected MTTR from the incident, got change failure rate %.1f and MTTR %.2f", metrics.ChangeFailRatePercent, metrics.MTTRHours)
+	}
+}
@@ -151,6 +151,21 @@ func (c *CHICalculator) analyzeFile(path string) (*CodeFile, error) {
 		return nil, err
 	}
 
+	return c.analyzeContent(path, content), nil
+}
+
+// AnalyzeSource analyzes the content of a source file, such as a file at a given commit.
+// Returns false for files CHI does not treat as code.
+func AnalyzeSource(path string, content []byte) (CodeFile, bool) {
+	c := &CHICalculator{}
+	if !c.isCodeFile(path) || c.shouldSkipPath(path) {
+		return CodeFile{}, false
+	}
+	return *c.analyzeContent(path, content), true
+}
+
+// analyzeContent analyzes source code read from path
+func (c *CHICalculator) analyzeContent(path string, content []byte) *CodeFile {
 	lines := strings.Split(string(content), "\n")
 	linesOfCode := c.countLinesOfCode(lines)
 
@@ -170,7 +185,7 @@ func (c *CHICalculator) analyzeFile(path string) (*CodeFile, error) {
 	// Generic duplication detection
 	file.Duplications = c.detectDuplications(lines)
 
-	return file, nil
+	return file
 }
 
 // detectLanguage detects the programming language of a file
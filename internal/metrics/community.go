@@ -0,0 +1,271 @@
+// Package metrics - Issue responsiveness and community health
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Author associations of repository maintainers; any other association is an outside contributor
+var maintainerAssociations = map[string]bool{"OWNER": true, "MEMBER": true, "COLLABORATOR": true}
+
+// goodFirstIssueLabels are label names marking issues for newcomers
+var goodFirstIssueLabels = []string{"good first issue", "good-first-issue", "good_first_issue"}
+
+// trendTolerance is the relative change within which a KPI is stable
+const trendTolerance = 0.05
+
+// CommunityItem is an issue or pull request with the maintainer activity it received
+type CommunityItem struct {
+	Number            int                 `json:"number"`
+	PullRequest       bool                `json:"pull_request"`
+	State             string              `json:"state"` // OPEN, CLOSED or MERGED
+	Author            string              `json:"author"`
+	AuthorAssociation string              `json:"author_association"` // OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, NONE, ...
+	AuthorIsBot       bool                `json:"author_is_bot,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	ClosedAt          *time.Time          `json:"closed_at,omitempty"`
+	Labels            []string            `json:"labels,omitempty"`
+	Responses         []CommunityResponse `json:"responses,omitempty"`       // Comments and reviews
+	ResponsesUntil    *time.Time          `json:"responses_until,omitempty"` // Responses are complete up to this time only, when more exist than were read
+}
+
+// CommunityItems are the issues and pull requests read for community health
+type CommunityItems struct {
+	Items      []CommunityItem `json:"items"`
+	Incomplete bool            `json:"incomplete,omitempty"` // Older items are missing, e.g. past the page cap
+}
+
+// CommunityResponse is a comment or review on an issue or pull request
+type CommunityResponse struct {
+	Author            string    `json:"author"`
+	AuthorAssociation string    `json:"author_association"`
+	AuthorIsBot       bool      `json:"author_is_bot,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// OpenIssueCounts is a point-in-time count of open issues
+type OpenIssueCounts struct {
+	Open       int  `json:"open"`
+	Unlabeled  int  `json:"unlabeled"`
+	GoodFirst  int  `json:"good_first"`
+	Incomplete bool `json:"incomplete,omitempty"` // Counts are missing, e.g. search is unavailable
+}
+
+// CommunityClient reads issues and pull requests with their maintainer responses.
+// GraphQLClient implements it.
+type CommunityClient interface {
+	GetCommunityItems(ctx context.Context, owner, repo string, since time.Time) (CommunityItems, error)
+	GetOpenIssueCounts(ctx context.Context, owner, repo string) (OpenIssueCounts, error)
+}
+
+// CommunityBenchmarks are the targets community KPIs are rated against
+type CommunityBenchmarks struct {
+	FirstResponseP50Hours float64 `json:"first_response_p50_hours" yaml:"first_response_p50_hours"` // Upper bound, issues and pull requests
+	IssueCloseRatePercent float64 `json:"issue_close_rate_pct" yaml:"issue_close_rate_pct"`
+	LabeledIssuePercent   float64 `json:"labeled_issue_pct" yaml:"labeled_issue_pct"`
+	GoodFirstIssuePercent float64 `json:"good_first_issue_pct" yaml:"good_first_issue_pct"`
+}
+
+// DefaultCommunityBenchmarks expects a first response within two days, half the opened issues
+// closed, 80% of issues labeled and 5% of open issues marked as good first issues
+func DefaultCommunityBenchmarks() CommunityBenchmarks {
+	return CommunityBenchmarks{
+		FirstResponseP50Hours: 48,
+		IssueCloseRatePercent: 50,
+		LabeledIssuePercent:   80,
+		GoodFirstIssuePercent: 5,
+	}
+}
+
+// withDefaults fills unset targets from the defaults
+func (b CommunityBenchmarks) withDefaults() CommunityBenchmarks {
+	defaults := DefaultCommunityBenchmarks()
+	if b.FirstResponseP50Hours <= 0 {
+		b.FirstResponseP50Hours = defaults.FirstResponseP50Hours
+	}
+	if b.IssueCloseRatePercent <= 0 {
+		b.IssueCloseRatePercent = defaults.IssueCloseRatePercent
+	}
+	if b.LabeledIssuePercent <= 0 {
+		b.LabeledIssuePercent = defaults.LabeledIssuePercent
+	}
+	if b.GoodFirstIssuePercent <= 0 {
+		b.GoodFirstIssuePercent = defaults.GoodFirstIssuePercent
+	}
+	return b
+}
+
+// communityPeriod holds the period-based measurements of one period
+type communityPeriod struct {
+	issueResponse, prResponse types.ResponseTime
+	opened, closed, labeled   int
+	incomplete                bool // Items whose first response is unknown were left out
+}
+
+// CalculateCommunityHealth computes community KPIs for items opened in the last periodDays,
+// comparing them with the period before and rating them against the benchmarks, whose unset
+// targets take their defaults. First
+// response times cover items by outside contributors, answered by a maintainer other than the
+// author; bots are ignored. Items should reach back two periods for the trends. Items whose
+// first response can't be told from the responses read are left out of response times, and
+// the result is marked incomplete along with incomplete items or counts.
+func CalculateCommunityHealth(items CommunityItems, open OpenIssueCounts, benchmarks CommunityBenchmarks, periodDays int, now time.Time) *types.CommunityHealth {
+	if periodDays <= 0 {
+		periodDays = 30
+	}
+	benchmarks = benchmarks.withDefaults()
+	periodStart := now.AddDate(0, 0, -periodDays)
+	previousStart := periodStart.AddDate(0, 0, -periodDays)
+
+	var current, previous []CommunityItem
+	for _, item := range items.Items {
+		switch {
+		case item.AuthorIsBot || item.CreatedAt.After(now):
+		case !item.CreatedAt.Before(periodStart):
+			current = append(current, item)
+		case !item.CreatedAt.Before(previousStart):
+			previous = append(previous, item)
+		}
+	}
+	cur, before := measureCommunityPeriod(current, now), measureCommunityPeriod(previous, periodStart)
+
+	health := &types.CommunityHealth{
+		PeriodDays:            periodDays,
+		IssueFirstResponse:    cur.issueResponse,
+		PRFirstResponse:       cur.prResponse,
+		IssuesOpened:          cur.opened,
+		IssuesClosed:          cur.closed,
+		IssueCloseRatePercent: percentOf(cur.closed, cur.opened),
+		LabeledIssuePercent:   percentOf(cur.labeled, cur.opened),
+		Incomplete:            items.Incomplete || open.Incomplete || cur.incomplete || before.incomplete,
+	}
+	if !open.Incomplete {
+		health.OpenIssues = open.Open
+		health.UnlabeledOpenIssues = open.Unlabeled
+		health.GoodFirstIssues = open.GoodFirst
+		health.GoodFirstIssuePercent = percentOf(open.GoodFirst, open.Open)
+	}
+
+	if cur.issueResponse.Responded > 0 {
+		health.KPIs = append(health.KPIs, communityKPI("issue_first_response_p50_hours",
+			cur.issueResponse.P50Hours, before.issueResponse.P50Hours, before.issueResponse.Responded > 0,
+			benchmarks.FirstResponseP50Hours, false))
+	}
+	if cur.prResponse.Responded > 0 {
+		health.KPIs = append(health.KPIs, communityKPI("pr_first_response_p50_hours",
+			cur.prResponse.P50Hours, before.prResponse.P50Hours, before.prResponse.Responded > 0,
+			benchmarks.FirstResponseP50Hours, false))
+	}
+	if cur.opened > 0 {
+		health.KPIs = append(health.KPIs,
+			communityKPI("issue_close_rate_pct", health.IssueCloseRatePercent,
+				percentOf(before.closed, before.opened), before.opened > 0, benchmarks.IssueCloseRatePercent, true),
+			communityKPI("labeled_issue_pct", health.LabeledIssuePercent,
+				percentOf(before.labeled, before.opened), before.opened > 0, benchmarks.LabeledIssuePercent, true))
+	}
+	if !open.Incomplete && open.Open > 0 {
+		health.KPIs = append(health.KPIs, communityKPI("good_first_issue_pct",
+			health.GoodFirstIssuePercent, 0, false, benchmarks.GoodFirstIssuePercent, true))
+	}
+	return health
+}
+
+// measureCommunityPeriod measures the items opened in one period, as of end
+func measureCommunityPeriod(items []CommunityItem, end time.Time) communityPeriod {
+	var period communityPeriod
+	var issueHours, prHours []float64
+
+	for _, item := range items {
+		if !item.PullRequest {
+			period.opened++
+			if item.ClosedAt != nil && !item.ClosedAt.After(end) {
+				period.closed++
+			}
+			if len(item.Labels) > 0 {
+				period.labeled++
+			}
+		}
+		if maintainerAssociations[strings.ToUpper(item.AuthorAssociation)] {
+			continue
+		}
+
+		at, responded, known := firstMaintainerResponse(item, end)
+		if !known {
+			period.incomplete = true
+			continue
+		}
+		response, hours := &period.issueResponse, &issueHours
+		if item.PullRequest {
+			response, hours = &period.prResponse, &prHours
+		}
+		response.Items++
+		if responded {
+			response.Responded++
+			*hours = append(*hours, at.Sub(item.CreatedAt).Hours())
+		} else if item.ClosedAt == nil || item.ClosedAt.After(end) {
+			response.Unanswered++
+		}
+	}
+
+	for _, measure := range []struct {
+		response *types.ResponseTime
+		hours    []float64
+	}{{&period.issueResponse, issueHours}, {&period.prResponse, prHours}} {
+		sort.Float64s(measure.hours)
+		measure.response.P50Hours = percentile(measure.hours, 0.50)
+		measure.response.P90Hours = percentile(measure.hours, 0.90)
+	}
+	return period
+}
+
+// firstMaintainerResponse returns the earliest response by a maintainer other than the author
+// until end, and whether it is known: responses past item.ResponsesUntil were not read, so an
+// earlier one may have been missed
+func firstMaintainerResponse(item CommunityItem, end time.Time) (time.Time, bool, bool) {
+	var first time.Time
+	for _, response := range item.Responses {
+		if response.AuthorIsBot || strings.EqualFold(response.Author, item.Author) ||
+			!maintainerAssociations[strings.ToUpper(response.AuthorAssociation)] ||
+			response.CreatedAt.After(end) {
+			continue
+		}
+		if first.IsZero() || response.CreatedAt.Before(first) {
+			first = response.CreatedAt
+		}
+	}
+	responded := !first.IsZero()
+	until := item.ResponsesUntil
+	known := until == nil || !until.Before(end) || (responded && !first.After(*until))
+	return first, responded, known
+}
+
+// communityKPI rates a KPI against its target and, when the previous period has data, its trend
+func communityKPI(metric string, value, previous float64, hasPrevious bool, target float64, higherIsBetter bool) types.CommunityKPI {
+	kpi := types.CommunityKPI{Metric: metric, Value: value, Target: target}
+	if higherIsBetter {
+		kpi.MeetsTarget = value >= target
+	} else {
+		kpi.MeetsTarget = value <= target
+	}
+	if !hasPrevious {
+		return kpi
+	}
+
+	kpi.Previous = previous
+	change := value - previous
+	switch {
+	case math.Abs(change) <= trendTolerance*math.Max(math.Abs(previous), 1):
+		kpi.Trend = "stable"
+	case (change > 0) == higherIsBetter:
+		kpi.Trend = "improving"
+	default:
+		kpi.Trend = "worsening"
+	}
+	return kpi
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCalculateCommunityHealthTruncatedResponses(t *testing.T) {
+	now := baseTime
+	opened := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	maintainer := func(at time.Time) CommunityResponse {
+		return CommunityResponse{Author: "maria", AuthorAssociation: "MEMBER", CreatedAt: at}
+	}
+	outsider := func(at time.Time) CommunityResponse {
+		return CommunityResponse{Author: "olga", AuthorAssociation: "NONE", CreatedAt: at}
+	}
+	until := func(at time.Time) *time.Time { return &at }
+
+	items := []CommunityItem{
+		{Number: 1, Author: "ana", AuthorAssociation: "CONTRIBUTOR", CreatedAt: opened(10),
+			Responses: []CommunityResponse{maintainer(opened(10).Add(2 * time.Hour))}},
+		// Answered before the responses that were not read
+		{Number: 2, Author: "ana", AuthorAssociation: "CONTRIBUTOR", CreatedAt: opened(3),
+			Responses:      []CommunityResponse{maintainer(opened(3).Add(4 * time.Hour))},
+			ResponsesUntil: until(opened(3).Add(6 * time.Hour))},
+		// Only outsiders among the responses read: a maintainer may have answered after them
+		{Number: 3, Author: "ana", AuthorAssociation: "CONTRIBUTOR", CreatedAt: opened(5),
+			Responses:      []CommunityResponse{outsider(opened(5).Add(time.Hour))},
+			ResponsesUntil: until(opened(5).Add(time.Hour))},
+	}
+
+	health := CalculateCommunityHealth(CommunityItems{Items: items}, OpenIssueCounts{Open: 10}, CommunityBenchmarks{}, 30, now)
+	if !health.Incomplete {
+		t.Error("Expected the health marked incomplete")
+	}
+	if response := health.IssueFirstResponse; response.Items != 2 || response.Responded != 2 || response.Unanswered != 0 {
+		t.Errorf("Expected issue 3 left out of response times, got %+v", response)
+	}
+	if health.IssuesOpened != 3 {
+		t.Errorf("Expected every issue counted as opened, got %d", health.IssuesOpened)
+	}
+
+	health = CalculateCommunityHealth(CommunityItems{Items: items[:2]}, OpenIssueCounts{Open: 10}, CommunityBenchmarks{}, 30, now)
+	if health.Incomplete {
+		t.Errorf("Expected complete health when every first response is known, got %+v", health)
+	}
+	health = CalculateCommunityHealth(CommunityItems{Items: items[:2]}, OpenIssueCounts{Incomplete: true}, CommunityBenchmarks{}, 30, now)
+	if !health.Incomplete || health.OpenIssues != 0 {
+		t.Errorf("Expected incomplete health without open issue counts, got %+v", health)
+	}
+}
+
+// staticToken authenticates every request with the same token
+type staticToken string
+
+func (s staticToken) GetAuthToken() (string, error) {
+	return string(s), nil
+}
+
+func TestGetCommunityItemsMarksTruncation(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	commentAt := createdAt.Add(time.Minute)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Every page has a next one, and every item more comments than were read
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"repository": map[string]interface{}{"items": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "next"},
+				"nodes": []map[string]interface{}{{
+					"number": requests, "state": "OPEN", "createdAt": createdAt, "authorAssociation": "NONE",
+					"author": map[string]interface{}{"login": "ana", "__typename": "User"},
+					"comments": map[string]interface{}{
+						"pageInfo": map[string]interface{}{"hasNextPage": true},
+						"nodes": []map[string]interface{}{{
+							"createdAt": commentAt, "authorAssociation": "NONE",
+							"author": map[string]interface{}{"login": "olga", "__typename": "User"},
+						}},
+					},
+				}},
+			}},
+		}})
+	}))
+	defer server.Close()
+
+	items, err := NewGraphQLClient(staticToken("token"), server.URL).GetCommunityItems(context.Background(), "acme", "api", createdAt.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetCommunityItems failed: %v", err)
+	}
+	if !items.Incomplete || len(items.Items) != 2*maxCommunityPages || requests != 2*maxCommunityPages {
+		t.Errorf("Expected %d pages of issues and of pull requests, marked incomplete, got %d items in %d requests",
+			maxCommunityPages, len(items.Items), requests)
+	}
+	if until := items.Items[0].ResponsesUntil; until == nil || !until.Equal(commentAt) {
+		t.Errorf("Expected responses complete up to the last comment read, got %v", until)
+	}
+}
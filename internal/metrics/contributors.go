@@ -0,0 +1,282 @@
+// Package metrics - Contributor onboarding time and retention cohorts
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// establishedWindow is the start of the history in which first contributions are assumed to
+// predate it: contributors first seen there are established, not new
+const establishedWindow = 30 * 24 * time.Hour
+
+// ContributorConfig tunes onboarding and retention metrics
+type ContributorConfig struct {
+	HistoryDays   int               `json:"history_days" yaml:"history_days"`     // History searched for first contributions
+	OnboardingPRs int               `json:"onboarding_prs" yaml:"onboarding_prs"` // Merged pull requests that complete onboarding
+	RetentionDays []int             `json:"retention_days" yaml:"retention_days"`
+	Aliases       map[string]string `json:"aliases,omitempty" yaml:"aliases,omitempty"` // Commit author names or emails to pull request logins
+}
+
+// PullRequestListClient lists pull requests without sizes or reviews, which contributor
+// history doesn't need over its long window. GitHubClient implements it.
+type PullRequestListClient interface {
+	ListPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]PullRequest, error)
+}
+
+// noreplyEmail matches GitHub noreply emails, "[id+]login@users.noreply.github.com"
+var noreplyEmail = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// contributorIdentities resolves commit and pull request authors to one key per contributor:
+// the login when it can be found, the email otherwise
+type contributorIdentities struct {
+	aliases map[string]string // Lowercased names, emails and logins to logins
+	logins  map[string]string // Lowercased emails to logins; "" when they disagree
+}
+
+// newContributorIdentities learns the login of a commit email from pull requests merged as
+// that commit. Squash and rebase merges keep the pull request author as commit author; merge
+// commits are authored by whoever merged, so they are skipped.
+func newContributorIdentities(commits []Commit, prs []PullRequest, aliases map[string]string) contributorIdentities {
+	identities := contributorIdentities{
+		aliases: make(map[string]string, len(aliases)),
+		logins:  make(map[string]string),
+	}
+	for name, login := range aliases {
+		identities.aliases[normalizeIdentity(name)] = normalizeIdentity(login)
+	}
+
+	bySHA := make(map[string]Commit, len(commits))
+	for _, commit := range commits {
+		bySHA[commit.SHA] = commit
+	}
+	for _, pr := range prs {
+		commit, ok := bySHA[pr.MergeCommitSHA]
+		email := normalizeIdentity(commit.Email)
+		if pr.MergeCommitSHA == "" || !ok || email == "" || strings.HasPrefix(commit.Message, "Merge ") {
+			continue
+		}
+		login := normalizeIdentity(pr.Author)
+		if known, seen := identities.logins[email]; seen && known != login {
+			login = ""
+		}
+		identities.logins[email] = login
+	}
+	return identities
+}
+
+// commitAuthor resolves the author of a commit through the aliases, the login of a GitHub
+// noreply email, or the login learned from merged pull requests; else it is the email, or the
+// name without one
+func (c contributorIdentities) commitAuthor(commit Commit) string {
+	email := normalizeIdentity(commit.Email)
+	name := normalizeIdentity(commit.Author)
+	for _, identity := range []string{email, name} {
+		if login, ok := c.aliases[identity]; ok && identity != "" {
+			return login
+		}
+	}
+	if match := noreplyEmail.FindStringSubmatch(email); match != nil {
+		return match[1]
+	}
+	if login := c.logins[email]; login != "" {
+		return login
+	}
+	if email != "" {
+		return email
+	}
+	return name
+}
+
+// pullRequestAuthor resolves the login of a pull request author through the aliases
+func (c contributorIdentities) pullRequestAuthor(pr PullRequest) string {
+	login := normalizeIdentity(pr.Author)
+	if alias, ok := c.aliases[login]; ok {
+		return alias
+	}
+	return login
+}
+
+// normalizeIdentity lowercases a name, email or login for comparison
+func normalizeIdentity(identity string) string {
+	return strings.ToLower(strings.TrimSpace(identity))
+}
+
+// DefaultContributorConfig searches two years of history, counts a contributor as onboarded at
+// their third merged pull request and measures retention after 30, 90 and 180 days
+func DefaultContributorConfig() ContributorConfig {
+	return ContributorConfig{
+		HistoryDays:   730,
+		OnboardingPRs: 3,
+		RetentionDays: []int{30, 90, 180},
+	}
+}
+
+// contributorActivity is the contribution history of one contributor
+type contributorActivity struct {
+	first, last time.Time
+	merged      []time.Time // Merge times of the contributor's pull requests
+}
+
+// contribute records a contribution
+func (c *contributorActivity) contribute(at time.Time) {
+	if c.first.IsZero() || at.Before(c.first) {
+		c.first = at
+	}
+	if at.After(c.last) {
+		c.last = at
+	}
+}
+
+// activeAfter reports whether the contributor contributed at or after t
+func (c *contributorActivity) activeAfter(t time.Time) bool {
+	return !c.last.Before(t)
+}
+
+// CalculateContributorMetrics measures onboarding time from a contributor's first commit or
+// pull request to their Nth merged pull request, retention of monthly cohorts of new
+// contributors, and the new vs returning mix of contributors active in the last periodDays.
+// Contributors are keyed by login, resolving commit authors as contributorIdentities does, so a
+// contributor's commits and pull requests count once; bots ("[bot]" suffix) are skipped. Contributors first seen in the first 30 days of the
+// history are treated as established: they are returning, and start no cohort or onboarding.
+// Returns nil without contributors.
+func CalculateContributorMetrics(commits []Commit, prs []PullRequest, config ContributorConfig, periodDays int, now time.Time) *types.ContributorMetrics {
+	defaults := DefaultContributorConfig()
+	if config.HistoryDays <= 0 {
+		config.HistoryDays = defaults.HistoryDays
+	}
+	if config.OnboardingPRs <= 0 {
+		config.OnboardingPRs = defaults.OnboardingPRs
+	}
+	if len(config.RetentionDays) == 0 {
+		config.RetentionDays = defaults.RetentionDays
+	}
+	historyStart := now.AddDate(0, 0, -config.HistoryDays)
+
+	identities := newContributorIdentities(commits, prs, config.Aliases)
+	contributors := make(map[string]*contributorActivity)
+	activity := func(name string) *contributorActivity {
+		if name == "" || strings.HasSuffix(name, "[bot]") {
+			return nil
+		}
+		contributor := contributors[name]
+		if contributor == nil {
+			contributor = &contributorActivity{}
+			contributors[name] = contributor
+		}
+		return contributor
+	}
+
+	for _, commit := range commits {
+		if commit.Date.Before(historyStart) || commit.Date.After(now) {
+			continue
+		}
+		if contributor := activity(identities.commitAuthor(commit)); contributor != nil {
+			contributor.contribute(commit.Date)
+		}
+	}
+	for _, pr := range prs {
+		if pr.CreatedAt.Before(historyStart) || pr.CreatedAt.After(now) {
+			continue
+		}
+		contributor := activity(identities.pullRequestAuthor(pr))
+		if contributor == nil {
+			continue
+		}
+		contributor.contribute(pr.CreatedAt)
+		if pr.MergedAt != nil && !pr.MergedAt.After(now) {
+			contributor.contribute(*pr.MergedAt)
+			contributor.merged = append(contributor.merged, *pr.MergedAt)
+		}
+	}
+	if len(contributors) == 0 {
+		return nil
+	}
+
+	result := &types.ContributorMetrics{
+		Contributors:        len(contributors),
+		OnboardingMergedPRs: config.OnboardingPRs,
+	}
+	periodStart := now.AddDate(0, 0, -periodDays)
+	newSince := historyStart.Add(establishedWindow)
+	overall := make([]types.RetentionRate, len(config.RetentionDays))
+	cohorts := make(map[string]*types.ContributorCohort)
+	var onboarding []float64
+
+	for _, contributor := range contributors {
+		isNew := !contributor.first.Before(newSince)
+
+		if contributor.activeAfter(periodStart) {
+			result.ActiveContributors++
+			if isNew && !contributor.first.Before(periodStart) {
+				result.NewContributors++
+			} else {
+				result.ReturningContributors++
+			}
+		}
+		if !isNew {
+			continue
+		}
+
+		sort.Slice(contributor.merged, func(i, j int) bool {
+			return contributor.merged[i].Before(contributor.merged[j])
+		})
+		if len(contributor.merged) >= config.OnboardingPRs {
+			onboarded := contributor.merged[config.OnboardingPRs-1]
+			onboarding = append(onboarding, onboarded.Sub(contributor.first).Hours()/24)
+		}
+
+		month := contributor.first.Format("2006-01")
+		cohort := cohorts[month]
+		if cohort == nil {
+			cohort = &types.ContributorCohort{Month: month, Retention: make([]types.RetentionRate, len(config.RetentionDays))}
+			cohorts[month] = cohort
+		}
+		cohort.Size++
+		for i, days := range config.RetentionDays {
+			after := contributor.first.AddDate(0, 0, days)
+			if after.After(now) {
+				continue // Too recent to tell
+			}
+			for _, rates := range [][]types.RetentionRate{overall, cohort.Retention} {
+				rates[i].Eligible++
+				if contributor.activeAfter(after) {
+					rates[i].Retained++
+				}
+			}
+		}
+	}
+
+	if result.ActiveContributors > 0 {
+		result.NewContributorPercent = float64(result.NewContributors) / float64(result.ActiveContributors) * 100
+	}
+	if len(onboarding) > 0 {
+		sort.Float64s(onboarding)
+		result.OnboardedContributors = len(onboarding)
+		result.OnboardingP50Days = percentile(onboarding, 0.50)
+		result.OnboardingP75Days = percentile(onboarding, 0.75)
+	}
+	result.Retention = finishRetention(overall, config.RetentionDays)
+	for _, cohort := range cohorts {
+		cohort.Retention = finishRetention(cohort.Retention, config.RetentionDays)
+		result.Cohorts = append(result.Cohorts, *cohort)
+	}
+	sort.Slice(result.Cohorts, func(i, j int) bool {
+		return result.Cohorts[i].Month < result.Cohorts[j].Month
+	})
+	return result
+}
+
+// finishRetention fills the days and percentages of retention rates
+func finishRetention(rates []types.RetentionRate, days []int) []types.RetentionRate {
+	for i := range rates {
+		rates[i].Days = days[i]
+		rates[i].Percent = percentOf(rates[i].Retained, rates[i].Eligible)
+	}
+	return rates
+}
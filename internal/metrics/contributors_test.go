@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateContributorMetricsResolvesAuthors(t *testing.T) {
+	now := baseTime
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	merged := func(days int) *time.Time {
+		at := daysAgo(days)
+		return &at
+	}
+
+	commits := []Commit{
+		{SHA: "a1", Author: "Alice Doe", Email: "Alice@acme.io", Date: daysAgo(100)},
+		{SHA: "a2", Author: "Alice Doe", Email: "alice@acme.io", Message: "Add retries (#1)", Date: daysAgo(99)},
+		{SHA: "b1", Author: "Bob", Email: "42+bob@users.noreply.github.com", Date: daysAgo(50)},
+		{SHA: "m1", Author: "Dan", Email: "dan@acme.io", Message: "Merge pull request #3 from carol/docs", Date: daysAgo(20)},
+		{SHA: "e1", Author: "Eve", Email: "eve@home.io", Date: daysAgo(10)},
+		{SHA: "d1", Author: "dependabot[bot]", Email: "49699333+dependabot[bot]@users.noreply.github.com", Date: daysAgo(5)},
+	}
+	prs := []PullRequest{
+		{Number: 1, Author: "alice", CreatedAt: daysAgo(100), MergedAt: merged(99), MergeCommitSHA: "a2"},
+		{Number: 2, Author: "bob", CreatedAt: daysAgo(50)},
+		{Number: 3, Author: "carol", CreatedAt: daysAgo(21), MergedAt: merged(20), MergeCommitSHA: "m1"},
+		{Number: 4, Author: "eve", CreatedAt: daysAgo(10)},
+		{Number: 5, Author: "dependabot[bot]", CreatedAt: daysAgo(5)},
+	}
+	config := ContributorConfig{HistoryDays: 365, Aliases: map[string]string{"Eve@Home.io": "Eve"}}
+
+	result := CalculateContributorMetrics(commits, prs, config, 30, now)
+	if result == nil {
+		t.Fatal("Expected contributor metrics")
+	}
+	// alice by the squashed pull request, bob by the noreply email, eve by alias; dan merged
+	// carol's pull request, so both count, and the bot counts for neither
+	if result.Contributors != 5 {
+		t.Errorf("Expected alice, bob, carol, dan and eve, got %d contributors", result.Contributors)
+	}
+	if result.ActiveContributors != 3 || result.NewContributors != 3 {
+		t.Errorf("Expected carol, dan and eve new in the last 30 days, got %+v", result)
+	}
+}
+
+func TestContributorIdentitiesDisagreeingLogins(t *testing.T) {
+	commits := []Commit{
+		{SHA: "s1", Email: "team@acme.io"},
+		{SHA: "s2", Email: "team@acme.io"},
+	}
+	prs := []PullRequest{
+		{Author: "alice", MergeCommitSHA: "s1"},
+		{Author: "bob", MergeCommitSHA: "s2"},
+	}
+	identities := newContributorIdentities(commits, prs, nil)
+	if author := identities.commitAuthor(Commit{Author: "Team", Email: "team@acme.io"}); author != "team@acme.io" {
+		t.Errorf("Expected a shared email to stay unresolved, got %q", author)
+	}
+	if author := identities.commitAuthor(Commit{Author: "Carol"}); author != "carol" {
+		t.Errorf("Expected the name without an email, got %q", author)
+	}
+}
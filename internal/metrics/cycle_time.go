@@ -0,0 +1,169 @@
+// Package metrics - Pull request cycle time broken down into lifecycle stages
+package metrics
+
+import (
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Pull request lifecycle stages
+const (
+	StageCoding = "coding" // First commit to PR opened
+	StagePickup = "pickup" // PR opened to first review
+	StageReview = "review" // First review to approval
+	StageMerge  = "merge"  // Approval to merge
+	StageDeploy = "deploy" // Merge to production deployment
+)
+
+// CycleTimeStages lists the lifecycle stages in order
+var CycleTimeStages = []string{StageCoding, StagePickup, StageReview, StageMerge, StageDeploy}
+
+// PRCycleTime holds the hours spent in each lifecycle stage of a pull request.
+// Stages missing one of their timestamps are absent from Stages.
+type PRCycleTime struct {
+	Number   int                `json:"number"`
+	OpenedAt time.Time          `json:"opened_at"`
+	MergedAt *time.Time         `json:"merged_at,omitempty"`
+	Stages   map[string]float64 `json:"stages"`
+}
+
+// CycleTimeBucket aggregates stage percentiles for pull requests merged in a period
+type CycleTimeBucket struct {
+	Start  time.Time              `json:"start"`
+	End    time.Time              `json:"end"`
+	Stages []types.CycleTimeStage `json:"stages"`
+}
+
+// calculatePRCycleTimes splits each pull request into lifecycle stages. A merge is
+// deployed by the deployment whose commit range contains the merge commit, or else
+// by the first production deployment after the merge. deployments are production
+// deployments and elapsed measures each stage.
+func calculatePRCycleTimes(prs []PullRequest, deployments []Deployment, commitLeadTimes []CommitLeadTime, elapsed func(start, end time.Time) float64) []PRCycleTime {
+	production := successfulDeployments(deployments)
+	deployedAt := make(map[string]time.Time)
+	for _, leadTime := range commitLeadTimes {
+		deployedAt[leadTime.SHA] = leadTime.DeployedAt
+	}
+
+	var cycles []PRCycleTime
+	for _, pr := range prs {
+		cycle := PRCycleTime{
+			Number:   pr.Number,
+			OpenedAt: pr.CreatedAt,
+			MergedAt: pr.MergedAt,
+			Stages:   make(map[string]float64),
+		}
+		stage := func(name string, start, end *time.Time) {
+			if start == nil || end == nil || start.IsZero() || end.IsZero() {
+				return
+			}
+			if end.Before(*start) {
+				cycle.Stages[name] = 0
+				return
+			}
+			cycle.Stages[name] = elapsed(*start, *end)
+		}
+
+		opened := pr.CreatedAt
+		stage(StageCoding, pr.FirstCommitAt, &opened)
+		stage(StagePickup, &opened, pr.FirstReviewAt)
+		stage(StageReview, pr.FirstReviewAt, pr.ApprovedAt)
+		stage(StageMerge, pr.ApprovedAt, pr.MergedAt)
+
+		if pr.MergedAt != nil {
+			if deployed := prDeployedAt(pr, production, deployedAt); deployed != nil {
+				stage(StageDeploy, pr.MergedAt, deployed)
+			}
+		}
+
+		cycles = append(cycles, cycle)
+	}
+
+	return cycles
+}
+
+// prDeployedAt finds when a merged pull request reached production
+func prDeployedAt(pr PullRequest, production []Deployment, deployedAt map[string]time.Time) *time.Time {
+	if pr.MergeCommitSHA != "" {
+		if t, ok := deployedAt[pr.MergeCommitSHA]; ok {
+			return &t
+		}
+		for _, deployment := range production {
+			if shaMatches(deployment.SHA, pr.MergeCommitSHA) {
+				t := deployment.CreatedAt
+				return &t
+			}
+		}
+	}
+
+	for _, deployment := range production {
+		if !deployment.CreatedAt.Before(*pr.MergedAt) {
+			t := deployment.CreatedAt
+			return &t
+		}
+	}
+	return nil
+}
+
+// summarizeCycleTime computes percentiles for each lifecycle stage with data
+func summarizeCycleTime(cycles []PRCycleTime) []types.CycleTimeStage {
+	var stages []types.CycleTimeStage
+	for _, name := range CycleTimeStages {
+		var hours []float64
+		for _, cycle := range cycles {
+			if h, ok := cycle.Stages[name]; ok {
+				hours = append(hours, h)
+			}
+		}
+		if len(hours) == 0 {
+			continue
+		}
+
+		stats := leadTimeStatsFromHours(hours)
+		stages = append(stages, types.CycleTimeStage{
+			Stage:        name,
+			P50Hours:     stats.P50,
+			P75Hours:     stats.P75,
+			P95Hours:     stats.P95,
+			MeanHours:    stats.Mean,
+			PullRequests: len(hours),
+		})
+	}
+	return stages
+}
+
+// bucketCycleTimes aggregates stage percentiles per period, bucketing pull requests
+// by merge time (or open time when unmerged)
+func bucketCycleTimes(cycles []PRCycleTime, timeRange TimeRange, period time.Duration) []CycleTimeBucket {
+	if period <= 0 || !timeRange.End.After(timeRange.Start) {
+		return nil
+	}
+
+	var buckets []CycleTimeBucket
+	for start := timeRange.Start; start.Before(timeRange.End); start = start.Add(period) {
+		end := start.Add(period)
+		if end.After(timeRange.End) {
+			end = timeRange.End
+		}
+		bucket := TimeRange{Start: start, End: end}
+
+		var inBucket []PRCycleTime
+		for _, cycle := range cycles {
+			at := cycle.OpenedAt
+			if cycle.MergedAt != nil {
+				at = *cycle.MergedAt
+			}
+			if bucket.Contains(at) {
+				inBucket = append(inBucket, cycle)
+			}
+		}
+
+		buckets = append(buckets, CycleTimeBucket{
+			Start:  start,
+			End:    end,
+			Stages: summarizeCycleTime(inBucket),
+		})
+	}
+	return buckets
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+// wallClock measures elapsed wall-clock hours
+func wallClock(start, end time.Time) float64 {
+	return end.Sub(start).Hours()
+}
+
+func TestCalculatePRCycleTimesStages(t *testing.T) {
+	deployments := []Deployment{
+		{ID: 1, Environment: "production", State: "success", SHA: "1111111", CreatedAt: at(20)},
+		{ID: 2, Environment: "production", State: "failure", SHA: "2222222", CreatedAt: at(12)},
+		{ID: 3, Environment: "production", State: "success", SHA: "3333333", CreatedAt: at(40)},
+	}
+	prs := []PullRequest{
+		{
+			Number: 1, FirstCommitAt: timePtr(at(-6)), CreatedAt: at(0),
+			FirstReviewAt: timePtr(at(2)), ApprovedAt: timePtr(at(5)), MergedAt: timePtr(at(8)),
+		},
+		// Open and unreviewed: only coding is known
+		{Number: 2, FirstCommitAt: timePtr(at(1)), CreatedAt: at(3)},
+		// Approved with a stale review timestamp after approval clamps to zero
+		{Number: 3, CreatedAt: at(0), FirstReviewAt: timePtr(at(4)), ApprovedAt: timePtr(at(3))},
+	}
+
+	cycles := calculatePRCycleTimes(prs, deployments, nil, wallClock)
+	if len(cycles) != 3 {
+		t.Fatalf("Expected 3 cycles, got %d", len(cycles))
+	}
+
+	expected := map[string]float64{StageCoding: 6, StagePickup: 2, StageReview: 3, StageMerge: 3, StageDeploy: 12}
+	for stage, hours := range expected {
+		if got, ok := cycles[0].Stages[stage]; !ok || got != hours {
+			t.Errorf("PR 1 %s: expected %.0fh, got %.1f (present %v)", stage, hours, got, ok)
+		}
+	}
+	if len(cycles[1].Stages) != 1 || cycles[1].Stages[StageCoding] != 2 {
+		t.Errorf("Expected only a 2h coding stage for the open PR, got %v", cycles[1].Stages)
+	}
+	if got, ok := cycles[2].Stages[StageReview]; !ok || got != 0 {
+		t.Errorf("Expected a negative review stage to clamp to zero, got %v", cycles[2].Stages)
+	}
+}
+
+func TestPRDeployedAt(t *testing.T) {
+	production := []Deployment{
+		{ID: 1, Environment: "production", State: "success", SHA: "aaaaaaa1111", CreatedAt: at(10)},
+		{ID: 2, Environment: "production", State: "success", SHA: "bbbbbbb2222", CreatedAt: at(30)},
+	}
+	leadTimes := map[string]time.Time{"ccccccc3333": at(50)}
+
+	tests := []struct {
+		name     string
+		pr       PullRequest
+		expected *time.Time
+	}{
+		{"merge commit in a deployed range", PullRequest{MergeCommitSHA: "ccccccc3333", MergedAt: timePtr(at(5))}, timePtr(at(50))},
+		{"merge commit deployed directly", PullRequest{MergeCommitSHA: "bbbbbbb", MergedAt: timePtr(at(5))}, timePtr(at(30))},
+		{"first deployment after merge", PullRequest{MergeCommitSHA: "ddddddd", MergedAt: timePtr(at(11))}, timePtr(at(30))},
+		{"not deployed yet", PullRequest{MergedAt: timePtr(at(31))}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := prDeployedAt(tt.pr, production, leadTimes)
+			if (got == nil) != (tt.expected == nil) || (got != nil && !got.Equal(*tt.expected)) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSummarizeAndBucketCycleTimes(t *testing.T) {
+	cycles := []PRCycleTime{
+		{Number: 1, OpenedAt: at(0), MergedAt: timePtr(at(10)), Stages: map[string]float64{StageCoding: 1, StagePickup: 4}},
+		{Number: 2, OpenedAt: at(1), MergedAt: timePtr(at(30)), Stages: map[string]float64{StageCoding: 3}},
+		{Number: 3, OpenedAt: at(26), Stages: map[string]float64{StageCoding: 5}},
+	}
+
+	stages := summarizeCycleTime(cycles)
+	if len(stages) != 2 || stages[0].Stage != StageCoding || stages[1].Stage != StagePickup {
+		t.Fatalf("Expected coding and pickup in lifecycle order, got %+v", stages)
+	}
+	if stages[0].PullRequests != 3 || stages[0].P50Hours != 3 || stages[0].MeanHours != 3 {
+		t.Errorf("Unexpected coding summary: %+v", stages[0])
+	}
+
+	// Merged PRs are bucketed by merge time, open PRs by open time
+	buckets := bucketCycleTimes(cycles, TimeRange{Start: at(0), End: at(48)}, 24*time.Hour)
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 daily buckets, got %d", len(buckets))
+	}
+	if buckets[0].Stages[0].PullRequests != 1 || buckets[1].Stages[0].PullRequests != 2 {
+		t.Errorf("Expected 1 then 2 pull requests per bucket, got %+v", buckets)
+	}
+	if got := bucketCycleTimes(cycles, TimeRange{Start: at(0), End: at(48)}, 0); got != nil {
+		t.Errorf("Expected no buckets without a period, got %+v", got)
+	}
+}
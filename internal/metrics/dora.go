@@ -4,7 +4,6 @@ package metrics
 import (
 	"context"
 	"fmt"
-	"math"
 	"sort"
 	"time"
 
@@ -13,8 +12,14 @@ import (
 
 // DORACalculator calculates DevOps Research and Assessment metrics
 type DORACalculator struct {
-	githubClient GitHubClient
-	jiraClient   JiraClient
+	githubClient    GitHubClient
+	jiraClient      JiraClient
+	incidentSources []IncidentSource
+	gitClient       GitClient
+	environments    EnvironmentConfig
+	teams           []TeamConfig
+	holidays        HolidayConfig
+	testRuns        TestRunSource
 }
 
 // GitHubClient interface for repository data access
@@ -31,18 +36,30 @@ type JiraClient interface {
 
 // PullRequest represents a GitHub pull request
 type PullRequest struct {
-	Number        int        `json:"number"`
-	Title         string     `json:"title"`
-	State         string     `json:"state"` // open, closed, merged
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
-	MergedAt      *time.Time `json:"merged_at"`
-	ClosedAt      *time.Time `json:"closed_at"`
-	Commits       int        `json:"commits"`
-	Additions     int        `json:"additions"`
-	Deletions     int        `json:"deletions"`
-	ChangedFiles  int        `json:"changed_files"`
-	FirstReviewAt *time.Time `json:"first_review_at"`
+	Number             int                 `json:"number"`
+	Title              string              `json:"title"`
+	State              string              `json:"state"` // open, closed, merged
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	MergedAt           *time.Time          `json:"merged_at"`
+	ClosedAt           *time.Time          `json:"closed_at"`
+	Commits            int                 `json:"commits"`
+	Additions          int                 `json:"additions"`
+	Deletions          int                 `json:"deletions"`
+	ChangedFiles       int                 `json:"changed_files"`
+	FirstReviewAt      *time.Time          `json:"first_review_at"`
+	ApprovedAt         *time.Time          `json:"approved_at,omitempty"`
+	FirstCommitAt      *time.Time          `json:"first_commit_at,omitempty"`
+	Labels             []string            `json:"labels,omitempty"`
+	HeadBranch         string              `json:"head_branch,omitempty"`
+	MergeCommitSHA     string              `json:"merge_commit_sha,omitempty"`
+	Author             string              `json:"author,omitempty"`
+	Reviews            []PullRequestReview `json:"reviews,omitempty"` // Submitted reviews, when the client provides them
+	Draft              bool                `json:"draft,omitempty"`
+	ReadyForReviewAt   *time.Time          `json:"ready_for_review_at,omitempty"` // Last time a draft was marked ready; nil when opened ready
+	URL                string              `json:"url,omitempty"`
+	RequestedReviewers []string            `json:"requested_reviewers,omitempty"`
+	Incomplete         bool                `json:"incomplete,omitempty"` // Sizes, commits or reviews could not be looked up
 }
 
 // Deployment represents a deployment event
@@ -57,24 +74,31 @@ type Deployment struct {
 
 // WorkflowRun represents a CI/CD pipeline run
 type WorkflowRun struct {
-	ID         int       `json:"id"`
-	Name       string    `json:"name"`
-	Status     string    `json:"status"`     // completed, in_progress, queued
-	Conclusion string    `json:"conclusion"` // success, failure, cancelled, skipped
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	SHA        string    `json:"sha"`
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`     // completed, in_progress, queued
+	Conclusion string     `json:"conclusion"` // success, failure, cancelled, skipped
+	CreatedAt  time.Time  `json:"created_at"`
+	QueuedAt   *time.Time `json:"queued_at,omitempty"`  // When this attempt was queued; CreatedAt stays at the first attempt's
+	StartedAt  *time.Time `json:"started_at,omitempty"` // When a runner picked this attempt up, if the provider reports it
+	Attempt    int        `json:"attempt,omitempty"`    // Rerun attempt of the run, starting at 1
+	Event      string     `json:"event,omitempty"`      // What triggered the run: pull_request, push, schedule...
+	UpdatedAt  time.Time  `json:"updated_at"`
+	SHA        string     `json:"sha"`
 }
 
 // Issue represents a Jira issue
 type Issue struct {
 	Key        string     `json:"key"`
+	Summary    string     `json:"summary,omitempty"`
 	Type       string     `json:"type"`
 	Status     string     `json:"status"`
 	Priority   string     `json:"priority"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
 	ResolvedAt *time.Time `json:"resolved_at"`
+	Components []string   `json:"components,omitempty"`
+	Labels     []string   `json:"labels,omitempty"`
 }
 
 // NewDORACalculator creates a new DORA metrics calculator
@@ -85,6 +109,59 @@ func NewDORACalculator(github GitHubClient, jira JiraClient) *DORACalculator {
 	}
 }
 
+// AddIncidentSource registers a source of real production incidents used for MTTR and change
+// failure rate, as EnhancedDORACalculator.AddIncidentSource does
+func (d *DORACalculator) AddIncidentSource(source IncidentSource) {
+	if source == nil {
+		return
+	}
+	d.incidentSources = append(d.incidentSources, source)
+}
+
+// SetGitClient sets the commit source used to detect revert commits after deployments.
+// When it also implements CommitRangeClient, it is used for commit-to-production lead time.
+func (d *DORACalculator) SetGitClient(git GitClient) {
+	d.gitClient = git
+}
+
+// SetEnvironmentConfig sets which deployment environments count as production, by default and per repository
+func (d *DORACalculator) SetEnvironmentConfig(config EnvironmentConfig) {
+	d.environments = config
+}
+
+// SetTeams sets the teams whose repositories report lead time, review latency and MTTR
+// in working hours, when the team has WorkingHoursOnly set
+func (d *DORACalculator) SetTeams(teams []TeamConfig) {
+	d.teams = teams
+}
+
+// SetHolidays sets the holiday calendars skipped by working-hours metrics, per team or repository
+func (d *DORACalculator) SetHolidays(config HolidayConfig) {
+	d.holidays = config
+}
+
+// SetTestRunSource sets where JUnit test results of CI runs come from for flaky test detection
+func (d *DORACalculator) SetTestRunSource(source TestRunSource) {
+	d.testRuns = source
+}
+
+// workingClock measures elapsed time in the working hours of the repository's team,
+// or in wall-clock hours when no team requires working hours
+func (d *DORACalculator) workingClock(repo types.Repository) workingClock {
+	return teamClock(d.teams, d.holidays, repositoryFullName(repo))
+}
+
+// commitRangeClient returns the first configured client able to walk git ancestry
+func (d *DORACalculator) commitRangeClient() CommitRangeClient {
+	if client, ok := d.gitClient.(CommitRangeClient); ok {
+		return client
+	}
+	if client, ok := d.githubClient.(CommitRangeClient); ok {
+		return client
+	}
+	return nil
+}
+
 // Calculate computes DORA metrics for a repository
 func (d *DORACalculator) Calculate(ctx context.Context, repo types.Repository, periodDays int) (*types.DORAMetrics, error) {
 	since := time.Now().AddDate(0, 0, -periodDays)
@@ -105,44 +182,106 @@ func (d *DORACalculator) Calculate(ctx context.Context, repo types.Repository, p
 		return nil, fmt.Errorf("failed to get workflow runs: %w", err)
 	}
 
+	// Optional sources degrade to warnings, as in the enhanced calculator
+	var warnings []string
+	var testRuns []TestRun
+	if d.testRuns != nil {
+		testRuns, err = d.testRuns.GetTestRuns(ctx, repo.Owner, repo.Name, since)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("test reports unavailable, flaky tests not detected: %v", err))
+		}
+	}
+
+	// Only production deployments count, so staging deploys do not inflate frequency or MTTR
+	matcher := newEnvironmentMatcher(d.environments.PatternsFor(repositoryFullName(repo)))
+	production := matcher.production(deployments)
+
+	if warning := incompletePullRequestsWarning(prs); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	// Lead time runs from commit authored to the production deployment containing it;
+	// PR open-to-merge is kept as merge time and used when commit ancestry is unavailable
+	clock := d.workingClock(repo)
+	mergeTime := leadTimeStatsFromHours(clock.mergeTimes(prs))
+	leadTime := mergeTime
+	var commitLeadTimes []CommitLeadTime
+	if client := d.commitRangeClient(); client != nil {
+		commitLeadTimes, err = collectCommitLeadTimes(ctx, client, repo.Owner, repo.Name, production)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("commit lead time unavailable, using PR merge time: %v", err))
+		} else {
+			commitLeadTimes = environmentLeadTimes(commitLeadTimes, environmentNames(production))
+			if len(commitLeadTimes) > 0 {
+				leadTime = leadTimeStatsFromHours(clock.leadTimeHours(commitLeadTimes))
+			}
+		}
+	}
+
+	// Break PR lifecycles into stages to show where lead time goes
+	cycles := calculatePRCycleTimes(prs, production, commitLeadTimes, clock.hours)
+
 	// Calculate each DORA metric
-	leadTime := d.calculateLeadTimeP95(prs)
-	deployFreq := d.calculateDeploymentFrequency(deployments, periodDays)
+	deployFreq := d.calculateDeploymentFrequency(production, periodDays)
 	changeFailRate := d.calculateChangeFailureRate(workflows)
-	mttr := d.calculateMTTR(workflows, deployments)
+	mttr := d.calculateMTTR(workflows, production, clock)
+
+	// Prefer real incidents over failed workflow runs when sources provide them
+	var incidents []Incident
+	if incidentMode(d.incidentSources, repo.Owner, repo.Name) {
+		var incidentWarnings []string
+		incidents, incidentWarnings = collectIncidents(ctx, d.incidentSources, repo.Owner, repo.Name, since)
+		warnings = append(warnings, incidentWarnings...)
+		incidents = matcher.productionIncidents(incidents)
+		changeFailRate = 0
+		mttr = calculateIncidentMTTR(incidents, clock.hours)
+	}
+
+	// Attribute reverts, hotfixes, rollbacks and incidents to the deployments that caused them
+	if len(successfulDeployments(production)) > 0 {
+		var commits []Commit
+		if d.gitClient != nil {
+			commits, err = d.gitClient.GetCommits(ctx, repo.Owner, repo.Name, since)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("commit history unavailable, reverts not detected: %v", err))
+			}
+		}
+		failures := attributeChangeFailures(production, prs, commits, incidents, DefaultChangeFailureRules())
+		changeFailRate = calculateAttributedChangeFailureRate(production, failures)
+	}
 
 	return &types.DORAMetrics{
-		LeadTimeP95Hours:        leadTime,
+		LeadTimeP50Hours:        leadTime.P50,
+		LeadTimeP75Hours:        leadTime.P75,
+		LeadTimeP95Hours:        leadTime.P95,
+		MergeTimeP95Hours:       mergeTime.P95,
 		DeploymentFrequencyWeek: deployFreq,
 		ChangeFailRatePercent:   changeFailRate,
 		MTTRHours:               mttr,
+		CycleTime:               summarizeCycleTime(cycles),
+		ReviewQuality:           CalculateReviewQuality(prs, DefaultReviewQualityConfig()),
+		BatchSize:               CalculateBatchSize(prs, clock.hours),
+		Pipelines:               CalculatePipelineStats(workflows, DefaultPipelineConfig()),
+		Flakiness:               DetectFlakiness(workflows, testRuns),
 		Period:                  periodDays,
 		CalculatedAt:            time.Now(),
+		Warnings:                warnings,
 	}, nil
 }
 
-// calculateLeadTimeP95 calculates the 95th percentile lead time in hours
-func (d *DORACalculator) calculateLeadTimeP95(prs []PullRequest) float64 {
-	var leadTimes []float64
-
+// incompletePullRequestsWarning reports pull requests whose sizes, commits or reviews are
+// missing, or returns an empty string when there are none
+func incompletePullRequestsWarning(prs []PullRequest) string {
+	incomplete := 0
 	for _, pr := range prs {
-		if pr.MergedAt != nil {
-			leadTime := pr.MergedAt.Sub(pr.CreatedAt).Hours()
-			leadTimes = append(leadTimes, leadTime)
+		if pr.Incomplete {
+			incomplete++
 		}
 	}
-
-	if len(leadTimes) == 0 {
-		return 0
+	if incomplete == 0 {
+		return ""
 	}
-
-	sort.Float64s(leadTimes)
-	p95Index := int(math.Ceil(0.95*float64(len(leadTimes)))) - 1
-	if p95Index >= len(leadTimes) {
-		p95Index = len(leadTimes) - 1
-	}
-
-	return leadTimes[p95Index]
+	return fmt.Sprintf("details unavailable for %d of %d pull requests, batch size and review quality are partial", incomplete, len(prs))
 }
 
 // calculateDeploymentFrequency calculates deployments per week
@@ -184,7 +323,7 @@ func (d *DORACalculator) calculateChangeFailureRate(workflows []WorkflowRun) flo
 }
 
 // calculateMTTR calculates Mean Time To Recovery in hours
-func (d *DORACalculator) calculateMTTR(workflows []WorkflowRun, deployments []Deployment) float64 {
+func (d *DORACalculator) calculateMTTR(workflows []WorkflowRun, deployments []Deployment, clock workingClock) float64 {
 	var recoveryTimes []float64
 
 	// Find failure -> success patterns in workflows
@@ -200,7 +339,7 @@ func (d *DORACalculator) calculateMTTR(workflows []WorkflowRun, deployments []De
 			if run.Conclusion == "failure" {
 				lastFailureTime = &run.UpdatedAt
 			} else if run.Conclusion == "success" && lastFailureTime != nil {
-				recoveryTime := run.UpdatedAt.Sub(*lastFailureTime).Hours()
+				recoveryTime := clock.hours(*lastFailureTime, run.UpdatedAt)
 				recoveryTimes = append(recoveryTimes, recoveryTime)
 				lastFailureTime = nil // Reset
 			}
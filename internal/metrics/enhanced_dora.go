@@ -4,8 +4,7 @@ package metrics
 import (
 	"context"
 	"fmt"
-	"math"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/types"
@@ -18,6 +17,9 @@ type EnhancedDORACalculator struct {
 	cache        *CacheMiddleware
 	timeUtils    *TimeUtils
 	config       DORAConfig
+	incidentSources []IncidentSource
+	gitClient    GitClient
+	testRuns     TestRunSource
 }
 
 // DORAConfig configures the enhanced DORA calculator
@@ -31,6 +33,17 @@ type DORAConfig struct {
 	CacheEnabled          bool          `json:"cache_enabled"`
 	DefaultCacheTTL       time.Duration `json:"default_cache_ttl"`
 	MaxDataPoints         int           `json:"max_data_points"`
+	HotfixLabels          []string      `json:"hotfix_labels"`
+	HotfixBranchPrefixes  []string      `json:"hotfix_branch_prefixes"`
+	FailureAttributionWindow time.Duration `json:"failure_attribution_window"` // Max time from deployment to an unlinked revert or hotfix
+	Environments          EnvironmentConfig `json:"environments"` // Production environment patterns, by default and per repository
+	Benchmarks            DORABenchmarks    `json:"benchmarks"`   // Performance band thresholds, State of DevOps by default
+	Anomaly               AnomalyConfig     `json:"anomaly"`      // Anomaly detection over the time series
+	Forecast              ForecastConfig    `json:"forecast"`     // Trend projection of the time series
+	Teams                 []TeamConfig      `json:"teams"`        // Working hours per team, matched by repository
+	Holidays              HolidayConfig     `json:"holidays"`     // Holiday calendars per team or repository
+	ReviewQuality         ReviewQualityConfig `json:"review_quality"` // Rubber-stamp threshold for review analytics
+	Pipelines             PipelineConfig      `json:"pipelines"`      // Developer wait estimate for CI analytics
 }
 
 // NewEnhancedDORACalculator creates a new enhanced DORA calculator
@@ -59,6 +72,25 @@ func NewEnhancedDORACalculator(
 	if config.MaxDataPoints == 0 {
 		config.MaxDataPoints = 1000
 	}
+	defaultRules := DefaultChangeFailureRules()
+	if len(config.HotfixLabels) == 0 {
+		config.HotfixLabels = defaultRules.HotfixLabels
+	}
+	if len(config.HotfixBranchPrefixes) == 0 {
+		config.HotfixBranchPrefixes = defaultRules.HotfixBranchPrefixes
+	}
+	if config.FailureAttributionWindow == 0 {
+		config.FailureAttributionWindow = defaultRules.AttributionWindow
+	}
+	config.Benchmarks = config.Benchmarks.WithDefaults()
+	config.Anomaly = config.Anomaly.withDefaults()
+	config.Forecast = config.Forecast.withDefaults()
+	if config.ReviewQuality.LargeDiffLines == 0 {
+		config.ReviewQuality = DefaultReviewQualityConfig()
+	}
+	if config.Pipelines.WaitFactor == 0 {
+		config.Pipelines = DefaultPipelineConfig()
+	}
 
 	return &EnhancedDORACalculator{
 		githubClient:  githubClient,
@@ -69,6 +101,33 @@ func NewEnhancedDORACalculator(
 	}
 }
 
+// AddIncidentSource registers a source of real production incidents.
+// When a source other than an IncidentStore is registered, or the store holds incidents of
+// the repository, MTTR and change failure rate are computed from incident open/resolve
+// timestamps instead of failed workflow runs.
+func (edc *EnhancedDORACalculator) AddIncidentSource(source IncidentSource) {
+	if source == nil {
+		return
+	}
+	edc.incidentSources = append(edc.incidentSources, source)
+}
+
+// Benchmarks returns the performance band thresholds used to classify DORA metrics
+func (edc *EnhancedDORACalculator) Benchmarks() DORABenchmarks {
+	return edc.config.Benchmarks
+}
+
+// SetGitClient sets the commit source used to detect revert commits after deployments.
+// When it also implements CommitRangeClient, it is used for commit-to-production lead time.
+func (edc *EnhancedDORACalculator) SetGitClient(git GitClient) {
+	edc.gitClient = git
+}
+
+// SetTestRunSource sets where JUnit test results of CI runs come from for flaky test detection
+func (edc *EnhancedDORACalculator) SetTestRunSource(source TestRunSource) {
+	edc.testRuns = source
+}
+
 // Calculate computes enhanced DORA metrics
 func (edc *EnhancedDORACalculator) Calculate(ctx context.Context, request MetricsRequest) (*EnhancedDORAMetrics, error) {
 	// Use cache if enabled
@@ -116,43 +175,144 @@ func (edc *EnhancedDORACalculator) calculateInternal(ctx context.Context, reques
 		return nil, fmt.Errorf("failed to get data: %w", err)
 	}
 
+	// Get real incidents when incident sources provide them
+	var incidents []Incident
+	var warnings []string
+	useIncidents := incidentMode(edc.incidentSources, repo.Owner, repo.Name)
+	if useIncidents {
+		incidents, warnings = collectIncidents(ctx, edc.incidentSources, repo.Owner, repo.Name, timeRange.Start)
+	}
+
+	// Get commits to detect reverts when a git client is configured
+	var commits []Commit
+	if edc.gitClient != nil {
+		commits, err = edc.gitClient.GetCommits(ctx, repo.Owner, repo.Name, timeRange.Start)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("commit history unavailable, reverts not detected: %v", err))
+		}
+	}
+
+	// Get test results to find flaky tests when a test run source is configured
+	var testRuns []TestRun
+	if edc.testRuns != nil {
+		testRuns, err = edc.testRuns.GetTestRuns(ctx, repo.Owner, repo.Name, timeRange.Start)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("test reports unavailable, flaky tests not detected: %v", err))
+		}
+	}
+	if warning := incompletePullRequestsWarning(pullRequests); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	// Only production deployments feed the headline metrics; every environment gets its own breakdown
+	matcher := edc.productionMatcher(request)
+	production := matcher.production(deployments)
+	if matcher.explicit && len(production) == 0 && len(deployments) > 0 {
+		warnings = append(warnings, fmt.Sprintf("no deployments matched production environments %v", matcher.patterns))
+	}
+	productionIncidents := matcher.productionIncidents(incidents)
+
+	// Link reverts, hotfixes, rollbacks and incidents to the deployments that caused them
+	changeFailures := attributeChangeFailures(production, pullRequests, commits, productionIncidents, edc.changeFailureRules())
+
+	// Lead time runs from commit authored to the production deployment containing it;
+	// PR open-to-merge is kept as merge time and used when commit ancestry is unavailable
+	clock, schedule, team := edc.workingClock(request)
+	if request.Team != "" && !strings.EqualFold(request.Team, team) {
+		warnings = append(warnings, fmt.Sprintf("team %s is not configured, using default working hours", request.Team))
+	}
+	mergeTime := leadTimeStatsFromHours(clock.mergeTimes(pullRequests))
+	leadTime := mergeTime
+	var commitLeadTimes []CommitLeadTime
+	environmentCommitLeadTimes, err := edc.calculateCommitLeadTimes(ctx, repo, deployments)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("commit lead time unavailable, using PR merge time: %v", err))
+	} else {
+		commitLeadTimes = environmentLeadTimes(environmentCommitLeadTimes, environmentNames(production))
+		if len(commitLeadTimes) > 0 {
+			leadTime = leadTimeStatsFromHours(clock.leadTimeHours(commitLeadTimes))
+		}
+	}
+
+	// Break PR lifecycles into stages to show where lead time goes
+	cycles := calculatePRCycleTimes(pullRequests, production, commitLeadTimes, clock.hours)
+	cycleTimeTrend := bucketCycleTimes(cycles, timeRange, edc.bucketDuration(request.Granularity))
+	batchSizeTrend := bucketBatchSize(pullRequests, timeRange, edc.bucketDuration(request.Granularity))
+	pipelineTrend := bucketPipelineStats(workflowRuns, timeRange, edc.bucketDuration(request.Granularity))
+
 	// Calculate basic DORA metrics
-	leadTimeP95 := edc.calculateEnhancedLeadTime(pullRequests, timeRange)
-	deploymentFreq := edc.calculateEnhancedDeploymentFrequency(deployments, timeRange)
-	changeFailureRate := edc.calculateEnhancedChangeFailureRate(workflowRuns, deployments)
-	mttr := edc.calculateEnhancedMTTR(workflowRuns, deployments, timeRange)
+	deploymentFreq := edc.calculateEnhancedDeploymentFrequency(production, timeRange)
+	changeFailureRate := edc.calculateEnhancedChangeFailureRate(workflowRuns, production)
+	mttr := edc.calculateEnhancedMTTR(workflowRuns, production, clock)
+	if useIncidents {
+		changeFailureRate = 0
+		mttr = calculateIncidentMTTR(productionIncidents, clock.hours)
+	}
+	if len(successfulDeployments(production)) > 0 {
+		changeFailureRate = calculateAttributedChangeFailureRate(production, changeFailures)
+	}
 
 	// Calculate additional metrics
-	incidentCount, failedDeployments := edc.analyzeIncidents(workflowRuns, deployments)
-	deploymentTrends := edc.calculateDeploymentTrends(deployments, timeRange)
-	timeSeries := edc.generateTimeSeries(pullRequests, deployments, workflowRuns, timeRange)
-	incidentBreakdown := edc.classifyIncidents(workflowRuns, deployments, timeRange)
+	incidentCount, failedDeployments := edc.analyzeIncidents(workflowRuns, production)
+	deploymentTrends := edc.calculateDeploymentTrends(production, timeRange)
+	timeSeries := edc.generateTimeSeries(pullRequests, production, workflowRuns, commitLeadTimes, timeRange, clock)
+	incidentBreakdown := edc.classifyIncidents(workflowRuns, production, timeRange)
+	if useIncidents {
+		incidentCount = len(productionIncidents)
+		incidentBreakdown = edc.classifySourcedIncidents(productionIncidents, changeFailures)
+	}
+	incidentBreakdown = append(incidentBreakdown, edc.classifyChangeFailures(changeFailures)...)
+	environmentBreakdown := edc.calculateEnvironmentBreakdown(deployments, production, pullRequests, commits, incidents, useIncidents, environmentCommitLeadTimes, timeRange, clock)
 
 	// Calculate confidence and data quality
 	confidence := edc.calculateConfidence(pullRequests, deployments, workflowRuns)
 	dataQuality := edc.assessDataQuality(pullRequests, deployments, workflowRuns, timeRange)
+	dataQuality.QualityWarnings = append(dataQuality.QualityWarnings, warnings...)
+
+	doraMetrics := types.DORAMetrics{
+		LeadTimeP50Hours:        leadTime.P50,
+		LeadTimeP75Hours:        leadTime.P75,
+		LeadTimeP95Hours:        leadTime.P95,
+		MergeTimeP95Hours:       mergeTime.P95,
+		DeploymentFrequencyWeek: deploymentFreq,
+		ChangeFailRatePercent:   changeFailureRate,
+		MTTRHours:               mttr,
+		CycleTime:               summarizeCycleTime(cycles),
+		ReviewQuality:           CalculateReviewQuality(pullRequests, edc.config.ReviewQuality),
+		BatchSize:               CalculateBatchSize(pullRequests, clock.hours),
+		Pipelines:               CalculatePipelineStats(workflowRuns, edc.config.Pipelines),
+		Flakiness:               DetectFlakiness(workflowRuns, testRuns),
+		Period:                  int(timeRange.Duration().Hours() / 24),
+		CalculatedAt:            time.Now(),
+	}
 
 	// Create enhanced metrics
 	enhanced := &EnhancedDORAMetrics{
-		DORAMetrics: types.DORAMetrics{
-			LeadTimeP95Hours:        leadTimeP95,
-			DeploymentFrequencyWeek: deploymentFreq,
-			ChangeFailRatePercent:   changeFailureRate,
-			MTTRHours:               mttr,
-			Period:                  int(timeRange.Duration().Hours() / 24),
-			CalculatedAt:            time.Now(),
-		},
+		DORAMetrics:            doraMetrics,
+		Performance:            edc.config.Benchmarks.Classify(doraMetrics),
 		TimeRange:              timeRange,
 		Granularity:            request.Granularity,
 		Timezone:               timeRange.Timezone,
 		IncidentCount:          incidentCount,
 		FailedDeployments:      failedDeployments,
-		TotalDeployments:       len(deployments),
-		MeanLeadTimeHours:      edc.calculateMeanLeadTime(pullRequests),
-		MedianLeadTimeHours:    edc.calculateMedianLeadTime(pullRequests),
+		TotalDeployments:       len(production),
+		MeanLeadTimeHours:      leadTime.Mean,
+		MedianLeadTimeHours:    leadTime.P50,
+		LeadTime:               leadTime,
+		MergeTime:              mergeTime,
+		CycleTimeTrend:         cycleTimeTrend,
+		BatchSizeTrend:         batchSizeTrend,
+		PipelineTrend:          pipelineTrend,
 		TimeSeries:             timeSeries,
+		Anomalies:              DetectDORAAnomalies(timeSeries, edc.config.Anomaly),
+		Forecasts:              ForecastDORA(timeSeries, edc.config.Forecast),
 		IncidentBreakdown:      incidentBreakdown,
+		ChangeFailures:         changeFailures,
 		DeploymentTrends:       deploymentTrends,
+		ProductionEnvironments: matcher.patterns,
+		EnvironmentBreakdown:   environmentBreakdown,
+		Team:                   team,
+		WorkingHours:           schedule,
 		Confidence:             confidence,
 		DataQuality:            dataQuality,
 		CacheInfo: CacheInfo{
@@ -204,45 +364,70 @@ func (edc *EnhancedDORACalculator) getDataViaREST(ctx context.Context, repo type
 
 // Enhanced calculation methods
 
-func (edc *EnhancedDORACalculator) calculateEnhancedLeadTime(pullRequests []PullRequest, timeRange TimeRange) float64 {
-	var leadTimes []float64
+// calculateCommitLeadTimes links commits to the first deployment of each environment that shipped them
+func (edc *EnhancedDORACalculator) calculateCommitLeadTimes(ctx context.Context, repo types.Repository, deployments []Deployment) ([]CommitLeadTime, error) {
+	client := edc.commitRangeClient()
+	if client == nil {
+		return nil, nil
+	}
+	return collectCommitLeadTimes(ctx, client, repo.Owner, repo.Name, deployments)
+}
 
-	for _, pr := range pullRequests {
-		if pr.MergedAt == nil {
-			continue
-		}
+// productionMatcher resolves production environments from the request, then the repository config, then the defaults
+func (edc *EnhancedDORACalculator) productionMatcher(request MetricsRequest) environmentMatcher {
+	return newEnvironmentMatcher(request.ProductionEnvironments, edc.config.Environments.PatternsFor(repositoryFullName(request.Repository)))
+}
 
-		// Calculate lead time considering business hours if configured
-		var leadTime float64
-		if edc.config.ExcludeWeekends {
-			workingHours, err := edc.timeUtils.CalculateWorkingHours(
-				pr.CreatedAt, *pr.MergedAt, timeRange.Timezone,
-				edc.config.BusinessHoursStart, edc.config.BusinessHoursEnd, true,
-			)
-			if err == nil {
-				leadTime = workingHours
-			} else {
-				leadTime = pr.MergedAt.Sub(pr.CreatedAt).Hours()
-			}
-		} else {
-			leadTime = pr.MergedAt.Sub(pr.CreatedAt).Hours()
-		}
+// workingClock resolves how elapsed times are measured for a request. Working hours apply when
+// the request asks for them, the repository's team always uses them, or ExcludeWeekends is set;
+// the team's working day and timezone override the configured business hours, and the
+// repository's or team's holiday calendar removes holidays.
+func (edc *EnhancedDORACalculator) workingClock(request MetricsRequest) (workingClock, *WorkingHours, string) {
+	team, hasTeam := TeamFor(edc.config.Teams, request.Team, repositoryFullName(request.Repository))
+	teamName := ""
+	if hasTeam {
+		teamName = team.Name
+	}
 
-		leadTimes = append(leadTimes, leadTime)
+	if !request.WorkingHours && !edc.config.ExcludeWeekends && !(hasTeam && team.WorkingHoursOnly) {
+		return newWorkingClock(edc.timeUtils, nil, nil), nil, teamName
 	}
 
-	if len(leadTimes) == 0 {
-		return 0
+	schedule := WorkingHours{
+		Timezone:  request.TimeRange.Timezone,
+		StartHour: edc.config.BusinessHoursStart,
+		EndHour:   edc.config.BusinessHoursEnd,
+	}
+	if hasTeam {
+		schedule = team.WorkingHours.withDefaults(schedule)
+	}
+
+	holidays := edc.config.Holidays.CalendarFor(schedule.HolidayCalendar, repositoryFullName(request.Repository))
+	schedule.HolidayCalendar = ""
+	if holidays != nil {
+		schedule.HolidayCalendar = holidays.Name
 	}
+	return newWorkingClock(edc.timeUtils, &schedule, holidays), &schedule, teamName
+}
 
-	// Calculate P95
-	sort.Float64s(leadTimes)
-	index := int(math.Ceil(0.95 * float64(len(leadTimes))))
-	if index >= len(leadTimes) {
-		index = len(leadTimes) - 1
+// bucketDuration returns the trend bucket size for a granularity, defaulting to a week
+func (edc *EnhancedDORACalculator) bucketDuration(granularity string) time.Duration {
+	duration, err := edc.timeUtils.GetPeriodDuration(granularity)
+	if err != nil || duration < 24*time.Hour {
+		return 7 * 24 * time.Hour
 	}
+	return duration
+}
 
-	return leadTimes[index]
+// commitRangeClient returns the first configured client able to walk git ancestry
+func (edc *EnhancedDORACalculator) commitRangeClient() CommitRangeClient {
+	if client, ok := edc.gitClient.(CommitRangeClient); ok {
+		return client
+	}
+	if client, ok := edc.githubClient.(CommitRangeClient); ok {
+		return client
+	}
+	return nil
 }
 
 func (edc *EnhancedDORACalculator) calculateEnhancedDeploymentFrequency(deployments []Deployment, timeRange TimeRange) float64 {
@@ -293,7 +478,7 @@ func (edc *EnhancedDORACalculator) calculateEnhancedChangeFailureRate(workflowRu
 	return (float64(failures) / float64(totalChanges)) * 100
 }
 
-func (edc *EnhancedDORACalculator) calculateEnhancedMTTR(workflowRuns []WorkflowRun, deployments []Deployment, timeRange TimeRange) float64 {
+func (edc *EnhancedDORACalculator) calculateEnhancedMTTR(workflowRuns []WorkflowRun, deployments []Deployment, clock workingClock) float64 {
 	var recoveryTimes []float64
 
 	// Analyze workflow runs for recovery patterns
@@ -306,7 +491,7 @@ func (edc *EnhancedDORACalculator) calculateEnhancedMTTR(workflowRuns []Workflow
 		for j := i + 1; j < len(workflowRuns); j++ {
 			nextRun := workflowRuns[j]
 			if nextRun.Conclusion == "success" {
-				recoveryTime := nextRun.UpdatedAt.Sub(run.UpdatedAt).Hours()
+				recoveryTime := clock.hours(run.UpdatedAt, nextRun.UpdatedAt)
 				if recoveryTime > 0 && recoveryTime < edc.config.IncidentThresholdHours*24 { // Max 24x threshold
 					recoveryTimes = append(recoveryTimes, recoveryTime)
 				}
@@ -325,7 +510,7 @@ func (edc *EnhancedDORACalculator) calculateEnhancedMTTR(workflowRuns []Workflow
 		for j := i + 1; j < len(deployments); j++ {
 			nextDeployment := deployments[j]
 			if nextDeployment.State == "success" {
-				recoveryTime := nextDeployment.UpdatedAt.Sub(deployment.UpdatedAt).Hours()
+				recoveryTime := clock.hours(deployment.UpdatedAt, nextDeployment.UpdatedAt)
 				if recoveryTime > 0 && recoveryTime < edc.config.IncidentThresholdHours*24 {
 					recoveryTimes = append(recoveryTimes, recoveryTime)
 				}
@@ -424,13 +609,31 @@ func (edc *EnhancedDORACalculator) calculateDeploymentTrends(deployments []Deplo
 	return trends
 }
 
-func (edc *EnhancedDORACalculator) generateTimeSeries(pullRequests []PullRequest, deployments []Deployment, workflowRuns []WorkflowRun, timeRange TimeRange) []DORATimeSeriesPoint {
+// generateTimeSeries builds daily points. Lead time is the median commit-to-production time of
+// the commits deployed that day, or of PR open-to-merge time when commit ancestry is unavailable.
+func (edc *EnhancedDORACalculator) generateTimeSeries(pullRequests []PullRequest, deployments []Deployment, workflowRuns []WorkflowRun, commitLeadTimes []CommitLeadTime, timeRange TimeRange, clock workingClock) []DORATimeSeriesPoint {
 	// Generate time series based on granularity
 	periods, err := edc.timeUtils.GetPeriodBoundaries(timeRange.End, "day", timeRange.Timezone, int(timeRange.Duration().Hours()/24))
 	if err != nil {
 		return nil
 	}
 
+	type leadTimeSample struct {
+		at    time.Time
+		hours float64
+	}
+	var samples []leadTimeSample
+	for _, leadTime := range commitLeadTimes {
+		samples = append(samples, leadTimeSample{leadTime.DeployedAt, clock.hours(leadTime.AuthoredAt, leadTime.DeployedAt)})
+	}
+	if len(samples) == 0 {
+		for _, pr := range pullRequests {
+			if pr.MergedAt != nil {
+				samples = append(samples, leadTimeSample{*pr.MergedAt, clock.hours(pr.CreatedAt, *pr.MergedAt)})
+			}
+		}
+	}
+
 	var timeSeries []DORATimeSeriesPoint
 	for _, period := range periods {
 		point := DORATimeSeriesPoint{
@@ -438,14 +641,13 @@ func (edc *EnhancedDORACalculator) generateTimeSeries(pullRequests []PullRequest
 		}
 
 		// Calculate metrics for this time period
-		for _, pr := range pullRequests {
-			if pr.MergedAt != nil && period.Contains(*pr.MergedAt) {
-				leadTime := pr.MergedAt.Sub(pr.CreatedAt).Hours()
-				if point.LeadTimeHours == 0 || leadTime > point.LeadTimeHours {
-					point.LeadTimeHours = leadTime
-				}
+		var leadTimes []float64
+		for _, sample := range samples {
+			if period.Contains(sample.at) {
+				leadTimes = append(leadTimes, sample.hours)
 			}
 		}
+		point.LeadTimeHours = leadTimeStatsFromHours(leadTimes).P50
 
 		for _, deployment := range deployments {
 			if period.Contains(deployment.CreatedAt) {
@@ -516,46 +718,102 @@ func (edc *EnhancedDORACalculator) classifyIncidents(workflowRuns []WorkflowRun,
 	return incidents
 }
 
-// Helper methods
-
-func (edc *EnhancedDORACalculator) calculateMeanLeadTime(pullRequests []PullRequest) float64 {
-	if len(pullRequests) == 0 {
-		return 0
+// classifySourcedIncidents groups real incidents by severity with resolution times
+// and the deployments they were attributed to
+func (edc *EnhancedDORACalculator) classifySourcedIncidents(incidents []Incident, failures []ChangeFailure) []IncidentClassification {
+	causedBy := make(map[string]string)
+	for _, failure := range failures {
+		if failure.Cause == ChangeFailureIncident {
+			causedBy[failure.Reference] = failureDeploymentKey(failure)
+		}
 	}
 
-	total := 0.0
-	count := 0
-	for _, pr := range pullRequests {
-		if pr.MergedAt != nil {
-			total += pr.MergedAt.Sub(pr.CreatedAt).Hours()
-			count++
+	incidentMap := make(map[string]*IncidentClassification)
+	resolvedCount := make(map[string]int)
+	var order []string
+
+	for _, incident := range incidents {
+		severity := incident.Severity
+		if severity == "" {
+			severity = edc.determineSeverity(incident.ResolutionTime())
+		}
+
+		classification, exists := incidentMap[severity]
+		if !exists {
+			classification = &IncidentClassification{
+				Type:     "outage",
+				Severity: severity,
+			}
+			incidentMap[severity] = classification
+			order = append(order, severity)
+		}
+
+		classification.Count++
+		if incident.IsResolved() {
+			resolvedCount[severity]++
+			classification.MeanResolutionTime += incident.ResolutionTime()
+			classification.TotalDowntimeHours += incident.ResolutionTime().Hours()
+		}
+		if sha := causedBy[incident.Source+":"+incident.ID]; sha != "" {
+			classification.AffectedDeployments = append(classification.AffectedDeployments, sha)
 		}
 	}
 
-	if count == 0 {
-		return 0
+	var classifications []IncidentClassification
+	for _, severity := range order {
+		classification := incidentMap[severity]
+		if resolved := resolvedCount[severity]; resolved > 0 {
+			classification.MeanResolutionTime /= time.Duration(resolved)
+		}
+		classifications = append(classifications, *classification)
 	}
-	return total / float64(count)
+
+	return classifications
 }
 
-func (edc *EnhancedDORACalculator) calculateMedianLeadTime(pullRequests []PullRequest) float64 {
-	var leadTimes []float64
-	for _, pr := range pullRequests {
-		if pr.MergedAt != nil {
-			leadTimes = append(leadTimes, pr.MergedAt.Sub(pr.CreatedAt).Hours())
+// classifyChangeFailures groups reverts, hotfixes and rollbacks with the deployments they were
+// attributed to. Resolution time is measured from the deployment to its remediation.
+func (edc *EnhancedDORACalculator) classifyChangeFailures(failures []ChangeFailure) []IncidentClassification {
+	incidentMap := make(map[string]*IncidentClassification)
+	var order []string
+
+	for _, failure := range failures {
+		if failure.Cause == ChangeFailureIncident {
+			continue
 		}
+
+		classification, exists := incidentMap[failure.Cause]
+		if !exists {
+			classification = &IncidentClassification{Type: failure.Cause}
+			incidentMap[failure.Cause] = classification
+			order = append(order, failure.Cause)
+		}
+
+		classification.Count++
+		classification.MeanResolutionTime += failure.DetectedAt.Sub(failure.DeployedAt)
+		classification.AffectedDeployments = append(classification.AffectedDeployments, failureDeploymentKey(failure))
 	}
 
-	if len(leadTimes) == 0 {
-		return 0
+	var classifications []IncidentClassification
+	for _, cause := range order {
+		classification := incidentMap[cause]
+		classification.MeanResolutionTime /= time.Duration(classification.Count)
+		classification.Severity = edc.determineSeverity(classification.MeanResolutionTime)
+		classifications = append(classifications, *classification)
 	}
 
-	sort.Float64s(leadTimes)
-	mid := len(leadTimes) / 2
-	if len(leadTimes)%2 == 0 {
-		return (leadTimes[mid-1] + leadTimes[mid]) / 2
+	return classifications
+}
+
+// Helper methods
+
+// changeFailureRules builds change failure attribution rules from the calculator config
+func (edc *EnhancedDORACalculator) changeFailureRules() ChangeFailureRules {
+	return ChangeFailureRules{
+		HotfixLabels:         edc.config.HotfixLabels,
+		HotfixBranchPrefixes: edc.config.HotfixBranchPrefixes,
+		AttributionWindow:    edc.config.FailureAttributionWindow,
 	}
-	return leadTimes[mid]
 }
 
 func (edc *EnhancedDORACalculator) calculateConfidence(pullRequests []PullRequest, deployments []Deployment, workflowRuns []WorkflowRun) float64 {
@@ -640,6 +898,15 @@ func (edc *EnhancedDORACalculator) getDataSources() []string {
 	if edc.config.EnableGraphQL {
 		sources = append(sources, "github_graphql_api")
 	}
+	if edc.gitClient != nil {
+		sources = append(sources, "git")
+	}
+	if edc.testRuns != nil {
+		sources = append(sources, "junit")
+	}
+	for _, source := range edc.incidentSources {
+		sources = append(sources, "incidents:"+source.Name())
+	}
 	return sources
 }
 
@@ -662,11 +929,44 @@ func (edc *EnhancedDORACalculator) convertGraphQLPullRequests(graphqlPRs []Graph
 			ChangedFiles:  gpr.ChangedFiles,
 		}
 
-		// Get first review time if available
+		// Get first review and approval times if available
 		if len(gpr.Reviews.Nodes) > 0 {
 			firstReview := gpr.Reviews.Nodes[0].SubmittedAt
 			pr.FirstReviewAt = &firstReview
 		}
+		for _, review := range gpr.Reviews.Nodes {
+			if review.State == "APPROVED" {
+				approved := review.SubmittedAt
+				pr.ApprovedAt = &approved
+				break
+			}
+		}
+		if len(gpr.Commits.Nodes) > 0 && !gpr.Commits.Nodes[0].Commit.AuthoredDate.IsZero() {
+			firstCommit := gpr.Commits.Nodes[0].Commit.AuthoredDate
+			pr.FirstCommitAt = &firstCommit
+		}
+		pr.Author = gpr.Author.Login
+		for _, review := range gpr.Reviews.Nodes {
+			prReview := PullRequestReview{
+				Reviewer:    review.Author.Login,
+				State:       review.State,
+				SubmittedAt: review.SubmittedAt,
+				Comments:    review.Comments.TotalCount,
+				BodyLength:  len(strings.TrimSpace(review.Body)),
+			}
+			if len(review.Comments.Nodes) > 0 {
+				started := review.Comments.Nodes[0].CreatedAt
+				prReview.StartedAt = &started
+			}
+			pr.Reviews = append(pr.Reviews, prReview)
+		}
+		for _, label := range gpr.Labels.Nodes {
+			pr.Labels = append(pr.Labels, label.Name)
+		}
+		pr.HeadBranch = gpr.HeadRefName
+		if gpr.MergeCommit != nil {
+			pr.MergeCommitSHA = gpr.MergeCommit.Oid
+		}
 
 		pullRequests = append(pullRequests, pr)
 	}
@@ -16,11 +16,14 @@ type TimeRange struct {
 
 // MetricsRequest represents a request for metrics calculation
 type MetricsRequest struct {
-	Repository  types.Repository `json:"repository"`
-	TimeRange   TimeRange        `json:"time_range"`
-	Granularity string           `json:"granularity"` // "hour", "day", "week", "month"
-	UseCache    bool             `json:"use_cache"`
-	CacheTTL    time.Duration    `json:"cache_ttl"`
+	Repository             types.Repository `json:"repository"`
+	TimeRange              TimeRange        `json:"time_range"`
+	Granularity            string           `json:"granularity"` // "hour", "day", "week", "month"
+	UseCache               bool             `json:"use_cache"`
+	CacheTTL               time.Duration    `json:"cache_ttl"`
+	ProductionEnvironments []string         `json:"production_environments,omitempty"` // Glob patterns overriding the configured production environments
+	WorkingHours           bool             `json:"working_hours"`                     // Measure lead time, review latency and MTTR in working hours
+	Team                   string           `json:"team,omitempty"`                    // Team whose working hours apply, instead of the repository's team
 }
 
 // Enhanced metrics with timezone and aggregation support
@@ -36,9 +39,22 @@ type EnhancedDORAMetrics struct {
 	TotalDeployments       int                      `json:"total_deployments"`
 	MeanLeadTimeHours      float64                  `json:"mean_lead_time_hours"`
 	MedianLeadTimeHours    float64                  `json:"median_lead_time_hours"`
+	LeadTime               LeadTimeStats            `json:"lead_time"`  // Commit authored to production deployment
+	MergeTime              LeadTimeStats            `json:"merge_time"` // PR opened to merged
+	CycleTimeTrend         []CycleTimeBucket        `json:"cycle_time_trend,omitempty"`
+	BatchSizeTrend         []BatchSizeBucket        `json:"batch_size_trend,omitempty"` // PR size distributions per period
+	PipelineTrend          []PipelineBucket         `json:"pipeline_trend,omitempty"`   // CI workflow statistics per period
 	TimeSeries             []DORATimeSeriesPoint    `json:"time_series,omitempty"`
+	Anomalies              []Anomaly                `json:"anomalies,omitempty"` // Spikes, drops and level shifts in the time series
+	Forecasts              []Forecast               `json:"forecasts,omitempty"` // Projections to the end of the quarter by default
 	IncidentBreakdown      []IncidentClassification `json:"incident_breakdown,omitempty"`
+	ChangeFailures         []ChangeFailure          `json:"change_failures,omitempty"`
 	DeploymentTrends       []DeploymentTrend        `json:"deployment_trends,omitempty"`
+	Performance            types.DORAPerformance    `json:"performance"` // Band per metric and distance to the next band
+	ProductionEnvironments []string                 `json:"production_environments"` // Patterns that selected production deployments
+	EnvironmentBreakdown   []EnvironmentDORAMetrics `json:"environment_breakdown,omitempty"`
+	Team                   string                   `json:"team,omitempty"`
+	WorkingHours           *WorkingHours            `json:"working_hours,omitempty"` // Schedule of elapsed times; absent for wall-clock hours
 	Confidence             float64                  `json:"confidence"`
 	DataQuality            DataQuality              `json:"data_quality"`
 	CacheInfo              CacheInfo                `json:"cache_info"`
@@ -92,7 +108,7 @@ type DORATimeSeriesPoint struct {
 
 // IncidentClassification represents incident analysis
 type IncidentClassification struct {
-	Type                string        `json:"type"` // "deployment_failure", "hotfix", "rollback", "outage"
+	Type                string        `json:"type"` // "deployment_failure", "revert", "hotfix", "rollback", "outage"
 	Severity            string        `json:"severity"` // "critical", "high", "medium", "low"
 	Count               int           `json:"count"`
 	MeanResolutionTime  time.Duration `json:"mean_resolution_time"`
@@ -181,11 +197,12 @@ type CHITrendAnalysis struct {
 
 // AIToolUsage represents usage of specific AI tools
 type AIToolUsage struct {
-	ToolName            string  `json:"tool_name"` // "copilot", "chatgpt", "codeium", etc.
+	ToolName            string  `json:"tool_name"` // "github-copilot", "chatgpt", "codeium", etc.
+	Commits             int     `json:"commits"`   // Commits the detection rules attribute to the tool
 	UsageHours          float64 `json:"usage_hours"`
 	AcceptanceRate      float64 `json:"acceptance_rate"`
-	LinesGenerated      int     `json:"lines_generated"`
-	LinesAccepted       int     `json:"lines_accepted"`
+	LinesGenerated      int     `json:"lines_generated"` // Lines inserted by accepted suggestions
+	LinesAccepted       int     `json:"lines_accepted"`  // Lines added by the tool's commits
 	CodeQualityScore    float64 `json:"code_quality_score"`
 	ProductivityBoost   float64 `json:"productivity_boost"` // Percentage increase
 }
@@ -205,12 +222,29 @@ type ProductivityMetrics struct {
 
 // CodeQualityImpact represents AI impact on code quality
 type CodeQualityImpact struct {
-	BugDensityReduction     float64 `json:"bug_density_reduction"`
+	BugDensityReduction     float64 `json:"bug_density_reduction"`  // Hotfix rate of human minus AI-assisted commits, in points
 	TestCoverageImprovement float64 `json:"test_coverage_improvement"`
-	CodeComplexityChange    float64 `json:"code_complexity_change"`
-	RefactoringFrequency    float64 `json:"refactoring_frequency"`
+	CodeComplexityChange    float64 `json:"code_complexity_change"` // Median complexity delta of AI-assisted minus human commits
+	RefactoringFrequency    float64 `json:"refactoring_frequency"`  // Rework rate of AI-assisted minus human commits, in points
 	CodeReviewPassRate      float64 `json:"code_review_pass_rate"`
 	SecurityVulnerabilities int     `json:"security_vulnerabilities"`
+
+	WindowDays  int                 `json:"window_days"`  // Follow-up window for reverts and hotfixes
+	ReworkDays  int                 `json:"rework_days"`  // Follow-up window for rework of the same lines
+	Comparisons []QualityComparison `json:"comparisons,omitempty"`
+}
+
+// QualityComparison compares one quality measure between human and AI-assisted commits
+type QualityComparison struct {
+	Metric      string  `json:"metric"` // revert_rate|hotfix_rate|rework_rate|complexity_delta|duplication_delta
+	Human       float64 `json:"human"`  // Percent for rates, median per commit for deltas
+	AI          float64 `json:"ai"`
+	Difference  float64 `json:"difference"` // AI minus human
+	HumanSample int     `json:"human_sample"`
+	AISample    int     `json:"ai_sample"`
+	Test        string  `json:"test"` // two_proportion_z|mann_whitney_u
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
 }
 
 // DeveloperEfficiency represents developer efficiency metrics
@@ -239,9 +273,9 @@ type HumanVsAIContributions struct {
 	AIAssistedCommits   int     `json:"ai_assisted_commits"`
 	HumanLinesAdded     int     `json:"human_lines_added"`
 	AILinesAdded        int     `json:"ai_lines_added"`
-	HumanTestsWritten   int     `json:"human_tests_written"`
+	HumanTestsWritten   int     `json:"human_tests_written"` // Test files added or changed
 	AITestsWritten      int     `json:"ai_tests_written"`
-	HumanBugsFixed      int     `json:"human_bugs_fixed"`
+	HumanBugsFixed      int     `json:"human_bugs_fixed"` // Commits whose subject is a fix
 	AIBugsFixed         int     `json:"ai_bugs_fixed"`
 	CollaborationScore  float64 `json:"collaboration_score"` // How well human and AI work together
 }
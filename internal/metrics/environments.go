@@ -0,0 +1,239 @@
+// Package metrics - Production environment selection and per-environment DORA breakdown
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// DefaultProductionEnvironments are the glob patterns used when no production environments are configured
+var DefaultProductionEnvironments = []string{"production", "prod", "production-*", "prod-*"}
+
+// unspecifiedEnvironment labels deployments that do not name an environment
+const unspecifiedEnvironment = "unspecified"
+
+// EnvironmentConfig selects which deployment environments count as production.
+// Patterns are case-insensitive globs as understood by path.Match, e.g. "prod", "prod-*" or "*-live".
+type EnvironmentConfig struct {
+	Production   []string            `json:"production"`             // Default patterns for every repository
+	Repositories map[string][]string `json:"repositories,omitempty"` // Patterns per "owner/name", replacing the defaults
+}
+
+// EnvironmentDORAMetrics holds DORA metrics computed from a single deployment environment
+type EnvironmentDORAMetrics struct {
+	Environment             string        `json:"environment"`
+	Production              bool          `json:"production"`
+	TotalDeployments        int           `json:"total_deployments"`
+	SuccessfulDeployments   int           `json:"successful_deployments"`
+	FailedDeployments       int           `json:"failed_deployments"`
+	DeploymentFrequencyWeek float64       `json:"deployment_frequency_per_week"`
+	ChangeFailRatePercent   float64       `json:"change_fail_rate_pct"`
+	MTTRHours               float64       `json:"mttr_hours"`
+	LeadTime                LeadTimeStats `json:"lead_time"`
+	ChangeFailures          int           `json:"change_failures"`
+	Incidents               int           `json:"incidents"`
+}
+
+// ValidateEnvironmentPatterns checks that every production environment pattern is a valid glob
+func ValidateEnvironmentPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return fmt.Errorf("invalid environment pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// PatternsFor returns the production patterns for a repository: the per-repo entry
+// when present, otherwise the defaults. Nil means nothing was configured.
+func (c EnvironmentConfig) PatternsFor(fullName string) []string {
+	for name, patterns := range c.Repositories {
+		if strings.EqualFold(name, fullName) && len(patterns) > 0 {
+			return patterns
+		}
+	}
+	if len(c.Production) > 0 {
+		return c.Production
+	}
+	return nil
+}
+
+// repositoryFullName returns "owner/name" for a repository
+func repositoryFullName(repo types.Repository) string {
+	if repo.FullName != "" {
+		return repo.FullName
+	}
+	return repo.Owner + "/" + repo.Name
+}
+
+// environmentMatcher decides which deployments are production deployments
+type environmentMatcher struct {
+	patterns []string
+	explicit bool // Patterns were configured rather than defaulted
+}
+
+// newEnvironmentMatcher creates a matcher from the first non-empty pattern list
+func newEnvironmentMatcher(candidates ...[]string) environmentMatcher {
+	for _, patterns := range candidates {
+		if len(patterns) > 0 {
+			return environmentMatcher{patterns: normalizePatterns(patterns), explicit: true}
+		}
+	}
+	return environmentMatcher{patterns: DefaultProductionEnvironments}
+}
+
+// isProduction reports whether an environment name matches a production pattern
+func (m environmentMatcher) isProduction(environment string) bool {
+	env := strings.ToLower(strings.TrimSpace(environment))
+	for _, pattern := range m.patterns {
+		if matched, err := path.Match(pattern, env); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// production keeps deployments to production environments. With the default patterns
+// and no matching environment, all deployments are kept so unnamed setups still report.
+func (m environmentMatcher) production(deployments []Deployment) []Deployment {
+	var production []Deployment
+	for _, deployment := range deployments {
+		if m.isProduction(deployment.Environment) {
+			production = append(production, deployment)
+		}
+	}
+	if len(production) == 0 && !m.explicit {
+		return deployments
+	}
+	return production
+}
+
+// productionIncidents keeps incidents in production environments and incidents without an environment
+func (m environmentMatcher) productionIncidents(incidents []Incident) []Incident {
+	var production []Incident
+	for _, incident := range incidents {
+		if incident.Environment == "" || m.isProduction(incident.Environment) {
+			production = append(production, incident)
+		}
+	}
+	return production
+}
+
+// normalizePatterns lowercases and trims patterns, dropping empty ones
+func normalizePatterns(patterns []string) []string {
+	normalized := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			normalized = append(normalized, pattern)
+		}
+	}
+	return normalized
+}
+
+// environmentName returns the grouping label for a deployment environment
+func environmentName(environment string) string {
+	if strings.TrimSpace(environment) == "" {
+		return unspecifiedEnvironment
+	}
+	return environment
+}
+
+// groupDeploymentsByEnvironment splits deployments by environment name
+func groupDeploymentsByEnvironment(deployments []Deployment) map[string][]Deployment {
+	groups := make(map[string][]Deployment)
+	for _, deployment := range deployments {
+		name := environmentName(deployment.Environment)
+		groups[name] = append(groups[name], deployment)
+	}
+	return groups
+}
+
+// environmentIncidents returns the incidents of one environment. Incidents without an
+// environment are assumed to be production incidents.
+func environmentIncidents(incidents []Incident, environment string, production bool) []Incident {
+	var matched []Incident
+	for _, incident := range incidents {
+		if strings.EqualFold(incident.Environment, environment) || (incident.Environment == "" && production) {
+			matched = append(matched, incident)
+		}
+	}
+	return matched
+}
+
+// environmentLeadTimes returns commit lead times for the given environments,
+// keeping only the first deployment of each commit
+func environmentLeadTimes(leadTimes []CommitLeadTime, environments map[string]bool) []CommitLeadTime {
+	var matched []CommitLeadTime
+	seen := make(map[string]bool)
+	for _, leadTime := range leadTimes {
+		if !environments[environmentName(leadTime.Environment)] || seen[leadTime.SHA] {
+			continue
+		}
+		seen[leadTime.SHA] = true
+		matched = append(matched, leadTime)
+	}
+	return matched
+}
+
+// environmentNames returns the set of environment names in a list of deployments
+func environmentNames(deployments []Deployment) map[string]bool {
+	names := make(map[string]bool)
+	for _, deployment := range deployments {
+		names[environmentName(deployment.Environment)] = true
+	}
+	return names
+}
+
+// sortEnvironmentBreakdown orders production environments first, then by deployment count
+func sortEnvironmentBreakdown(breakdown []EnvironmentDORAMetrics) {
+	sort.SliceStable(breakdown, func(i, j int) bool {
+		if breakdown[i].Production != breakdown[j].Production {
+			return breakdown[i].Production
+		}
+		if breakdown[i].TotalDeployments != breakdown[j].TotalDeployments {
+			return breakdown[i].TotalDeployments > breakdown[j].TotalDeployments
+		}
+		return breakdown[i].Environment < breakdown[j].Environment
+	})
+}
+
+// calculateEnvironmentBreakdown computes DORA metrics separately for every deployment environment.
+// Workflow runs are not tied to an environment, so MTTR comes from deployment recoveries or incidents.
+func (edc *EnhancedDORACalculator) calculateEnvironmentBreakdown(deployments, production []Deployment, pullRequests []PullRequest, commits []Commit, incidents []Incident, useIncidents bool, leadTimes []CommitLeadTime, timeRange TimeRange, clock workingClock) []EnvironmentDORAMetrics {
+	productionNames := environmentNames(production)
+
+	var breakdown []EnvironmentDORAMetrics
+	for name, envDeployments := range groupDeploymentsByEnvironment(deployments) {
+		isProduction := productionNames[name]
+		envIncidents := environmentIncidents(incidents, name, isProduction)
+		failures := attributeChangeFailures(envDeployments, pullRequests, commits, envIncidents, edc.changeFailureRules())
+		envLeadTimes := environmentLeadTimes(leadTimes, map[string]bool{name: true})
+
+		_, failedDeployments := edc.analyzeIncidents(nil, envDeployments)
+		mttr := edc.calculateEnhancedMTTR(nil, envDeployments, clock)
+		if useIncidents {
+			mttr = calculateIncidentMTTR(envIncidents, clock.hours)
+		}
+
+		breakdown = append(breakdown, EnvironmentDORAMetrics{
+			Environment:             name,
+			Production:              isProduction,
+			TotalDeployments:        len(envDeployments),
+			SuccessfulDeployments:   len(successfulDeployments(envDeployments)),
+			FailedDeployments:       failedDeployments,
+			DeploymentFrequencyWeek: edc.calculateEnhancedDeploymentFrequency(envDeployments, timeRange),
+			ChangeFailRatePercent:   calculateAttributedChangeFailureRate(envDeployments, failures),
+			MTTRHours:               mttr,
+			LeadTime:                leadTimeStatsFromHours(clock.leadTimeHours(envLeadTimes)),
+			ChangeFailures:          len(failures),
+			Incidents:               len(envIncidents),
+		})
+	}
+
+	sortEnvironmentBreakdown(breakdown)
+	return breakdown
+}
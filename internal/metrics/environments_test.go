@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+func TestEnvironmentMatcherGlobs(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		environment string
+		production  bool
+	}{
+		{"default exact", nil, "production", true},
+		{"default short name", nil, "PROD", true},
+		{"default prefix glob", nil, "production-eu", true},
+		{"default excludes staging", nil, "staging", false},
+		{"default excludes lookalike", nil, "preprod", false},
+		{"configured suffix glob", []string{"*-live"}, "shop-live", true},
+		{"configured replaces defaults", []string{"*-live"}, "production", false},
+		{"configured case and whitespace", []string{"  Prod-* "}, " prod-US ", true},
+		{"character class", []string{"prod-[ab]"}, "prod-c", false},
+		{"glob does not cross slashes", []string{"prod-*"}, "prod-eu/west", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher := newEnvironmentMatcher(tt.patterns)
+			if got := matcher.isProduction(tt.environment); got != tt.production {
+				t.Errorf("isProduction(%q) with %v = %v, expected %v", tt.environment, tt.patterns, got, tt.production)
+			}
+		})
+	}
+}
+
+func TestEnvironmentMatcherProduction(t *testing.T) {
+	deployments := []Deployment{
+		{ID: 1, Environment: "staging"},
+		{ID: 2, Environment: "preview-42"},
+	}
+
+	// Without configuration, setups that never name production keep every deployment
+	if got := newEnvironmentMatcher().production(deployments); len(got) != 2 {
+		t.Errorf("Expected all deployments without configured patterns, got %+v", got)
+	}
+	// Explicit patterns never fall back
+	if got := newEnvironmentMatcher([]string{"prod"}).production(deployments); len(got) != 0 {
+		t.Errorf("Expected no deployments with explicit patterns, got %+v", got)
+	}
+
+	deployments = append(deployments, Deployment{ID: 3, Environment: "Production"})
+	got := newEnvironmentMatcher(nil, []string{"production"}).production(deployments)
+	if len(got) != 1 || got[0].ID != 3 {
+		t.Errorf("Expected only the production deployment from the first non-empty pattern list, got %+v", got)
+	}
+}
+
+func TestEnvironmentMatcherProductionIncidents(t *testing.T) {
+	incidents := []Incident{
+		{ID: "1", Environment: "production"},
+		{ID: "2", Environment: "staging"},
+		{ID: "3"},
+	}
+	got := newEnvironmentMatcher().productionIncidents(incidents)
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("Expected production and unlabeled incidents, got %+v", got)
+	}
+}
+
+func TestEnvironmentConfigPatternsFor(t *testing.T) {
+	config := EnvironmentConfig{
+		Production:   []string{"prod"},
+		Repositories: map[string][]string{"Acme/Shop": {"*-live"}, "acme/empty": {}},
+	}
+
+	if got := config.PatternsFor("acme/shop"); len(got) != 1 || got[0] != "*-live" {
+		t.Errorf("Expected the per-repository patterns matched case-insensitively, got %v", got)
+	}
+	if got := config.PatternsFor("acme/empty"); len(got) != 1 || got[0] != "prod" {
+		t.Errorf("Expected an empty repository entry to fall back to the defaults, got %v", got)
+	}
+	if got := (EnvironmentConfig{}).PatternsFor("acme/shop"); got != nil {
+		t.Errorf("Expected nil without configuration, got %v", got)
+	}
+	if name := repositoryFullName(types.Repository{Owner: "acme", Name: "shop"}); name != "acme/shop" {
+		t.Errorf("Expected acme/shop, got %s", name)
+	}
+}
+
+func TestValidateEnvironmentPatterns(t *testing.T) {
+	if err := ValidateEnvironmentPatterns([]string{"prod", "prod-*", "eu-[0-9]"}); err != nil {
+		t.Errorf("Expected valid patterns, got %v", err)
+	}
+	if err := ValidateEnvironmentPatterns([]string{"prod-[a"}); err == nil {
+		t.Error("Expected an error for an unterminated character class")
+	}
+}
+
+func TestEnvironmentLeadTimesKeepsFirstDeployment(t *testing.T) {
+	leadTimes := []CommitLeadTime{
+		{SHA: "a", Environment: "staging"},
+		{SHA: "a", Environment: "production"},
+		{SHA: "b", Environment: ""},
+		{SHA: "b", Environment: "production"},
+	}
+	got := environmentLeadTimes(leadTimes, map[string]bool{"production": true, unspecifiedEnvironment: true})
+	if len(got) != 2 || got[0].Environment != "production" || got[1].Environment != "" {
+		t.Errorf("Expected the first production deployment of each commit, got %+v", got)
+	}
+}
+
+func TestSortEnvironmentBreakdown(t *testing.T) {
+	breakdown := []EnvironmentDORAMetrics{
+		{Environment: "staging", TotalDeployments: 30},
+		{Environment: "prod-us", Production: true, TotalDeployments: 5},
+		{Environment: "prod-eu", Production: true, TotalDeployments: 5},
+		{Environment: "production", Production: true, TotalDeployments: 9},
+	}
+	sortEnvironmentBreakdown(breakdown)
+
+	expected := []string{"production", "prod-eu", "prod-us", "staging"}
+	for i, name := range expected {
+		if breakdown[i].Environment != name {
+			t.Errorf("Position %d: expected %s, got %s", i, name, breakdown[i].Environment)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package metrics - Flaky workflow and test detection
+package metrics
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Test result statuses
+const (
+	TestPassed  = "passed"
+	TestFailed  = "failed"
+	TestSkipped = "skipped"
+)
+
+// flakinessTrendThreshold is the change in flakiness score points that makes a trend
+const flakinessTrendThreshold = 5.0
+
+// TestResult is the outcome of one test case in a JUnit report
+type TestResult struct {
+	Suite           string  `json:"suite"`
+	Name            string  `json:"name"`
+	Status          string  `json:"status"` // passed, failed, skipped
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// TestRun is the set of test results produced by one CI run of a commit
+type TestRun struct {
+	Repository string       `json:"repository"` // "owner/name"
+	Workflow   string       `json:"workflow"`
+	SHA        string       `json:"sha"`
+	RunID      int          `json:"run_id,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Results    []TestResult `json:"results"`
+}
+
+// TestRunSource provides test results of CI runs for flaky test detection
+type TestRunSource interface {
+	GetTestRuns(ctx context.Context, owner, repo string, since time.Time) ([]TestRun, error)
+}
+
+// junitSuite is a <testsuite> element, possibly nested
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	TestCases []junitCase  `xml:"testcase"`
+	Suites    []junitSuite `xml:"testsuite"`
+}
+
+// junitCase is a <testcase> element
+type junitCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	Time      string    `xml:"time,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+// ParseJUnitXML reads test results from a JUnit XML report with a <testsuites> or <testsuite> root
+func ParseJUnitXML(r io.Reader) ([]TestResult, error) {
+	// Both roots decode into the same shape: suites nested in suites
+	var root junitSuite
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+	}
+
+	var results []TestResult
+	var walk func(suite junitSuite)
+	walk = func(suite junitSuite) {
+		for _, testCase := range suite.TestCases {
+			result := TestResult{
+				Suite:  firstNonEmpty(strings.TrimSpace(testCase.ClassName), suite.Name),
+				Name:   testCase.Name,
+				Status: TestPassed,
+			}
+			switch {
+			case testCase.Failure != nil || testCase.Error != nil:
+				result.Status = TestFailed
+			case testCase.Skipped != nil:
+				result.Status = TestSkipped
+			}
+			fmt.Sscanf(testCase.Time, "%g", &result.DurationSeconds)
+			results = append(results, result)
+		}
+		for _, nested := range suite.Suites {
+			walk(nested)
+		}
+	}
+	walk(root)
+	return results, nil
+}
+
+// LoadJUnitFiles adds the results of JUnit reports to a test run. Paths may be glob patterns
+// such as "build/test-results/*.xml".
+func LoadJUnitFiles(run TestRun, paths ...string) (TestRun, error) {
+	for _, pattern := range paths {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return run, fmt.Errorf("invalid JUnit report pattern %s: %w", pattern, err)
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return run, fmt.Errorf("failed to open JUnit report %s: %w", file, err)
+			}
+			results, err := ParseJUnitXML(f)
+			f.Close()
+			if err != nil {
+				return run, fmt.Errorf("%s: %w", file, err)
+			}
+			run.Results = append(run.Results, results...)
+		}
+	}
+	return run, nil
+}
+
+// TestReportStore keeps uploaded test runs per repository in memory
+type TestReportStore struct {
+	mu    sync.RWMutex
+	runs  map[string][]TestRun
+	limit int
+}
+
+// NewTestReportStore creates a store keeping up to limit test runs per repository
+func NewTestReportStore(limit int) *TestReportStore {
+	if limit <= 0 {
+		limit = 1000
+	}
+	return &TestReportStore{
+		runs:  make(map[string][]TestRun),
+		limit: limit,
+	}
+}
+
+// Add stores a test run of its repository
+func (s *TestReportStore) Add(run TestRun) error {
+	if run.Repository == "" || run.SHA == "" {
+		return fmt.Errorf("test run requires repository and sha")
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(run.Repository)
+	runs := append(s.runs[key], run)
+	if len(runs) > s.limit {
+		runs = runs[len(runs)-s.limit:]
+	}
+	s.runs[key] = runs
+	return nil
+}
+
+// GetTestRuns implements TestRunSource
+func (s *TestReportStore) GetTestRuns(ctx context.Context, owner, repo string, since time.Time) ([]TestRun, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var runs []TestRun
+	for _, run := range s.runs[strings.ToLower(owner+"/"+repo)] {
+		if !run.CreatedAt.Before(since) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// commitOutcome tracks the outcomes of a workflow or test on one commit
+type commitOutcome struct {
+	firstAt  time.Time
+	failed   bool
+	passed   bool
+	flaky    bool // Workflows: a failure was followed by a success, or a rerun passed. Tests: both failed and passed.
+	attempts int
+}
+
+// DetectFlakiness finds workflows that fail and then pass on the same commit, and tests whose
+// results flip between failing and passing on the same commit. Trends compare the second half
+// of the time span with the first. Returns nil without runs or test reports.
+func DetectFlakiness(runs []WorkflowRun, testRuns []TestRun) *types.Flakiness {
+	if len(runs) == 0 && len(testRuns) == 0 {
+		return nil
+	}
+	flakiness := &types.Flakiness{TestReports: len(testRuns)}
+
+	for name, workflowRuns := range runsByWorkflow(runs) {
+		sort.SliceStable(workflowRuns, func(i, j int) bool {
+			return workflowRuns[i].CreatedAt.Before(workflowRuns[j].CreatedAt)
+		})
+
+		workflow := types.FlakyWorkflow{Name: name}
+		outcomes := make(map[string]*commitOutcome)
+		var failedRuns []WorkflowRun
+		for _, run := range workflowRuns {
+			if run.SHA == "" || (run.Conclusion != "success" && run.Conclusion != "failure") {
+				continue
+			}
+			outcome := outcomes[run.SHA]
+			if outcome == nil {
+				outcome = &commitOutcome{firstAt: run.CreatedAt}
+				outcomes[run.SHA] = outcome
+			}
+			outcome.attempts += max(run.Attempt, 1)
+
+			switch run.Conclusion {
+			case "failure":
+				outcome.failed = true
+				failedRuns = append(failedRuns, run)
+			case "success":
+				// Providers listing only the latest attempt report reruns through Attempt
+				if outcome.failed || run.Attempt > 1 {
+					outcome.flaky = true
+				}
+			}
+		}
+
+		for _, outcome := range outcomes {
+			if outcome.flaky {
+				workflow.Reruns += outcome.attempts - 1
+			}
+		}
+		for _, run := range failedRuns {
+			if duration, ok := runDuration(run); ok && outcomes[run.SHA].flaky {
+				workflow.WastedHours += duration.Hours()
+			}
+		}
+
+		workflow.Commits, workflow.FlakyCommits, workflow.FlakinessScore, workflow.Trend = scoreOutcomes(outcomes)
+		if workflow.Commits == 0 {
+			continue
+		}
+		flakiness.RerunHours += workflow.WastedHours
+		flakiness.Workflows = append(flakiness.Workflows, workflow)
+	}
+	sort.SliceStable(flakiness.Workflows, func(i, j int) bool {
+		if flakiness.Workflows[i].FlakinessScore != flakiness.Workflows[j].FlakinessScore {
+			return flakiness.Workflows[i].FlakinessScore > flakiness.Workflows[j].FlakinessScore
+		}
+		return flakiness.Workflows[i].Name < flakiness.Workflows[j].Name
+	})
+
+	flakiness.Tests = detectFlakyTests(testRuns)
+	return flakiness
+}
+
+// detectFlakyTests finds tests that both failed and passed on the same commit, in either order
+func detectFlakyTests(testRuns []TestRun) []types.FlakyTest {
+	type testKey struct{ workflow, suite, name string }
+	tests := make(map[testKey]*types.FlakyTest)
+	outcomes := make(map[testKey]map[string]*commitOutcome)
+
+	sorted := append([]TestRun(nil), testRuns...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	for _, run := range sorted {
+		for _, result := range run.Results {
+			if result.Status == TestSkipped || run.SHA == "" {
+				continue
+			}
+			key := testKey{run.Workflow, result.Suite, result.Name}
+			test := tests[key]
+			if test == nil {
+				test = &types.FlakyTest{Name: result.Name, Suite: result.Suite, Workflow: run.Workflow}
+				tests[key] = test
+				outcomes[key] = make(map[string]*commitOutcome)
+			}
+			test.Executions++
+
+			outcome := outcomes[key][run.SHA]
+			if outcome == nil {
+				outcome = &commitOutcome{firstAt: run.CreatedAt}
+				outcomes[key][run.SHA] = outcome
+			}
+			if result.Status == TestFailed {
+				test.Failures++
+				outcome.failed = true
+			} else {
+				outcome.passed = true
+			}
+			outcome.flaky = outcome.failed && outcome.passed
+		}
+	}
+
+	var flaky []types.FlakyTest
+	for key, test := range tests {
+		test.Commits, test.FlakyCommits, test.FlakinessScore, test.Trend = scoreOutcomes(outcomes[key])
+		if test.FlakyCommits > 0 {
+			flaky = append(flaky, *test)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool {
+		if flaky[i].FlakinessScore != flaky[j].FlakinessScore {
+			return flaky[i].FlakinessScore > flaky[j].FlakinessScore
+		}
+		if flaky[i].FlakyCommits != flaky[j].FlakyCommits {
+			return flaky[i].FlakyCommits > flaky[j].FlakyCommits
+		}
+		return flaky[i].Suite+"."+flaky[i].Name < flaky[j].Suite+"."+flaky[j].Name
+	})
+	return flaky
+}
+
+// scoreOutcomes returns the number of commits, flaky commits, the percent of flaky commits
+// and the trend between the first and second half of the commits' time span
+func scoreOutcomes(outcomes map[string]*commitOutcome) (int, int, float64, string) {
+	if len(outcomes) == 0 {
+		return 0, 0, 0, ""
+	}
+
+	var first, last time.Time
+	flakyCommits := 0
+	for _, outcome := range outcomes {
+		if first.IsZero() || outcome.firstAt.Before(first) {
+			first = outcome.firstAt
+		}
+		if outcome.firstAt.After(last) {
+			last = outcome.firstAt
+		}
+		if outcome.flaky {
+			flakyCommits++
+		}
+	}
+	score := float64(flakyCommits) / float64(len(outcomes)) * 100
+
+	midpoint := first.Add(last.Sub(first) / 2)
+	var earlier, later, earlierFlaky, laterFlaky int
+	for _, outcome := range outcomes {
+		if outcome.firstAt.Before(midpoint) {
+			earlier++
+			earlierFlaky += boolCount(outcome.flaky)
+		} else {
+			later++
+			laterFlaky += boolCount(outcome.flaky)
+		}
+	}
+	if earlier == 0 || later == 0 {
+		return len(outcomes), flakyCommits, score, ""
+	}
+
+	change := float64(laterFlaky)/float64(later)*100 - float64(earlierFlaky)/float64(earlier)*100
+	trend := types.TrendStable
+	switch {
+	case change >= flakinessTrendThreshold:
+		trend = types.TrendDegrading
+	case change <= -flakinessTrendThreshold:
+		trend = types.TrendImproving
+	}
+	return len(outcomes), flakyCommits, score, trend
+}
+
+// boolCount returns 1 for true and 0 for false
+func boolCount(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
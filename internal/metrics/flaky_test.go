@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const junitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="auth">
+    <testcase classname="auth.LoginTest" name="TestLogin" time="1.5"><failure message="timeout"/></testcase>
+    <testcase name="TestLogout" time="0.2"/>
+    <testsuite name="auth.nested">
+      <testcase name="TestToken" time="0.1"><error/></testcase>
+      <testcase name="TestRefresh"><skipped/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+func TestParseJUnitXML(t *testing.T) {
+	results, err := ParseJUnitXML(strings.NewReader(junitReport))
+	if err != nil {
+		t.Fatalf("ParseJUnitXML failed: %v", err)
+	}
+
+	expected := []TestResult{
+		{Suite: "auth.LoginTest", Name: "TestLogin", Status: TestFailed, DurationSeconds: 1.5},
+		{Suite: "auth", Name: "TestLogout", Status: TestPassed, DurationSeconds: 0.2},
+		{Suite: "auth.nested", Name: "TestToken", Status: TestFailed, DurationSeconds: 0.1},
+		{Suite: "auth.nested", Name: "TestRefresh", Status: TestSkipped},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %+v", len(expected), results)
+	}
+	for i, result := range results {
+		if result != expected[i] {
+			t.Errorf("Result %d = %+v, expected %+v", i, result, expected[i])
+		}
+	}
+
+	// A single <testsuite> root works the same
+	results, err = ParseJUnitXML(strings.NewReader(`<testsuite name="cart"><testcase name="TestAdd"/></testsuite>`))
+	if err != nil || len(results) != 1 || results[0].Suite != "cart" {
+		t.Errorf("Expected one result of suite cart, got %+v, %v", results, err)
+	}
+
+	if _, err := ParseJUnitXML(strings.NewReader("not xml")); err == nil {
+		t.Error("Expected an error for a report that is not XML")
+	}
+}
+
+func TestLoadJUnitFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"auth.xml":  junitReport,
+		"cart.xml":  `<testsuite name="cart"><testcase name="TestAdd"/></testsuite>`,
+		"notes.txt": "not a report",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run, err := LoadJUnitFiles(TestRun{Repository: "acme/api", SHA: "abc"}, filepath.Join(dir, "*.xml"))
+	if err != nil {
+		t.Fatalf("LoadJUnitFiles failed: %v", err)
+	}
+	if run.Repository != "acme/api" || len(run.Results) != 5 {
+		t.Errorf("Expected the 5 results of both reports, got %d", len(run.Results))
+	}
+
+	if _, err := LoadJUnitFiles(TestRun{}, filepath.Join(dir, "notes.txt")); err == nil || !strings.Contains(err.Error(), "notes.txt") {
+		t.Errorf("Expected an error naming the invalid report, got %v", err)
+	}
+}
+
+func TestTestReportStore(t *testing.T) {
+	store := NewTestReportStore(2)
+	for i, sha := range []string{"a", "b", "c"} {
+		if err := store.Add(TestRun{Repository: "Acme/API", SHA: sha, CreatedAt: at(float64(i))}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := store.Add(TestRun{Repository: "acme/api"}); err == nil {
+		t.Error("Expected an error for a test run without sha")
+	}
+
+	// Only the latest two runs are kept, and repositories match regardless of case
+	runs, err := store.GetTestRuns(context.Background(), "acme", "api", at(0))
+	if err != nil {
+		t.Fatalf("GetTestRuns failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].SHA != "b" || runs[1].SHA != "c" {
+		t.Errorf("Expected runs b and c, got %+v", runs)
+	}
+	if runs, _ := store.GetTestRuns(context.Background(), "acme", "api", at(2)); len(runs) != 1 {
+		t.Errorf("Expected only run c since hour 2, got %+v", runs)
+	}
+}
+
+func TestDetectFlakyTestsInEitherOrder(t *testing.T) {
+	testRuns := []TestRun{
+		{Workflow: "ci", SHA: "a", CreatedAt: at(1), Results: []TestResult{
+			{Suite: "auth", Name: "TestLogin", Status: TestPassed},
+			{Suite: "cart", Name: "TestAdd", Status: TestFailed},
+		}},
+		{Workflow: "ci", SHA: "a", CreatedAt: at(0), Results: []TestResult{
+			{Suite: "auth", Name: "TestLogin", Status: TestFailed},
+			{Suite: "cart", Name: "TestAdd", Status: TestPassed},
+			{Suite: "cart", Name: "TestRemove", Status: TestSkipped},
+		}},
+		{Workflow: "ci", SHA: "b", CreatedAt: at(2), Results: []TestResult{
+			{Suite: "auth", Name: "TestLogin", Status: TestPassed},
+			{Suite: "cart", Name: "TestAdd", Status: TestPassed},
+			{Suite: "cart", Name: "TestRemove", Status: TestFailed},
+		}},
+	}
+
+	flaky := detectFlakyTests(testRuns)
+	if len(flaky) != 2 {
+		t.Fatalf("Expected TestLogin (fail then pass) and TestAdd (pass then fail), got %+v", flaky)
+	}
+	for _, test := range flaky {
+		if test.Name != "TestLogin" && test.Name != "TestAdd" {
+			t.Errorf("Unexpected flaky test %+v", test)
+		}
+		if test.Commits != 2 || test.FlakyCommits != 1 || test.FlakinessScore != 50 || test.Executions != 3 || test.Failures != 1 {
+			t.Errorf("Expected one flaky commit of two for %s, got %+v", test.Name, test)
+		}
+	}
+}
+
+func TestDetectFlakinessOfWorkflows(t *testing.T) {
+	runs := []WorkflowRun{
+		// Commit a failed and then passed; commit b passed on its second attempt
+		completedRun(1, "ci", "push", "failure", 0, 0, 0.5),
+		completedRun(2, "ci", "push", "success", 1, 1, 1.5),
+		completedRun(3, "ci", "push", "success", 2, 2, 2.5),
+		completedRun(4, "ci", "push", "success", 3, 3, 3.5),
+		completedRun(5, "ci", "push", "cancelled", 4, 4, 4.5),
+	}
+	runs[0].SHA, runs[1].SHA, runs[2].SHA, runs[3].SHA, runs[4].SHA = "a", "a", "b", "c", "d"
+	runs[2].Attempt = 2
+
+	flakiness := DetectFlakiness(runs, []TestRun{{SHA: "a"}})
+	if flakiness == nil || len(flakiness.Workflows) != 1 {
+		t.Fatalf("Expected flakiness of the ci workflow, got %+v", flakiness)
+	}
+	ci := flakiness.Workflows[0]
+	if ci.Commits != 3 || ci.FlakyCommits != 2 || ci.Reruns != 2 {
+		t.Errorf("Expected 2 flaky commits of 3 with 2 reruns, got %+v", ci)
+	}
+	if ci.WastedHours != 0.5 || flakiness.RerunHours != 0.5 {
+		t.Errorf("Expected the half hour of the failed run wasted, got %.2f", ci.WastedHours)
+	}
+	if flakiness.TestReports != 1 {
+		t.Errorf("Expected 1 test report, got %d", flakiness.TestReports)
+	}
+
+	if DetectFlakiness(nil, nil) != nil {
+		t.Error("Expected no flakiness without runs or test reports")
+	}
+}
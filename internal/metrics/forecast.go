@@ -0,0 +1,370 @@
+// Package metrics - Trend forecasting with prediction intervals over DORA and CHI series
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Forecast methods
+const (
+	ForecastLinear        = "linear"         // Linear trend
+	ForecastLinearWeekday = "linear_weekday" // Linear trend with additive weekday seasonality
+)
+
+// minForecastPoints is the shortest series a trend is fitted to
+const minForecastPoints = 5
+
+// ForecastConfig tunes trend forecasting
+type ForecastConfig struct {
+	HorizonDays int     `json:"horizon_days"` // Days past the last observation; 0 projects to the end of its quarter
+	Level       float64 `json:"level"`        // Prediction interval coverage, 0-1
+}
+
+// DefaultForecastConfig projects to the end of the quarter with 80% prediction intervals
+func DefaultForecastConfig() ForecastConfig {
+	return ForecastConfig{Level: 0.8}
+}
+
+// withDefaults fills unset settings from DefaultForecastConfig
+func (c ForecastConfig) withDefaults() ForecastConfig {
+	if c.Level <= 0 || c.Level >= 1 {
+		c.Level = DefaultForecastConfig().Level
+	}
+	if c.HorizonDays < 0 {
+		c.HorizonDays = 0
+	}
+	return c
+}
+
+// ForecastPoint is a projected value with its prediction interval
+type ForecastPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Lower     float64   `json:"lower"`
+	Upper     float64   `json:"upper"`
+}
+
+// Forecast projects one metric series forward
+type Forecast struct {
+	Source         string          `json:"source"` // "dora", "chi"
+	Metric         string          `json:"metric"`
+	Method         string          `json:"method"`
+	Level          float64         `json:"level"`
+	Observations   int             `json:"observations"`
+	SlopePerDay    float64         `json:"slope_per_day"`
+	ResidualStdDev float64         `json:"residual_std_dev"`
+	Horizon        ForecastPoint   `json:"horizon"` // Projection at the end of the horizon
+	Points         []ForecastPoint `json:"points,omitempty"`
+
+	model trendModel
+}
+
+// trendModel is an ordinary least squares trend over days since the first observation
+type trendModel struct {
+	start     time.Time
+	location  *time.Location
+	intercept float64
+	slope     float64
+	seasonal  []float64 // Additive effect per weekday, nil without seasonality
+	meanDay   float64
+	sxx       float64
+	n         int
+	stdDev    float64
+	z         float64
+}
+
+// ForecastSeries fits a trend to a series and projects it over the horizon.
+// Daily series spanning at least two weeks also get a weekday seasonal component.
+func ForecastSeries(source, metric string, points []SeriesPoint, config ForecastConfig) (Forecast, error) {
+	config = config.withDefaults()
+	if len(points) < minForecastPoints {
+		return Forecast{}, fmt.Errorf("not enough observations to forecast %s: %d, need %d", metric, len(points), minForecastPoints)
+	}
+
+	sorted := append([]SeriesPoint(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	model := fitTrend(sorted)
+	model.z = math.Sqrt2 * math.Erfinv(config.Level)
+
+	method := ForecastLinear
+	step := seriesStep(sorted)
+	if step <= 24*time.Hour && sorted[len(sorted)-1].Timestamp.Sub(sorted[0].Timestamp) >= 14*24*time.Hour {
+		model = fitWeekdayTrend(sorted, model)
+		method = ForecastLinearWeekday
+	}
+
+	last := sorted[len(sorted)-1].Timestamp
+	end := last.AddDate(0, 0, config.HorizonDays)
+	if config.HorizonDays == 0 {
+		end = QuarterEnd(last)
+	}
+
+	forecast := Forecast{
+		Source:         source,
+		Metric:         metric,
+		Method:         method,
+		Level:          config.Level,
+		Observations:   len(sorted),
+		SlopePerDay:    model.slope,
+		ResidualStdDev: model.stdDev,
+		model:          model,
+	}
+	for at := last.Add(step); !at.After(end); at = at.Add(step) {
+		forecast.Points = append(forecast.Points, forecast.At(at))
+	}
+	forecast.Horizon = forecast.At(end)
+	return forecast, nil
+}
+
+// At projects the series at a point in time with its prediction interval.
+// DORA and CHI metrics are never negative, so projections are floored at zero.
+func (f Forecast) At(at time.Time) ForecastPoint {
+	value, stderr := f.model.predict(at)
+	return ForecastPoint{
+		Timestamp: at,
+		Value:     math.Max(0, value),
+		Lower:     math.Max(0, value-f.model.z*stderr),
+		Upper:     math.Max(0, value+f.model.z*stderr),
+	}
+}
+
+// Probability returns the chance that the series meets target at a point in time,
+// under normally distributed prediction errors
+func (f Forecast) Probability(target float64, at time.Time, higherIsBetter bool) float64 {
+	value, stderr := f.model.predict(at)
+	if stderr == 0 {
+		if (higherIsBetter && value >= target) || (!higherIsBetter && value <= target) {
+			return 1
+		}
+		return 0
+	}
+	below := 0.5 * (1 + math.Erf((target-value)/(stderr*math.Sqrt2)))
+	if higherIsBetter {
+		return 1 - below
+	}
+	return below
+}
+
+// ForecastDORA projects the daily DORA series. Lead time is the daily P50 from commit to
+// deployment; a lead time P95 cannot be derived from daily medians, so none is projected.
+func ForecastDORA(points []DORATimeSeriesPoint, config ForecastConfig) []Forecast {
+	var leadTime, deployments, failureRate, recovery []SeriesPoint
+	for _, point := range points {
+		if point.LeadTimeHours > 0 {
+			leadTime = append(leadTime, SeriesPoint{point.Timestamp, point.LeadTimeHours})
+		}
+		deployments = append(deployments, SeriesPoint{point.Timestamp, float64(point.DeploymentCount)})
+		if point.DeploymentCount > 0 {
+			failureRate = append(failureRate, SeriesPoint{point.Timestamp, point.ChangeFailureRate})
+		}
+		if point.RecoveryTimeHours > 0 {
+			recovery = append(recovery, SeriesPoint{point.Timestamp, point.RecoveryTimeHours})
+		}
+	}
+
+	series := []struct {
+		metric string
+		points []SeriesPoint
+	}{
+		{"lead_time_hours", leadTime},
+		{"deployment_count", deployments},
+		{"change_failure_rate", failureRate},
+		{"recovery_time_hours", recovery},
+	}
+
+	var forecasts []Forecast
+	for _, s := range series {
+		if forecast, err := ForecastSeries("dora", s.metric, s.points, config); err == nil {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+	return forecasts
+}
+
+// ForecastCHI projects successive CHI measurements
+func ForecastCHI(history []types.CHIMetrics, config ForecastConfig) []Forecast {
+	series := []struct {
+		metric string
+		value  func(types.CHIMetrics) float64
+	}{
+		{"chi_score", func(m types.CHIMetrics) float64 { return float64(m.Score) }},
+		{"duplication_pct", func(m types.CHIMetrics) float64 { return m.DuplicationPercent }},
+		{"cyclomatic_avg", func(m types.CHIMetrics) float64 { return m.CyclomaticComplexity }},
+		{"test_coverage_pct", func(m types.CHIMetrics) float64 { return m.TestCoverage }},
+	}
+
+	var forecasts []Forecast
+	for _, s := range series {
+		points := make([]SeriesPoint, 0, len(history))
+		for _, chi := range history {
+			points = append(points, SeriesPoint{chi.CalculatedAt, s.value(chi)})
+		}
+		if forecast, err := ForecastSeries("chi", s.metric, points, config); err == nil {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+	return forecasts
+}
+
+// QuarterEnd returns the last moment of the calendar quarter containing t
+func QuarterEnd(t time.Time) time.Time {
+	firstMonth := time.Month((int(t.Month())-1)/3*3 + 1)
+	start := time.Date(t.Year(), firstMonth, 1, 0, 0, 0, 0, t.Location())
+	return start.AddDate(0, 3, 0).Add(-time.Nanosecond)
+}
+
+// fitTrend fits value = intercept + slope*day by ordinary least squares
+func fitTrend(points []SeriesPoint) trendModel {
+	start := points[0].Timestamp
+	model := trendModel{start: start, location: start.Location(), n: len(points)}
+
+	days := make([]float64, len(points))
+	for i, point := range points {
+		days[i] = point.Timestamp.Sub(start).Hours() / 24
+		model.meanDay += days[i]
+	}
+	model.meanDay /= float64(len(points))
+
+	meanValue := 0.0
+	for _, point := range points {
+		meanValue += point.Value
+	}
+	meanValue /= float64(len(points))
+
+	sxy := 0.0
+	for i, point := range points {
+		dx := days[i] - model.meanDay
+		model.sxx += dx * dx
+		sxy += dx * (point.Value - meanValue)
+	}
+	if model.sxx > 0 {
+		model.slope = sxy / model.sxx
+	}
+	model.intercept = meanValue - model.slope*model.meanDay
+	model.stdDev = model.residualStdDev(points, 2)
+	return model
+}
+
+// fitWeekdayTrend estimates weekday effects from the residuals of a plain trend,
+// then refits the trend on the deseasonalized series
+func fitWeekdayTrend(points []SeriesPoint, base trendModel) trendModel {
+	sums := make([]float64, 7)
+	counts := make([]int, 7)
+	for _, point := range points {
+		value, _ := base.predict(point.Timestamp)
+		weekday := point.Timestamp.In(base.location).Weekday()
+		sums[weekday] += point.Value - value
+		counts[weekday]++
+	}
+
+	seasonal := make([]float64, 7)
+	mean := 0.0
+	observed := 0
+	for weekday := range seasonal {
+		if counts[weekday] > 0 {
+			seasonal[weekday] = sums[weekday] / float64(counts[weekday])
+			mean += seasonal[weekday]
+			observed++
+		}
+	}
+	if observed == 0 {
+		return base
+	}
+	mean /= float64(observed)
+	for weekday := range seasonal {
+		if counts[weekday] > 0 {
+			seasonal[weekday] -= mean
+		}
+	}
+
+	deseasonalized := make([]SeriesPoint, len(points))
+	for i, point := range points {
+		weekday := point.Timestamp.In(base.location).Weekday()
+		deseasonalized[i] = SeriesPoint{point.Timestamp, point.Value - seasonal[weekday]}
+	}
+
+	model := fitTrend(deseasonalized)
+	model.seasonal = seasonal
+	model.z = base.z
+	model.stdDev = model.residualStdDev(points, 2+observed-1)
+	return model
+}
+
+// residualStdDev returns the standard deviation of the fit's residuals,
+// discounting the given number of estimated parameters
+func (m trendModel) residualStdDev(points []SeriesPoint, parameters int) float64 {
+	dof := len(points) - parameters
+	if dof < 1 {
+		dof = 1
+	}
+	squares := 0.0
+	for _, point := range points {
+		value, _ := m.predict(point.Timestamp)
+		squares += (point.Value - value) * (point.Value - value)
+	}
+	return math.Sqrt(squares / float64(dof))
+}
+
+// predict returns the trend value at a point in time and its prediction standard error
+func (m trendModel) predict(at time.Time) (float64, float64) {
+	day := at.Sub(m.start).Hours() / 24
+	value := m.intercept + m.slope*day
+	if m.seasonal != nil {
+		value += m.seasonal[at.In(m.location).Weekday()]
+	}
+
+	leverage := 1.0 / float64(m.n)
+	if m.sxx > 0 {
+		leverage += (day - m.meanDay) * (day - m.meanDay) / m.sxx
+	}
+	return value, m.stdDev * math.Sqrt(1+leverage)
+}
+
+// seriesStep returns the median spacing between observations, at least a day
+func seriesStep(points []SeriesPoint) time.Duration {
+	gaps := make([]float64, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		gaps = append(gaps, float64(points[i].Timestamp.Sub(points[i-1].Timestamp)))
+	}
+	if len(gaps) == 0 {
+		return 24 * time.Hour
+	}
+	sort.Float64s(gaps)
+	step := time.Duration(gaps[len(gaps)/2])
+	if step < 24*time.Hour {
+		return 24 * time.Hour
+	}
+	return step.Round(24 * time.Hour)
+}
+
+// AssessTarget judges whether the series can reach target by a point in time:
+// on track from a 70% chance, at risk from 30%, unrealistic below
+func (f Forecast) AssessTarget(target float64, at time.Time, higherIsBetter bool) types.MilestoneForecast {
+	point := f.At(at)
+	probability := f.Probability(target, at, higherIsBetter)
+
+	assessment := types.MilestoneUnrealistic
+	switch {
+	case probability >= 0.7:
+		assessment = types.MilestoneOnTrack
+	case probability >= 0.3:
+		assessment = types.MilestoneAtRisk
+	}
+
+	return types.MilestoneForecast{
+		Projected:   point.Value,
+		Lower:       point.Lower,
+		Upper:       point.Upper,
+		Level:       f.Level,
+		Probability: math.Round(probability*100) / 100,
+		Assessment:  assessment,
+	}
+}
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// dailySeries builds one point per day from baseTime with the given value function
+func dailySeries(days int, value func(day int, at time.Time) float64) []SeriesPoint {
+	points := make([]SeriesPoint, 0, days)
+	for day := 0; day < days; day++ {
+		at := baseTime.AddDate(0, 0, day)
+		points = append(points, SeriesPoint{at, value(day, at)})
+	}
+	return points
+}
+
+// noisyTrend is 50 + 0.5/day with residuals alternating by ±2 over ten days
+func noisyTrend() []SeriesPoint {
+	return dailySeries(10, func(day int, _ time.Time) float64 {
+		noise := 2.0
+		if day%2 == 1 {
+			noise = -2
+		}
+		return 50 + 0.5*float64(day) + noise
+	})
+}
+
+func TestForecastSeriesLinearFit(t *testing.T) {
+	points := dailySeries(10, func(day int, _ time.Time) float64 { return 20 + 1.5*float64(day) })
+
+	forecast, err := ForecastSeries("chi", "chi_score", points, ForecastConfig{HorizonDays: 5})
+	if err != nil {
+		t.Fatalf("ForecastSeries failed: %v", err)
+	}
+	if forecast.Method != ForecastLinear {
+		t.Errorf("Expected a plain trend for a series shorter than two weeks, got %s", forecast.Method)
+	}
+	if math.Abs(forecast.SlopePerDay-1.5) > 1e-9 || forecast.ResidualStdDev > 1e-9 {
+		t.Errorf("Expected slope 1.5 with no residuals, got %.4f and %.4f", forecast.SlopePerDay, forecast.ResidualStdDev)
+	}
+
+	// The horizon is five days past the last observation, day 14
+	if !forecast.Horizon.Timestamp.Equal(baseTime.AddDate(0, 0, 14)) || math.Abs(forecast.Horizon.Value-41) > 1e-9 {
+		t.Errorf("Expected 41 on day 14, got %.4f at %s", forecast.Horizon.Value, forecast.Horizon.Timestamp)
+	}
+	if forecast.Horizon.Lower != forecast.Horizon.Value || forecast.Horizon.Upper != forecast.Horizon.Value {
+		t.Errorf("Expected a zero-width interval for an exact fit, got %+v", forecast.Horizon)
+	}
+	if len(forecast.Points) != 5 {
+		t.Errorf("Expected one projected point per day of the horizon, got %d", len(forecast.Points))
+	}
+
+	// Projections never go negative
+	falling := dailySeries(10, func(day int, _ time.Time) float64 { return 10 - float64(day) })
+	forecast, err = ForecastSeries("dora", "lead_time_hours", falling, ForecastConfig{HorizonDays: 30})
+	if err != nil {
+		t.Fatalf("ForecastSeries failed: %v", err)
+	}
+	if forecast.Horizon.Value != 0 || forecast.Horizon.Lower != 0 {
+		t.Errorf("Expected the projection floored at zero, got %+v", forecast.Horizon)
+	}
+
+	if _, err := ForecastSeries("chi", "chi_score", points[:minForecastPoints-1], ForecastConfig{}); err == nil {
+		t.Error("Expected an error for too few observations")
+	}
+}
+
+func TestForecastSeriesWeekdaySeasonality(t *testing.T) {
+	// A slow upward trend with weekends 8 lower, over four weeks
+	points := dailySeries(28, func(day int, at time.Time) float64 {
+		value := 10 + 0.1*float64(day)
+		if weekday := at.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			value -= 8
+		}
+		return value
+	})
+
+	forecast, err := ForecastSeries("dora", "deployment_count", points, ForecastConfig{HorizonDays: 14})
+	if err != nil {
+		t.Fatalf("ForecastSeries failed: %v", err)
+	}
+	if forecast.Method != ForecastLinearWeekday {
+		t.Fatalf("Expected weekday seasonality over four weeks of daily data, got %s", forecast.Method)
+	}
+	if math.Abs(forecast.SlopePerDay-0.1) > 0.02 {
+		t.Errorf("Expected the deseasonalized slope near 0.1, got %.4f", forecast.SlopePerDay)
+	}
+
+	// baseTime is a Monday; day 33 is a Saturday and day 35 the following Monday
+	saturday := forecast.At(baseTime.AddDate(0, 0, 33))
+	monday := forecast.At(baseTime.AddDate(0, 0, 35))
+	if dip := monday.Value - saturday.Value; math.Abs(dip-8.2) > 0.5 {
+		t.Errorf("Expected the weekend dip to carry into the projection, got Monday %.2f and Saturday %.2f", monday.Value, saturday.Value)
+	}
+}
+
+func TestForecastPredictionIntervals(t *testing.T) {
+	points := noisyTrend()
+
+	forecast, err := ForecastSeries("chi", "chi_score", points, ForecastConfig{HorizonDays: 30, Level: 0.8})
+	if err != nil {
+		t.Fatalf("ForecastSeries failed: %v", err)
+	}
+	if forecast.ResidualStdDev <= 0 {
+		t.Fatalf("Expected residual spread from the noise, got %.4f", forecast.ResidualStdDev)
+	}
+
+	near := forecast.At(baseTime.AddDate(0, 0, 10))
+	far := forecast.At(baseTime.AddDate(0, 0, 39))
+	for _, point := range []ForecastPoint{near, far} {
+		if !(point.Lower < point.Value && point.Value < point.Upper) {
+			t.Errorf("Expected the value inside its interval, got %+v", point)
+		}
+		if math.Abs((point.Upper-point.Value)-(point.Value-point.Lower)) > 1e-9 {
+			t.Errorf("Expected a symmetric interval, got %+v", point)
+		}
+	}
+	if far.Upper-far.Lower <= near.Upper-near.Lower {
+		t.Errorf("Expected the interval to widen with distance, got %.2f near and %.2f far", near.Upper-near.Lower, far.Upper-far.Lower)
+	}
+
+	wide, err := ForecastSeries("chi", "chi_score", points, ForecastConfig{HorizonDays: 30, Level: 0.95})
+	if err != nil {
+		t.Fatalf("ForecastSeries failed: %v", err)
+	}
+	if wide.Horizon.Upper-wide.Horizon.Lower <= forecast.Horizon.Upper-forecast.Horizon.Lower {
+		t.Errorf("Expected a 95%% interval wider than an 80%% one")
+	}
+
+	// The 80% interval leaves 10% on each side
+	if p := forecast.Probability(far.Upper, far.Timestamp, false); math.Abs(p-0.9) > 1e-6 {
+		t.Errorf("Expected a 90%% chance of staying below the upper bound, got %.4f", p)
+	}
+}
+
+func TestForecastAssessTarget(t *testing.T) {
+	forecast, err := ForecastSeries("chi", "test_coverage_pct", noisyTrend(), ForecastConfig{HorizonDays: 10})
+	if err != nil {
+		t.Fatalf("ForecastSeries failed: %v", err)
+	}
+	at := baseTime.AddDate(0, 0, 19)
+	projected := forecast.At(at).Value
+
+	tests := []struct {
+		name           string
+		target         float64
+		higherIsBetter bool
+		expected       string
+	}{
+		{"well below projection", projected - 20, true, types.MilestoneOnTrack},
+		{"at projection", projected, true, types.MilestoneAtRisk},
+		{"well above projection", projected + 20, true, types.MilestoneUnrealistic},
+		{"lower is better and below projection", projected - 20, false, types.MilestoneUnrealistic},
+		{"lower is better and above projection", projected + 20, false, types.MilestoneOnTrack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment := forecast.AssessTarget(tt.target, at, tt.higherIsBetter)
+			if assessment.Assessment != tt.expected {
+				t.Errorf("Expected %s for target %.2f against %.2f, got %s (p=%.2f)",
+					tt.expected, tt.target, projected, assessment.Assessment, assessment.Probability)
+			}
+			if assessment.Level != forecast.Level || assessment.Projected != projected {
+				t.Errorf("Expected the projection and level of the forecast, got %+v", assessment)
+			}
+		})
+	}
+}
+
+func TestForecastDORAMetrics(t *testing.T) {
+	var points []DORATimeSeriesPoint
+	for day := 0; day < 10; day++ {
+		points = append(points, DORATimeSeriesPoint{
+			Timestamp:         baseTime.AddDate(0, 0, day),
+			LeadTimeHours:     20 + float64(day),
+			DeploymentCount:   2,
+			ChangeFailureRate: 10,
+		})
+	}
+
+	forecasts := ForecastDORA(points, ForecastConfig{HorizonDays: 7})
+	metrics := make(map[string]bool)
+	for _, forecast := range forecasts {
+		metrics[forecast.Metric] = true
+	}
+	for _, metric := range []string{"lead_time_hours", "deployment_count", "change_failure_rate"} {
+		if !metrics[metric] {
+			t.Errorf("Expected a %s forecast, got %v", metric, metrics)
+		}
+	}
+	// No recovery samples, and no percentile derived from the daily medians
+	if metrics["recovery_time_hours"] || metrics["lead_time_p95_hours"] {
+		t.Errorf("Unexpected forecasts %v", metrics)
+	}
+}
+
+func TestQuarterEnd(t *testing.T) {
+	tests := []struct {
+		at       time.Time
+		expected time.Time
+	}{
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 11, 15, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := QuarterEnd(tt.at); !got.Equal(tt.expected.Add(-time.Nanosecond)) {
+			t.Errorf("QuarterEnd(%s) = %s", tt.at, got)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -132,6 +133,7 @@ type GraphQLCommit struct {
 	Oid             string    `json:"oid"`
 	Message         string    `json:"message"`
 	CommittedDate   time.Time `json:"committedDate"`
+	AuthoredDate    time.Time `json:"authoredDate"`
 	Author          GitActor  `json:"author"`
 	Committer       GitActor  `json:"committer"`
 	Additions       int       `json:"additions"`
@@ -176,6 +178,8 @@ type GraphQLPullRequest struct {
 	Commits       PullRequestCommits `json:"commits"`
 	Labels        Labels    `json:"labels"`
 	Assignees     Assignees `json:"assignees"`
+	HeadRefName   string    `json:"headRefName"`
+	MergeCommit   *GraphQLCommit `json:"mergeCommit"`
 }
 
 type PullRequestConnection struct {
@@ -192,6 +196,8 @@ type Review struct {
 	State       string    `json:"state"`
 	SubmittedAt time.Time `json:"submittedAt"`
 	Author      User      `json:"author"`
+	Body        string    `json:"body"`
+	Comments    Comments  `json:"comments"`
 }
 
 type Comments struct {
@@ -207,7 +213,11 @@ type Comment struct {
 
 type PullRequestCommits struct {
 	TotalCount int      `json:"totalCount"`
-	Nodes      []GraphQLCommit `json:"nodes"`
+	Nodes      []PullRequestCommit `json:"nodes"`
+}
+
+type PullRequestCommit struct {
+	Commit GraphQLCommit `json:"commit"`
 }
 
 type Labels struct {
@@ -395,6 +405,10 @@ func (gc *GraphQLClient) GetRepositoryMetrics(ctx context.Context, owner, repo s
 					updatedAt
 					mergedAt
 					closedAt
+					headRefName
+					mergeCommit {
+						oid
+					}
 					author {
 						login
 						name
@@ -404,21 +418,33 @@ func (gc *GraphQLClient) GetRepositoryMetrics(ctx context.Context, owner, repo s
 					additions
 					deletions
 					changedFiles
-					reviews(first: 10) {
+					reviews(first: 50) {
 						totalCount
 						nodes {
 							state
 							submittedAt
+							body
 							author {
 								login
 							}
+							comments(first: 1) {
+								totalCount
+								nodes {
+									createdAt
+								}
+							}
 						}
 					}
 					comments {
 						totalCount
 					}
-					commits {
+					commits(first: 1) {
 						totalCount
+						nodes {
+							commit {
+								authoredDate
+							}
+						}
 					}
 					labels(first: 10) {
 						totalCount
@@ -635,6 +661,229 @@ func (gc *GraphQLClient) GetOrganizationRepositories(ctx context.Context, org st
 	return repositories, nil
 }
 
+// maxCommunityPages caps the pages of issues and of pull requests read for community health
+const maxCommunityPages = 20
+
+// communityActor is the author of an issue, pull request, comment or review
+type communityActor struct {
+	Login string `json:"login"`
+	Type  string `json:"__typename"` // User, Bot, ...
+}
+
+// communityNode is an issue or pull request in the community queries
+type communityNode struct {
+	Number            int             `json:"number"`
+	State             string          `json:"state"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	ClosedAt          *time.Time      `json:"closedAt"`
+	AuthorAssociation string          `json:"authorAssociation"`
+	Author            *communityActor `json:"author"`
+	Labels            struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Comments struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			CreatedAt         time.Time       `json:"createdAt"`
+			AuthorAssociation string          `json:"authorAssociation"`
+			Author            *communityActor `json:"author"`
+		} `json:"nodes"`
+	} `json:"comments"`
+	Reviews struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			SubmittedAt       *time.Time      `json:"submittedAt"`
+			AuthorAssociation string          `json:"authorAssociation"`
+			Author            *communityActor `json:"author"`
+		} `json:"nodes"`
+	} `json:"reviews"`
+}
+
+// communityPage is one page of issues or pull requests
+type communityPage struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []communityNode `json:"nodes"`
+}
+
+// GetCommunityItems implements CommunityClient, reading issues and pull requests created since,
+// newest first, with their first comments and reviews. Items past maxCommunityPages are left
+// out and the result is marked incomplete.
+func (gc *GraphQLClient) GetCommunityItems(ctx context.Context, owner, repo string, since time.Time) (CommunityItems, error) {
+	const actor = `author { login __typename }`
+	const comments = `comments(first: 20) { pageInfo { hasNextPage } nodes { createdAt authorAssociation ` + actor + ` } }`
+	const fields = `number state createdAt closedAt authorAssociation ` + actor + `
+					labels(first: 10) { nodes { name } }
+					` + comments
+
+	queries := map[string]string{
+		"issues": `
+	query GetCommunityIssues($owner: String!, $name: String!, $cursor: String) {
+		repository(owner: $owner, name: $name) {
+			items: issues(first: 50, after: $cursor, orderBy: {field: CREATED_AT, direction: DESC}) {
+				pageInfo { hasNextPage endCursor }
+				nodes {
+					` + fields + `
+				}
+			}
+		}
+	}`,
+		"pullRequests": `
+	query GetCommunityPullRequests($owner: String!, $name: String!, $cursor: String) {
+		repository(owner: $owner, name: $name) {
+			items: pullRequests(first: 50, after: $cursor, orderBy: {field: CREATED_AT, direction: DESC}) {
+				pageInfo { hasNextPage endCursor }
+				nodes {
+					` + fields + `
+					reviews(first: 20) { pageInfo { hasNextPage } nodes { submittedAt authorAssociation ` + actor + ` } }
+				}
+			}
+		}
+	}`,
+	}
+
+	var items CommunityItems
+	for _, kind := range []string{"issues", "pullRequests"} {
+		var cursor *string
+		done := false
+		for page := 0; page < maxCommunityPages && !done; page++ {
+			variables := map[string]interface{}{
+				"owner":  owner,
+				"name":   repo,
+				"cursor": cursor,
+			}
+			var response struct {
+				Repository struct {
+					Items communityPage `json:"items"`
+				} `json:"repository"`
+			}
+			if err := gc.executeQuery(ctx, queries[kind], variables, &response); err != nil {
+				return CommunityItems{}, fmt.Errorf("failed to get community %s: %w", kind, err)
+			}
+
+			done = !response.Repository.Items.PageInfo.HasNextPage
+			for _, node := range response.Repository.Items.Nodes {
+				if node.CreatedAt.Before(since) {
+					done = true
+					break
+				}
+				items.Items = append(items.Items, node.communityItem(kind == "pullRequests"))
+			}
+			endCursor := response.Repository.Items.PageInfo.EndCursor
+			cursor = &endCursor
+		}
+		if !done {
+			items.Incomplete = true
+		}
+	}
+	return items, nil
+}
+
+// communityItem converts a GraphQL issue or pull request node
+func (n communityNode) communityItem(pullRequest bool) CommunityItem {
+	item := CommunityItem{
+		Number:            n.Number,
+		PullRequest:       pullRequest,
+		State:             n.State,
+		AuthorAssociation: n.AuthorAssociation,
+		CreatedAt:         n.CreatedAt,
+		ClosedAt:          n.ClosedAt,
+	}
+	item.Author, item.AuthorIsBot = n.Author.identity()
+	for _, label := range n.Labels.Nodes {
+		item.Labels = append(item.Labels, label.Name)
+	}
+	for _, comment := range n.Comments.Nodes {
+		response := CommunityResponse{AuthorAssociation: comment.AuthorAssociation, CreatedAt: comment.CreatedAt}
+		response.Author, response.AuthorIsBot = comment.Author.identity()
+		item.Responses = append(item.Responses, response)
+	}
+	var lastReview *time.Time
+	for _, review := range n.Reviews.Nodes {
+		if review.SubmittedAt == nil {
+			continue // Pending review
+		}
+		response := CommunityResponse{AuthorAssociation: review.AuthorAssociation, CreatedAt: *review.SubmittedAt}
+		response.Author, response.AuthorIsBot = review.Author.identity()
+		item.Responses = append(item.Responses, response)
+		lastReview = review.SubmittedAt
+	}
+
+	// Comments and reviews come oldest first, so a truncated list is complete up to its last
+	// node; without one, nothing of it was read
+	if n.Comments.PageInfo.HasNextPage {
+		until := n.CreatedAt
+		if len(n.Comments.Nodes) > 0 {
+			until = n.Comments.Nodes[len(n.Comments.Nodes)-1].CreatedAt
+		}
+		item.ResponsesUntil = &until
+	}
+	if n.Reviews.PageInfo.HasNextPage {
+		until := n.CreatedAt
+		if lastReview != nil {
+			until = *lastReview
+		}
+		if item.ResponsesUntil == nil || until.Before(*item.ResponsesUntil) {
+			item.ResponsesUntil = &until
+		}
+	}
+	return item
+}
+
+// identity returns the login of an actor and whether it is a bot. Deleted accounts have no actor.
+func (a *communityActor) identity() (string, bool) {
+	if a == nil {
+		return "ghost", false
+	}
+	return a.Login, a.Type == "Bot" || strings.HasSuffix(a.Login, "[bot]")
+}
+
+// GetOpenIssueCounts implements CommunityClient with issue searches
+func (gc *GraphQLClient) GetOpenIssueCounts(ctx context.Context, owner, repo string) (OpenIssueCounts, error) {
+	query := `
+	query GetOpenIssueCounts($open: String!, $unlabeled: String!, $goodFirst: String!) {
+		open: search(query: $open, type: ISSUE) { issueCount }
+		unlabeled: search(query: $unlabeled, type: ISSUE) { issueCount }
+		goodFirst: search(query: $goodFirst, type: ISSUE) { issueCount }
+	}`
+
+	labels := make([]string, 0, len(goodFirstIssueLabels))
+	for _, label := range goodFirstIssueLabels {
+		labels = append(labels, `"`+label+`"`)
+	}
+	open := fmt.Sprintf("repo:%s/%s is:issue is:open", owner, repo)
+	variables := map[string]interface{}{
+		"open":      open,
+		"unlabeled": open + " no:label",
+		"goodFirst": open + " label:" + strings.Join(labels, ","),
+	}
+
+	type count struct {
+		IssueCount int `json:"issueCount"`
+	}
+	var response struct {
+		Open      count `json:"open"`
+		Unlabeled count `json:"unlabeled"`
+		GoodFirst count `json:"goodFirst"`
+	}
+	if err := gc.executeQuery(ctx, query, variables, &response); err != nil {
+		return OpenIssueCounts{Incomplete: true}, fmt.Errorf("failed to count open issues: %w", err)
+	}
+	return OpenIssueCounts{
+		Open:      response.Open.IssueCount,
+		Unlabeled: response.Unlabeled.IssueCount,
+		GoodFirst: response.GoodFirst.IssueCount,
+	}, nil
+}
+
 // Private methods
 
 // executeQuery executes a GraphQL query
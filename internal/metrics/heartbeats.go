@@ -0,0 +1,196 @@
+// Package metrics - Editor heartbeats aggregated into coding time
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeartbeatTimeout is the longest gap between two heartbeats still counted as coding,
+// matching WakaTime's default keystroke timeout
+const HeartbeatTimeout = 15 * time.Minute
+
+// Heartbeat is an editor activity ping in the WakaTime API format
+type Heartbeat struct {
+	User      string  `json:"user,omitempty"`
+	Entity    string  `json:"entity"`             // File, domain or app
+	Type      string  `json:"type"`               // file, domain, app
+	Category  string  `json:"category,omitempty"` // coding, building, debugging, code reviewing, ...
+	Time      float64 `json:"time"`               // Unix seconds with fractions
+	Project   string  `json:"project,omitempty"`
+	Branch    string  `json:"branch,omitempty"`
+	Language  string  `json:"language,omitempty"`
+	IsWrite   bool    `json:"is_write,omitempty"`
+	Lines     int     `json:"lines,omitempty"`
+	LineNo    int     `json:"lineno,omitempty"`
+	CursorPos int     `json:"cursorpos,omitempty"`
+	UserAgent string  `json:"user_agent,omitempty"`
+}
+
+// Timestamp returns the heartbeat time
+func (h Heartbeat) Timestamp() time.Time {
+	seconds, fraction := math.Modf(h.Time)
+	return time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+}
+
+// isCoding reports whether the heartbeat is coding activity; plugins omit the category for coding
+func (h Heartbeat) isCoding() bool {
+	return h.Category == "" || h.Category == "coding"
+}
+
+// HeartbeatStore keeps editor heartbeats per user in memory and aggregates them into coding
+// time, standing in for wakatime.com when editor activity must stay on-prem
+type HeartbeatStore struct {
+	mu         sync.RWMutex
+	heartbeats map[string][]Heartbeat
+	limit      int
+}
+
+// NewHeartbeatStore creates a store keeping up to limit heartbeats per user
+func NewHeartbeatStore(limit int) *HeartbeatStore {
+	if limit <= 0 {
+		limit = 500000
+	}
+	return &HeartbeatStore{
+		heartbeats: make(map[string][]Heartbeat),
+		limit:      limit,
+	}
+}
+
+// Add stores heartbeats of a user
+func (s *HeartbeatStore) Add(user string, heartbeats ...Heartbeat) error {
+	if user == "" {
+		return fmt.Errorf("heartbeat user is required")
+	}
+	for i, heartbeat := range heartbeats {
+		if heartbeat.Entity == "" || heartbeat.Time <= 0 {
+			return fmt.Errorf("heartbeat %d: entity and time are required", i+1)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(user)
+	stored := s.heartbeats[key]
+	for _, heartbeat := range heartbeats {
+		heartbeat.User = user
+		stored = append(stored, heartbeat)
+	}
+	if len(stored) > s.limit {
+		stored = stored[len(stored)-s.limit:]
+	}
+	s.heartbeats[key] = stored
+	return nil
+}
+
+// GetCodingTime implements WakaTimeClient. An empty user covers every user; an empty repo
+// covers every project. Projects match the repository name with or without its owner.
+func (s *HeartbeatStore) GetCodingTime(ctx context.Context, user, repo string, since time.Time) (*CodingTime, error) {
+	codingTime := s.CodingTime(user, repo, since, time.Now())
+	return &codingTime, nil
+}
+
+// CodingTime aggregates heartbeats between since and until into coding time per project and
+// language. Consecutive heartbeats of a user up to HeartbeatTimeout apart count as activity.
+func (s *HeartbeatStore) CodingTime(user, repo string, since, until time.Time) CodingTime {
+	s.mu.RLock()
+	var users [][]Heartbeat
+	if user != "" {
+		users = append(users, s.heartbeats[strings.ToLower(user)])
+	} else {
+		for _, heartbeats := range s.heartbeats {
+			users = append(users, heartbeats)
+		}
+	}
+	s.mu.RUnlock()
+
+	var total, coding float64
+	languages := make(map[string]float64)
+	projects := make(map[string]float64)
+	for _, heartbeats := range users {
+		var selected []Heartbeat
+		for _, heartbeat := range heartbeats {
+			at := heartbeat.Timestamp()
+			if at.Before(since) || !at.Before(until) || !matchesProject(heartbeat.Project, repo) {
+				continue
+			}
+			selected = append(selected, heartbeat)
+		}
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i].Time < selected[j].Time
+		})
+
+		// Each heartbeat is credited with the time until the next one, unless the gap is idle time
+		for i := 0; i+1 < len(selected); i++ {
+			gap := selected[i+1].Time - selected[i].Time
+			if gap <= 0 || gap > HeartbeatTimeout.Seconds() {
+				continue
+			}
+			hours := gap / 3600
+			total += hours
+			if selected[i].isCoding() {
+				coding += hours
+				languages[firstNonEmpty(selected[i].Language, "Other")] += hours
+			}
+			projects[firstNonEmpty(selected[i].Project, "Unknown")] += hours
+		}
+	}
+
+	periodDays := int(until.Sub(since).Hours() / 24)
+	if periodDays == 0 {
+		periodDays = 1
+	}
+	codingTime := CodingTime{
+		TotalHours:  total,
+		CodingHours: coding,
+		Period:      periodDays,
+	}
+	for name, hours := range languages {
+		codingTime.Languages = append(codingTime.Languages, LanguageTime{Name: name, Hours: hours})
+	}
+	sort.Slice(codingTime.Languages, func(i, j int) bool {
+		return codingTime.Languages[i].Hours > codingTime.Languages[j].Hours
+	})
+	for name, hours := range projects {
+		codingTime.Projects = append(codingTime.Projects, ProjectTime{Name: name, Hours: hours})
+	}
+	sort.Slice(codingTime.Projects, func(i, j int) bool {
+		return codingTime.Projects[i].Hours > codingTime.Projects[j].Hours
+	})
+	return codingTime
+}
+
+// Users returns the users with stored heartbeats
+func (s *HeartbeatStore) Users() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []string
+	for _, heartbeats := range s.heartbeats {
+		if len(heartbeats) > 0 {
+			users = append(users, heartbeats[0].User)
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+// matchesProject reports whether an editor project is the repository, given as "owner/name" or "name"
+func matchesProject(project, repo string) bool {
+	if repo == "" {
+		return true
+	}
+	if strings.EqualFold(project, repo) {
+		return true
+	}
+	if _, name, ok := strings.Cut(repo, "/"); ok {
+		return strings.EqualFold(project, name)
+	}
+	return false
+}
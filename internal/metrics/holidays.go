@@ -0,0 +1,294 @@
+// Package metrics - Holiday calendars for business-day and working-hours calculations
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Holiday is a non-working day. Recurring holidays repeat on the same month and day every year.
+type Holiday struct {
+	Date      string `json:"date" yaml:"date"` // YYYY-MM-DD
+	Name      string `json:"name" yaml:"name"`
+	Recurring bool   `json:"recurring,omitempty" yaml:"recurring"`
+}
+
+// HolidayCalendar is a named list of holidays, e.g. one per country or office
+type HolidayCalendar struct {
+	Name     string    `json:"name" yaml:"name"`
+	Holidays []Holiday `json:"holidays" yaml:"holidays"`
+
+	dates     map[string]string // YYYY-MM-DD -> name
+	recurring map[string]string // MM-DD -> name
+}
+
+// HolidayConfig assigns holiday calendars to repositories. Teams pick theirs through
+// WorkingHours.HolidayCalendar; a repository assignment takes precedence over the team's.
+// Calendars are loaded from Files by LoadCalendars or set directly.
+type HolidayConfig struct {
+	Files        map[string]string           `json:"files,omitempty" yaml:"calendars"` // Calendar name -> ICS or YAML file
+	Calendars    map[string]*HolidayCalendar `json:"calendars" yaml:"-"`
+	Default      string                      `json:"default" yaml:"default"`           // Calendar for repositories and teams without one
+	Repositories map[string]string           `json:"repositories" yaml:"repositories"` // "owner/name" or glob -> calendar name
+}
+
+// NewHolidayCalendar creates a calendar from a list of holidays
+func NewHolidayCalendar(name string, holidays []Holiday) (*HolidayCalendar, error) {
+	calendar := &HolidayCalendar{Name: name}
+	for _, holiday := range holidays {
+		if err := calendar.Add(holiday); err != nil {
+			return nil, err
+		}
+	}
+	return calendar, nil
+}
+
+// Add adds a holiday to the calendar
+func (c *HolidayCalendar) Add(holiday Holiday) error {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(holiday.Date))
+	if err != nil {
+		return fmt.Errorf("invalid holiday date %q: %w", holiday.Date, err)
+	}
+	if c.dates == nil {
+		c.dates = make(map[string]string)
+		c.recurring = make(map[string]string)
+	}
+
+	holiday.Date = date.Format("2006-01-02")
+	c.Holidays = append(c.Holidays, holiday)
+	if holiday.Recurring {
+		c.recurring[date.Format("01-02")] = holiday.Name
+	} else {
+		c.dates[holiday.Date] = holiday.Name
+	}
+	return nil
+}
+
+// IsHoliday reports whether the calendar day of t, in t's location, is a holiday
+func (c *HolidayCalendar) IsHoliday(t time.Time) bool {
+	_, ok := c.HolidayName(t)
+	return ok
+}
+
+// HolidayName returns the name of the holiday on the calendar day of t
+func (c *HolidayCalendar) HolidayName(t time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	if name, ok := c.dates[t.Format("2006-01-02")]; ok {
+		return name, true
+	}
+	name, ok := c.recurring[t.Format("01-02")]
+	return name, ok
+}
+
+// LoadHolidayCalendar reads a calendar from an ICS file or a YAML (or JSON) list of holidays.
+// The calendar is named after the file unless the YAML sets a name.
+func LoadHolidayCalendar(file string) (*HolidayCalendar, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday calendar %s: %w", file, err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if strings.EqualFold(filepath.Ext(file), ".ics") {
+		return ParseICSCalendar(name, bytes.NewReader(data))
+	}
+	return ParseHolidayYAML(name, data)
+}
+
+// LoadHolidayCalendars loads calendars from files keyed by calendar name
+func LoadHolidayCalendars(files map[string]string) (map[string]*HolidayCalendar, error) {
+	calendars := make(map[string]*HolidayCalendar, len(files))
+	for name, file := range files {
+		calendar, err := LoadHolidayCalendar(file)
+		if err != nil {
+			return nil, err
+		}
+		calendar.Name = name
+		calendars[name] = calendar
+	}
+	return calendars, nil
+}
+
+// ParseHolidayYAML parses a calendar of the form
+//
+//	name: br
+//	holidays:
+//	  - date: 2025-04-21
+//	    name: Tiradentes
+//	    recurring: true
+func ParseHolidayYAML(name string, data []byte) (*HolidayCalendar, error) {
+	var file HolidayCalendar
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse holiday calendar: %w", err)
+	}
+	if file.Name != "" {
+		name = file.Name
+	}
+	return NewHolidayCalendar(name, file.Holidays)
+}
+
+// ParseICSCalendar reads all-day VEVENTs from an iCalendar stream. Multi-day events
+// add every day up to DTEND, and events with a yearly RRULE become recurring holidays.
+func ParseICSCalendar(name string, r io.Reader) (*HolidayCalendar, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ICS calendar: %w", err)
+	}
+
+	calendar := &HolidayCalendar{Name: name}
+	var event map[string]string
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]string)
+		case line == "END:VEVENT":
+			if event == nil {
+				continue
+			}
+			if err := calendar.addICSEvent(event); err != nil {
+				return nil, err
+			}
+			event = nil
+		case event != nil:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			// Drop parameters such as DTSTART;VALUE=DATE
+			key, _, _ = strings.Cut(key, ";")
+			event[strings.ToUpper(key)] = value
+		}
+	}
+	return calendar, nil
+}
+
+// addICSEvent adds the days covered by an ICS event
+func (c *HolidayCalendar) addICSEvent(event map[string]string) error {
+	start, err := parseICSDate(event["DTSTART"])
+	if err != nil {
+		return err
+	}
+	end := start.AddDate(0, 0, 1)
+	if value, ok := event["DTEND"]; ok {
+		if end, err = parseICSDate(value); err != nil {
+			return err
+		}
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+	}
+
+	recurring := strings.Contains(strings.ToUpper(event["RRULE"]), "FREQ=YEARLY")
+	summary := strings.ReplaceAll(event["SUMMARY"], `\,`, ",")
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if err := c.Add(Holiday{Date: day.Format("2006-01-02"), Name: summary, Recurring: recurring}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseICSDate parses the date part of an ICS DATE or DATE-TIME value
+func parseICSDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid ICS date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid ICS date %q: %w", value, err)
+	}
+	return date, nil
+}
+
+// unfoldICSLines splits an ICS stream into logical lines, joining folded continuations
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// CalendarFor returns the holiday calendar of a repository: its own assignment, then
+// the team's calendar, then the default. Nil means no holidays.
+func (c HolidayConfig) CalendarFor(teamCalendar, fullName string) *HolidayCalendar {
+	// An exact repository wins over globs, and longer globs over shorter ones
+	name := strings.ToLower(fullName)
+	best := ""
+	for pattern := range c.Repositories {
+		if strings.EqualFold(pattern, fullName) {
+			best = pattern
+			break
+		}
+		if matched, err := path.Match(strings.ToLower(pattern), name); err == nil && matched && len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+	if best != "" {
+		return c.Calendars[c.Repositories[best]]
+	}
+	if teamCalendar != "" {
+		return c.Calendars[teamCalendar]
+	}
+	return c.Calendars[c.Default]
+}
+
+// LoadCalendars loads the calendar files of Files into Calendars
+func (c *HolidayConfig) LoadCalendars() error {
+	calendars, err := LoadHolidayCalendars(c.Files)
+	if err != nil {
+		return err
+	}
+	if c.Calendars == nil {
+		c.Calendars = make(map[string]*HolidayCalendar, len(calendars))
+	}
+	for name, calendar := range calendars {
+		c.Calendars[name] = calendar
+	}
+	return nil
+}
+
+// Validate checks that every calendar assigned by default, to a repository or to one of
+// the teams was loaded
+func (c HolidayConfig) Validate(teams []TeamConfig) error {
+	names := []string{c.Default}
+	for _, calendar := range c.Repositories {
+		names = append(names, calendar)
+	}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if _, ok := c.Calendars[name]; !ok {
+			return fmt.Errorf("holiday calendar %s is not loaded", name)
+		}
+	}
+	for _, team := range teams {
+		name := team.WorkingHours.HolidayCalendar
+		if name == "" {
+			continue
+		}
+		if _, ok := c.Calendars[name]; !ok {
+			return fmt.Errorf("team %s: holiday calendar %s is not loaded", team.Name, name)
+		}
+	}
+	return nil
+}
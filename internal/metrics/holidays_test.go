@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestParseICSCalendar(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250421",
+		"SUMMARY:Tiradentes",
+		"RRULE:FREQ=YEARLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250303",
+		"DTEND;VALUE=DATE:20250305",
+		"SUMMARY:Carnaval\\, segunda e",
+		"  terça",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20251225T000000Z",
+		"DTEND:20251225T000000Z",
+		"SUMMARY:Natal",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	calendar, err := ParseICSCalendar("br", strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("ParseICSCalendar failed: %v", err)
+	}
+	if calendar.Name != "br" {
+		t.Errorf("Expected calendar br, got %q", calendar.Name)
+	}
+
+	tests := []struct {
+		at      time.Time
+		holiday string
+	}{
+		{day(2025, 4, 21), "Tiradentes"},
+		{day(2031, 4, 21), "Tiradentes"}, // Yearly RRULE recurs
+		{day(2025, 3, 3), "Carnaval, segunda e terça"},
+		{day(2025, 3, 4), "Carnaval, segunda e terça"},
+		{day(2025, 3, 5), ""}, // DTEND is exclusive
+		{day(2025, 12, 25), "Natal"},
+		{day(2026, 12, 25), ""}, // Without RRULE the event happens once
+	}
+	for _, tt := range tests {
+		name, ok := calendar.HolidayName(tt.at)
+		if ok != (tt.holiday != "") || name != tt.holiday {
+			t.Errorf("HolidayName(%s) = %q, %v, expected %q", tt.at.Format("2006-01-02"), name, ok, tt.holiday)
+		}
+	}
+
+	invalid := "BEGIN:VEVENT\nDTSTART:2025\nEND:VEVENT\n"
+	if _, err := ParseICSCalendar("bad", strings.NewReader(invalid)); err == nil {
+		t.Error("Expected an error for an invalid DTSTART")
+	}
+}
+
+func TestParseHolidayYAML(t *testing.T) {
+	data := []byte(`name: us
+holidays:
+  - date: 2025-07-04
+    name: Independence Day
+    recurring: true
+  - date: 2025-11-27
+    name: Thanksgiving
+`)
+
+	calendar, err := ParseHolidayYAML("file-name", data)
+	if err != nil {
+		t.Fatalf("ParseHolidayYAML failed: %v", err)
+	}
+	if calendar.Name != "us" {
+		t.Errorf("Expected the name set in the file, got %q", calendar.Name)
+	}
+	if !calendar.IsHoliday(day(2030, 7, 4)) || !calendar.IsHoliday(day(2025, 11, 27)) || calendar.IsHoliday(day(2026, 11, 27)) {
+		t.Errorf("Unexpected holidays %+v", calendar.Holidays)
+	}
+
+	// Holidays match the calendar day in the time's own location
+	tokyo := time.FixedZone("JST", 9*3600)
+	if !calendar.IsHoliday(time.Date(2025, 7, 3, 20, 0, 0, 0, time.UTC).In(tokyo)) {
+		t.Error("Expected the evening of July 3rd UTC to be July 4th in Tokyo")
+	}
+
+	if _, err := ParseHolidayYAML("bad", []byte("holidays:\n  - date: 04/07/2025\n")); err == nil {
+		t.Error("Expected an error for a date not in YYYY-MM-DD")
+	}
+	var none *HolidayCalendar
+	if none.IsHoliday(day(2025, 7, 4)) {
+		t.Error("Expected a nil calendar to have no holidays")
+	}
+}
+
+func TestHolidayConfigLoadAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	ics := filepath.Join(dir, "brazil.ics")
+	yml := filepath.Join(dir, "us.yml")
+	if err := os.WriteFile(ics, []byte("BEGIN:VEVENT\nDTSTART;VALUE=DATE:20250421\nSUMMARY:Tiradentes\nEND:VEVENT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(yml, []byte("holidays:\n  - {date: 2025-07-04, name: Independence Day}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := HolidayConfig{
+		Files:        map[string]string{"br": ics, "us": yml},
+		Default:      "us",
+		Repositories: map[string]string{"acme/*": "br", "acme/api": "us"},
+	}
+	if err := config.LoadCalendars(); err != nil {
+		t.Fatalf("LoadCalendars failed: %v", err)
+	}
+	if config.Calendars["br"].Name != "br" || !config.Calendars["br"].IsHoliday(day(2025, 4, 21)) {
+		t.Errorf("Expected the ICS calendar loaded under its configured name, got %+v", config.Calendars["br"])
+	}
+
+	teams := []TeamConfig{{Name: "payments", WorkingHours: WorkingHours{HolidayCalendar: "br"}}}
+	if err := config.Validate(teams); err != nil {
+		t.Errorf("Expected a valid config, got %v", err)
+	}
+	unknown := []TeamConfig{{Name: "support", WorkingHours: WorkingHours{HolidayCalendar: "uk"}}}
+	if err := config.Validate(unknown); err == nil || !strings.Contains(err.Error(), "support") {
+		t.Errorf("Expected an error naming the team with an unknown calendar, got %v", err)
+	}
+
+	// An exact repository wins over globs; the team's calendar comes before the default
+	if got := config.CalendarFor("br", "acme/api"); got != config.Calendars["us"] {
+		t.Errorf("Expected the exact repository assignment, got %+v", got)
+	}
+	if got := config.CalendarFor("us", "acme/web"); got != config.Calendars["br"] {
+		t.Errorf("Expected the glob assignment over the team's calendar, got %+v", got)
+	}
+	if got := config.CalendarFor("br", "other/web"); got != config.Calendars["br"] {
+		t.Errorf("Expected the team's calendar, got %+v", got)
+	}
+	if got := config.CalendarFor("", "other/web"); got != config.Calendars["us"] {
+		t.Errorf("Expected the default calendar, got %+v", got)
+	}
+
+	missing := HolidayConfig{Files: map[string]string{"de": filepath.Join(dir, "missing.ics")}}
+	if err := missing.LoadCalendars(); err == nil {
+		t.Error("Expected an error for a missing calendar file")
+	}
+}
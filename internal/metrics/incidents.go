@@ -0,0 +1,211 @@
+// Package metrics - Incident sources for MTTR and change failure rate
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IncidentSource provides production incidents with real open and resolve timestamps
+type IncidentSource interface {
+	Name() string
+	GetIncidents(ctx context.Context, owner, repo string, since time.Time) ([]Incident, error)
+}
+
+// Incident represents a production incident reported by an incident source
+type Incident struct {
+	ID            string     `json:"id"`
+	Source        string     `json:"source"` // "github_issues", "jira", "pagerduty", "opsgenie", "webhook"
+	Repository    string     `json:"repository,omitempty"`
+	Title         string     `json:"title"`
+	Severity      string     `json:"severity"` // "critical", "high", "medium", "low"
+	Environment   string     `json:"environment,omitempty"`
+	OpenedAt      time.Time  `json:"opened_at"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	DeploymentSHA string     `json:"deployment_sha,omitempty"` // Deployment that caused the incident, when known
+	URL           string     `json:"url,omitempty"`
+}
+
+// IsResolved reports whether the incident has a resolve timestamp
+func (i Incident) IsResolved() bool {
+	return i.ResolvedAt != nil && !i.ResolvedAt.Before(i.OpenedAt)
+}
+
+// ResolutionTime returns the time between open and resolve
+func (i Incident) ResolutionTime() time.Duration {
+	if !i.IsResolved() {
+		return 0
+	}
+	return i.ResolvedAt.Sub(i.OpenedAt)
+}
+
+// collectIncidents gathers incidents from all sources, skipping sources that fail
+func collectIncidents(ctx context.Context, sources []IncidentSource, owner, repo string, since time.Time) ([]Incident, []string) {
+	var incidents []Incident
+	var warnings []string
+
+	for _, source := range sources {
+		found, err := source.GetIncidents(ctx, owner, repo, since)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("incident source %s failed: %v", source.Name(), err))
+			continue
+		}
+		incidents = append(incidents, found...)
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].OpenedAt.Before(incidents[j].OpenedAt)
+	})
+
+	return incidents, warnings
+}
+
+// calculateIncidentMTTR calculates mean time to recovery in hours from resolved incidents,
+// measuring each resolution with elapsed
+func calculateIncidentMTTR(incidents []Incident, elapsed func(start, end time.Time) float64) float64 {
+	total := 0.0
+	resolved := 0
+	for _, incident := range incidents {
+		if !incident.IsResolved() {
+			continue
+		}
+		total += elapsed(incident.OpenedAt, *incident.ResolvedAt)
+		resolved++
+	}
+
+	if resolved == 0 {
+		return 0
+	}
+	return total / float64(resolved)
+}
+
+// deploymentKey identifies a deployment by SHA, falling back to its ID
+func deploymentKey(deployment Deployment) string {
+	if deployment.SHA != "" {
+		return deployment.SHA
+	}
+	return fmt.Sprintf("deployment-%d", deployment.ID)
+}
+
+// shaMatches compares two commit SHAs, allowing either to be abbreviated
+func shaMatches(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// IncidentStore keeps incidents received through webhooks in memory
+type IncidentStore struct {
+	mu        sync.RWMutex
+	incidents map[string]Incident
+}
+
+// NewIncidentStore creates a new in-memory incident store
+func NewIncidentStore() *IncidentStore {
+	return &IncidentStore{
+		incidents: make(map[string]Incident),
+	}
+}
+
+// Record stores or updates an incident, keyed by source and ID
+func (s *IncidentStore) Record(incident Incident) error {
+	if incident.ID == "" {
+		return fmt.Errorf("incident ID is required")
+	}
+	if incident.OpenedAt.IsZero() {
+		return fmt.Errorf("incident opened_at is required")
+	}
+	if incident.Source == "" {
+		incident.Source = "webhook"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := incident.Source + ":" + incident.ID
+	if existing, ok := s.incidents[key]; ok {
+		// Updates keep the original open time and any earlier resolve time
+		if existing.OpenedAt.Before(incident.OpenedAt) {
+			incident.OpenedAt = existing.OpenedAt
+		}
+		if incident.ResolvedAt == nil {
+			incident.ResolvedAt = existing.ResolvedAt
+		}
+		incident.Repository = firstNonEmpty(incident.Repository, existing.Repository)
+		incident.Title = firstNonEmpty(incident.Title, existing.Title)
+		incident.Severity = firstNonEmpty(incident.Severity, existing.Severity)
+		incident.Environment = firstNonEmpty(incident.Environment, existing.Environment)
+		incident.DeploymentSHA = firstNonEmpty(incident.DeploymentSHA, existing.DeploymentSHA)
+		incident.URL = firstNonEmpty(incident.URL, existing.URL)
+	}
+	s.incidents[key] = incident
+	return nil
+}
+
+// Name implements IncidentSource
+func (s *IncidentStore) Name() string {
+	return "webhook"
+}
+
+// GetIncidents implements IncidentSource
+func (s *IncidentStore) GetIncidents(ctx context.Context, owner, repo string, since time.Time) ([]Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fullName := owner + "/" + repo
+	var incidents []Incident
+	for _, incident := range s.incidents {
+		if incident.Repository != "" && !strings.EqualFold(incident.Repository, fullName) {
+			continue
+		}
+		if incident.OpenedAt.Before(since) {
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, nil
+}
+
+// HasIncidents reports whether the store holds incidents of a repository, at any time
+func (s *IncidentStore) HasIncidents(owner, repo string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fullName := owner + "/" + repo
+	for _, incident := range s.incidents {
+		if incident.Repository == "" || strings.EqualFold(incident.Repository, fullName) {
+			return true
+		}
+	}
+	return false
+}
+
+// incidentMode reports whether incidents replace the workflow and deployment heuristics for
+// MTTR and change failure rate: when a source other than the webhook store is registered, or
+// the store has received incidents of the repository. An empty store alone would report no
+// failures and no recovery time rather than an unknown.
+func incidentMode(sources []IncidentSource, owner, repo string) bool {
+	for _, source := range sources {
+		store, ok := source.(*IncidentStore)
+		if !ok || store.HasIncidents(owner, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
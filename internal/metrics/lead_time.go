@@ -0,0 +1,113 @@
+// Package metrics - Commit-to-production lead time from deployment SHAs and git ancestry
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CommitRangeClient lists the commits reachable from head but not from base (git log base..head)
+type CommitRangeClient interface {
+	GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]Commit, error)
+}
+
+// LeadTimeStats holds commit-to-production lead time percentiles in hours
+type LeadTimeStats struct {
+	P50     float64 `json:"p50_hours"`
+	P75     float64 `json:"p75_hours"`
+	P95     float64 `json:"p95_hours"`
+	Mean    float64 `json:"mean_hours"`
+	Commits int     `json:"commits"`
+}
+
+// CommitLeadTime is the lead time of a single commit to the deployment that shipped it
+type CommitLeadTime struct {
+	SHA           string    `json:"sha"`
+	AuthoredAt    time.Time `json:"authored_at"`
+	Environment   string    `json:"environment,omitempty"`
+	DeploymentSHA string    `json:"deployment_sha"`
+	DeployedAt    time.Time `json:"deployed_at"`
+	Hours         float64   `json:"hours"`
+}
+
+// collectCommitLeadTimes links every commit to the first deployment of each environment containing it.
+// Commits shipped by a deployment are those in previous..current for consecutive deployments
+// of the same environment, so the oldest deployment in the window only serves as a base.
+// Callers pass production deployments, or all deployments for a per-environment breakdown.
+func collectCommitLeadTimes(ctx context.Context, client CommitRangeClient, owner, repo string, deployments []Deployment) ([]CommitLeadTime, error) {
+	var leadTimes []CommitLeadTime
+	seen := make(map[string]bool)
+	lastByEnv := make(map[string]Deployment)
+
+	for _, deployment := range successfulDeployments(deployments) {
+		previous, ok := lastByEnv[deployment.Environment]
+		lastByEnv[deployment.Environment] = deployment
+		if !ok || deployment.SHA == "" || previous.SHA == "" || shaMatches(previous.SHA, deployment.SHA) {
+			continue
+		}
+
+		commits, err := client.GetCommitsBetween(ctx, owner, repo, previous.SHA, deployment.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits between %s and %s: %w", shortSHA(previous.SHA), shortSHA(deployment.SHA), err)
+		}
+
+		for _, commit := range commits {
+			key := deployment.Environment + "|" + commit.SHA
+			if seen[key] || commit.Date.IsZero() || commit.Date.After(deployment.CreatedAt) {
+				continue
+			}
+			seen[key] = true
+			leadTimes = append(leadTimes, CommitLeadTime{
+				SHA:           commit.SHA,
+				AuthoredAt:    commit.Date,
+				Environment:   deployment.Environment,
+				DeploymentSHA: deployment.SHA,
+				DeployedAt:    deployment.CreatedAt,
+				Hours:         deployment.CreatedAt.Sub(commit.Date).Hours(),
+			})
+		}
+	}
+
+	return leadTimes, nil
+}
+
+// leadTimeStatsFromHours computes percentiles from a list of durations in hours
+func leadTimeStatsFromHours(hours []float64) LeadTimeStats {
+	if len(hours) == 0 {
+		return LeadTimeStats{}
+	}
+
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, h := range sorted {
+		total += h
+	}
+
+	return LeadTimeStats{
+		P50:     percentile(sorted, 0.50),
+		P75:     percentile(sorted, 0.75),
+		P95:     percentile(sorted, 0.95),
+		Mean:    total / float64(len(sorted)),
+		Commits: len(sorted),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+func TestTimeSeriesUsesCommitLeadTime(t *testing.T) {
+	calculator := NewEnhancedDORACalculator(&doraTestClient{}, nil, nil, DORAConfig{})
+	timeRange := TimeRange{Start: baseTime, End: at(72), Timezone: "UTC"}
+	prs := []PullRequest{
+		{Number: 1, CreatedAt: at(-200), MergedAt: timePtr(at(2))},
+	}
+	leadTimes := []CommitLeadTime{
+		{SHA: "a", AuthoredAt: at(-10), DeployedAt: at(4)},
+		{SHA: "b", AuthoredAt: at(-2), DeployedAt: at(4)},
+		{SHA: "c", AuthoredAt: at(0), DeployedAt: at(4)},
+		{SHA: "d", AuthoredAt: at(20), DeployedAt: at(30)},
+	}
+	clock := newWorkingClock(nil, nil, nil)
+
+	series := calculator.generateTimeSeries(prs, nil, nil, leadTimes, timeRange, clock)
+	byDay := make(map[time.Time]float64)
+	for _, point := range series {
+		byDay[point.Timestamp] = point.LeadTimeHours
+	}
+	if got := byDay[baseTime.Truncate(24*time.Hour)]; got != 6 {
+		t.Errorf("Expected the median commit lead time of 6h on day one, got %.1f", got)
+	}
+	if got := byDay[baseTime.Truncate(24*time.Hour).AddDate(0, 0, 1)]; got != 10 {
+		t.Errorf("Expected 10h on day two, got %.1f", got)
+	}
+
+	// Without commit ancestry the series falls back to PR merge time
+	series = calculator.generateTimeSeries(prs, nil, nil, nil, timeRange, clock)
+	for _, point := range series {
+		if point.Timestamp.Equal(baseTime.Truncate(24*time.Hour)) && point.LeadTimeHours != 202 {
+			t.Errorf("Expected PR merge time of 202h, got %.1f", point.LeadTimeHours)
+		}
+	}
+}
+
+// failingRangeClient serves commits but fails every ancestry query
+type failingRangeClient struct{}
+
+func (failingRangeClient) GetCommits(ctx context.Context, owner, repo string, since time.Time) ([]Commit, error) {
+	return nil, nil
+}
+
+func (failingRangeClient) GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]Commit, error) {
+	return nil, errors.New("object not found")
+}
+
+func TestDORACalculatorDegradesWithoutCommitRange(t *testing.T) {
+	now := time.Now()
+	client := &doraTestClient{
+		deployments: []Deployment{
+			{ID: 1, Environment: "production", State: "success", SHA: "aaaaaaa", CreatedAt: now.Add(-72 * time.Hour)},
+			{ID: 2, Environment: "production", State: "success", SHA: "bbbbbbb", CreatedAt: now.Add(-48 * time.Hour)},
+		},
+		prs: []PullRequest{
+			{Number: 1, CreatedAt: now.Add(-60 * time.Hour), MergedAt: timePtr(now.Add(-50 * time.Hour))},
+		},
+	}
+	dora := NewDORACalculator(client, nil)
+	dora.SetGitClient(failingRangeClient{})
+
+	metrics, err := dora.Calculate(context.Background(), types.Repository{Owner: "test-owner", Name: "test-repo"}, 30)
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if metrics.LeadTimeP50Hours < 9.9 || metrics.LeadTimeP50Hours > 10.1 {
+		t.Errorf("Expected lead time to fall back to PR merge time of 10h, got %.2f", metrics.LeadTimeP50Hours)
+	}
+	if len(metrics.Warnings) != 1 {
+		t.Errorf("Expected a commit lead time warning, got %v", metrics.Warnings)
+	}
+}
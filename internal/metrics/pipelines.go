@@ -0,0 +1,273 @@
+// Package metrics - CI workflow duration, queue time and reliability
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+const (
+	minWorkflowRuns           = 3    // Workflows with fewer runs are not ranked as least reliable
+	workflowRankingSize       = 3    // Workflows listed as slowest or least reliable
+	durationTrendThreshold    = 20.0 // Percent change in median duration that makes a trend
+	successRateTrendThreshold = 5.0  // Change in success rate points that makes a trend
+)
+
+// PipelineConfig tunes CI analytics
+type PipelineConfig struct {
+	// WaitFactor is the share of CI wall time a developer is blocked waiting on a run,
+	// used to estimate developer hours lost to CI
+	WaitFactor float64 `json:"wait_factor"`
+}
+
+// DefaultPipelineConfig assumes developers are blocked for half of the time CI runs
+func DefaultPipelineConfig() PipelineConfig {
+	return PipelineConfig{WaitFactor: 0.5}
+}
+
+// RunEventPullRequest is the event of runs triggered by a pull request
+const RunEventPullRequest = "pull_request"
+
+// pullRequestEvents are the run triggers, across providers, of pull request CI
+var pullRequestEvents = map[string]bool{
+	RunEventPullRequest:   true,
+	"pull_request_target": true, // GitHub, for pull requests from forks
+	"merge_request_event": true, // GitLab
+}
+
+// PipelineBucket holds workflow statistics for runs created in a period
+type PipelineBucket struct {
+	Start     time.Time             `json:"start"`
+	End       time.Time             `json:"end"`
+	Workflows []types.WorkflowStats `json:"workflows"`
+}
+
+// CalculatePipelineStats computes duration and queue percentiles, success rate and trend of
+// each workflow, ranks the slowest and least reliable workflows, and estimates developer
+// hours lost waiting on CI. Only runs triggered by a pull request keep a developer waiting;
+// scheduled and branch runs are left out of the estimate. Trends compare the second half of
+// the runs' time span with the first. Returns nil without runs.
+func CalculatePipelineStats(runs []WorkflowRun, config PipelineConfig) *types.PipelineStats {
+	if len(runs) == 0 {
+		return nil
+	}
+	if config.WaitFactor <= 0 {
+		config.WaitFactor = DefaultPipelineConfig().WaitFactor
+	}
+
+	first, last := runs[0].CreatedAt, runs[0].CreatedAt
+	for _, run := range runs {
+		if run.CreatedAt.Before(first) {
+			first = run.CreatedAt
+		}
+		if run.CreatedAt.After(last) {
+			last = run.CreatedAt
+		}
+	}
+	midpoint := first.Add(last.Sub(first) / 2)
+
+	stats := &types.PipelineStats{Runs: len(runs)}
+	ciHours := 0.0
+	for name, workflowRuns := range runsByWorkflow(runs) {
+		workflow := workflowStats(name, workflowRuns)
+
+		var earlier, later []WorkflowRun
+		for _, run := range workflowRuns {
+			if run.CreatedAt.Before(midpoint) {
+				earlier = append(earlier, run)
+			} else {
+				later = append(later, run)
+			}
+		}
+		applyWorkflowTrend(&workflow, workflowStats(name, earlier), workflowStats(name, later))
+		stats.Workflows = append(stats.Workflows, workflow)
+
+		for _, run := range workflowRuns {
+			duration, ok := runDuration(run)
+			if !ok {
+				continue
+			}
+			if run.Conclusion == "failure" {
+				stats.FailedRunHours += duration.Hours()
+			}
+			if pullRequestEvents[run.Event] {
+				queue, _ := runQueueTime(run)
+				ciHours += duration.Hours() + queue.Hours()
+			}
+		}
+	}
+	stats.DeveloperWaitHours = ciHours * config.WaitFactor
+
+	sort.SliceStable(stats.Workflows, func(i, j int) bool {
+		if stats.Workflows[i].DurationP50Minutes != stats.Workflows[j].DurationP50Minutes {
+			return stats.Workflows[i].DurationP50Minutes > stats.Workflows[j].DurationP50Minutes
+		}
+		return stats.Workflows[i].Name < stats.Workflows[j].Name
+	})
+	for _, workflow := range stats.Workflows {
+		if len(stats.SlowestWorkflows) == workflowRankingSize {
+			break
+		}
+		if workflow.DurationP50Minutes > 0 {
+			stats.SlowestWorkflows = append(stats.SlowestWorkflows, workflow.Name)
+		}
+	}
+
+	reliability := append([]types.WorkflowStats(nil), stats.Workflows...)
+	sort.SliceStable(reliability, func(i, j int) bool {
+		return reliability[i].SuccessRatePercent < reliability[j].SuccessRatePercent
+	})
+	for _, workflow := range reliability {
+		if len(stats.LeastReliableWorkflows) == workflowRankingSize {
+			break
+		}
+		if workflow.Runs >= minWorkflowRuns && workflow.SuccessRatePercent < 100 {
+			stats.LeastReliableWorkflows = append(stats.LeastReliableWorkflows, workflow.Name)
+		}
+	}
+
+	return stats
+}
+
+// bucketPipelineStats computes workflow statistics per period, bucketing runs by creation time
+func bucketPipelineStats(runs []WorkflowRun, timeRange TimeRange, period time.Duration) []PipelineBucket {
+	if period <= 0 || !timeRange.End.After(timeRange.Start) || len(runs) == 0 {
+		return nil
+	}
+
+	var buckets []PipelineBucket
+	for start := timeRange.Start; start.Before(timeRange.End); start = start.Add(period) {
+		end := start.Add(period)
+		if end.After(timeRange.End) {
+			end = timeRange.End
+		}
+		bucket := TimeRange{Start: start, End: end}
+
+		var inBucket []WorkflowRun
+		for _, run := range runs {
+			if bucket.Contains(run.CreatedAt) {
+				inBucket = append(inBucket, run)
+			}
+		}
+
+		var workflows []types.WorkflowStats
+		for name, workflowRuns := range runsByWorkflow(inBucket) {
+			workflows = append(workflows, workflowStats(name, workflowRuns))
+		}
+		sort.Slice(workflows, func(i, j int) bool {
+			return workflows[i].Name < workflows[j].Name
+		})
+
+		buckets = append(buckets, PipelineBucket{Start: start, End: end, Workflows: workflows})
+	}
+	return buckets
+}
+
+// runsByWorkflow groups runs by workflow name
+func runsByWorkflow(runs []WorkflowRun) map[string][]WorkflowRun {
+	byName := make(map[string][]WorkflowRun)
+	for _, run := range runs {
+		name := run.Name
+		if name == "" {
+			name = "unnamed"
+		}
+		byName[name] = append(byName[name], run)
+	}
+	return byName
+}
+
+// workflowStats computes duration and queue percentiles and success rate of a workflow's runs
+func workflowStats(name string, runs []WorkflowRun) types.WorkflowStats {
+	stats := types.WorkflowStats{Name: name, Runs: len(runs)}
+
+	var durations, queues []float64
+	succeeded, decided := 0, 0
+	for _, run := range runs {
+		if duration, ok := runDuration(run); ok {
+			durations = append(durations, duration.Minutes())
+		}
+		if queue, ok := runQueueTime(run); ok {
+			queues = append(queues, queue.Minutes())
+		}
+		// Cancelled and skipped runs say nothing about reliability
+		switch run.Conclusion {
+		case "success":
+			succeeded++
+			decided++
+		case "failure":
+			decided++
+		}
+	}
+
+	if decided > 0 {
+		stats.SuccessRatePercent = float64(succeeded) / float64(decided) * 100
+	}
+	sort.Float64s(durations)
+	sort.Float64s(queues)
+	stats.DurationP50Minutes = percentile(durations, 0.50)
+	stats.DurationP95Minutes = percentile(durations, 0.95)
+	stats.QueueP50Minutes = percentile(queues, 0.50)
+	stats.QueueP95Minutes = percentile(queues, 0.95)
+	return stats
+}
+
+// applyWorkflowTrend compares a workflow's later runs with its earlier runs. A workflow
+// degrades when it got slower or less reliable, and improves when it got faster or more
+// reliable without degrading.
+func applyWorkflowTrend(workflow *types.WorkflowStats, earlier, later types.WorkflowStats) {
+	if earlier.Runs == 0 || later.Runs == 0 {
+		return
+	}
+	if earlier.DurationP50Minutes > 0 {
+		workflow.DurationChangePercent = (later.DurationP50Minutes - earlier.DurationP50Minutes) / earlier.DurationP50Minutes * 100
+	}
+	workflow.SuccessRateChangePoints = later.SuccessRatePercent - earlier.SuccessRatePercent
+
+	switch {
+	case workflow.DurationChangePercent >= durationTrendThreshold || workflow.SuccessRateChangePoints <= -successRateTrendThreshold:
+		workflow.Trend = types.TrendDegrading
+	case workflow.DurationChangePercent <= -durationTrendThreshold || workflow.SuccessRateChangePoints >= successRateTrendThreshold:
+		workflow.Trend = types.TrendImproving
+	default:
+		workflow.Trend = types.TrendStable
+	}
+}
+
+// runDuration returns how long a completed attempt executed, from start (or creation) to last update
+func runDuration(run WorkflowRun) (time.Duration, bool) {
+	if run.Status != "completed" || run.Conclusion == "skipped" {
+		return 0, false
+	}
+	start := run.CreatedAt
+	switch {
+	case run.StartedAt != nil && !run.StartedAt.IsZero():
+		start = *run.StartedAt
+	case run.QueuedAt != nil && !run.QueuedAt.IsZero():
+		start = *run.QueuedAt
+	case run.Attempt > 1:
+		// The creation time of the first attempt would add the earlier attempts
+		return 0, false
+	}
+	if !run.UpdatedAt.After(start) {
+		return 0, false
+	}
+	return run.UpdatedAt.Sub(start), true
+}
+
+// runQueueTime returns how long an attempt waited for a runner, known only when the provider
+// reports the start time. A rerun keeps the creation time of its first attempt, so its queue
+// time is known only when the provider reports when the rerun was queued.
+func runQueueTime(run WorkflowRun) (time.Duration, bool) {
+	queued := run.CreatedAt
+	if run.QueuedAt != nil && !run.QueuedAt.IsZero() {
+		queued = *run.QueuedAt
+	} else if run.Attempt > 1 {
+		return 0, false
+	}
+	if run.StartedAt == nil || run.StartedAt.IsZero() || run.StartedAt.Before(queued) {
+		return 0, false
+	}
+	return run.StartedAt.Sub(queued), true
+}
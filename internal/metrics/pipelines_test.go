@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// completedRun is a run created at created hours, picked up at started and finished at finished
+func completedRun(id int, name, event, conclusion string, created, started, finished float64) WorkflowRun {
+	return WorkflowRun{
+		ID:         id,
+		Name:       name,
+		Status:     "completed",
+		Conclusion: conclusion,
+		Event:      event,
+		Attempt:    1,
+		CreatedAt:  at(created),
+		StartedAt:  timePtr(at(started)),
+		UpdatedAt:  at(finished),
+	}
+}
+
+func TestCalculatePipelineStats(t *testing.T) {
+	runs := []WorkflowRun{
+		completedRun(1, "ci", RunEventPullRequest, "success", 0, 0.5, 1.5),
+		completedRun(2, "ci", "pull_request_target", "failure", 10, 10.5, 11.5),
+		// Branch and scheduled runs keep nobody waiting
+		completedRun(3, "ci", "push", "success", 20, 20, 22),
+		completedRun(4, "nightly", "schedule", "failure", 30, 30, 34),
+	}
+
+	stats := CalculatePipelineStats(runs, PipelineConfig{})
+	if stats == nil {
+		t.Fatal("Expected pipeline stats for workflow runs")
+	}
+	if stats.Runs != 4 {
+		t.Errorf("Expected 4 runs, got %d", stats.Runs)
+	}
+	// Two pull request runs of an hour each after half an hour in the queue, half of it waited
+	if stats.DeveloperWaitHours != 1.5 {
+		t.Errorf("Expected 1.5 developer hours waiting on pull request CI, got %.2f", stats.DeveloperWaitHours)
+	}
+	if stats.FailedRunHours != 5 {
+		t.Errorf("Expected 5 hours of failed runs, got %.2f", stats.FailedRunHours)
+	}
+
+	if !reflect.DeepEqual(stats.SlowestWorkflows, []string{"nightly", "ci"}) {
+		t.Errorf("Expected nightly to be the slowest workflow, got %v", stats.SlowestWorkflows)
+	}
+	// nightly fails every run, but one run is too few to rank it
+	if !reflect.DeepEqual(stats.LeastReliableWorkflows, []string{"ci"}) {
+		t.Errorf("Expected only ci ranked as unreliable, got %v", stats.LeastReliableWorkflows)
+	}
+
+	ci := stats.Workflows[1]
+	if ci.Name != "ci" || ci.Runs != 3 || ci.DurationP50Minutes != 60 || ci.QueueP50Minutes != 30 {
+		t.Errorf("Unexpected ci stats %+v", ci)
+	}
+	if ci.SuccessRatePercent < 66.6 || ci.SuccessRatePercent > 66.7 {
+		t.Errorf("Expected a success rate of 2 in 3, got %.2f", ci.SuccessRatePercent)
+	}
+	// The later run took twice as long as the earlier ones
+	if ci.DurationChangePercent != 100 || ci.Trend != types.TrendDegrading {
+		t.Errorf("Expected ci to degrade with a 100%% slower median, got %.1f%% and %s", ci.DurationChangePercent, ci.Trend)
+	}
+
+	stats = CalculatePipelineStats(runs, PipelineConfig{WaitFactor: 1})
+	if stats.DeveloperWaitHours != 3 {
+		t.Errorf("Expected all 3 hours of pull request CI with a wait factor of 1, got %.2f", stats.DeveloperWaitHours)
+	}
+
+	if CalculatePipelineStats(nil, DefaultPipelineConfig()) != nil {
+		t.Error("Expected no pipeline stats without runs")
+	}
+}
+
+func TestRunTimesOfReruns(t *testing.T) {
+	rerun := completedRun(1, "ci", RunEventPullRequest, "success", 0, 5, 6)
+	rerun.Attempt = 2
+
+	queuedRerun := rerun
+	queuedRerun.QueuedAt = timePtr(at(4.5))
+
+	unstartedRerun := rerun
+	unstartedRerun.StartedAt = nil
+
+	tests := []struct {
+		name     string
+		run      WorkflowRun
+		queue    time.Duration
+		queueOK  bool
+		duration time.Duration
+		ok       bool
+	}{
+		{"first attempt", completedRun(1, "ci", "push", "success", 0, 0.25, 1.25), 15 * time.Minute, true, time.Hour, true},
+		// The first attempt's creation would count the earlier attempts as queue time
+		{"rerun without queue time", rerun, 0, false, time.Hour, true},
+		{"rerun with queue time", queuedRerun, 30 * time.Minute, true, time.Hour, true},
+		{"rerun without start", unstartedRerun, 0, false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue, ok := runQueueTime(tt.run)
+			if queue != tt.queue || ok != tt.queueOK {
+				t.Errorf("runQueueTime() = %v, %v, expected %v, %v", queue, ok, tt.queue, tt.queueOK)
+			}
+			duration, ok := runDuration(tt.run)
+			if duration != tt.duration || ok != tt.ok {
+				t.Errorf("runDuration() = %v, %v, expected %v, %v", duration, ok, tt.duration, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+// Package metrics - Pull request size distributions and batch-size limits
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// prSizeBands groups pull requests by changed lines; the last band is unbounded
+var prSizeBands = []struct {
+	name     string
+	maxLines int
+}{
+	{"xs", 10}, {"s", 50}, {"m", 200}, {"l", 400}, {"xl", 1000}, {"xxl", 0},
+}
+
+const (
+	minBandPullRequests = 3   // Bands with fewer pull requests don't drive the recommended limit
+	bandSlowdownFactor  = 2.0 // A band is too large once its median lead time doubles the repository's
+	maxOversizedPRs     = 20
+)
+
+// BatchSizeBucket holds pull request size distributions for pull requests merged in a period
+type BatchSizeBucket struct {
+	Start        time.Time              `json:"start"`
+	End          time.Time              `json:"end"`
+	PullRequests int                    `json:"pull_requests"`
+	LinesChanged types.SizeDistribution `json:"lines_changed"`
+	FilesChanged types.SizeDistribution `json:"files_changed"`
+	Commits      types.SizeDistribution `json:"commits"`
+}
+
+// prSize is the size and timing of a merged pull request
+type prSize struct {
+	pr         PullRequest
+	lines      int
+	leadTime   float64  // First commit, or opened when unknown, to merge
+	reviewTime *float64 // Opened to approved
+}
+
+// CalculateBatchSize measures the size of merged pull requests, correlates size with lead and
+// review time, and recommends a size limit: the largest band whose median lead time stays
+// within twice the median of all merged pull requests. elapsed measures lead and review
+// time. Pull requests without changed lines, as when the source reports no sizes, are left
+// out. Returns nil when no merged pull request has a size, so no limit is recommended.
+func CalculateBatchSize(prs []PullRequest, elapsed func(start, end time.Time) float64) *types.BatchSize {
+	sizes := mergedPRSizes(prs, elapsed)
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	var lines, files, commits, leadTimes, reviewLines, reviewTimes []float64
+	for _, size := range sizes {
+		lines = append(lines, float64(size.lines))
+		files = append(files, float64(size.pr.ChangedFiles))
+		commits = append(commits, float64(size.pr.Commits))
+		leadTimes = append(leadTimes, size.leadTime)
+		if size.reviewTime != nil {
+			reviewLines = append(reviewLines, float64(size.lines))
+			reviewTimes = append(reviewTimes, *size.reviewTime)
+		}
+	}
+
+	batch := &types.BatchSize{
+		PullRequests:          len(sizes),
+		LinesChanged:          sizeDistribution(lines),
+		FilesChanged:          sizeDistribution(files),
+		Commits:               sizeDistribution(commits),
+		LeadTimeCorrelation:   spearmanCorrelation(lines, leadTimes),
+		ReviewTimeCorrelation: spearmanCorrelation(reviewLines, reviewTimes),
+		Bands:                 sizeBands(sizes),
+	}
+	sortedLeadTimes := append([]float64(nil), leadTimes...)
+	sort.Float64s(sortedLeadTimes)
+	batch.RecommendedMaxLines = recommendedMaxLines(batch.Bands, batch.LinesChanged, percentile(sortedLeadTimes, 0.50))
+
+	var withinLimit []float64
+	for _, size := range sizes {
+		if size.lines <= batch.RecommendedMaxLines {
+			withinLimit = append(withinLimit, float64(size.pr.ChangedFiles))
+			continue
+		}
+		batch.Oversized = append(batch.Oversized, types.OversizedPR{
+			Number:        size.pr.Number,
+			Title:         size.pr.Title,
+			LinesChanged:  size.lines,
+			FilesChanged:  size.pr.ChangedFiles,
+			Commits:       size.pr.Commits,
+			LeadTimeHours: size.leadTime,
+		})
+	}
+	if len(withinLimit) == 0 {
+		withinLimit = files
+	}
+	sort.Float64s(withinLimit)
+	batch.RecommendedMaxFiles = int(math.Max(1, math.Ceil(percentile(withinLimit, 0.90))))
+
+	sort.SliceStable(batch.Oversized, func(i, j int) bool {
+		return batch.Oversized[i].LinesChanged > batch.Oversized[j].LinesChanged
+	})
+	if len(batch.Oversized) > maxOversizedPRs {
+		batch.Oversized = batch.Oversized[:maxOversizedPRs]
+	}
+
+	return batch
+}
+
+// mergedPRSizes collects size and timing of merged pull requests with changed lines
+func mergedPRSizes(prs []PullRequest, elapsed func(start, end time.Time) float64) []prSize {
+	var sizes []prSize
+	for _, pr := range prs {
+		if pr.MergedAt == nil || pr.Additions+pr.Deletions <= 0 {
+			continue
+		}
+		start := pr.CreatedAt
+		if pr.FirstCommitAt != nil && pr.FirstCommitAt.Before(start) {
+			start = *pr.FirstCommitAt
+		}
+
+		size := prSize{
+			pr:       pr,
+			lines:    pr.Additions + pr.Deletions,
+			leadTime: elapsed(start, *pr.MergedAt),
+		}
+		if pr.ApprovedAt != nil && !pr.ApprovedAt.Before(pr.CreatedAt) {
+			reviewTime := elapsed(pr.CreatedAt, *pr.ApprovedAt)
+			size.reviewTime = &reviewTime
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes
+}
+
+// sizeBands computes median lead and review time per size band. Empty bands are left out.
+func sizeBands(sizes []prSize) []types.PRSizeBand {
+	var bands []types.PRSizeBand
+	lower := 0
+	for _, band := range prSizeBands {
+		var leadTimes, reviewTimes []float64
+		for _, size := range sizes {
+			if size.lines < lower || (band.maxLines > 0 && size.lines > band.maxLines) {
+				continue
+			}
+			leadTimes = append(leadTimes, size.leadTime)
+			if size.reviewTime != nil {
+				reviewTimes = append(reviewTimes, *size.reviewTime)
+			}
+		}
+		lower = band.maxLines + 1
+
+		if len(leadTimes) == 0 {
+			continue
+		}
+		sort.Float64s(leadTimes)
+		sort.Float64s(reviewTimes)
+		bands = append(bands, types.PRSizeBand{
+			Band:               band.name,
+			MaxLines:           band.maxLines,
+			PullRequests:       len(leadTimes),
+			LeadTimeP50Hours:   percentile(leadTimes, 0.50),
+			ReviewTimeP50Hours: percentile(reviewTimes, 0.50),
+		})
+	}
+	return bands
+}
+
+// recommendedMaxLines returns the upper bound of the largest band whose median lead time stays
+// within bandSlowdownFactor of the median lead time of all merged pull requests, stopping at
+// the first band that exceeds it. Bands with too few pull requests are skipped; without any,
+// the P75 of lines changed is rounded up to the next band bound.
+func recommendedMaxLines(bands []types.PRSizeBand, lines types.SizeDistribution, medianLeadTime float64) int {
+	// Sub-hour medians would make any slowdown look large
+	threshold := bandSlowdownFactor * math.Max(medianLeadTime, 1)
+	limit := 0
+	for _, band := range bands {
+		if band.PullRequests < minBandPullRequests || band.MaxLines == 0 {
+			continue
+		}
+		if band.LeadTimeP50Hours > threshold {
+			break
+		}
+		limit = band.MaxLines
+	}
+	if limit > 0 {
+		return limit
+	}
+
+	for _, band := range prSizeBands {
+		if band.maxLines > 0 && lines.P75 <= float64(band.maxLines) {
+			return band.maxLines
+		}
+	}
+	return prSizeBands[len(prSizeBands)-2].maxLines
+}
+
+// bucketBatchSize computes size distributions per period, bucketing pull requests by merge time
+func bucketBatchSize(prs []PullRequest, timeRange TimeRange, period time.Duration) []BatchSizeBucket {
+	if period <= 0 || !timeRange.End.After(timeRange.Start) {
+		return nil
+	}
+
+	var buckets []BatchSizeBucket
+	for start := timeRange.Start; start.Before(timeRange.End); start = start.Add(period) {
+		end := start.Add(period)
+		if end.After(timeRange.End) {
+			end = timeRange.End
+		}
+		bucket := TimeRange{Start: start, End: end}
+
+		var lines, files, commits []float64
+		for _, pr := range prs {
+			if pr.MergedAt == nil || !bucket.Contains(*pr.MergedAt) {
+				continue
+			}
+			lines = append(lines, float64(pr.Additions+pr.Deletions))
+			files = append(files, float64(pr.ChangedFiles))
+			commits = append(commits, float64(pr.Commits))
+		}
+
+		buckets = append(buckets, BatchSizeBucket{
+			Start:        start,
+			End:          end,
+			PullRequests: len(lines),
+			LinesChanged: sizeDistribution(lines),
+			FilesChanged: sizeDistribution(files),
+			Commits:      sizeDistribution(commits),
+		})
+	}
+	return buckets
+}
+
+// sizeDistribution computes percentiles, mean and max of sizes
+func sizeDistribution(values []float64) types.SizeDistribution {
+	if len(values) == 0 {
+		return types.SizeDistribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, value := range sorted {
+		total += value
+	}
+	return types.SizeDistribution{
+		P50:  percentile(sorted, 0.50),
+		P75:  percentile(sorted, 0.75),
+		P95:  percentile(sorted, 0.95),
+		Mean: total / float64(len(sorted)),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// spearmanCorrelation returns the rank correlation of two paired samples, from -1 to 1.
+// Sizes and durations are heavily skewed, so ranks are compared rather than values.
+// Returns 0 with fewer than three pairs or when either sample is constant.
+func spearmanCorrelation(xs, ys []float64) float64 {
+	if len(xs) != len(ys) || len(xs) < 3 {
+		return 0
+	}
+	rx, ry := ranks(xs), ranks(ys)
+
+	n := float64(len(rx))
+	meanX, meanY := 0.0, 0.0
+	for i := range rx {
+		meanX += rx[i] / n
+		meanY += ry[i] / n
+	}
+
+	var cov, varX, varY float64
+	for i := range rx {
+		dx, dy := rx[i]-meanX, ry[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return math.Round(cov/math.Sqrt(varX*varY)*1000) / 1000
+}
+
+// ranks returns 1-based ranks of values, averaging the ranks of ties
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+
+	result := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[order[k]] = rank
+		}
+		i = j + 1
+	}
+	return result
+}
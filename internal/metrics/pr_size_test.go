@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// mergedPR opens a pull request at baseTime and merges it leadHours later
+func mergedPR(number, lines, files int, leadHours float64) PullRequest {
+	return PullRequest{
+		Number:       number,
+		CreatedAt:    baseTime,
+		MergedAt:     timePtr(at(leadHours)),
+		Additions:    lines,
+		ChangedFiles: files,
+	}
+}
+
+func TestCalculateBatchSizeRecommendsLargestFastBand(t *testing.T) {
+	prs := []PullRequest{
+		// xs, s and m land within twice the median lead time of 6 hours
+		mergedPR(1, 5, 1, 2), mergedPR(2, 8, 1, 3), mergedPR(3, 10, 1, 4),
+		mergedPR(4, 20, 1, 4), mergedPR(5, 30, 1, 5), mergedPR(6, 40, 1, 6),
+		mergedPR(7, 100, 1, 6), mergedPR(8, 150, 1, 8), mergedPR(9, 200, 7, 10),
+		// l takes over a day, and the single xxl pull request longer still
+		mergedPR(10, 300, 10, 30), mergedPR(11, 350, 12, 40), mergedPR(12, 400, 15, 50),
+		mergedPR(13, 2000, 60, 100),
+		// Neither an unmerged nor an unsized pull request counts
+		{Number: 14, CreatedAt: baseTime, Additions: 50},
+		mergedPR(15, 0, 0, 1),
+	}
+
+	batch := CalculateBatchSize(prs, wallClock)
+	if batch == nil {
+		t.Fatal("Expected batch size metrics for merged pull requests")
+	}
+	if batch.PullRequests != 13 {
+		t.Errorf("Expected 13 sized merged pull requests, got %d", batch.PullRequests)
+	}
+
+	var bands []string
+	for _, band := range batch.Bands {
+		bands = append(bands, band.Band)
+	}
+	if !reflect.DeepEqual(bands, []string{"xs", "s", "m", "l", "xxl"}) {
+		t.Errorf("Expected the empty xl band left out, got %v", bands)
+	}
+	if l := batch.Bands[3]; l.PullRequests != 3 || l.MaxLines != 400 || l.LeadTimeP50Hours != 40 {
+		t.Errorf("Unexpected l band %+v", l)
+	}
+	if xxl := batch.Bands[4]; xxl.MaxLines != 0 || xxl.PullRequests != 1 {
+		t.Errorf("Expected an unbounded xxl band, got %+v", xxl)
+	}
+
+	if batch.RecommendedMaxLines != 200 {
+		t.Errorf("Expected the m band bound of 200 lines, got %d", batch.RecommendedMaxLines)
+	}
+	if batch.RecommendedMaxFiles != 7 {
+		t.Errorf("Expected the P90 of files within the limit, got %d", batch.RecommendedMaxFiles)
+	}
+	if len(batch.Oversized) != 4 || batch.Oversized[0].Number != 13 || batch.Oversized[0].LeadTimeHours != 100 {
+		t.Errorf("Expected 4 oversized pull requests, largest first, got %+v", batch.Oversized)
+	}
+	if batch.LeadTimeCorrelation < 0.9 {
+		t.Errorf("Expected lead time to rise with size, got ρ=%.3f", batch.LeadTimeCorrelation)
+	}
+}
+
+func TestCalculateBatchSizeFallsBackToP75(t *testing.T) {
+	// Too few pull requests per band to compare their lead times
+	prs := []PullRequest{mergedPR(1, 30, 2, 5), mergedPR(2, 120, 4, 9)}
+
+	batch := CalculateBatchSize(prs, wallClock)
+	if batch == nil || batch.RecommendedMaxLines != 200 {
+		t.Fatalf("Expected the P75 of 120 lines rounded up to 200, got %+v", batch)
+	}
+}
+
+func TestCalculateBatchSizeWithoutSizes(t *testing.T) {
+	// Sources that report no sizes must not produce a 10 line limit
+	prs := []PullRequest{mergedPR(1, 0, 0, 5), mergedPR(2, 0, 0, 9), mergedPR(3, 0, 0, 12)}
+
+	if batch := CalculateBatchSize(prs, wallClock); batch != nil {
+		t.Errorf("Expected no batch size or recommendation without sizes, got %+v", batch)
+	}
+	if batch := CalculateBatchSize(nil, wallClock); batch != nil {
+		t.Errorf("Expected no batch size without pull requests, got %+v", batch)
+	}
+}
+
+func TestSpearmanCorrelation(t *testing.T) {
+	tests := []struct {
+		name     string
+		xs, ys   []float64
+		expected float64
+	}{
+		{"monotonic but not linear", []float64{1, 2, 3, 4, 5}, []float64{1, 4, 9, 100, 1000}, 1},
+		{"reversed", []float64{10, 20, 30, 40}, []float64{8, 6, 4, 2}, -1},
+		{"ties share their rank", []float64{1, 2, 2, 3}, []float64{1, 2, 3, 4}, 0.949},
+		{"too few pairs", []float64{1, 2}, []float64{1, 2}, 0},
+		{"constant sample", []float64{5, 5, 5}, []float64{1, 2, 3}, 0},
+		{"mismatched samples", []float64{1, 2, 3}, []float64{1, 2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spearmanCorrelation(tt.xs, tt.ys); got != tt.expected {
+				t.Errorf("spearmanCorrelation(%v, %v) = %.3f, expected %.3f", tt.xs, tt.ys, got, tt.expected)
+			}
+		})
+	}
+
+	if got := ranks([]float64{30, 10, 20, 20}); !reflect.DeepEqual(got, []float64{4, 1, 2.5, 2.5}) {
+		t.Errorf("Expected tied values to average their ranks, got %v", got)
+	}
+}
+
+func TestDORACalculatorWarnsAboutIncompletePullRequests(t *testing.T) {
+	now := time.Now()
+	client := &doraTestClient{
+		prs: []PullRequest{
+			{Number: 1, CreatedAt: now.Add(-60 * time.Hour), MergedAt: timePtr(now.Add(-50 * time.Hour)), Additions: 40},
+			{Number: 2, CreatedAt: now.Add(-40 * time.Hour), MergedAt: timePtr(now.Add(-30 * time.Hour)), Incomplete: true},
+		},
+	}
+
+	metrics, err := NewDORACalculator(client, nil).Calculate(context.Background(), types.Repository{Owner: "test-owner", Name: "test-repo"}, 30)
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if len(metrics.Warnings) != 1 || !strings.Contains(metrics.Warnings[0], "1 of 2 pull requests") {
+		t.Errorf("Expected a warning about 1 of 2 incomplete pull requests, got %v", metrics.Warnings)
+	}
+}
@@ -0,0 +1,175 @@
+// Package metrics - Review depth, reviewer load and rubber-stamp detection
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Review states as reported by GitHub
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+)
+
+// PullRequestReview is a submitted review of a pull request
+type PullRequestReview struct {
+	Reviewer    string     `json:"reviewer"`
+	State       string     `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	SubmittedAt time.Time  `json:"submitted_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"` // First inline comment of the review
+	Comments    int        `json:"comments"`             // Inline comments
+	BodyLength  int        `json:"body_length"`          // Characters in the review summary
+}
+
+// ReviewQualityConfig tunes review analytics
+type ReviewQualityConfig struct {
+	LargeDiffLines int `json:"large_diff_lines"` // Added plus deleted lines from which an uncommented approval is a rubber stamp
+}
+
+// DefaultReviewQualityConfig treats diffs of 400 changed lines or more as large
+func DefaultReviewQualityConfig() ReviewQualityConfig {
+	return ReviewQualityConfig{LargeDiffLines: 400}
+}
+
+// CalculateReviewQuality analyzes submitted reviews: comments per review, time spent reviewing,
+// approvals of large diffs without comments, and how reviews are spread across reviewers.
+// Self-reviews and bots are ignored. Returns nil when no pull request carries reviews.
+func CalculateReviewQuality(prs []PullRequest, config ReviewQualityConfig) *types.ReviewQuality {
+	if config.LargeDiffLines <= 0 {
+		config.LargeDiffLines = DefaultReviewQualityConfig().LargeDiffLines
+	}
+
+	quality := &types.ReviewQuality{}
+	loads := make(map[string]*types.ReviewerLoad)
+	var reviewHours []float64
+	comments := 0
+
+	for _, pr := range prs {
+		changedLines := pr.Additions + pr.Deletions
+		reviewers := make(map[string]bool)
+		// A reviewer who commented in any of their reviews of the pull request engaged with it
+		engaged := make(map[string]bool)
+		for _, review := range pr.Reviews {
+			if review.Comments > 0 || review.BodyLength > 0 {
+				engaged[review.Reviewer] = true
+			}
+		}
+
+		for _, review := range pr.Reviews {
+			if !countsAsReview(review, pr.Author) {
+				continue
+			}
+
+			load := loads[review.Reviewer]
+			if load == nil {
+				load = &types.ReviewerLoad{Reviewer: review.Reviewer}
+				loads[review.Reviewer] = load
+			}
+			if !reviewers[review.Reviewer] {
+				reviewers[review.Reviewer] = true
+				load.PullRequests++
+			}
+
+			quality.Reviews++
+			load.Reviews++
+			load.Comments += review.Comments
+			comments += review.Comments
+			if review.StartedAt != nil && review.SubmittedAt.After(*review.StartedAt) {
+				reviewHours = append(reviewHours, review.SubmittedAt.Sub(*review.StartedAt).Hours())
+			}
+
+			if review.State != ReviewApproved {
+				continue
+			}
+			quality.Approvals++
+			load.Approvals++
+			if changedLines < config.LargeDiffLines {
+				continue
+			}
+			quality.LargeDiffApprovals++
+			if !engaged[review.Reviewer] {
+				load.RubberStamps++
+				quality.RubberStamps = append(quality.RubberStamps, types.RubberStamp{
+					Number:       pr.Number,
+					Reviewer:     review.Reviewer,
+					ChangedLines: changedLines,
+					ApprovedAt:   review.SubmittedAt,
+				})
+			}
+		}
+
+		if len(reviewers) > 0 {
+			quality.ReviewedPullRequests++
+		}
+	}
+
+	if quality.Reviews == 0 {
+		return nil
+	}
+
+	quality.CommentsPerReview = float64(comments) / float64(quality.Reviews)
+	if len(reviewHours) > 0 {
+		sort.Float64s(reviewHours)
+		quality.ReviewTimeP50Hours = percentile(reviewHours, 0.50)
+	}
+	if quality.LargeDiffApprovals > 0 {
+		quality.RubberStampRatePercent = float64(len(quality.RubberStamps)) / float64(quality.LargeDiffApprovals) * 100
+	}
+
+	counts := make([]float64, 0, len(loads))
+	for _, load := range loads {
+		load.SharePercent = float64(load.Reviews) / float64(quality.Reviews) * 100
+		quality.ReviewerLoad = append(quality.ReviewerLoad, *load)
+		counts = append(counts, float64(load.Reviews))
+	}
+	sort.SliceStable(quality.ReviewerLoad, func(i, j int) bool {
+		if quality.ReviewerLoad[i].Reviews != quality.ReviewerLoad[j].Reviews {
+			return quality.ReviewerLoad[i].Reviews > quality.ReviewerLoad[j].Reviews
+		}
+		return quality.ReviewerLoad[i].Reviewer < quality.ReviewerLoad[j].Reviewer
+	})
+	quality.TopReviewerSharePercent = quality.ReviewerLoad[0].SharePercent
+	quality.ReviewConcentration = giniCoefficient(counts)
+
+	return quality
+}
+
+// countsAsReview reports whether a review was submitted by someone other than the author or a bot
+func countsAsReview(review PullRequestReview, author string) bool {
+	if review.Reviewer == "" || review.SubmittedAt.IsZero() {
+		return false
+	}
+	if author != "" && strings.EqualFold(review.Reviewer, author) {
+		return false
+	}
+	return !strings.HasSuffix(strings.ToLower(review.Reviewer), "[bot]")
+}
+
+// giniCoefficient measures inequality of non-negative values: 0 when equal, approaching 1
+// when a single value holds everything
+func giniCoefficient(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	weighted := 0.0
+	for i, value := range sorted {
+		total += value
+		weighted += float64(i+1) * value
+	}
+	if total == 0 {
+		return 0
+	}
+	n := float64(len(sorted))
+	gini := (2*weighted)/(n*total) - (n+1)/n
+	return math.Round(gini*1000) / 1000
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateReviewQuality(t *testing.T) {
+	prs := []PullRequest{
+		{
+			Number: 1, Author: "dev", Additions: 450, Deletions: 50,
+			Reviews: []PullRequestReview{
+				{Reviewer: "alice", State: ReviewApproved, SubmittedAt: at(2)},
+				{Reviewer: "bob", State: ReviewCommented, SubmittedAt: at(3), StartedAt: timePtr(at(1)), Comments: 3},
+				{Reviewer: "dev", State: ReviewCommented, SubmittedAt: at(3), Comments: 1}, // Self-review
+				{Reviewer: "dependabot[bot]", State: ReviewApproved, SubmittedAt: at(3)},
+			},
+		},
+		{
+			// Alice commented in an earlier review, so her approval of the large diff is not a rubber stamp
+			Number: 2, Author: "dev", Additions: 600,
+			Reviews: []PullRequestReview{
+				{Reviewer: "alice", State: ReviewCommented, SubmittedAt: at(4), StartedAt: timePtr(at(2)), Comments: 2},
+				{Reviewer: "alice", State: ReviewApproved, SubmittedAt: at(5)},
+			},
+		},
+		{
+			// Small diffs are approved without comments all the time
+			Number: 3, Author: "dev", Additions: 10,
+			Reviews: []PullRequestReview{
+				{Reviewer: "alice", State: ReviewApproved, SubmittedAt: at(6)},
+			},
+		},
+		{Number: 4, Author: "dev", Additions: 900},
+	}
+
+	quality := CalculateReviewQuality(prs, ReviewQualityConfig{})
+	if quality == nil {
+		t.Fatal("Expected review quality for reviewed pull requests")
+	}
+
+	if quality.Reviews != 5 || quality.Approvals != 3 || quality.ReviewedPullRequests != 3 {
+		t.Errorf("Expected 5 reviews with 3 approvals over 3 pull requests, got %d, %d and %d",
+			quality.Reviews, quality.Approvals, quality.ReviewedPullRequests)
+	}
+	if quality.CommentsPerReview != 1 {
+		t.Errorf("Expected 1 comment per review, got %.2f", quality.CommentsPerReview)
+	}
+	if quality.ReviewTimeP50Hours != 2 {
+		t.Errorf("Expected a median of 2 hours from first comment to submission, got %.2f", quality.ReviewTimeP50Hours)
+	}
+
+	// Only the 500 line approval of pull request 1 went through without a word
+	if quality.LargeDiffApprovals != 2 || len(quality.RubberStamps) != 1 || quality.RubberStampRatePercent != 50 {
+		t.Fatalf("Expected 1 rubber stamp out of 2 large diff approvals, got %+v", quality.RubberStamps)
+	}
+	if stamp := quality.RubberStamps[0]; stamp.Number != 1 || stamp.Reviewer != "alice" || stamp.ChangedLines != 500 || !stamp.ApprovedAt.Equal(at(2)) {
+		t.Errorf("Unexpected rubber stamp %+v", stamp)
+	}
+
+	// Alice holds 4 of the 5 reviews
+	if len(quality.ReviewerLoad) != 2 || quality.ReviewerLoad[0].Reviewer != "alice" {
+		t.Fatalf("Expected alice to lead the reviewer load, got %+v", quality.ReviewerLoad)
+	}
+	alice := quality.ReviewerLoad[0]
+	if alice.Reviews != 4 || alice.PullRequests != 3 || alice.Approvals != 3 || alice.RubberStamps != 1 || alice.Comments != 2 {
+		t.Errorf("Unexpected load for alice %+v", alice)
+	}
+	if quality.TopReviewerSharePercent != 80 {
+		t.Errorf("Expected alice to hold 80%% of reviews, got %.2f", quality.TopReviewerSharePercent)
+	}
+	if quality.ReviewConcentration != 0.3 {
+		t.Errorf("Expected a Gini coefficient of 0.3 for loads of 4 and 1, got %.3f", quality.ReviewConcentration)
+	}
+
+	// A higher threshold turns the 500 and 600 line diffs into ordinary ones
+	quality = CalculateReviewQuality(prs, ReviewQualityConfig{LargeDiffLines: 1000})
+	if quality.LargeDiffApprovals != 0 || len(quality.RubberStamps) != 0 || quality.RubberStampRatePercent != 0 {
+		t.Errorf("Expected no large diff approvals over 1000 lines, got %+v", quality)
+	}
+
+	if CalculateReviewQuality(prs[3:], DefaultReviewQualityConfig()) != nil {
+		t.Error("Expected no review quality without reviews")
+	}
+}
+
+func TestGiniCoefficient(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		expected float64
+	}{
+		{"single reviewer", []float64{7}, 0},
+		{"equal loads", []float64{3, 3, 3}, 0},
+		{"no reviews", []float64{0, 0}, 0},
+		{"one of four holds everything", []float64{0, 0, 0, 10}, 0.75},
+		{"uneven loads", []float64{1, 2, 3, 4}, 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := giniCoefficient(tt.values); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("giniCoefficient(%v) = %.3f, expected %.3f", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
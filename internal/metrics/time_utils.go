@@ -9,6 +9,7 @@ import (
 // TimeUtils provides utilities for timezone-aware time handling
 type TimeUtils struct {
 	defaultTimezone string
+	holidays        *HolidayCalendar
 }
 
 // NewTimeUtils creates a new TimeUtils instance
@@ -21,6 +22,15 @@ func NewTimeUtils(defaultTimezone string) *TimeUtils {
 	}
 }
 
+// WithHolidays returns a copy whose business days and working hours also skip
+// the holidays of calendar. A nil calendar only skips weekends.
+func (tu *TimeUtils) WithHolidays(calendar *HolidayCalendar) *TimeUtils {
+	return &TimeUtils{
+		defaultTimezone: tu.defaultTimezone,
+		holidays:        calendar,
+	}
+}
+
 // ParseTimeRange parses a time range with timezone support
 func (tu *TimeUtils) ParseTimeRange(start, end time.Time, timezone string) (TimeRange, error) {
 	if timezone == "" {
@@ -121,9 +131,9 @@ func (tu *TimeUtils) GetPeriodBoundaries(baseTime time.Time, granularity string,
 	return periods, nil
 }
 
-// GetBusinessHours filters time ranges to business hours only
+// GetBusinessHours filters time ranges to business hours only. An endHour of 24 ends the day at midnight.
 func (tu *TimeUtils) GetBusinessHours(timeRange TimeRange, startHour, endHour int, excludeWeekends bool) ([]TimeRange, error) {
-	if startHour < 0 || startHour > 23 || endHour < 0 || endHour > 23 {
+	if startHour < 0 || startHour > 23 || endHour < 0 || endHour > 24 {
 		return nil, fmt.Errorf("invalid hour range: %d-%d", startHour, endHour)
 	}
 
@@ -137,8 +147,8 @@ func (tu *TimeUtils) GetBusinessHours(timeRange TimeRange, startHour, endHour in
 	end := timeRange.End.In(loc)
 
 	for current.Before(end) {
-		// Skip weekends if requested
-		if excludeWeekends && (current.Weekday() == time.Saturday || current.Weekday() == time.Sunday) {
+		// Skip weekends if requested, and holidays always
+		if (excludeWeekends && (current.Weekday() == time.Saturday || current.Weekday() == time.Sunday)) || tu.holidays.IsHoliday(current) {
 			current = current.AddDate(0, 0, 1)
 			current = time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, loc)
 			continue
@@ -257,10 +267,10 @@ func (tu *TimeUtils) GetPeriodDuration(granularity string) (time.Duration, error
 	}
 }
 
-// IsBusinessDay checks if a date is a business day (Monday-Friday)
+// IsBusinessDay checks if a date is a business day (Monday-Friday, outside the holiday calendar)
 func (tu *TimeUtils) IsBusinessDay(t time.Time) bool {
 	weekday := t.Weekday()
-	return weekday != time.Saturday && weekday != time.Sunday
+	return weekday != time.Saturday && weekday != time.Sunday && !tu.holidays.IsHoliday(t)
 }
 
 // GetNextBusinessDay returns the next business day
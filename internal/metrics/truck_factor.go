@@ -0,0 +1,174 @@
+// Package metrics - Truck factor from a degree-of-authorship model
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Degree-of-authorship weights (Fritz et al., as used by Avelino et al. for truck factors)
+const (
+	doaBase             = 3.293
+	doaFirstAuthorship  = 1.098
+	doaDeliveries       = 0.164
+	doaAcceptances      = 0.321
+	authorshipThreshold = 0.75 // Normalized DOA above which an author owns a file
+	maxAtRiskFiles      = 100
+)
+
+// FileChange is one commit by an author to a file, under the file's current path
+type FileChange struct {
+	File    string `json:"file"`
+	Author  string `json:"author"`         // Identity of the author, such as the email
+	Name    string `json:"name,omitempty"` // Display name of the author
+	Created bool   `json:"created"`        // The commit added the file
+}
+
+// FileHistoryClient lists the changes to every file of the current tree, following renames
+type FileHistoryClient interface {
+	GetFileHistory(ctx context.Context, owner, repo string) ([]FileChange, error)
+}
+
+// DegreeOfAuthorship computes the degree of authorship of each author of each file:
+// DOA = 3.293 + 1.098 * FA + 0.164 * DL - 0.321 * ln(1 + AC), where FA is 1 for the author
+// who created the file, DL counts the author's changes and AC the changes by others.
+func DegreeOfAuthorship(changes []FileChange) map[string]map[string]float64 {
+	deliveries := make(map[string]map[string]int)
+	created := make(map[string]string)
+	totals := make(map[string]int)
+	for _, change := range changes {
+		if deliveries[change.File] == nil {
+			deliveries[change.File] = make(map[string]int)
+		}
+		deliveries[change.File][change.Author]++
+		totals[change.File]++
+		if change.Created {
+			created[change.File] = change.Author
+		}
+	}
+
+	doa := make(map[string]map[string]float64, len(deliveries))
+	for file, authors := range deliveries {
+		doa[file] = make(map[string]float64, len(authors))
+		for author, count := range authors {
+			firstAuthorship := 0.0
+			if created[file] == author {
+				firstAuthorship = 1
+			}
+			acceptances := float64(totals[file] - count)
+			doa[file][author] = doaBase + doaFirstAuthorship*firstAuthorship +
+				doaDeliveries*float64(count) - doaAcceptances*math.Log(1+acceptances)
+		}
+	}
+	return doa
+}
+
+// knowledgeOwners returns the owners of each file: authors whose DOA is above 75% of the
+// file's highest and at least the base DOA. A file no author has the base DOA of has no owners.
+func knowledgeOwners(doa map[string]map[string]float64) map[string][]string {
+	owners := make(map[string][]string, len(doa))
+	for file, authors := range doa {
+		top := math.Inf(-1)
+		for _, value := range authors {
+			top = math.Max(top, value)
+		}
+		owners[file] = []string{}
+		for author, value := range authors {
+			if value/top > authorshipThreshold && value >= doaBase {
+				owners[file] = append(owners[file], author)
+			}
+		}
+		sort.Strings(owners[file])
+	}
+	return owners
+}
+
+// CalculateTruckFactor finds the truck factor of the files in changes: authors owning the most
+// files leave one at a time until more than half the files have no owner left. Files without
+// owners count as orphaned from the start. Returns nil without changes.
+func CalculateTruckFactor(changes []FileChange) *types.TruckFactorReport {
+	owners := knowledgeOwners(DegreeOfAuthorship(changes))
+	if len(owners) == 0 {
+		return nil
+	}
+	names := make(map[string]string)
+	for _, change := range changes {
+		if change.Name != "" {
+			names[change.Author] = change.Name
+		}
+	}
+
+	report := &types.TruckFactorReport{TotalFiles: len(owners)}
+	remaining := make(map[string]int, len(owners)) // Owners left per file
+	for file, fileOwners := range owners {
+		remaining[file] = len(fileOwners)
+		if len(fileOwners) == 0 {
+			report.OrphanedFiles++
+		}
+	}
+	departed := make(map[string]bool)
+
+	for report.OrphanedFiles*2 <= report.TotalFiles {
+		// The author owning the most files with owners left goes next
+		files := make(map[string]int)
+		for file, fileOwners := range owners {
+			if remaining[file] == 0 {
+				continue
+			}
+			for _, author := range fileOwners {
+				if !departed[author] {
+					files[author]++
+				}
+			}
+		}
+		next := ""
+		for author, count := range files {
+			if next == "" || count > files[next] || (count == files[next] && author < next) {
+				next = author
+			}
+		}
+		if next == "" {
+			break
+		}
+
+		departed[next] = true
+		report.KeyAuthors = append(report.KeyAuthors, types.KnowledgeOwner{
+			Author:       next,
+			Name:         names[next],
+			Files:        files[next],
+			FilesPercent: float64(files[next]) / float64(report.TotalFiles) * 100,
+		})
+		for file, fileOwners := range owners {
+			for _, author := range fileOwners {
+				if author == next {
+					remaining[file]--
+					if remaining[file] == 0 {
+						report.OrphanedFiles++
+					}
+				}
+			}
+		}
+	}
+
+	report.TruckFactor = len(report.KeyAuthors)
+	report.OrphanedPercent = float64(report.OrphanedFiles) / float64(report.TotalFiles) * 100
+	for file, fileOwners := range owners {
+		if remaining[file] == 0 {
+			report.AtRiskFiles = append(report.AtRiskFiles, types.FileOwnership{File: file, Owners: fileOwners})
+		}
+	}
+	// Files with the fewest owners are the most at risk
+	sort.Slice(report.AtRiskFiles, func(i, j int) bool {
+		if len(report.AtRiskFiles[i].Owners) != len(report.AtRiskFiles[j].Owners) {
+			return len(report.AtRiskFiles[i].Owners) < len(report.AtRiskFiles[j].Owners)
+		}
+		return report.AtRiskFiles[i].File < report.AtRiskFiles[j].File
+	})
+	if len(report.AtRiskFiles) > maxAtRiskFiles {
+		report.AtRiskFiles = report.AtRiskFiles[:maxAtRiskFiles]
+	}
+	return report
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestDegreeOfAuthorship(t *testing.T) {
+	changes := []FileChange{
+		{File: "main.go", Author: "alice@acme.io", Created: true},
+		{File: "main.go", Author: "alice@acme.io"},
+		{File: "main.go", Author: "bob@acme.io"},
+	}
+	doa := DegreeOfAuthorship(changes)
+
+	// 3.293 + 1.098 + 0.164*2 - 0.321*ln(2), and 3.293 + 0.164 - 0.321*ln(3)
+	if alice := doa["main.go"]["alice@acme.io"]; math.Abs(alice-4.4965) > 0.001 {
+		t.Errorf("Expected alice's DOA 4.4965, got %.4f", alice)
+	}
+	if bob := doa["main.go"]["bob@acme.io"]; math.Abs(bob-3.1043) > 0.001 {
+		t.Errorf("Expected bob's DOA 3.1043, got %.4f", bob)
+	}
+	// Bob is below the base DOA, so only alice owns the file
+	if owners := knowledgeOwners(doa)["main.go"]; len(owners) != 1 || owners[0] != "alice@acme.io" {
+		t.Errorf("Expected alice as the only owner, got %v", owners)
+	}
+}
+
+func TestCalculateTruckFactor(t *testing.T) {
+	var changes []FileChange
+	for _, file := range []string{"a.go", "b.go", "c.go"} {
+		changes = append(changes, FileChange{File: file, Author: "alice@acme.io", Name: "Alice", Created: true})
+	}
+	changes = append(changes, FileChange{File: "d.go", Author: "bob@acme.io", Name: "Bob", Created: true})
+	// Touched once by each of 60 authors after alice: even its creator stays below the base DOA
+	changes = append(changes, FileChange{File: "shared.go", Author: "alice@acme.io", Name: "Alice", Created: true})
+	for i := 0; i < 60; i++ {
+		changes = append(changes, FileChange{File: "shared.go", Author: fmt.Sprintf("dev%d@acme.io", i)})
+	}
+
+	report := CalculateTruckFactor(changes)
+	if report == nil {
+		t.Fatal("Expected a truck factor report")
+	}
+	// shared.go is orphaned from the start; alice leaving orphans 3 more of 5 files
+	if report.TotalFiles != 5 || report.TruckFactor != 1 || report.OrphanedFiles != 4 || report.OrphanedPercent != 80 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if owner := report.KeyAuthors[0]; owner.Author != "alice@acme.io" || owner.Name != "Alice" || owner.Files != 3 {
+		t.Errorf("Expected alice to own 3 files, got %+v", owner)
+	}
+	if len(report.AtRiskFiles) != 4 || report.AtRiskFiles[0].File != "shared.go" || len(report.AtRiskFiles[0].Owners) != 0 {
+		t.Errorf("Expected shared.go without owners first among files at risk, got %+v", report.AtRiskFiles)
+	}
+
+	if CalculateTruckFactor(nil) != nil {
+		t.Error("Expected no report without changes")
+	}
+}
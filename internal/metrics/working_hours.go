@@ -0,0 +1,164 @@
+// Package metrics - Team working hours for elapsed-time metrics
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// WorkingHours defines a team's working day. Elapsed times measured against it skip
+// nights, holidays of the calendar and, unless WorkWeekends is set, Saturdays and Sundays.
+type WorkingHours struct {
+	Timezone        string `json:"timezone" yaml:"timezone"`
+	StartHour       int    `json:"start_hour" yaml:"start_hour"`
+	EndHour         int    `json:"end_hour" yaml:"end_hour"`
+	WorkWeekends    bool   `json:"work_weekends,omitempty" yaml:"work_weekends"`
+	HolidayCalendar string `json:"holiday_calendar,omitempty" yaml:"holiday_calendar"` // Name of a calendar in HolidayConfig
+}
+
+// TeamConfig assigns a working-hours definition to the repositories a team owns
+type TeamConfig struct {
+	Name             string       `json:"name" yaml:"name"`
+	Repositories     []string     `json:"repositories" yaml:"repositories"` // "owner/name" or globs such as "owner/*"
+	WorkingHours     WorkingHours `json:"working_hours" yaml:"working_hours"`
+	WorkingHoursOnly bool         `json:"working_hours_only" yaml:"working_hours_only"` // Always report this team's metrics in working hours
+}
+
+// Validate checks the timezone and that the working day starts before it ends.
+// EndHour 24 ends the day at midnight, so 0-24 is a full day.
+func (w WorkingHours) Validate() error {
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %s: %w", w.Timezone, err)
+		}
+	}
+	if w.StartHour < 0 || w.EndHour > 24 || w.StartHour >= w.EndHour {
+		return fmt.Errorf("invalid working hours: %d-%d", w.StartHour, w.EndHour)
+	}
+	return nil
+}
+
+// withDefaults fills the timezone and hours left unset from defaults
+func (w WorkingHours) withDefaults(defaults WorkingHours) WorkingHours {
+	if w.Timezone == "" {
+		w.Timezone = defaults.Timezone
+	}
+	if w.StartHour == 0 && w.EndHour == 0 {
+		w.StartHour, w.EndHour = defaults.StartHour, defaults.EndHour
+	}
+	return w
+}
+
+// Owns reports whether a repository ("owner/name") belongs to the team
+func (t TeamConfig) Owns(fullName string) bool {
+	name := strings.ToLower(fullName)
+	for _, pattern := range t.Repositories {
+		if matched, err := path.Match(strings.ToLower(pattern), name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTeams checks every team has a name and valid working hours
+func ValidateTeams(teams []TeamConfig) error {
+	for _, team := range teams {
+		if team.Name == "" {
+			return fmt.Errorf("team name is required")
+		}
+		if team.WorkingHours == (WorkingHours{}) {
+			continue
+		}
+		if err := team.WorkingHours.withDefaults(WorkingHours{StartHour: 9, EndHour: 17}).Validate(); err != nil {
+			return fmt.Errorf("team %s: %w", team.Name, err)
+		}
+	}
+	return nil
+}
+
+// TeamFor returns the team with the given name, or else the first team owning the repository
+func TeamFor(teams []TeamConfig, name, fullName string) (TeamConfig, bool) {
+	if name != "" {
+		for _, team := range teams {
+			if strings.EqualFold(team.Name, name) {
+				return team, true
+			}
+		}
+	}
+	for _, team := range teams {
+		if team.Owns(fullName) {
+			return team, true
+		}
+	}
+	return TeamConfig{}, false
+}
+
+// ElapsedHours returns the hours between start and end for a repository ("owner/name"): the
+// working hours of its team when the team is measured in working hours, wall-clock otherwise
+func ElapsedHours(teams []TeamConfig, holidays HolidayConfig, fullName string, start, end time.Time) float64 {
+	return teamClock(teams, holidays, fullName).hours(start, end)
+}
+
+// teamClock measures elapsed time in the working hours of the team owning the repository,
+// or in wall-clock hours when no team requires working hours
+func teamClock(teams []TeamConfig, holidays HolidayConfig, fullName string) workingClock {
+	team, ok := TeamFor(teams, "", fullName)
+	if !ok || !team.WorkingHoursOnly {
+		return newWorkingClock(nil, nil, nil)
+	}
+	schedule := team.WorkingHours.withDefaults(WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17})
+	calendar := holidays.CalendarFor(schedule.HolidayCalendar, fullName)
+	return newWorkingClock(NewTimeUtils(schedule.Timezone), &schedule, calendar)
+}
+
+// workingClock measures elapsed time in wall-clock hours, or in working hours when a schedule is set
+type workingClock struct {
+	timeUtils *TimeUtils
+	schedule  *WorkingHours
+}
+
+// newWorkingClock creates a clock measuring working hours of schedule, or wall-clock hours when nil.
+// Holidays of the calendar are non-working days.
+func newWorkingClock(timeUtils *TimeUtils, schedule *WorkingHours, holidays *HolidayCalendar) workingClock {
+	if schedule != nil {
+		timeUtils = timeUtils.WithHolidays(holidays)
+	}
+	return workingClock{timeUtils: timeUtils, schedule: schedule}
+}
+
+// hours returns the hours between two instants, counting only working time when a schedule is set
+func (c workingClock) hours(start, end time.Time) float64 {
+	if c.schedule != nil && !end.Before(start) {
+		workingHours, err := c.timeUtils.CalculateWorkingHours(
+			start, end, c.schedule.Timezone,
+			c.schedule.StartHour, c.schedule.EndHour, !c.schedule.WorkWeekends,
+		)
+		if err == nil {
+			return workingHours
+		}
+	}
+	return end.Sub(start).Hours()
+}
+
+// mergeTimes returns PR open-to-merge durations
+func (c workingClock) mergeTimes(prs []PullRequest) []float64 {
+	var hours []float64
+	for _, pr := range prs {
+		if pr.MergedAt == nil {
+			continue
+		}
+		hours = append(hours, c.hours(pr.CreatedAt, *pr.MergedAt))
+	}
+	return hours
+}
+
+// leadTimeHours returns commit authored-to-deployed durations
+func (c workingClock) leadTimeHours(leadTimes []CommitLeadTime) []float64 {
+	hours := make([]float64, 0, len(leadTimes))
+	for _, leadTime := range leadTimes {
+		hours = append(hours, c.hours(leadTime.AuthoredAt, leadTime.DeployedAt))
+	}
+	return hours
+}
@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWorkingHoursValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours WorkingHours
+		valid bool
+	}{
+		{"office day", WorkingHours{Timezone: "Europe/Berlin", StartHour: 9, EndHour: 17}, true},
+		{"full day", WorkingHours{StartHour: 0, EndHour: 24}, true},
+		{"past midnight", WorkingHours{StartHour: 9, EndHour: 25}, false},
+		{"ends before start", WorkingHours{StartHour: 17, EndHour: 9}, false},
+		{"negative start", WorkingHours{StartHour: -1, EndHour: 17}, false},
+		{"unknown timezone", WorkingHours{Timezone: "Mars/Olympus", StartHour: 9, EndHour: 17}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hours.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate(%+v) = %v, expected valid %v", tt.hours, err, tt.valid)
+			}
+		})
+	}
+}
+
+func workingClockFor(t *testing.T, schedule *WorkingHours, holidays ...Holiday) workingClock {
+	t.Helper()
+	var calendar *HolidayCalendar
+	if len(holidays) > 0 {
+		var err error
+		if calendar, err = NewHolidayCalendar("office", holidays); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return newWorkingClock(NewTimeUtils("UTC"), schedule, calendar)
+}
+
+func localTime(t *testing.T, timezone string, year int, month time.Month, day, hour int) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(year, month, day, hour, 0, 0, 0, loc)
+}
+
+func TestWorkingClockHours(t *testing.T) {
+	// Friday 2025-03-07 16:00 UTC to Monday 2025-03-10 10:00 UTC
+	friday := time.Date(2025, 3, 7, 16, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule *WorkingHours
+		holidays []Holiday
+		start    time.Time
+		end      time.Time
+		expected float64
+	}{
+		{"wall clock without schedule", nil, nil, friday, monday, 66},
+		{"weekend skipped", &WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17}, nil, friday, monday, 2},
+		{"weekend worked", &WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17, WorkWeekends: true}, nil, friday, monday, 18},
+		{"holiday skipped", &WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17}, []Holiday{{Date: "2025-03-10", Name: "Office closed"}}, friday, monday, 1},
+		{"full day", &WorkingHours{Timezone: "UTC", StartHour: 0, EndHour: 24}, nil, friday, monday, 18},
+		{
+			// 09:00-17:00 in São Paulo (UTC-3) is 12:00-20:00 UTC
+			"team timezone",
+			&WorkingHours{Timezone: "America/Sao_Paulo", StartHour: 9, EndHour: 17},
+			nil,
+			time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC),
+			8,
+		},
+		{
+			"same instants in UTC",
+			&WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17},
+			nil,
+			time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC),
+			7,
+		},
+		{
+			// Clocks spring forward on Sunday 2025-03-09 in New York, a 23-hour day
+			"spring forward full day",
+			&WorkingHours{Timezone: "America/New_York", StartHour: 0, EndHour: 24, WorkWeekends: true},
+			nil,
+			localTime(t, "America/New_York", 2025, 3, 8, 0),
+			localTime(t, "America/New_York", 2025, 3, 10, 0),
+			47,
+		},
+		{
+			"spring forward office day",
+			&WorkingHours{Timezone: "America/New_York", StartHour: 9, EndHour: 17, WorkWeekends: true},
+			nil,
+			localTime(t, "America/New_York", 2025, 3, 9, 0),
+			localTime(t, "America/New_York", 2025, 3, 10, 0),
+			8,
+		},
+		{
+			// Clocks fall back on Sunday 2025-11-02, a 25-hour day
+			"fall back full day",
+			&WorkingHours{Timezone: "America/New_York", StartHour: 0, EndHour: 24, WorkWeekends: true},
+			nil,
+			localTime(t, "America/New_York", 2025, 11, 2, 0),
+			localTime(t, "America/New_York", 2025, 11, 3, 0),
+			25,
+		},
+		{"end before start falls back to wall clock", &WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17}, nil, monday, friday, -66},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := workingClockFor(t, tt.schedule, tt.holidays...)
+			if got := clock.hours(tt.start, tt.end); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("hours(%s, %s) = %.2f, expected %.2f", tt.start, tt.end, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTeamForAndValidateTeams(t *testing.T) {
+	teams := []TeamConfig{
+		{Name: "payments", Repositories: []string{"acme/pay-*"}},
+		{Name: "platform", Repositories: []string{"acme/*"}, WorkingHours: WorkingHours{Timezone: "UTC", StartHour: 0, EndHour: 24}},
+	}
+
+	if team, ok := TeamFor(teams, "", "Acme/Pay-API"); !ok || team.Name != "payments" {
+		t.Errorf("Expected the first owning team, got %+v", team)
+	}
+	if team, ok := TeamFor(teams, "PLATFORM", "acme/pay-api"); !ok || team.Name != "platform" {
+		t.Errorf("Expected the named team to take precedence, got %+v", team)
+	}
+	if _, ok := TeamFor(teams, "", "other/repo"); ok {
+		t.Error("Expected no team for an unowned repository")
+	}
+
+	if err := ValidateTeams(teams); err != nil {
+		t.Errorf("Expected valid teams, got %v", err)
+	}
+	if err := ValidateTeams([]TeamConfig{{Name: "night", WorkingHours: WorkingHours{StartHour: 22, EndHour: 6}}}); err == nil {
+		t.Error("Expected an error for a working day ending before it starts")
+	}
+	if err := ValidateTeams([]TeamConfig{{Repositories: []string{"acme/*"}}}); err == nil {
+		t.Error("Expected an error for a team without a name")
+	}
+}
@@ -0,0 +1,288 @@
+// Package repositories - Line-level AI attribution with git blame
+package repositories
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// blameHeaderRegex matches the header of a blamed line, with a SHA-1 or SHA-256 object name
+var blameHeaderRegex = regexp.MustCompile(`^([0-9a-f]{40}(?:[0-9a-f]{24})?) \d+ \d+`)
+
+// generatedHeaderRegex matches the marker of generated Go files and most code generators
+var generatedHeaderRegex = regexp.MustCompile(`(?i)^\s*(//|#|/\*|\*|--)\s*code generated .* do not edit`)
+
+// attributionLimits bound the files and lines blamed for line attribution
+type attributionLimits struct {
+	maxFiles int
+	maxLines int
+}
+
+// defaultAttributionLimits keep blaming a large monorepo within seconds
+var defaultAttributionLimits = attributionLimits{maxFiles: 5000, maxLines: 1000000}
+
+// attributionCache holds the line attribution of one HEAD
+type attributionCache struct {
+	head  string
+	hunks []metrics.BlameHunk
+}
+
+// SetLineAttributionLimits bounds line attribution to the first maxFiles files and maxLines
+// lines of the tree, in path order; 0 keeps the default
+func (g *GitClient) SetLineAttributionLimits(maxFiles, maxLines int) {
+	g.attributionMu.Lock()
+	defer g.attributionMu.Unlock()
+
+	g.attributionLimits = defaultAttributionLimits
+	if maxFiles > 0 {
+		g.attributionLimits.maxFiles = maxFiles
+	}
+	if maxLines > 0 {
+		g.attributionLimits.maxLines = maxLines
+	}
+	g.attribution = nil
+}
+
+// GetLineAttribution implements metrics.LineAttributionClient. It blames the text files of
+// HEAD, ignoring whitespace-only changes, and marks lines of commits the AI detection rules
+// match. Vendored and generated files are left out, as are files git fails to blame. The
+// result is kept until HEAD moves.
+func (g *GitClient) GetLineAttribution(ctx context.Context, owner, repo string) ([]metrics.BlameHunk, error) {
+	head, err := g.resolveHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.attributionMu.Lock()
+	defer g.attributionMu.Unlock()
+	if g.attribution != nil && g.attribution.head == head {
+		return append([]metrics.BlameHunk(nil), g.attribution.hunks...), nil
+	}
+
+	files, err := g.textFiles(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	var sources []string
+	for _, file := range files {
+		if !isVendoredOrGenerated(file) {
+			sources = append(sources, file)
+		}
+	}
+	if len(sources) > g.attributionLimits.maxFiles {
+		sources = sources[:g.attributionLimits.maxFiles]
+	}
+
+	hunks := g.blameFiles(ctx, head, sources, g.attributionLimits.maxLines)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	shas := make(map[string]bool)
+	for _, hunk := range hunks {
+		shas[hunk.SHA] = true
+	}
+	providers, err := g.aiCommits(ctx, shas)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hunks {
+		if provider, ok := providers[hunks[i].SHA]; ok {
+			hunks[i].AIAssisted = true
+			hunks[i].AIProvider = provider
+		}
+	}
+
+	g.attribution = &attributionCache{head: head, hunks: hunks}
+	return append([]metrics.BlameHunk(nil), hunks...), nil
+}
+
+// resolveHead returns the object name of HEAD
+func (g *GitClient) resolveHead(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", g.repoPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// textFiles lists the non-binary, non-empty files of a commit
+func (g *GitClient) textFiles(ctx context.Context, rev string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "grep", "-I", "--name-only", "-e", "", rev)
+	output, err := cmd.Output()
+	if err != nil {
+		// git grep exits with 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if file := strings.TrimPrefix(line, rev+":"); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// blameFiles blames files of rev in parallel, keeping path order. Once maxLines lines are
+// blamed no further files are started. Files that fail to blame or turn out to be generated
+// are skipped.
+func (g *GitClient) blameFiles(ctx context.Context, rev string, files []string, maxLines int) []metrics.BlameHunk {
+	results := make([][]metrics.BlameHunk, len(files))
+	var lines atomic.Int64
+	next := make(chan int)
+
+	workers := min(runtime.NumCPU(), 8)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				hunks, err := g.blameFile(ctx, rev, files[i])
+				if err != nil {
+					continue
+				}
+				for _, hunk := range hunks {
+					lines.Add(int64(hunk.Lines))
+				}
+				results[i] = hunks
+			}
+		}()
+	}
+	for i := range files {
+		if ctx.Err() != nil || lines.Load() >= int64(maxLines) {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var hunks []metrics.BlameHunk
+	for _, fileHunks := range results {
+		hunks = append(hunks, fileHunks...)
+	}
+	return hunks
+}
+
+// blameFile counts the lines of a file of rev last changed by each commit. Generated files
+// have no hunks.
+func (g *GitClient) blameFile(ctx context.Context, rev, file string) ([]metrics.BlameHunk, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "blame", "-w", "--line-porcelain", rev, "--", file)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", file, err)
+	}
+	return parseBlamePorcelain(file, bytes.NewReader(output))
+}
+
+// parseBlamePorcelain counts the lines per commit in git blame --line-porcelain output. A file
+// whose first lines carry a "Code generated ... DO NOT EDIT" marker has no hunks.
+func parseBlamePorcelain(file string, r io.Reader) ([]metrics.BlameHunk, error) {
+	bySHA := make(map[string]*metrics.BlameHunk)
+	var order []string
+	var current *metrics.BlameHunk
+	contentLines := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// Content line closes the entry of one blamed line
+			contentLines++
+			if contentLines <= 5 && generatedHeaderRegex.MatchString(line[1:]) {
+				return nil, nil
+			}
+			if current != nil {
+				current.Lines++
+			}
+		case blameHeaderRegex.MatchString(line):
+			sha := blameHeaderRegex.FindStringSubmatch(line)[1]
+			current = bySHA[sha]
+			if current == nil {
+				current = &metrics.BlameHunk{File: file, SHA: sha}
+				bySHA[sha] = current
+				order = append(order, sha)
+			}
+		case strings.HasPrefix(line, "author ") && current != nil:
+			current.Author = strings.TrimPrefix(line, "author ")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blame of %s: %w", file, err)
+	}
+
+	hunks := make([]metrics.BlameHunk, 0, len(order))
+	for _, sha := range order {
+		hunks = append(hunks, *bySHA[sha])
+	}
+	return hunks, nil
+}
+
+// vendoredDirs are directories of third-party or build output code
+var vendoredDirs = map[string]bool{
+	"vendor": true, "node_modules": true, "third_party": true, "bower_components": true, "dist": true,
+}
+
+// generatedSuffixes end the names of generated files and lock files
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_generated.go", ".gen.go", ".min.js", ".min.css", ".js.map", ".css.map",
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "Cargo.lock", "poetry.lock",
+	"composer.lock", "Gemfile.lock",
+}
+
+// isVendoredOrGenerated reports whether a path of the tree holds vendored or generated code,
+// which no one in the repository wrote
+func isVendoredOrGenerated(file string) bool {
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if vendoredDirs[dir] {
+			return true
+		}
+	}
+	name := path.Base(file)
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, "zz_generated")
+}
+
+// aiCommits returns the AI tool of each AI-assisted commit among shas
+func (g *GitClient) aiCommits(ctx context.Context, shas map[string]bool) (map[string]string, error) {
+	list := make([]string, 0, len(shas))
+	for sha := range shas {
+		list = append(list, sha)
+	}
+	messages, err := g.commitMessages(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make(map[string]string)
+	for sha, message := range messages {
+		commit := metrics.Commit{SHA: sha, Message: message}
+		g.detectAICommit(&commit, message)
+		if commit.AIAssisted {
+			providers[sha] = commit.AIProvider
+		}
+	}
+	return providers, nil
+}
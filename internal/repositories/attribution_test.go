@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// testRepo is a throwaway git repository
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := &testRepo{t: t, dir: t.TempDir()}
+	repo.git("init", "-q", "-b", "main")
+	return repo
+}
+
+// git runs a git command in the repository, failing the test on error
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commit writes files and commits them as author with message
+func (r *testRepo) commit(author, message string, files map[string]string) {
+	r.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(r.dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git("add", "-A")
+	r.git("-c", "user.name="+author, "-c", "user.email="+strings.ToLower(author)+"@example.com",
+		"commit", "-q", "--author", author+" <"+strings.ToLower(author)+"@example.com>", "-m", message)
+}
+
+func TestGetLineAttribution(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("Alice", "Add service", map[string]string{
+		"cmd/main.go":           "package main\n\nfunc main() {}\n",
+		"vendor/lib/lib.go":     "package lib\n",
+		"api/api.pb.go":         "package api\n",
+		"api/mock.go":           "// Code generated by mockgen. DO NOT EDIT.\npackage api\n",
+		"web/package-lock.json": "{}\n",
+	})
+	repo.commit("Bob", "Add handler\n\nai-assisted", map[string]string{
+		"cmd/handler.go": "package main\n\nfunc handle() {}\n\nfunc other() {}\n",
+	})
+
+	rules, err := metrics.NewAIDetectionRules([]metrics.AIToolRule{{Tool: "copilot", MessagePatterns: []string{`\bai-assisted\b`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewGitClient(repo.dir)
+	client.SetAIDetectionRules(rules)
+
+	hunks, err := client.GetLineAttribution(context.Background(), "acme", "api")
+	if err != nil {
+		t.Fatalf("GetLineAttribution failed: %v", err)
+	}
+	byFile := make(map[string]metrics.BlameHunk)
+	for _, hunk := range hunks {
+		byFile[hunk.File] = hunk
+	}
+	if len(byFile) != 2 {
+		t.Fatalf("Expected only cmd/main.go and cmd/handler.go, vendored and generated files left out, got %+v", hunks)
+	}
+	if main := byFile["cmd/main.go"]; main.Lines != 3 || main.Author != "Alice" || main.AIAssisted {
+		t.Errorf("Unexpected hunk of cmd/main.go %+v", main)
+	}
+	if handler := byFile["cmd/handler.go"]; handler.Lines != 5 || !handler.AIAssisted || handler.AIProvider != "copilot" {
+		t.Errorf("Unexpected hunk of cmd/handler.go %+v", handler)
+	}
+
+	// HEAD has not moved, so the cached attribution is returned
+	head := repo.git("rev-parse", "HEAD")
+	if client.attribution == nil || client.attribution.head != head {
+		t.Fatalf("Expected the attribution of %s cached", head)
+	}
+	client.attribution.hunks = client.attribution.hunks[:1]
+	if cached, _ := client.GetLineAttribution(context.Background(), "acme", "api"); len(cached) != 1 {
+		t.Errorf("Expected the cached attribution, got %+v", cached)
+	}
+
+	// A new commit blames the tree again, up to the file limit: api/mock.go, which is only
+	// known to be generated once blamed, and the first two files of cmd
+	repo.commit("Alice", "Add util", map[string]string{"cmd/util.go": "package main\n"})
+	client.SetLineAttributionLimits(3, 0)
+	hunks, err = client.GetLineAttribution(context.Background(), "acme", "api")
+	if err != nil {
+		t.Fatalf("GetLineAttribution failed: %v", err)
+	}
+	var files []string
+	for _, hunk := range hunks {
+		files = append(files, hunk.File)
+	}
+	if strings.Join(files, ",") != "cmd/handler.go,cmd/main.go" {
+		t.Errorf("Expected the first 3 files in path order, got %v", files)
+	}
+
+	// The line limit stops starting new files
+	client.SetLineAttributionLimits(0, 1)
+	if hunks, _ := client.GetLineAttribution(context.Background(), "acme", "api"); len(hunks) == 0 || len(hunks) > 3 {
+		t.Errorf("Expected the line limit to stop blaming early, got %+v", hunks)
+	}
+}
+
+func TestParseBlamePorcelain(t *testing.T) {
+	sha256 := strings.Repeat("ab", 32)
+	sha1 := strings.Repeat("cd", 20)
+	porcelain := strings.Join([]string{
+		sha256 + " 1 1 2",
+		"author Alice",
+		"filename main.go",
+		"\tpackage main",
+		sha256 + " 2 2",
+		"author Alice",
+		"filename main.go",
+		"\t",
+		sha1 + " 3 3 1",
+		"author Bob",
+		"filename main.go",
+		"\tfunc main() {}",
+	}, "\n")
+
+	hunks, err := parseBlamePorcelain("main.go", strings.NewReader(porcelain))
+	if err != nil {
+		t.Fatalf("parseBlamePorcelain failed: %v", err)
+	}
+	if len(hunks) != 2 || hunks[0].SHA != sha256 || hunks[0].Lines != 2 || hunks[1].SHA != sha1 || hunks[1].Author != "Bob" {
+		t.Errorf("Expected 2 lines of the SHA-256 commit and 1 of the SHA-1 commit, got %+v", hunks)
+	}
+
+	generated := sha1 + " 1 1 1\nauthor Bob\n\t# Code generated by protoc-gen-python. DO NOT EDIT.\n"
+	if hunks, err := parseBlamePorcelain("api_pb2.py", strings.NewReader(generated)); err != nil || len(hunks) != 0 {
+		t.Errorf("Expected no hunks for a generated file, got %+v, %v", hunks, err)
+	}
+}
+
+func TestIsVendoredOrGenerated(t *testing.T) {
+	tests := map[string]bool{
+		"vendor/github.com/pkg/errors/errors.go": true,
+		"web/node_modules/react/index.js":        true,
+		"api/v1/service.pb.go":                   true,
+		"pkg/apis/zz_generated.deepcopy.go":      true,
+		"web/static/app.min.js":                  true,
+		"go.sum":                                 true,
+		"internal/vendors/client.go":             false,
+		"cmd/main.go":                            false,
+		"docs/vendor.md":                         false,
+	}
+	for file, expected := range tests {
+		if got := isVendoredOrGenerated(file); got != expected {
+			t.Errorf("isVendoredOrGenerated(%q) = %v, expected %v", file, got, expected)
+		}
+	}
+}
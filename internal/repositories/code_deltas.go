@@ -0,0 +1,263 @@
+// Package repositories - Complexity and duplication deltas and changed lines of commits
+package repositories
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// hunkHeaderRegex matches the header of a diff hunk, whose line counts default to 1
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// GetCommitCodeDeltas implements metrics.CommitCodeDeltaClient. Each code file a commit touched
+// is analyzed as of the commit and its first parent; added files count from zero and deleted
+// files down to zero. Vendored and generated files are left out. All commits are listed by one
+// git log and all files read by one git cat-file, so unknown commits and unreadable files are
+// skipped rather than failing the call.
+func (g *GitClient) GetCommitCodeDeltas(ctx context.Context, owner, repo string, shas []string) (map[string]metrics.CodeDelta, error) {
+	deltas := make(map[string]metrics.CodeDelta, len(shas))
+	if len(shas) == 0 {
+		return deltas, nil
+	}
+
+	changed, err := g.changedFiles(ctx, shas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read each file after and before each commit
+	var objects []string
+	for _, sha := range shas {
+		for _, file := range changed[sha] {
+			if _, ok := metrics.AnalyzeSource(file, nil); ok && !isVendoredOrGenerated(file) {
+				objects = append(objects, sha+":"+file, sha+"^:"+file)
+			}
+		}
+	}
+	contents, err := g.readObjects(ctx, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sha := range shas {
+		files, ok := changed[sha]
+		if !ok {
+			continue
+		}
+		var delta metrics.CodeDelta
+		for _, file := range files {
+			after, okAfter := metrics.AnalyzeSource(file, contents[sha+":"+file])
+			before, okBefore := metrics.AnalyzeSource(file, contents[sha+"^:"+file])
+			if !okAfter || !okBefore || isVendoredOrGenerated(file) {
+				continue
+			}
+			delta.ComplexityDelta += after.CyclomaticComplexity - before.CyclomaticComplexity
+			delta.DuplicatedLinesDelta += duplicatedLines(after) - duplicatedLines(before)
+		}
+		deltas[sha] = delta
+	}
+	return deltas, nil
+}
+
+// GetCommitHunks implements metrics.CommitHunkClient with one git log of all commits, diffed
+// against their first parent without context lines. Unknown commits are skipped.
+func (g *GitClient) GetCommitHunks(ctx context.Context, owner, repo string, shas []string) (map[string][]metrics.DiffHunk, error) {
+	if len(shas) == 0 {
+		return make(map[string][]metrics.DiffHunk), nil
+	}
+
+	shas, err := g.existingCommits(ctx, shas)
+	if err != nil || len(shas) == 0 {
+		return make(map[string][]metrics.DiffHunk), err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "log", "--no-walk=unsorted", "--stdin",
+		"--format=%x1e%H", "--diff-merges=first-parent", "--no-renames", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", "-U0")
+	cmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit hunks: %w", err)
+	}
+	return parseCommitHunks(bytes.NewReader(output))
+}
+
+// parseCommitHunks reads the hunks of git log -U0 output whose commits start with a "\x1e<sha>"
+// line. Every listed commit has an entry, empty when it changed no text.
+func parseCommitHunks(r io.Reader) (map[string][]metrics.DiffHunk, error) {
+	hunks := make(map[string][]metrics.DiffHunk)
+	var sha, oldFile, file string
+	inHeader := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\x1e"):
+			sha = strings.TrimPrefix(line, "\x1e")
+			hunks[sha] = []metrics.DiffHunk{}
+			inHeader = false
+		case strings.HasPrefix(line, "diff --git "):
+			oldFile, file = "", ""
+			inHeader = true
+		case inHeader && strings.HasPrefix(line, "--- "):
+			oldFile = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = oldFile
+			}
+		case strings.HasPrefix(line, "@@ ") && sha != "" && file != "":
+			inHeader = false
+			match := hunkHeaderRegex.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			hunks[sha] = append(hunks[sha], metrics.DiffHunk{
+				File:     file,
+				OldStart: parseInt(match[1]),
+				OldLines: hunkLines(match[2]),
+				NewStart: parseInt(match[3]),
+				NewLines: hunkLines(match[4]),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read commit hunks: %w", err)
+	}
+	return hunks, nil
+}
+
+// hunkLines parses the line count of a hunk header, 1 when omitted
+func hunkLines(count string) int {
+	if count == "" {
+		return 1
+	}
+	return parseInt(count)
+}
+
+// existingCommits keeps the commits among shas that exist in the repository, with one git
+// cat-file
+func (g *GitClient) existingCommits(ctx context.Context, shas []string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "cat-file", "--batch-check=%(objecttype)")
+	cmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check commits: %w", err)
+	}
+
+	// One line per sha: its type, or "<sha> missing"
+	lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	var existing []string
+	for i, sha := range shas {
+		if i < len(lines) && lines[i] == "commit" {
+			existing = append(existing, sha)
+		}
+	}
+	return existing, nil
+}
+
+// changedFiles lists the files each commit touched against its first parent, with one git log.
+// Unknown commits have no entry.
+func (g *GitClient) changedFiles(ctx context.Context, shas []string) (map[string][]string, error) {
+	shas, err := g.existingCommits(ctx, shas)
+	if err != nil || len(shas) == 0 {
+		return make(map[string][]string), err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "log", "--no-walk=unsorted", "--stdin",
+		"--format=%x1e%H", "--diff-merges=first-parent", "--no-renames", "--name-only")
+	cmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of commits: %w", err)
+	}
+
+	files := make(map[string][]string)
+	for _, record := range strings.Split(string(output), "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if lines[0] == "" {
+			continue
+		}
+		files[lines[0]] = []string{}
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				files[lines[0]] = append(files[lines[0]], line)
+			}
+		}
+	}
+	return files, nil
+}
+
+// readObjects reads objects named like "<rev>:<path>" with one git cat-file. Objects that do not
+// exist, such as a file before the commit that added it, are left out.
+func (g *GitClient) readObjects(ctx context.Context, names []string) (map[string][]byte, error) {
+	contents := make(map[string][]byte, len(names))
+	if len(names) == 0 {
+		return contents, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(names, "\n") + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+
+	reader := bufio.NewReader(stdout)
+	var readErr error
+	for _, name := range names {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			readErr = fmt.Errorf("failed to read %s: %w", name, err)
+			break
+		}
+		// "<oid> <type> <size>", or "<name> missing" and similar without content
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		content := make([]byte, size+1) // Content ends with a newline
+		if _, err := io.ReadFull(reader, content); err != nil {
+			readErr = fmt.Errorf("failed to read %s: %w", name, err)
+			break
+		}
+		if fields[1] == "blob" {
+			contents[name] = content[:size]
+		}
+	}
+	if readErr != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, readErr
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to read files: %w", err)
+	}
+	return contents, nil
+}
+
+// duplicatedLines counts the lines in duplicated blocks of a file
+func duplicatedLines(file metrics.CodeFile) int {
+	lines := 0
+	for _, duplication := range file.Duplications {
+		lines += duplication.EndLine - duplication.StartLine + 1
+	}
+	return lines
+}
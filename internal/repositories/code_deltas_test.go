@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+const (
+	simpleSource  = "package main\n\nfunc main() {}\n"
+	branchySource = "package main\n\nfunc main() {\n\tif true {\n\t}\n\tfor {\n\t}\n}\n"
+)
+
+func TestGetCommitCodeDeltas(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("Alice", "Add main", map[string]string{"main.go": simpleSource, "README.md": "# api\n"})
+	root := repo.git("rev-parse", "HEAD")
+	repo.commit("Bob", "Add branches", map[string]string{
+		"main.go":           branchySource,
+		"vendor/lib/lib.go": branchySource,
+	})
+	branches := repo.git("rev-parse", "HEAD")
+	repo.git("rm", "-q", "main.go")
+	repo.git("-c", "user.name=Alice", "-c", "user.email=alice@example.com", "commit", "-q", "-m", "Remove main")
+	removal := repo.git("rev-parse", "HEAD")
+
+	unknown := strings.Repeat("0", 40)
+	deltas, err := NewGitClient(repo.dir).GetCommitCodeDeltas(context.Background(), "acme", "api", []string{root, branches, removal, unknown})
+	if err != nil {
+		t.Fatalf("GetCommitCodeDeltas failed: %v", err)
+	}
+
+	// Added files count from zero, removed files down to zero, vendored files not at all
+	expected := map[string]int{root: 1, branches: 2, removal: -3}
+	for sha, complexity := range expected {
+		if delta, ok := deltas[sha]; !ok || delta.ComplexityDelta != complexity {
+			t.Errorf("Complexity delta of %s = %+v, expected %d", sha[:7], delta, complexity)
+		}
+	}
+	if _, ok := deltas[unknown]; ok || len(deltas) != 3 {
+		t.Errorf("Expected the unknown commit skipped, got %+v", deltas)
+	}
+}
+
+func TestGetCommitHunks(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("Alice", "Add main", map[string]string{"main.go": simpleSource, "logo.png": "\x89PNG\x00\x01"})
+	root := repo.git("rev-parse", "HEAD")
+	repo.commit("Bob", "Add branches", map[string]string{"main.go": branchySource})
+	branches := repo.git("rev-parse", "HEAD")
+	repo.git("rm", "-q", "main.go")
+	repo.git("-c", "user.name=Alice", "-c", "user.email=alice@example.com", "commit", "-q", "-m", "Remove main")
+	removal := repo.git("rev-parse", "HEAD")
+
+	hunks, err := NewGitClient(repo.dir).GetCommitHunks(context.Background(), "acme", "api", []string{root, branches, removal, strings.Repeat("0", 40)})
+	if err != nil {
+		t.Fatalf("GetCommitHunks failed: %v", err)
+	}
+	expected := map[string][]metrics.DiffHunk{
+		// The binary file has no hunks
+		root:     {{File: "main.go", NewStart: 1, NewLines: 3}},
+		branches: {{File: "main.go", OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 6}},
+		removal:  {{File: "main.go", OldStart: 1, OldLines: 8}},
+	}
+	if len(hunks) != len(expected) {
+		t.Fatalf("Expected hunks of 3 commits, unknown one skipped, got %+v", hunks)
+	}
+	for sha, want := range expected {
+		got := hunks[sha]
+		if len(got) != len(want) || got[0] != want[0] {
+			t.Errorf("Hunks of %s = %+v, expected %+v", sha[:7], got, want)
+		}
+	}
+}
+
+func TestParseCommitHunks(t *testing.T) {
+	output := strings.Join([]string{
+		"\x1eabc",
+		"",
+		"diff --git a/old.go b/old.go",
+		"deleted file mode 100644",
+		"--- a/old.go",
+		"+++ /dev/null",
+		"@@ -1,2 +0,0 @@",
+		"-package old",
+		"--- not a header",
+		"diff --git a/new.go b/new.go",
+		"--- a/new.go",
+		"+++ b/new.go",
+		"@@ -4 +4,0 @@ func main() {",
+		"-\tprintln()",
+		"\x1edef",
+	}, "\n")
+
+	hunks, err := parseCommitHunks(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("parseCommitHunks failed: %v", err)
+	}
+	expected := []metrics.DiffHunk{
+		{File: "old.go", OldStart: 1, OldLines: 2},
+		{File: "new.go", OldStart: 4, OldLines: 1, NewStart: 4},
+	}
+	if len(hunks["abc"]) != 2 || hunks["abc"][0] != expected[0] || hunks["abc"][1] != expected[1] {
+		t.Errorf("Hunks of abc = %+v, expected %+v", hunks["abc"], expected)
+	}
+	if got, ok := hunks["def"]; !ok || len(got) != 0 {
+		t.Errorf("Expected an empty entry for a commit without changes, got %+v", got)
+	}
+}
@@ -3,37 +3,59 @@ package repositories
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/metrics"
 )
 
+var (
+	trailerRegex     = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):\s*(.*)$`)
+	coAuthorRegex    = regexp.MustCompile(`(?im)^co-authored-by:\s*(.+)$`)
+	mergeBranchRegex = regexp.MustCompile(`^Merge pull request #\d+ from [^/\s]+/(\S+)|^Merge (?:remote-tracking )?branch '(?:origin/)?([^']+)'`)
+)
+
 // GitClient implements local Git repository analysis
 type GitClient struct {
-	repoPath string
+	repoPath  string
+	detection *metrics.AIDetectionRules
+
+	attributionLimits attributionLimits
+	attributionMu     sync.Mutex
+	attribution       *attributionCache // Line attribution of the last blamed HEAD
 }
 
 // NewGitClient creates a new Git client for local repository analysis
 func NewGitClient(repoPath string) *GitClient {
 	return &GitClient{
-		repoPath: repoPath,
+		repoPath:          repoPath,
+		detection:         metrics.DefaultAIDetectionRules(),
+		attributionLimits: defaultAttributionLimits,
 	}
 }
 
+// SetAIDetectionRules sets the rules deciding which commits are AI-assisted and by which tool.
+// Use the same rules as the AI metrics calculator so both agree.
+func (g *GitClient) SetAIDetectionRules(rules *metrics.AIDetectionRules) {
+	g.detection = rules
+
+	g.attributionMu.Lock()
+	g.attribution = nil
+	g.attributionMu.Unlock()
+}
+
 // GetCommits fetches commits from local Git repository
 func (g *GitClient) GetCommits(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Commit, error) {
-	// Git log command with JSON-like format
 	sinceArg := since.Format("2006-01-02")
 	cmd := exec.CommandContext(ctx, "git",
 		"-C", g.repoPath,
 		"log",
 		"--since="+sinceArg,
-		"--pretty=format:{\"sha\":\"%H\",\"message\":\"%s\",\"author\":\"%an\",\"date\":\"%ai\"}",
+		"--pretty=format:"+gitLogFormat,
 		"--stat=1000,1000", // Get file stats
 		"--name-only",
 	)
@@ -43,79 +65,86 @@ func (g *GitClient) GetCommits(ctx context.Context, owner, repo string, since ti
 		return nil, fmt.Errorf("failed to run git log: %w", err)
 	}
 
-	return g.parseGitLog(string(output))
+	commits, err := g.parseGitLog(string(output))
+	if err != nil {
+		return nil, err
+	}
+	return commits, g.detectAIAssistance(ctx, commits, "--since="+sinceArg)
 }
 
+// GetCommitsBetween implements metrics.CommitRangeClient using git ancestry (base..head).
+// Merge commits are skipped so lead time reflects authored changes.
+func (g *GitClient) GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]metrics.Commit, error) {
+	revRange := head
+	if base != "" {
+		revRange = base + ".." + head
+	}
+
+	cmd := exec.CommandContext(ctx, "git",
+		"-C", g.repoPath,
+		"log",
+		"--no-merges",
+		"--pretty=format:"+gitLogFormat,
+		"--stat=1000,1000",
+		"--name-only",
+		revRange,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run git log %s: %w", revRange, err)
+	}
+
+	commits, err := g.parseGitLog(string(output))
+	if err != nil {
+		return nil, err
+	}
+	return commits, g.detectAIAssistance(ctx, commits, revRange)
+}
+
+// gitLogFormat starts each commit of git log with a record separator and splits its header
+// fields with unit separators, which commit subjects and author names cannot contain
+const gitLogFormat = "%x1e%H%x1f%s%x1f%an%x1f%ae%x1f%ai"
+
 // parseGitLog parses git log output into commit structures
 func (g *GitClient) parseGitLog(output string) ([]metrics.Commit, error) {
 	var commits []metrics.Commit
-	lines := strings.Split(output, "\n")
 
-	var currentCommit *metrics.Commit
-	var inStats bool
+	for _, record := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 5 {
+			continue // Skip malformed records
+		}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		date, err := time.Parse("2006-01-02 15:04:05 -0700", fields[4])
+		if err != nil {
 			continue
 		}
 
-		// Try to parse as JSON commit header
-		if strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}") {
-			// Save previous commit
-			if currentCommit != nil {
-				commits = append(commits, *currentCommit)
-			}
-
-			var commitData struct {
-				SHA     string `json:"sha"`
-				Message string `json:"message"`
-				Author  string `json:"author"`
-				Date    string `json:"date"`
-			}
-
-			if err := json.Unmarshal([]byte(line), &commitData); err != nil {
-				continue // Skip malformed lines
-			}
+		commit := metrics.Commit{
+			SHA:     fields[0],
+			Message: fields[1],
+			Author:  fields[2],
+			Email:   fields[3],
+			Date:    date,
+			Files:   []string{},
+		}
 
-			date, err := time.Parse("2006-01-02 15:04:05 -0700", commitData.Date)
-			if err != nil {
+		// Parse file stats and names
+		for _, line := range lines[1:] {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.Contains(line, "|") {
 				continue
 			}
-
-			currentCommit = &metrics.Commit{
-				SHA:     commitData.SHA,
-				Message: commitData.Message,
-				Author:  commitData.Author,
-				Date:    date,
-				Files:   []string{},
-			}
-
-			// Analyze AI assistance indicators
-			g.analyzeAIAssistance(currentCommit)
-			inStats = false
-			continue
-		}
-
-		// Parse file stats
-		if currentCommit != nil {
 			// Check for git stat line (e.g., "2 files changed, 10 insertions(+), 5 deletions(-)")
 			if strings.Contains(line, "file") && (strings.Contains(line, "changed") || strings.Contains(line, "insertion") || strings.Contains(line, "deletion")) {
-				g.parseStatLine(currentCommit, line)
-				inStats = true
+				g.parseStatLine(&commit, line)
 				continue
 			}
-
-			// File names (after stats line)
-			if inStats && !strings.Contains(line, "|") {
-				currentCommit.Files = append(currentCommit.Files, line)
-			}
+			commit.Files = append(commit.Files, line)
 		}
-	}
-
-	// Add last commit
-	if currentCommit != nil {
-		commits = append(commits, *currentCommit)
+		commits = append(commits, commit)
 	}
 
 	return commits, nil
@@ -147,181 +176,147 @@ func parseInt(s string) int {
 	return result
 }
 
-// analyzeAIAssistance detects AI assistance indicators in commits
-func (g *GitClient) analyzeAIAssistance(commit *metrics.Commit) {
-	message := strings.ToLower(commit.Message)
-
-	// Check for Co-authored-by patterns in commit message
-	coAuthorRegex := regexp.MustCompile(`co-authored-by:\s*([^<]+)`)
-	matches := coAuthorRegex.FindAllStringSubmatch(commit.Message, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			coAuthor := strings.TrimSpace(match[1])
-			commit.CoAuthoredBy = append(commit.CoAuthoredBy, coAuthor)
-
-			// Check if co-author is AI
-			if g.isAICoAuthor(coAuthor) {
-				commit.AIAssisted = true
-				commit.AIProvider = g.detectAIProvider(coAuthor)
-			}
-		}
-	}
+// GetCommitTrailers extracts Git trailers from commits (for AI detection)
+func (g *GitClient) GetCommitTrailers(ctx context.Context, sha string) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "git",
+		"-C", g.repoPath,
+		"show", "--format=%B", "--no-patch", sha,
+	)
 
-	// Check for AI keywords in commit message
-	aiPatterns := []string{
-		"copilot", "github copilot", "gh copilot",
-		"codewhisperer", "amazon codewhisperer",
-		"codeium", "tabnine", "cursor",
-		"ai-assisted", "ai-generated", "ai assisted", "ai generated",
-		"with ai", "using ai", "ai help", "ai suggestion",
-		"assisted by", "generated by", "suggested by",
-		"auto-generated", "auto generated",
-		"#copilot", "#ai", "#codewhisperer", "#codeium",
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit trailers: %w", err)
 	}
 
-	for _, pattern := range aiPatterns {
-		if strings.Contains(message, pattern) {
-			commit.AIAssisted = true
-			if commit.AIProvider == "" {
-				commit.AIProvider = g.detectAIProvider(pattern)
-			}
-			break
-		}
-	}
+	return parseTrailers(string(output)), nil
+}
 
-	// Check for commit message patterns that suggest AI assistance
-	aiMessagePatterns := []string{
-		"fix:", "feat:", "refactor:", // Conventional commits often AI-assisted
-		"update", "improve", "enhance", // Generic improvement words
-		"add", "remove", "delete", // Simple actions
+// parseTrailers extracts the Git trailers of a commit message: "Key: value" lines forming
+// its last paragraph, after the subject. Later values of a repeated key win.
+func parseTrailers(message string) map[string]string {
+	trailers := make(map[string]string)
+	paragraphs := strings.Split(strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n")), "\n\n")
+	if len(paragraphs) < 2 {
+		return trailers
 	}
 
-	if !commit.AIAssisted {
-		// If message is very generic and short, might be AI-assisted
-		words := strings.Fields(message)
-		if len(words) <= 3 {
-			for _, pattern := range aiMessagePatterns {
-				if strings.HasPrefix(message, pattern) {
-					// Weak indication of AI assistance (lower confidence)
-					break
-				}
-			}
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		match := trailerRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			return make(map[string]string) // Not a trailer block
 		}
+		trailers[match[1]] = strings.TrimSpace(match[2])
 	}
+	return trailers
 }
 
-// isAICoAuthor checks if a co-author name indicates AI assistance
-func (g *GitClient) isAICoAuthor(coAuthor string) bool {
-	aiCoAuthors := []string{
-		"github-copilot", "copilot", "gh-copilot",
-		"codewhisperer", "amazon-codewhisperer",
-		"codeium", "tabnine", "cursor-ai",
-		"ai-assistant", "ai-copilot", "assistant",
-		"bot", "automation", "auto",
-	}
-
-	coAuthorLower := strings.ToLower(coAuthor)
-	for _, ai := range aiCoAuthors {
-		if strings.Contains(coAuthorLower, ai) {
-			return true
-		}
+// coAuthors returns the "Name <email>" of every Co-authored-by trailer of a commit message
+func coAuthors(message string) []string {
+	var authors []string
+	for _, match := range coAuthorRegex.FindAllStringSubmatch(message, -1) {
+		authors = append(authors, strings.TrimSpace(match[1]))
 	}
-
-	return false
+	return authors
 }
 
-// detectAIProvider detects which AI provider was used
-func (g *GitClient) detectAIProvider(text string) string {
-	textLower := strings.ToLower(text)
-
-	if strings.Contains(textLower, "copilot") || strings.Contains(textLower, "github") {
-		return "github-copilot"
+// detectAIAssistance fills trailers, co-authors, branches and the AI tool of commits listed by
+// git log with revArgs. Detection reads the full message, which the log only has the subject of.
+func (g *GitClient) detectAIAssistance(ctx context.Context, commits []metrics.Commit, revArgs ...string) error {
+	if len(commits) == 0 {
+		return nil
 	}
-	if strings.Contains(textLower, "codewhisperer") || strings.Contains(textLower, "amazon") {
-		return "amazon-codewhisperer"
+	shas := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		shas = append(shas, commit.SHA)
 	}
-	if strings.Contains(textLower, "codeium") {
-		return "codeium"
+	messages, err := g.commitMessages(ctx, shas)
+	if err != nil {
+		return err
 	}
-	if strings.Contains(textLower, "tabnine") {
-		return "tabnine"
+	branches, err := g.mergedBranches(ctx, revArgs...)
+	if err != nil {
+		return err
 	}
-	if strings.Contains(textLower, "cursor") {
-		return "cursor"
+
+	for i := range commits {
+		commits[i].Branch = branches[commits[i].SHA]
+		g.detectAICommit(&commits[i], messages[commits[i].SHA])
 	}
+	return nil
+}
+
+// detectAICommit fills trailers, co-authors and the AI tool of a commit from its full message
+func (g *GitClient) detectAICommit(commit *metrics.Commit, message string) {
+	if message == "" {
+		message = commit.Message
+	}
+	commit.Trailers = parseTrailers(message)
+	commit.CoAuthoredBy = coAuthors(message)
 
-	return "unknown-ai"
+	probe := *commit
+	probe.Message = message
+	commit.AIProvider, commit.AIAssisted = g.detection.Detect(probe)
 }
 
-// GetCommitTrailers extracts Git trailers from commits (for AI detection)
-func (g *GitClient) GetCommitTrailers(ctx context.Context, sha string) (map[string]string, error) {
-	cmd := exec.CommandContext(ctx, "git",
-		"-C", g.repoPath,
-		"show", "--format=%B", "--no-patch", sha,
-	)
+// commitMessages reads the full messages of commits
+func (g *GitClient) commitMessages(ctx context.Context, shas []string) (map[string]string, error) {
+	messages := make(map[string]string, len(shas))
+	if len(shas) == 0 {
+		return messages, nil
+	}
 
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "log", "--no-walk=unsorted", "--stdin", "--format=%H%x1f%B%x1e")
+	cmd.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get commit trailers: %w", err)
+		return nil, fmt.Errorf("failed to read commit messages: %w", err)
 	}
 
-	trailers := make(map[string]string)
-	lines := strings.Split(string(output), "\n")
+	for _, record := range strings.Split(string(output), "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimSpace(record), "\x1f")
+		if ok {
+			messages[sha] = message
+		}
+	}
+	return messages, nil
+}
 
-	// Git trailers are at the end of commit message
-	inTrailers := false
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+// mergedBranches names the branch of commits brought in by the merge commits listed by git log
+// with revArgs, from merge subjects such as "Merge pull request #12 from owner/branch" or
+// "Merge branch 'name'". Commits of nested merges keep their innermost branch.
+func (g *GitClient) mergedBranches(ctx context.Context, revArgs ...string) (map[string]string, error) {
+	args := append([]string{"-C", g.repoPath, "log", "--merges", "--reverse", "--format=%H%x1f%s"}, revArgs...)
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge commits: %w", err)
+	}
 
-		if line == "" {
-			if inTrailers {
-				break // End of trailers
-			}
+	branches := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		sha, subject, ok := strings.Cut(line, "\x1f")
+		if !ok {
 			continue
 		}
-
-		// Trailer format: "Key: Value"
-		if strings.Contains(line, ":") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-
-				// Common trailer keys
-				if isTrailerKey(key) {
-					trailers[key] = value
-					inTrailers = true
-				}
-			}
-		} else if inTrailers {
-			break // No more trailers
+		match := mergeBranchRegex.FindStringSubmatch(subject)
+		if match == nil {
+			continue
+		}
+		branch := match[1]
+		if branch == "" {
+			branch = match[2]
 		}
-	}
-
-	return trailers, nil
-}
 
-// isTrailerKey checks if a key is a valid Git trailer key
-func isTrailerKey(key string) bool {
-	trailerKeys := []string{
-		"Co-authored-by", "Co-Authored-By",
-		"Signed-off-by", "Signed-Off-By",
-		"Reviewed-by", "Reviewed-By",
-		"Tested-by", "Tested-By",
-		"Reported-by", "Reported-By",
-		"Suggested-by", "Suggested-By",
-		"AI-Assisted", "AI-Generated",
-		"Copilot-Assisted", "Codewhisperer-Assisted",
-		"Generated-By", "Assisted-By",
-	}
-
-	for _, trailerKey := range trailerKeys {
-		if strings.EqualFold(key, trailerKey) {
-			return true
+		merged, err := exec.CommandContext(ctx, "git", "-C", g.repoPath, "rev-list", "--no-merges", sha+"^1.."+sha+"^2").Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits merged by %s: %w", sha, err)
+		}
+		for _, commit := range strings.Fields(string(merged)) {
+			if _, seen := branches[commit]; !seen {
+				branches[commit] = branch
+			}
 		}
 	}
-
-	return false
+	return branches, nil
 }
 
 // GetRepositoryStats gets overall repository statistics
@@ -393,34 +388,57 @@ type ContributorStat struct {
 	Commits int    `json:"commits"`
 }
 
-// IDEClient implements IDE telemetry client (mock for now)
+// IDEClient reads AI assistance data from ingested IDE telemetry
 type IDEClient struct {
-	// This would integrate with VS Code, JetBrains, etc. telemetry APIs
-	// For now, we'll use a simplified implementation
+	telemetry *metrics.AITelemetryStore
+	mock      bool
+}
+
+// NewIDEClient creates an IDE telemetry client backed by a telemetry store, filled by the
+// ingestion endpoint or metrics.LoadAITelemetryFiles
+func NewIDEClient(telemetry *metrics.AITelemetryStore) *IDEClient {
+	return &IDEClient{telemetry: telemetry}
 }
 
-// NewIDEClient creates a new IDE telemetry client
-func NewIDEClient() *IDEClient {
-	return &IDEClient{}
+// NewMockIDEClient creates an IDE telemetry client returning fixed sample data.
+// Only meant for demos and tests: HIR and AAC computed from it are not real.
+func NewMockIDEClient() *IDEClient {
+	return &IDEClient{mock: true}
 }
 
 // GetAIAssistData gets AI assistance data from IDE telemetry
 func (i *IDEClient) GetAIAssistData(ctx context.Context, user, repo string, since time.Time) (*metrics.AIAssistData, error) {
-	// This is a mock implementation
-	// In production, this would integrate with:
-	// - VS Code Copilot telemetry
-	// - JetBrains AI Assistant telemetry
-	// - Cursor AI telemetry
-	// - Local IDE logs/settings
-
-	return &metrics.AIAssistData{
-		TotalSuggestions:    150,
-		AcceptedSuggestions: 90,
-		AcceptanceRate:      0.6,
-		TimeWithAI:          12.5, // hours
-		LinesGenerated:      450,
-		Provider:            "github-copilot",
-	}, nil
+	if i.mock {
+		return &metrics.AIAssistData{
+			TotalSuggestions:    150,
+			AcceptedSuggestions: 90,
+			AcceptanceRate:      0.6,
+			TimeWithAI:          12.5, // hours
+			LinesGenerated:      450,
+			Provider:            "github-copilot",
+		}, nil
+	}
+
+	if i.telemetry == nil {
+		return nil, fmt.Errorf("IDE telemetry is not configured")
+	}
+	return i.telemetry.GetAIAssistData(ctx, user, repo, since)
+}
+
+// GetAIAssistDataByTool implements metrics.AIToolTelemetryClient
+func (i *IDEClient) GetAIAssistDataByTool(ctx context.Context, user, repo string, since time.Time) (map[string]*metrics.AIAssistData, error) {
+	if i.mock {
+		data, err := i.GetAIAssistData(ctx, user, repo, since)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]*metrics.AIAssistData{data.Provider: data}, nil
+	}
+
+	if i.telemetry == nil {
+		return nil, fmt.Errorf("IDE telemetry is not configured")
+	}
+	return i.telemetry.GetAIAssistDataByTool(ctx, user, repo, since)
 }
 
 /*
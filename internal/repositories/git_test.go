@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetCommitsDetectsAIWithDefaultRules(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("Alice", "Fix claude provider timeout", map[string]string{"provider.go": "package main\n"})
+	repo.commit("Bob", "Add retries\n\nCo-authored-by: Claude <noreply@anthropic.com>", map[string]string{"retry.go": "package main\n"})
+
+	commits, err := NewGitClient(repo.dir).GetCommits(context.Background(), "acme", "api", time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %+v", commits)
+	}
+	retries, timeout := commits[0], commits[1]
+	if !retries.AIAssisted || retries.AIProvider != "claude" || retries.Email != "bob@example.com" {
+		t.Errorf("Expected Bob's commit co-authored by the agent, got %+v", retries)
+	}
+	if timeout.AIAssisted {
+		t.Errorf("Expected a commit mentioning the tool not to be AI-assisted, got %+v", timeout)
+	}
+}
+
+func TestGetCommitsKeepsQuotedSubjectsAndAuthors(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("Alice", "Add retries", map[string]string{"retry.go": "package main\n"})
+	repo.git("rm", "-q", "retry.go")
+	repo.git("-c", "user.name=Bob", "-c", "user.email=bob@example.com",
+		"commit", "-q", "--author", `Bob "Quoted" Ross <bob@example.com>`, "-m", `Revert "Add retries"`)
+
+	commits, err := NewGitClient(repo.dir).GetCommits(context.Background(), "acme", "api", time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected the revert and the reverted commit, got %+v", commits)
+	}
+	revert := commits[0]
+	if revert.Message != `Revert "Add retries"` || revert.Author != `Bob "Quoted" Ross` || revert.Email != "bob@example.com" {
+		t.Errorf("Expected the quoted subject and author, got %+v", revert)
+	}
+	if len(revert.Files) != 1 || revert.Files[0] != "retry.go" {
+		t.Errorf("Expected the reverted file, got %v", revert.Files)
+	}
+}
@@ -0,0 +1,358 @@
+// Package repositories - Incident sources backed by GitHub issues, Jira, and incident tool exports.
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/services/github"
+)
+
+// GitHubIssueIncidentSource reads incidents from GitHub issues carrying incident labels
+type GitHubIssueIncidentSource struct {
+	service *github.Service
+	labels  []string
+}
+
+// NewGitHubIssueIncidentSource creates an incident source for labeled GitHub issues
+func NewGitHubIssueIncidentSource(service *github.Service, labels []string) *GitHubIssueIncidentSource {
+	if len(labels) == 0 {
+		labels = []string{"incident"}
+	}
+	return &GitHubIssueIncidentSource{
+		service: service,
+		labels:  labels,
+	}
+}
+
+// Name implements metrics.IncidentSource
+func (g *GitHubIssueIncidentSource) Name() string {
+	return "github_issues"
+}
+
+// GetIncidents implements metrics.IncidentSource
+func (g *GitHubIssueIncidentSource) GetIncidents(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Incident, error) {
+	if g.service == nil {
+		return nil, fmt.Errorf("GitHub service not initialized")
+	}
+
+	var incidents []metrics.Incident
+	// GitHub ANDs multiple labels, so query each incident label separately
+	seen := make(map[int]bool)
+	for _, label := range g.labels {
+		issues, err := g.service.GetIssues(ctx, owner, repo, since, []string{label})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if seen[issue.Number] || issue.CreatedAt.Before(since) {
+				continue
+			}
+			seen[issue.Number] = true
+
+			var labelNames []string
+			for _, l := range issue.Labels {
+				labelNames = append(labelNames, l.Name)
+			}
+
+			incidents = append(incidents, metrics.Incident{
+				ID:         strconv.Itoa(issue.Number),
+				Source:     g.Name(),
+				Repository: owner + "/" + repo,
+				Title:      issue.Title,
+				Severity:   severityFromLabels(labelNames),
+				OpenedAt:   issue.CreatedAt,
+				ResolvedAt: issue.ClosedAt,
+				URL:        issue.HTMLURL,
+			})
+		}
+	}
+
+	return incidents, nil
+}
+
+// JiraIncidentSource reads incidents from Jira issues of incident types
+type JiraIncidentSource struct {
+	client       metrics.JiraClient
+	project      string
+	issueTypes   []string
+	repositories map[string]string // Component or label names to "owner/repo"
+}
+
+// NewJiraIncidentSource creates an incident source for a Jira project. When repositories maps
+// component or label names to repositories ("owner/repo"), an incident only applies to the
+// repositories of its components and labels; otherwise incidents apply to every repository.
+func NewJiraIncidentSource(client metrics.JiraClient, project string, issueTypes []string, repositories map[string]string) *JiraIncidentSource {
+	if len(issueTypes) == 0 {
+		issueTypes = []string{"Incident"}
+	}
+	return &JiraIncidentSource{
+		client:       client,
+		project:      project,
+		issueTypes:   issueTypes,
+		repositories: repositories,
+	}
+}
+
+// Name implements metrics.IncidentSource
+func (j *JiraIncidentSource) Name() string {
+	return "jira"
+}
+
+// GetIncidents implements metrics.IncidentSource
+func (j *JiraIncidentSource) GetIncidents(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Incident, error) {
+	issues, err := j.client.GetIssues(ctx, j.project, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Jira issues: %w", err)
+	}
+
+	fullName := owner + "/" + repo
+	var incidents []metrics.Incident
+	for _, issue := range issues {
+		if !containsFold(j.issueTypes, issue.Type) || !j.appliesTo(issue, fullName) {
+			continue
+		}
+
+		title := issue.Summary
+		if title == "" {
+			title = issue.Key
+		}
+		incidents = append(incidents, metrics.Incident{
+			ID:         issue.Key,
+			Source:     j.Name(),
+			Repository: fullName,
+			Title:      title,
+			Severity:   severityFromPriority(issue.Priority),
+			OpenedAt:   issue.CreatedAt,
+			ResolvedAt: issue.ResolvedAt,
+		})
+	}
+
+	return incidents, nil
+}
+
+// appliesTo reports whether an issue is an incident of a repository through the repository
+// mapping of its components and labels
+func (j *JiraIncidentSource) appliesTo(issue metrics.Issue, fullName string) bool {
+	if len(j.repositories) == 0 {
+		return true
+	}
+	for _, names := range [][]string{issue.Components, issue.Labels} {
+		for _, name := range names {
+			for key, repository := range j.repositories {
+				if strings.EqualFold(key, name) && strings.EqualFold(repository, fullName) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// FileIncidentSource reads incidents from PagerDuty or Opsgenie JSON exports on disk
+type FileIncidentSource struct {
+	paths      []string
+	repository string
+}
+
+// NewFileIncidentSource creates an incident source for exported incident files.
+// Paths may contain glob patterns. When repository is set ("owner/repo"), the
+// incidents only apply to that repository; otherwise they apply to every repository.
+func NewFileIncidentSource(paths []string, repository string) *FileIncidentSource {
+	return &FileIncidentSource{
+		paths:      paths,
+		repository: repository,
+	}
+}
+
+// Name implements metrics.IncidentSource
+func (f *FileIncidentSource) Name() string {
+	return "file"
+}
+
+// GetIncidents implements metrics.IncidentSource
+func (f *FileIncidentSource) GetIncidents(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Incident, error) {
+	fullName := owner + "/" + repo
+	if f.repository != "" && !strings.EqualFold(f.repository, fullName) {
+		return nil, nil
+	}
+
+	var incidents []metrics.Incident
+	for _, pattern := range f.paths {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid incident file pattern %s: %w", pattern, err)
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read incident file %s: %w", file, err)
+			}
+
+			parsed, err := ParseIncidentExport(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse incident file %s: %w", file, err)
+			}
+
+			for _, incident := range parsed {
+				if incident.OpenedAt.Before(since) {
+					continue
+				}
+				incident.Repository = fullName
+				incidents = append(incidents, incident)
+			}
+		}
+	}
+
+	return incidents, nil
+}
+
+// ParseIncidentExport parses a PagerDuty ("incidents") or Opsgenie ("data") JSON export
+func ParseIncidentExport(data []byte) ([]metrics.Incident, error) {
+	var envelope struct {
+		Incidents []PagerDutyIncident `json:"incidents"`
+		Data      []OpsgenieIncident  `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	var incidents []metrics.Incident
+	for _, pd := range envelope.Incidents {
+		incidents = append(incidents, pd.toIncident())
+	}
+	for _, og := range envelope.Data {
+		incidents = append(incidents, og.toIncident())
+	}
+
+	return incidents, nil
+}
+
+// PagerDutyIncident is an incident from the PagerDuty REST API or export
+type PagerDutyIncident struct {
+	ID                 string     `json:"id"`
+	IncidentNumber     int        `json:"incident_number"`
+	Title              string     `json:"title"`
+	Status             string     `json:"status"` // triggered, acknowledged, resolved
+	Urgency            string     `json:"urgency"`
+	HTMLURL            string     `json:"html_url"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+	LastStatusChangeAt *time.Time `json:"last_status_change_at"`
+	Priority           *struct {
+		Summary string `json:"summary"`
+	} `json:"priority"`
+	Service struct {
+		Summary string `json:"summary"`
+	} `json:"service"`
+}
+
+func (pd PagerDutyIncident) toIncident() metrics.Incident {
+	incident := metrics.Incident{
+		ID:          pd.ID,
+		Source:      "pagerduty",
+		Title:       pd.Title,
+		Severity:    severityFromPriority(pd.Urgency),
+		Environment: pd.Service.Summary,
+		OpenedAt:    pd.CreatedAt,
+		URL:         pd.HTMLURL,
+	}
+	if pd.Priority != nil && pd.Priority.Summary != "" {
+		incident.Severity = severityFromPriority(pd.Priority.Summary)
+	}
+	if pd.Status == "resolved" {
+		incident.ResolvedAt = pd.ResolvedAt
+		if incident.ResolvedAt == nil {
+			incident.ResolvedAt = pd.LastStatusChangeAt
+		}
+	}
+	return incident
+}
+
+// OpsgenieIncident is an incident from the Opsgenie Incident API or export
+type OpsgenieIncident struct {
+	ID              string     `json:"id"`
+	TinyID          string     `json:"tinyId"`
+	Message         string     `json:"message"`
+	Status          string     `json:"status"` // open, resolved, closed
+	Priority        string     `json:"priority"`
+	Tags            []string   `json:"tags"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	ImpactStartDate *time.Time `json:"impactStartDate"`
+	ImpactEndDate   *time.Time `json:"impactEndDate"`
+}
+
+func (og OpsgenieIncident) toIncident() metrics.Incident {
+	incident := metrics.Incident{
+		ID:       og.ID,
+		Source:   "opsgenie",
+		Title:    og.Message,
+		Severity: severityFromPriority(og.Priority),
+		OpenedAt: og.CreatedAt,
+	}
+	if og.ImpactStartDate != nil {
+		incident.OpenedAt = *og.ImpactStartDate
+	}
+	if og.Status == "resolved" || og.Status == "closed" {
+		if og.ImpactEndDate != nil {
+			incident.ResolvedAt = og.ImpactEndDate
+		} else {
+			updatedAt := og.UpdatedAt
+			incident.ResolvedAt = &updatedAt
+		}
+	}
+	return incident
+}
+
+// severityFromPriority maps tool-specific priority names to incident severities
+func severityFromPriority(priority string) string {
+	switch strings.ToLower(strings.TrimSpace(priority)) {
+	case "p1", "sev1", "sev-1", "highest", "blocker", "critical":
+		return "critical"
+	case "p2", "sev2", "sev-2", "high", "major":
+		return "high"
+	case "p3", "sev3", "sev-3", "medium", "moderate":
+		return "medium"
+	case "":
+		return ""
+	default:
+		return "low"
+	}
+}
+
+// severityFromLabels derives severity from labels like "sev1", "P2" or "severity:high"
+func severityFromLabels(labels []string) string {
+	for _, label := range labels {
+		value := label
+		if idx := strings.IndexAny(value, ":/"); idx >= 0 {
+			value = value[idx+1:]
+		}
+		if severity := severityFromPriority(value); severity != "" && severity != "low" {
+			return severity
+		}
+		if strings.EqualFold(value, "low") || strings.EqualFold(value, "p4") || strings.EqualFold(value, "sev4") {
+			return "low"
+		}
+	}
+	return ""
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
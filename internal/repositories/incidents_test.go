@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/services/github"
+)
+
+func TestParseIncidentExport(t *testing.T) {
+	data := []byte(`{
+		"incidents": [
+			{"id": "PD1", "title": "API down", "status": "resolved", "urgency": "high",
+			 "created_at": "2025-01-10T10:00:00Z", "last_status_change_at": "2025-01-10T12:00:00Z",
+			 "priority": {"summary": "P1"}, "service": {"summary": "production"}},
+			{"id": "PD2", "title": "Slow queries", "status": "acknowledged", "urgency": "low",
+			 "created_at": "2025-01-11T10:00:00Z", "resolved_at": "2025-01-11T11:00:00Z"}
+		],
+		"data": [
+			{"id": "OG1", "message": "Queue backlog", "status": "closed", "priority": "P3",
+			 "createdAt": "2025-01-12T08:00:00Z", "updatedAt": "2025-01-12T20:00:00Z",
+			 "impactStartDate": "2025-01-12T07:30:00Z", "impactEndDate": "2025-01-12T09:00:00Z"},
+			{"id": "OG2", "message": "Disk full", "status": "resolved", "priority": "P2",
+			 "createdAt": "2025-01-13T08:00:00Z", "updatedAt": "2025-01-13T10:00:00Z"}
+		]
+	}`)
+
+	incidents, err := ParseIncidentExport(data)
+	if err != nil {
+		t.Fatalf("ParseIncidentExport() failed: %v", err)
+	}
+	if len(incidents) != 4 {
+		t.Fatalf("Expected 4 incidents, got %d", len(incidents))
+	}
+
+	tests := []struct {
+		id         string
+		source     string
+		severity   string
+		resolution time.Duration
+		resolved   bool
+	}{
+		{"PD1", "pagerduty", "critical", 2 * time.Hour, true}, // Priority wins over urgency, resolved at last status change
+		{"PD2", "pagerduty", "low", 0, false},                 // Not resolved yet, resolved_at ignored
+		{"OG1", "opsgenie", "medium", 90 * time.Minute, true}, // Impact window
+		{"OG2", "opsgenie", "high", 2 * time.Hour, true},      // Falls back to the last update
+	}
+	for i, tt := range tests {
+		incident := incidents[i]
+		if incident.ID != tt.id || incident.Source != tt.source {
+			t.Errorf("Incident %d: expected %s from %s, got %s from %s", i, tt.id, tt.source, incident.ID, incident.Source)
+		}
+		if incident.Severity != tt.severity {
+			t.Errorf("%s: expected severity %q, got %q", tt.id, tt.severity, incident.Severity)
+		}
+		if incident.IsResolved() != tt.resolved {
+			t.Errorf("%s: expected resolved %v, got %v", tt.id, tt.resolved, incident.IsResolved())
+		}
+		if got := incident.ResolutionTime(); got != tt.resolution {
+			t.Errorf("%s: expected resolution time %v, got %v", tt.id, tt.resolution, got)
+		}
+	}
+	if incidents[0].Environment != "production" {
+		t.Errorf("Expected the PagerDuty service as environment, got %q", incidents[0].Environment)
+	}
+}
+
+func TestParseIncidentExportInvalid(t *testing.T) {
+	if _, err := ParseIncidentExport([]byte("not json")); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestSeverityMapping(t *testing.T) {
+	priorities := map[string]string{
+		"P1":       "critical",
+		"SEV-1":    "critical",
+		"Highest":  "critical",
+		"p2":       "high",
+		"major":    "high",
+		"P3":       "medium",
+		"moderate": "medium",
+		"P5":       "low",
+		"":         "",
+	}
+	for priority, expected := range priorities {
+		if got := severityFromPriority(priority); got != expected {
+			t.Errorf("severityFromPriority(%q) = %q, expected %q", priority, got, expected)
+		}
+	}
+
+	labels := []struct {
+		labels   []string
+		expected string
+	}{
+		{[]string{"incident", "sev1"}, "critical"},
+		{[]string{"severity:high"}, "high"},
+		{[]string{"priority/P3"}, "medium"},
+		{[]string{"incident", "P4"}, "low"},
+		{[]string{"incident", "bug"}, ""},
+	}
+	for _, tt := range labels {
+		if got := severityFromLabels(tt.labels); got != tt.expected {
+			t.Errorf("severityFromLabels(%v) = %q, expected %q", tt.labels, got, tt.expected)
+		}
+	}
+}
+
+func TestGitHubIssueIncidentSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test-owner/test-repo/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("labels") {
+		case "incident":
+			w.Write([]byte(`[
+				{"number": 1, "title": "Outage", "html_url": "https://github.com/test-owner/test-repo/issues/1",
+				 "labels": [{"name": "incident"}, {"name": "sev1"}],
+				 "created_at": "2025-01-10T10:00:00Z", "closed_at": "2025-01-10T13:00:00Z"},
+				{"number": 2, "title": "Old outage", "labels": [{"name": "incident"}],
+				 "created_at": "2024-12-01T10:00:00Z"},
+				{"number": 3, "title": "Fix outage", "labels": [{"name": "incident"}],
+				 "created_at": "2025-01-11T10:00:00Z", "pull_request": {"url": "https://api.github.com/pulls/3"}}
+			]`))
+		case "outage":
+			// Issue 1 carries both labels and must be counted once
+			w.Write([]byte(`[
+				{"number": 1, "title": "Outage", "labels": [{"name": "incident"}, {"name": "outage"}],
+				 "created_at": "2025-01-10T10:00:00Z", "closed_at": "2025-01-10T13:00:00Z"},
+				{"number": 4, "title": "Degraded", "labels": [{"name": "outage"}],
+				 "created_at": "2025-01-12T10:00:00Z"}
+			]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	service, err := github.NewService(&github.Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             5 * time.Second,
+		RetryBackoffMs:      10,
+		CacheTTLMinutes:     1,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	source := NewGitHubIssueIncidentSource(service, []string{"incident", "outage"})
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	incidents, err := source.GetIncidents(context.Background(), "test-owner", "test-repo", since)
+	if err != nil {
+		t.Fatalf("GetIncidents() failed: %v", err)
+	}
+	if len(incidents) != 2 {
+		t.Fatalf("Expected 2 incidents (deduplicated, no pull requests, none before since), got %d: %+v", len(incidents), incidents)
+	}
+
+	outage := incidents[0]
+	if outage.ID != "1" || outage.Source != "github_issues" || outage.Repository != "test-owner/test-repo" {
+		t.Errorf("Unexpected incident identity: %+v", outage)
+	}
+	if outage.Severity != "critical" {
+		t.Errorf("Expected severity from the sev1 label, got %q", outage.Severity)
+	}
+	if got := outage.ResolutionTime(); got != 3*time.Hour {
+		t.Errorf("Expected resolution time 3h, got %v", got)
+	}
+	if incidents[1].ID != "4" || incidents[1].IsResolved() {
+		t.Errorf("Expected open incident 4, got %+v", incidents[1])
+	}
+}
+
+// stubJiraClient returns fixed Jira issues
+type stubJiraClient struct {
+	issues  []metrics.Issue
+	project string
+}
+
+func (s *stubJiraClient) GetIssues(ctx context.Context, project string, since time.Time) ([]metrics.Issue, error) {
+	s.project = project
+	return s.issues, nil
+}
+
+func TestJiraIncidentSource(t *testing.T) {
+	resolved := time.Date(2025, 1, 10, 14, 0, 0, 0, time.UTC)
+	client := &stubJiraClient{issues: []metrics.Issue{
+		{Key: "OPS-1", Type: "Incident", Priority: "Highest", CreatedAt: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), ResolvedAt: &resolved},
+		{Key: "OPS-2", Type: "Bug", Priority: "High", CreatedAt: time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC)},
+		{Key: "OPS-3", Type: "incident", Priority: "Medium", CreatedAt: time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)},
+	}}
+
+	source := NewJiraIncidentSource(client, "OPS", nil, nil)
+	incidents, err := source.GetIncidents(context.Background(), "test-owner", "test-repo", time.Time{})
+	if err != nil {
+		t.Fatalf("GetIncidents() failed: %v", err)
+	}
+	if client.project != "OPS" {
+		t.Errorf("Expected project OPS to be queried, got %q", client.project)
+	}
+	if len(incidents) != 2 {
+		t.Fatalf("Expected 2 incidents of type Incident, got %d", len(incidents))
+	}
+	if incidents[0].ID != "OPS-1" || incidents[0].Severity != "critical" || incidents[0].ResolutionTime() != 4*time.Hour {
+		t.Errorf("Unexpected first incident: %+v", incidents[0])
+	}
+	if incidents[1].ID != "OPS-3" || incidents[1].Severity != "medium" || incidents[1].IsResolved() {
+		t.Errorf("Unexpected second incident: %+v", incidents[1])
+	}
+}
+
+func TestJiraIncidentSourceRepositories(t *testing.T) {
+	opened := time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)
+	client := &stubJiraClient{issues: []metrics.Issue{
+		{Key: "OPS-1", Summary: "Checkout down", Type: "Incident", Components: []string{"Checkout"}, CreatedAt: opened},
+		{Key: "OPS-2", Summary: "Search slow", Type: "Incident", Labels: []string{"search"}, CreatedAt: opened},
+		{Key: "OPS-3", Type: "Incident", Labels: []string{"checkout-api"}, CreatedAt: opened},
+		{Key: "OPS-4", Summary: "Unmapped", Type: "Incident", CreatedAt: opened},
+	}}
+	source := NewJiraIncidentSource(client, "OPS", nil, map[string]string{
+		"checkout":     "acme/checkout",
+		"checkout-api": "acme/checkout",
+		"search":       "acme/search",
+	})
+
+	incidents, err := source.GetIncidents(context.Background(), "acme", "checkout", time.Time{})
+	if err != nil {
+		t.Fatalf("GetIncidents() failed: %v", err)
+	}
+	if len(incidents) != 2 {
+		t.Fatalf("Expected the incidents of the checkout component and label, got %+v", incidents)
+	}
+	if incidents[0].Title != "Checkout down" || incidents[1].Title != "OPS-3" {
+		t.Errorf("Expected summaries as titles, the key without one, got %q and %q", incidents[0].Title, incidents[1].Title)
+	}
+}
+
+func TestFileIncidentSource(t *testing.T) {
+	dir := t.TempDir()
+	export := `{"incidents": [
+		{"id": "PD1", "title": "API down", "status": "resolved", "urgency": "high",
+		 "created_at": "2025-01-10T10:00:00Z", "resolved_at": "2025-01-10T11:00:00Z"},
+		{"id": "PD0", "title": "Old", "status": "resolved", "urgency": "low",
+		 "created_at": "2024-01-10T10:00:00Z", "resolved_at": "2024-01-10T11:00:00Z"}
+	]}`
+	if err := os.WriteFile(filepath.Join(dir, "pagerduty.json"), []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := NewFileIncidentSource([]string{filepath.Join(dir, "*.json")}, "test-owner/test-repo")
+	incidents, err := source.GetIncidents(context.Background(), "test-owner", "test-repo", since)
+	if err != nil {
+		t.Fatalf("GetIncidents() failed: %v", err)
+	}
+	if len(incidents) != 1 || incidents[0].ID != "PD1" || incidents[0].Repository != "test-owner/test-repo" {
+		t.Errorf("Expected only PD1 for the repository, got %+v", incidents)
+	}
+
+	other, err := source.GetIncidents(context.Background(), "test-owner", "other-repo", since)
+	if err != nil {
+		t.Fatalf("GetIncidents() failed: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("Expected no incidents for another repository, got %d", len(other))
+	}
+}
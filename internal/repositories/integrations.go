@@ -60,6 +60,15 @@ func (g *GitHubClient) GetPullRequests(ctx context.Context, owner, repo string,
 	return g.service.GetPullRequests(ctx, owner, repo, since)
 }
 
+// ListPullRequests lists pull requests from GitHub API without sizes or reviews
+func (g *GitHubClient) ListPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	if g.service == nil {
+		return nil, fmt.Errorf("GitHub service not initialized")
+	}
+
+	return g.service.ListPullRequests(ctx, owner, repo, since)
+}
+
 // GetDeployments fetches deployments from GitHub API
 func (g *GitHubClient) GetDeployments(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Deployment, error) {
 	if g.service == nil {
@@ -104,7 +113,7 @@ func (j *JiraClient) GetIssues(ctx context.Context, project string, since time.T
 	jql := fmt.Sprintf("project = %s AND created >= %s ORDER BY created DESC",
 		project, since.Format("2006-01-02"))
 
-	url := fmt.Sprintf("%s/rest/api/3/search?jql=%s&fields=key,issuetype,status,priority,created,updated,resolutiondate",
+	url := fmt.Sprintf("%s/rest/api/3/search?jql=%s&fields=key,summary,issuetype,status,priority,created,updated,resolutiondate,components,labels",
 		j.baseURL, jql)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -134,11 +143,16 @@ func (j *JiraClient) GetIssues(ctx context.Context, project string, since time.T
 	for _, ji := range response.Issues {
 		issue := metrics.Issue{
 			Key:       ji.Key,
+			Summary:   ji.Fields.Summary,
 			Type:      ji.Fields.IssueType.Name,
 			Status:    ji.Fields.Status.Name,
 			Priority:  ji.Fields.Priority.Name,
 			CreatedAt: ji.Fields.Created,
 			UpdatedAt: ji.Fields.Updated,
+			Labels:    ji.Fields.Labels,
+		}
+		for _, component := range ji.Fields.Components {
+			issue.Components = append(issue.Components, component.Name)
 		}
 
 		if ji.Fields.ResolutionDate != nil {
@@ -162,12 +176,19 @@ type JiraIssue struct {
 }
 
 type JiraFields struct {
-	IssueType      JiraIssueType `json:"issuetype"`
-	Status         JiraStatus    `json:"status"`
-	Priority       JiraPriority  `json:"priority"`
-	Created        time.Time     `json:"created"`
-	Updated        time.Time     `json:"updated"`
-	ResolutionDate *time.Time    `json:"resolutiondate"`
+	Summary        string          `json:"summary"`
+	IssueType      JiraIssueType   `json:"issuetype"`
+	Status         JiraStatus      `json:"status"`
+	Priority       JiraPriority    `json:"priority"`
+	Created        time.Time       `json:"created"`
+	Updated        time.Time       `json:"updated"`
+	ResolutionDate *time.Time      `json:"resolutiondate"`
+	Components     []JiraComponent `json:"components"`
+	Labels         []string        `json:"labels"`
+}
+
+type JiraComponent struct {
+	Name string `json:"name"`
 }
 
 type JiraIssueType struct {
@@ -198,12 +219,25 @@ func NewWakaTimeClient(apiKey string) *WakaTimeClient {
 	}
 }
 
+// NewWakaTimeClientWithBaseURL creates a client for a WakaTime-compatible API, such as the
+// gateway's heartbeat receiver at https://<gateway>/api/v1
+func NewWakaTimeClientWithBaseURL(apiKey, baseURL string) *WakaTimeClient {
+	client := NewWakaTimeClient(apiKey)
+	client.baseURL = strings.TrimSuffix(baseURL, "/")
+	return client
+}
+
 // GetCodingTime fetches coding time from WakaTime API
 func (w *WakaTimeClient) GetCodingTime(ctx context.Context, user, repo string, since time.Time) (*metrics.CodingTime, error) {
 	// WakaTime API for summaries
 	start := since.Format("2006-01-02")
 	end := time.Now().Format("2006-01-02")
 
+	// WakaTime only serves summaries per user; without one, those of the API key's owner,
+	// which for a team key of the gateway's heartbeat receiver is the whole team
+	if user == "" {
+		user = "current"
+	}
 	url := fmt.Sprintf("%s/users/%s/summaries?start=%s&end=%s&project=%s",
 		w.baseURL, user, start, end, repo)
 
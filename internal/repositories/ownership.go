@@ -0,0 +1,81 @@
+// Package repositories - File history for code ownership
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// GetFileHistory implements metrics.FileHistoryClient. It walks the history oldest first,
+// moving the changes of renamed files to their new path and dropping deleted files, so only
+// files of the current tree remain. Authors are keyed by their email after .mailmap, so one
+// person committing under several names counts once. Merge commits and vendored or generated
+// files are skipped.
+func (g *GitClient) GetFileHistory(ctx context.Context, owner, repo string) ([]metrics.FileChange, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "-c", "core.quotePath=false",
+		"log", "--reverse", "--no-merges", "--find-renames", "--name-status", "--format=%x1e%aE%x1f%aN")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file history: %w", err)
+	}
+
+	history := make(map[string][]metrics.FileChange)
+	for _, record := range strings.Split(string(output), "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		email, name, _ := strings.Cut(lines[0], "\x1f")
+		author := strings.ToLower(strings.TrimSpace(email))
+		name = strings.TrimSpace(name)
+		if author == "" {
+			// Commits without an email are keyed by name
+			author = name
+		}
+		if author == "" {
+			continue
+		}
+
+		for _, line := range lines[1:] {
+			fields := strings.Split(strings.TrimSpace(line), "\t")
+			if len(fields) < 2 || fields[0] == "" {
+				continue
+			}
+			path := fields[len(fields)-1]
+			switch fields[0][0] {
+			case 'A':
+				history[path] = []metrics.FileChange{{File: path, Author: author, Name: name, Created: true}}
+			case 'D':
+				delete(history, path)
+			case 'R':
+				// Renamed files keep the changes made under their old path
+				moved := history[fields[1]]
+				delete(history, fields[1])
+				for i := range moved {
+					moved[i].File = path
+				}
+				history[path] = append(moved, metrics.FileChange{File: path, Author: author, Name: name})
+			case 'C':
+				history[path] = []metrics.FileChange{{File: path, Author: author, Name: name, Created: true}}
+			default:
+				history[path] = append(history[path], metrics.FileChange{File: path, Author: author, Name: name})
+			}
+		}
+	}
+
+	files := make([]string, 0, len(history))
+	for file := range history {
+		if !isVendoredOrGenerated(file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	var changes []metrics.FileChange
+	for _, file := range files {
+		changes = append(changes, history[file]...)
+	}
+	return changes, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetFileHistory(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("Alice", "Add service", map[string]string{
+		".mailmap":          "Alice <alice@example.com> <al@old.example.com>\n",
+		"old.go":            "package main\n",
+		"vendor/lib/lib.go": "package lib\n",
+		"api/api.pb.go":     "package api\n",
+	})
+	// The same person under an old name and email
+	if err := os.WriteFile(filepath.Join(repo.dir, "old.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo.git("add", "-A")
+	repo.git("commit", "-q", "--author", "Al <AL@old.example.com>", "-m", "Add main")
+	repo.git("mv", "old.go", "main.go")
+	repo.git("commit", "-q", "--author", "Bob <bob@example.com>", "-m", "Rename main")
+
+	changes, err := NewGitClient(repo.dir).GetFileHistory(context.Background(), "acme", "api")
+	if err != nil {
+		t.Fatalf("GetFileHistory failed: %v", err)
+	}
+
+	var main []string
+	for _, change := range changes {
+		switch change.File {
+		case "main.go":
+			main = append(main, change.Author+"|"+change.Name)
+		case ".mailmap":
+		default:
+			t.Errorf("Unexpected file %+v; vendored, generated and renamed-away files are left out", change)
+		}
+	}
+	expected := []string{"alice@example.com|Alice", "alice@example.com|Alice", "bob@example.com|Bob"}
+	if len(main) != len(expected) {
+		t.Fatalf("Expected the changes of main.go under its old name, by alice twice, got %v", main)
+	}
+	for i := range expected {
+		if main[i] != expected[i] {
+			t.Errorf("Change %d of main.go = %s, expected %s", i, main[i], expected[i])
+		}
+	}
+}
@@ -4,6 +4,10 @@ package scorecard
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/metrics"
@@ -15,6 +19,27 @@ type Engine struct {
 	doraCalculator *metrics.DORACalculator
 	chiCalculator  *metrics.CHICalculator
 	aiCalculator   *metrics.AIMetricsCalculator
+
+	// DORA calculators keyed by repository host (e.g. gitlab.com)
+	hostCalculators map[string]*metrics.DORACalculator
+
+	// Performance band thresholds for DORA classification
+	benchmarks metrics.DORABenchmarks
+
+	// Optional CHI history for anomaly detection
+	chiHistory *metrics.CHIHistoryStore
+
+	// Optional file history for the truck factor
+	fileHistory metrics.FileHistoryClient
+
+	// Optional contributor history for onboarding and retention
+	contributorCommits metrics.GitClient
+	contributorPRs     metrics.PullRequestListClient
+	contributorConfig  metrics.ContributorConfig
+
+	// Optional issue and pull request data for community health
+	communityClient     metrics.CommunityClient
+	communityBenchmarks metrics.CommunityBenchmarks
 }
 
 // NewEngine creates a new scorecard engine
@@ -27,13 +52,98 @@ func NewEngine(
 		doraCalculator: dora,
 		chiCalculator:  chi,
 		aiCalculator:   ai,
+		benchmarks:     metrics.DefaultDORABenchmarks(),
+	}
+}
+
+// SetBenchmarks replaces the State of DevOps bands used to classify DORA metrics.
+// Unset thresholds keep their defaults.
+func (e *Engine) SetBenchmarks(benchmarks metrics.DORABenchmarks) error {
+	benchmarks = benchmarks.WithDefaults()
+	if err := benchmarks.Validate(); err != nil {
+		return err
+	}
+	e.benchmarks = benchmarks
+	return nil
+}
+
+// SetCHIHistory records every CHI measurement taken by GenerateScorecard in store
+func (e *Engine) SetCHIHistory(store *metrics.CHIHistoryStore) {
+	e.chiHistory = store
+}
+
+// SetFileHistoryClient computes the bus factor as a truck factor from the authorship of the
+// files read by client. Without it the bus factor is reported as 0, unknown.
+func (e *Engine) SetFileHistoryClient(client metrics.FileHistoryClient) {
+	e.fileHistory = client
+}
+
+// SetContributorHistory measures contributor onboarding and retention from the commits and
+// pull requests read by the clients over config.HistoryDays. Either client may be nil.
+func (e *Engine) SetContributorHistory(commits metrics.GitClient, pullRequests metrics.PullRequestListClient, config metrics.ContributorConfig) {
+	e.contributorCommits = commits
+	e.contributorPRs = pullRequests
+	e.contributorConfig = config
+}
+
+// SetCommunityClient measures issue responsiveness and community health from the issues and
+// pull requests read by client, rating them against benchmarks
+func (e *Engine) SetCommunityClient(client metrics.CommunityClient, benchmarks metrics.CommunityBenchmarks) {
+	e.communityClient = client
+	e.communityBenchmarks = benchmarks
+}
+
+// CHIAnomalies looks for anomalies in the recorded CHI history of a repository
+func (e *Engine) CHIAnomalies(repo types.Repository, config metrics.AnomalyConfig) []metrics.Anomaly {
+	if e.chiHistory == nil {
+		return nil
+	}
+	return metrics.DetectCHIAnomalies(e.chiHistory.History(repositoryKey(repo)), config)
+}
+
+// RegisterHost routes repositories hosted on host to a dedicated DORA calculator,
+// so GitLab or self-managed instances can be analyzed alongside GitHub
+func (e *Engine) RegisterHost(host string, dora *metrics.DORACalculator) {
+	if e.hostCalculators == nil {
+		e.hostCalculators = make(map[string]*metrics.DORACalculator)
+	}
+	e.hostCalculators[strings.ToLower(host)] = dora
+}
+
+// doraCalculatorFor selects the DORA calculator for a repository by its clone URL host
+func (e *Engine) doraCalculatorFor(repo types.Repository) *metrics.DORACalculator {
+	if dora, ok := e.hostCalculators[repositoryHost(repo.CloneURL)]; ok {
+		return dora
+	}
+	return e.doraCalculator
+}
+
+// repositoryHost extracts the host from an HTTPS or SCP-style (git@host:path) clone URL
+func repositoryHost(cloneURL string) string {
+	if cloneURL == "" {
+		return ""
+	}
+	if !strings.Contains(cloneURL, "://") {
+		if at := strings.Index(cloneURL, "@"); at >= 0 {
+			cloneURL = cloneURL[at+1:]
+		}
+		if colon := strings.Index(cloneURL, ":"); colon >= 0 {
+			return strings.ToLower(cloneURL[:colon])
+		}
+		return ""
+	}
+
+	parsed, err := url.Parse(cloneURL)
+	if err != nil {
+		return ""
 	}
+	return strings.ToLower(parsed.Hostname())
 }
 
 // GenerateScorecard creates a comprehensive repository scorecard
 func (e *Engine) GenerateScorecard(ctx context.Context, repo types.Repository, user string, periodDays int) (*types.Scorecard, error) {
 	// Calculate DORA metrics
-	doraMetrics, err := e.doraCalculator.Calculate(ctx, repo, periodDays)
+	doraMetrics, err := e.doraCalculatorFor(repo).Calculate(ctx, repo, periodDays)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate DORA metrics: %w", err)
 	}
@@ -43,6 +153,9 @@ func (e *Engine) GenerateScorecard(ctx context.Context, repo types.Repository, u
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate CHI metrics: %w", err)
 	}
+	if e.chiHistory != nil {
+		e.chiHistory.Record(repositoryKey(repo), *chiMetrics)
+	}
 
 	// Calculate AI Impact metrics
 	aiMetrics, err := e.aiCalculator.Calculate(ctx, repo, user, periodDays)
@@ -50,9 +163,21 @@ func (e *Engine) GenerateScorecard(ctx context.Context, repo types.Repository, u
 		return nil, fmt.Errorf("failed to calculate AI metrics: %w", err)
 	}
 
-	// Calculate additional metrics
-	busFactor := e.calculateBusFactor(ctx, repo)
-	firstReviewP50 := e.calculateFirstReviewP50(ctx, repo, periodDays)
+	// Calculate additional metrics; sections without data are left out with a warning
+	var warnings []string
+	truckFactor, err := e.calculateTruckFactor(ctx, repo)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("truck factor unavailable: %v", err))
+	}
+	busFactor := 0
+	if truckFactor != nil {
+		busFactor = truckFactor.TruckFactor
+	}
+	contributors, contributorWarnings := e.calculateContributors(ctx, repo, periodDays)
+	warnings = append(warnings, contributorWarnings...)
+	community, communityWarnings := e.calculateCommunityHealth(ctx, repo, periodDays)
+	warnings = append(warnings, communityWarnings...)
+	firstReviewP50 := e.calculateFirstReviewP50(doraMetrics)
 
 	// Calculate confidence scores
 	confidence := e.calculateConfidence(doraMetrics, chiMetrics, aiMetrics)
@@ -64,8 +189,13 @@ func (e *Engine) GenerateScorecard(ctx context.Context, repo types.Repository, u
 		CHI:                 *chiMetrics,
 		AI:                  *aiMetrics,
 		BusFactor:           busFactor,
+		TruckFactor:         truckFactor,
+		Contributors:        contributors,
+		Community:           community,
 		FirstReviewP50Hours: firstReviewP50,
+		DORAPerformance:     e.benchmarks.Classify(*doraMetrics),
 		Confidence:          confidence,
+		Warnings:            warnings,
 		GeneratedAt:         time.Now(),
 	}
 
@@ -94,7 +224,7 @@ func (e *Engine) GenerateCodeHealthReport(ctx context.Context, scorecard *types.
 	drivers := e.identifyCHIDrivers(scorecard)
 	refactorPlan := e.generateRefactorPlan(scorecard, drivers)
 	guardrails := e.generateGuardrails(scorecard)
-	milestones := e.generateMilestones(refactorPlan)
+	milestones := e.generateMilestones(scorecard, refactorPlan)
 
 	return &types.CodeHealthReport{
 		CHINow:       scorecard.CHI.Score,
@@ -107,15 +237,26 @@ func (e *Engine) GenerateCodeHealthReport(ctx context.Context, scorecard *types.
 
 // GenerateDORAReport generates P3 DORA & Ops report
 func (e *Engine) GenerateDORAReport(ctx context.Context, scorecard *types.Scorecard) (*types.DORAReport, error) {
-	bottlenecks := e.identifyBottlenecks(scorecard)
+	bottlenecks := append(e.identifyBottlenecks(scorecard), e.identifyReviewQualityBottlenecks(scorecard)...)
+	bottlenecks = append(bottlenecks, e.identifyBatchSizeBottlenecks(scorecard)...)
+	bottlenecks = append(bottlenecks, e.identifyPipelineBottlenecks(scorecard)...)
 	playbook := e.generatePlaybook(scorecard, bottlenecks)
 	experiments := e.generateExperiments(scorecard)
 
 	return &types.DORAReport{
+		LeadTimeP50Hours:        scorecard.DORA.LeadTimeP50Hours,
+		LeadTimeP75Hours:        scorecard.DORA.LeadTimeP75Hours,
 		LeadTimeP95Hours:        scorecard.DORA.LeadTimeP95Hours,
+		MergeTimeP95Hours:       scorecard.DORA.MergeTimeP95Hours,
 		DeploymentFrequencyWeek: scorecard.DORA.DeploymentFrequencyWeek,
 		ChangeFailRatePercent:   scorecard.DORA.ChangeFailRatePercent,
 		MTTRHours:               scorecard.DORA.MTTRHours,
+		CycleTime:               scorecard.DORA.CycleTime,
+		ReviewQuality:           scorecard.DORA.ReviewQuality,
+		BatchSize:               scorecard.DORA.BatchSize,
+		Pipelines:               scorecard.DORA.Pipelines,
+		Flakiness:               scorecard.DORA.Flakiness,
+		Performance:             e.benchmarks.Classify(scorecard.DORA),
 		Bottlenecks:             bottlenecks,
 		Playbook:                playbook,
 		Experiments:             experiments,
@@ -127,26 +268,97 @@ func (e *Engine) GenerateCommunityReport(ctx context.Context, scorecard *types.S
 	roadmap := e.generateCommunityRoadmap(scorecard)
 	visibility := e.generateVisibilityItems(scorecard)
 
-	return &types.CommunityReport{
-		BusFactor:         scorecard.BusFactor,
-		OnboardingP50Days: int(scorecard.FirstReviewP50Hours / 24), // Convert hours to days
-		Roadmap:           roadmap,
-		Visibility:        visibility,
-	}, nil
+	report := &types.CommunityReport{
+		BusFactor:    scorecard.BusFactor,
+		TruckFactor:  scorecard.TruckFactor,
+		Contributors: scorecard.Contributors,
+		Health:       scorecard.Community,
+		Roadmap:      roadmap,
+		Visibility:   visibility,
+	}
+	if contributors := scorecard.Contributors; contributors != nil && contributors.OnboardedContributors > 0 {
+		report.OnboardingP50Days = int(math.Round(contributors.OnboardingP50Days))
+	}
+	if quality := scorecard.DORA.ReviewQuality; quality != nil {
+		report.ReviewerLoad = quality.ReviewerLoad
+		report.ReviewConcentration = quality.ReviewConcentration
+	}
+	return report, nil
+}
+
+// calculateTruckFactor computes the truck factor from file authorship. Returns nil without a
+// file history client, and nil with the error when the history cannot be read.
+func (e *Engine) calculateTruckFactor(ctx context.Context, repo types.Repository) (*types.TruckFactorReport, error) {
+	if e.fileHistory == nil {
+		return nil, nil
+	}
+	changes, err := e.fileHistory.GetFileHistory(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+	return metrics.CalculateTruckFactor(changes), nil
 }
 
-// calculateBusFactor estimates the bus factor (key person dependency)
-func (e *Engine) calculateBusFactor(ctx context.Context, repo types.Repository) int {
-	// Simplified bus factor calculation
-	// In production, analyze commit authorship distribution
-	return 2 // Default conservative estimate
+// calculateContributors measures onboarding and retention from the contributor history,
+// leaving out a history that can't be read with a warning. Returns nil without contributor
+// history clients.
+func (e *Engine) calculateContributors(ctx context.Context, repo types.Repository, periodDays int) (*types.ContributorMetrics, []string) {
+	if e.contributorCommits == nil && e.contributorPRs == nil {
+		return nil, nil
+	}
+	config := e.contributorConfig
+	if config.HistoryDays <= 0 {
+		config.HistoryDays = metrics.DefaultContributorConfig().HistoryDays
+	}
+	since := time.Now().AddDate(0, 0, -config.HistoryDays)
+
+	var warnings []string
+	var commits []metrics.Commit
+	if e.contributorCommits != nil {
+		var err error
+		if commits, err = e.contributorCommits.GetCommits(ctx, repo.Owner, repo.Name, since); err != nil {
+			warnings = append(warnings, fmt.Sprintf("contributor commits unavailable: %v", err))
+		}
+	}
+	var prs []metrics.PullRequest
+	if e.contributorPRs != nil {
+		var err error
+		if prs, err = e.contributorPRs.ListPullRequests(ctx, repo.Owner, repo.Name, since); err != nil {
+			warnings = append(warnings, fmt.Sprintf("contributor pull requests unavailable: %v", err))
+		}
+	}
+	return metrics.CalculateContributorMetrics(commits, prs, config, periodDays, time.Now()), warnings
+}
+
+// calculateCommunityHealth measures community KPIs for the period, reading two periods of
+// issues and pull requests for trends. Without issues it is left out with a warning; without
+// open issue counts it is measured without them. Returns nil without a community client.
+func (e *Engine) calculateCommunityHealth(ctx context.Context, repo types.Repository, periodDays int) (*types.CommunityHealth, []string) {
+	if e.communityClient == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	items, err := e.communityClient.GetCommunityItems(ctx, repo.Owner, repo.Name, now.AddDate(0, 0, -2*periodDays))
+	if err != nil {
+		return nil, []string{fmt.Sprintf("community health unavailable: %v", err)}
+	}
+	var warnings []string
+	open, err := e.communityClient.GetOpenIssueCounts(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		open = metrics.OpenIssueCounts{Incomplete: true}
+		warnings = append(warnings, fmt.Sprintf("open issue counts unavailable: %v", err))
+	}
+	return metrics.CalculateCommunityHealth(items, open, e.communityBenchmarks, periodDays, now), warnings
 }
 
-// calculateFirstReviewP50 calculates median first review time
-func (e *Engine) calculateFirstReviewP50(ctx context.Context, repo types.Repository, periodDays int) float64 {
-	// Simplified calculation
-	// In production, analyze PR review times from GitHub API
-	return 8.0 // 8 hours default
+// calculateFirstReviewP50 returns the median time from PR open to first review
+func (e *Engine) calculateFirstReviewP50(dora *types.DORAMetrics) float64 {
+	for _, stage := range dora.CycleTime {
+		if stage.Stage == metrics.StagePickup {
+			return stage.P50Hours
+		}
+	}
+	return 8.0 // 8 hours default when no review data is available
 }
 
 // calculateConfidence calculates confidence scores for metrics
@@ -173,19 +385,37 @@ func (e *Engine) calculateConfidence(dora *types.DORAMetrics, chi *types.CHIMetr
 	}
 }
 
-// generateExecutiveSummary creates executive summary
+// bandGrades maps DORA performance bands to executive grades
+var bandGrades = map[string]string{
+	types.BandElite:  "A",
+	types.BandHigh:   "B",
+	types.BandMedium: "C",
+	types.BandLow:    "D",
+}
+
+// generateExecutiveSummary creates executive summary. The grade is the worse of the
+// DORA delivery band and the code health grade.
 func (e *Engine) generateExecutiveSummary(scorecard *types.Scorecard) types.ExecutiveSummary {
-	grade := "C"
-	if scorecard.CHI.Score >= 80 && scorecard.DORA.LeadTimeP95Hours < 48 {
-		grade = "A"
-	} else if scorecard.CHI.Score >= 60 && scorecard.DORA.LeadTimeP95Hours < 72 {
-		grade = "B"
-	} else if scorecard.CHI.Score < 40 || scorecard.DORA.LeadTimeP95Hours > 120 {
-		grade = "D"
+	performance := e.benchmarks.Classify(scorecard.DORA)
+
+	chiGrade := "D"
+	switch {
+	case scorecard.CHI.Score >= 80:
+		chiGrade = "A"
+	case scorecard.CHI.Score >= 60:
+		chiGrade = "B"
+	case scorecard.CHI.Score >= 40:
+		chiGrade = "C"
+	}
+
+	grade := chiGrade
+	if deliveryGrade, ok := bandGrades[performance.Overall]; ok && deliveryGrade > grade {
+		grade = deliveryGrade
 	}
 
 	return types.ExecutiveSummary{
 		Grade:            grade,
+		DeliveryBand:     performance.Overall,
 		CHI:              scorecard.CHI.Score,
 		LeadTimeP95Hours: scorecard.DORA.LeadTimeP95Hours,
 		DeploysPerWeek:   scorecard.DORA.DeploymentFrequencyWeek,
@@ -219,7 +449,7 @@ func (e *Engine) generateTopFocus(scorecard *types.Scorecard) []types.FocusArea
 	}
 
 	// Focus 3: Bus Factor if too low
-	if scorecard.BusFactor <= 1 {
+	if scorecard.BusFactor == 1 {
 		focus = append(focus, types.FocusArea{
 			Title:      "Increase Bus Factor",
 			Why:        "Single point of failure in team knowledge",
@@ -275,6 +505,40 @@ func (e *Engine) generateQuickWins(scorecard *types.Scorecard, hotspots []string
 		})
 	}
 
+	wins = append(wins, e.generateFlakyTestWins(scorecard)...)
+
+	return wins
+}
+
+// maxFlakyTestWins caps how many flaky tests are listed as quick wins
+const maxFlakyTestWins = 3
+
+// generateFlakyTestWins lists the flakiest tests, or else the flakiest workflow, as quick wins
+func (e *Engine) generateFlakyTestWins(scorecard *types.Scorecard) []types.QuickWin {
+	flakiness := scorecard.DORA.Flakiness
+	if flakiness == nil {
+		return nil
+	}
+
+	var wins []types.QuickWin
+	for i, test := range flakiness.Tests {
+		if i == maxFlakyTestWins {
+			break
+		}
+		wins = append(wins, types.QuickWin{
+			Action:       fmt.Sprintf("Fix or quarantine flaky test %s.%s", test.Suite, test.Name),
+			Effort:       "S",
+			ExpectedGain: fmt.Sprintf("Flipped on %d of %d commits (%.0f%%)", test.FlakyCommits, test.Commits, test.FlakinessScore),
+		})
+	}
+	if len(wins) == 0 && len(flakiness.Workflows) > 0 && flakiness.Workflows[0].FlakyCommits > 0 {
+		workflow := flakiness.Workflows[0]
+		wins = append(wins, types.QuickWin{
+			Action:       fmt.Sprintf("Upload JUnit reports of workflow %s to pinpoint its flaky tests", workflow.Name),
+			Effort:       "S",
+			ExpectedGain: fmt.Sprintf("Needed reruns on %.0f%% of commits, %.1f CI hours wasted", workflow.FlakinessScore, workflow.WastedHours),
+		})
+	}
 	return wins
 }
 
@@ -282,7 +546,7 @@ func (e *Engine) generateQuickWins(scorecard *types.Scorecard, hotspots []string
 func (e *Engine) generateRisks(scorecard *types.Scorecard) []types.Risk {
 	var risks []types.Risk
 
-	if scorecard.BusFactor <= 1 {
+	if scorecard.BusFactor == 1 {
 		risks = append(risks, types.Risk{
 			Risk:       "Single point of failure in team knowledge",
 			Mitigation: "Document processes and cross-train team members",
@@ -398,17 +662,94 @@ func (e *Engine) generateGuardrails(scorecard *types.Scorecard) []string {
 	return guardrails
 }
 
-// generateMilestones creates refactoring milestones
-func (e *Engine) generateMilestones(plan []types.RefactorStep) []types.Milestone {
-	return []types.Milestone{
-		{InDays: 14, Goal: "Complete first refactoring step"},
-		{InDays: 30, Goal: "Achieve 10-point CHI improvement"},
+// generateMilestones creates refactoring milestones. With recorded CHI history,
+// each measurable target is judged against the metric's projected trend.
+func (e *Engine) generateMilestones(scorecard *types.Scorecard, plan []types.RefactorStep) []types.Milestone {
+	first := types.Milestone{InDays: 14, Goal: "Complete first refactoring step"}
+	if len(plan) > 0 {
+		switch plan[0].Theme {
+		case "duplication":
+			first.Metric, first.Target = "duplication_pct", 15
+		case "complexity":
+			first.Metric, first.Target = "cyclomatic_avg", 8
+		}
+	}
+
+	milestones := []types.Milestone{
+		first,
+		{InDays: 30, Goal: "Achieve 10-point CHI improvement", Metric: "chi_score", Target: float64(min(scorecard.CHI.Score+10, 100))},
 		{InDays: 60, Goal: "Implement all quality guardrails"},
 	}
+
+	if e.chiHistory == nil {
+		return milestones
+	}
+	forecasts := make(map[string]metrics.Forecast)
+	config := metrics.ForecastConfig{HorizonDays: 60, Level: metrics.DefaultForecastConfig().Level}
+	for _, forecast := range metrics.ForecastCHI(e.chiHistory.History(repositoryKey(scorecard.Repository)), config) {
+		forecasts[forecast.Metric] = forecast
+	}
+
+	from := scorecard.GeneratedAt
+	if from.IsZero() {
+		from = time.Now()
+	}
+	for i, milestone := range milestones {
+		forecast, ok := forecasts[milestone.Metric]
+		if !ok {
+			continue
+		}
+		assessment := forecast.AssessTarget(milestone.Target, from.AddDate(0, 0, milestone.InDays), milestone.Metric == "chi_score")
+		milestones[i].Forecast = &assessment
+	}
+	return milestones
+}
+
+// cycleTimeStageNames describes each PR lifecycle stage for bottleneck evidence
+var cycleTimeStageNames = map[string]string{
+	metrics.StageCoding: "Coding (first commit → PR opened)",
+	metrics.StagePickup: "Pickup (PR opened → first review)",
+	metrics.StageReview: "Review (first review → approval)",
+	metrics.StageMerge:  "Merge (approval → merged)",
+	metrics.StageDeploy: "Deploy (merged → production)",
 }
 
-// identifyBottlenecks identifies DORA bottlenecks
+// identifyBottlenecks names the PR lifecycle stages where cycle time is spent.
+// The slowest stage is always reported; other stages only when they take at
+// least 30% of the median cycle time.
 func (e *Engine) identifyBottlenecks(scorecard *types.Scorecard) []types.Bottleneck {
+	stages := append([]types.CycleTimeStage(nil), scorecard.DORA.CycleTime...)
+	total := 0.0
+	for _, stage := range stages {
+		total += stage.P50Hours
+	}
+	if total == 0 {
+		return e.identifyThresholdBottlenecks(scorecard)
+	}
+
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].P50Hours > stages[j].P50Hours
+	})
+
+	var bottlenecks []types.Bottleneck
+	for i, stage := range stages {
+		share := stage.P50Hours / total
+		if i > 0 && share < 0.3 {
+			break
+		}
+		bottlenecks = append(bottlenecks, types.Bottleneck{
+			Area: stage.Stage,
+			Evidence: fmt.Sprintf("%s takes %.1fh at P50 and %.1fh at P95, %.0f%% of median PR cycle time across %d PRs",
+				cycleTimeStageNames[stage.Stage], stage.P50Hours, stage.P95Hours, share*100, stage.PullRequests),
+		})
+	}
+
+	return bottlenecks
+}
+
+// identifyThresholdBottlenecks flags bottlenecks from summary thresholds when no
+// PR cycle time data is available
+func (e *Engine) identifyThresholdBottlenecks(scorecard *types.Scorecard) []types.Bottleneck {
 	var bottlenecks []types.Bottleneck
 
 	if scorecard.FirstReviewP50Hours > 12 {
@@ -418,7 +759,8 @@ func (e *Engine) identifyBottlenecks(scorecard *types.Scorecard) []types.Bottlen
 		})
 	}
 
-	if scorecard.DORA.LeadTimeP95Hours > 72 {
+	// Measured PR sizes replace this heuristic, see identifyBatchSizeBottlenecks
+	if scorecard.DORA.LeadTimeP95Hours > 72 && scorecard.DORA.BatchSize == nil {
 		bottlenecks = append(bottlenecks, types.Bottleneck{
 			Area:     "batch_size",
 			Evidence: "Large PRs causing long lead times",
@@ -428,13 +770,96 @@ func (e *Engine) identifyBottlenecks(scorecard *types.Scorecard) []types.Bottlen
 	return bottlenecks
 }
 
+// Review quality thresholds: share of large-diff approvals given without comments, and
+// share of all reviews done by a single reviewer
+const (
+	rubberStampRateThreshold  = 20.0
+	topReviewerShareThreshold = 50.0
+)
+
+// identifyReviewQualityBottlenecks flags reviews that approve large diffs without comments
+func (e *Engine) identifyReviewQualityBottlenecks(scorecard *types.Scorecard) []types.Bottleneck {
+	quality := scorecard.DORA.ReviewQuality
+	if quality == nil || quality.RubberStampRatePercent < rubberStampRateThreshold {
+		return nil
+	}
+	return []types.Bottleneck{{
+		Area: "review",
+		Evidence: fmt.Sprintf("%.0f%% of approvals on large PRs (%d of %d) were given without any comment",
+			quality.RubberStampRatePercent, len(quality.RubberStamps), quality.LargeDiffApprovals),
+	}}
+}
+
+// batchSizeCorrelationThreshold is the rank correlation of PR size with lead time from
+// which oversized PRs count as a bottleneck
+const batchSizeCorrelationThreshold = 0.3
+
+// identifyBatchSizeBottlenecks flags oversized PRs when larger PRs take measurably longer to land
+func (e *Engine) identifyBatchSizeBottlenecks(scorecard *types.Scorecard) []types.Bottleneck {
+	batch := scorecard.DORA.BatchSize
+	if batch == nil || len(batch.Oversized) == 0 || batch.LeadTimeCorrelation < batchSizeCorrelationThreshold {
+		return nil
+	}
+	return []types.Bottleneck{{
+		Area: "batch_size",
+		Evidence: fmt.Sprintf("%d of %d merged PRs exceed %d changed lines; size correlates with lead time (ρ=%.2f) and review time (ρ=%.2f)",
+			len(batch.Oversized), batch.PullRequests, batch.RecommendedMaxLines, batch.LeadTimeCorrelation, batch.ReviewTimeCorrelation),
+	}}
+}
+
+// CI thresholds: median duration of the slowest workflow and success rate of the least reliable
+const (
+	slowWorkflowMinutes          = 15.0
+	unreliableWorkflowSuccessPct = 80.0
+)
+
+// identifyPipelineBottlenecks flags slow or unreliable CI workflows
+func (e *Engine) identifyPipelineBottlenecks(scorecard *types.Scorecard) []types.Bottleneck {
+	pipelines := scorecard.DORA.Pipelines
+	if pipelines == nil {
+		return nil
+	}
+
+	byName := make(map[string]types.WorkflowStats, len(pipelines.Workflows))
+	for _, workflow := range pipelines.Workflows {
+		byName[workflow.Name] = workflow
+	}
+
+	var bottlenecks []types.Bottleneck
+	if len(pipelines.SlowestWorkflows) > 0 {
+		slowest := byName[pipelines.SlowestWorkflows[0]]
+		if slowest.DurationP50Minutes >= slowWorkflowMinutes {
+			bottlenecks = append(bottlenecks, types.Bottleneck{
+				Area: "pipeline",
+				Evidence: fmt.Sprintf("Workflow %s takes %.0f min at P50 and %.0f min at P95 plus %.0f min queued; about %.0f developer hours lost waiting on CI",
+					slowest.Name, slowest.DurationP50Minutes, slowest.DurationP95Minutes, slowest.QueueP50Minutes, pipelines.DeveloperWaitHours),
+			})
+		}
+	}
+	if len(pipelines.LeastReliableWorkflows) > 0 {
+		unreliable := byName[pipelines.LeastReliableWorkflows[0]]
+		if unreliable.SuccessRatePercent < unreliableWorkflowSuccessPct {
+			bottlenecks = append(bottlenecks, types.Bottleneck{
+				Area: "pipeline",
+				Evidence: fmt.Sprintf("Workflow %s succeeds in %.0f%% of %d runs; %.1f CI hours spent on failed runs",
+					unreliable.Name, unreliable.SuccessRatePercent, unreliable.Runs, pipelines.FailedRunHours),
+			})
+		}
+	}
+	return bottlenecks
+}
+
 // generatePlaybook creates operational playbook
 func (e *Engine) generatePlaybook(scorecard *types.Scorecard, bottlenecks []types.Bottleneck) []types.PlaybookItem {
 	var playbook []types.PlaybookItem
 
+	sizePolicy := "Max 300 LOC or 5 files per PR"
+	if batch := scorecard.DORA.BatchSize; batch != nil && batch.RecommendedMaxLines > 0 {
+		sizePolicy = fmt.Sprintf("Max %d LOC or %d files per PR", batch.RecommendedMaxLines, batch.RecommendedMaxFiles)
+	}
 	playbook = append(playbook, types.PlaybookItem{
 		Name:           "Smaller PRs",
-		Policy:         "Max 300 LOC or 5 files per PR",
+		Policy:         sizePolicy,
 		ExpectedEffect: "Lead time P95 -20%",
 	})
 
@@ -444,6 +869,25 @@ func (e *Engine) generatePlaybook(scorecard *types.Scorecard, bottlenecks []type
 		ExpectedEffect: "Lead time -15%",
 	})
 
+	if quality := scorecard.DORA.ReviewQuality; quality != nil && quality.RubberStampRatePercent >= rubberStampRateThreshold {
+		playbook = append(playbook, types.PlaybookItem{
+			Name:           "Review depth",
+			Policy:         "Large PRs need inline comments or a second approval",
+			ExpectedEffect: "Rubber-stamp rate < 10%, CFR -10%",
+		})
+	}
+
+	for _, bottleneck := range bottlenecks {
+		if bottleneck.Area == "pipeline" {
+			playbook = append(playbook, types.PlaybookItem{
+				Name:           "Fast, reliable CI",
+				Policy:         "P50 ≤ 10 min per workflow, quarantine failing jobs, cache dependencies",
+				ExpectedEffect: "Developer wait hours -30%",
+			})
+			break
+		}
+	}
+
 	return playbook
 }
 
@@ -463,29 +907,130 @@ func (e *Engine) generateExperiments(scorecard *types.Scorecard) []types.Experim
 	}
 }
 
+// Contributor health thresholds: share of new contributors still active after 90 days, and
+// share of active contributors who are new
+const (
+	minRetention90Percent    = 50.0
+	minNewContributorPercent = 20.0
+)
+
 // generateCommunityRoadmap creates community growth roadmap
 func (e *Engine) generateCommunityRoadmap(scorecard *types.Scorecard) []types.RoadmapItem {
 	var roadmap []types.RoadmapItem
 
-	roadmap = append(roadmap, types.RoadmapItem{
-		Item:          "CONTRIBUTING guide + PR templates",
-		Why:           "Reduce onboarding friction",
-		SuccessMetric: "First review P50 ≤ 8h",
-	})
+	contributors := scorecard.Contributors
+	if contributors != nil && contributors.OnboardedContributors > 0 {
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item: "CONTRIBUTING guide + PR templates",
+			Why: fmt.Sprintf("New contributors take %.0f days (P50) to reach %d merged PRs",
+				contributors.OnboardingP50Days, contributors.OnboardingMergedPRs),
+			SuccessMetric: fmt.Sprintf("Onboarding P50 ≤ %.0f days", math.Max(contributors.OnboardingP50Days/2, 1)),
+		})
+	} else {
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item:          "CONTRIBUTING guide + PR templates",
+			Why:           "Reduce onboarding friction",
+			SuccessMetric: "First review P50 ≤ 8h",
+		})
+	}
+
+	if retention, ok := retentionAt(contributors, 90); ok && retention.Percent < minRetention90Percent {
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item: "Mentor new contributors through their first PRs",
+			Why: fmt.Sprintf("Only %.0f%% of new contributors are still active after 90 days",
+				retention.Percent),
+			SuccessMetric: fmt.Sprintf("90-day retention ≥ %.0f%%", minRetention90Percent),
+		})
+	}
 
-	if scorecard.BusFactor <= 1 {
+	health := scorecard.Community
+	for _, response := range []struct{ metric, kind string }{
+		{"issue_first_response_p50_hours", "issues"},
+		{"pr_first_response_p50_hours", "pull requests"},
+	} {
+		if kpi, ok := missedCommunityKPI(health, response.metric); ok {
+			roadmap = append(roadmap, types.RoadmapItem{
+				Item: "Triage rotation for outside contributions",
+				Why: fmt.Sprintf("Outside contributors wait %.0fh (P50) for a first response on %s",
+					kpi.Value, response.kind),
+				SuccessMetric: fmt.Sprintf("First response P50 ≤ %.0fh", kpi.Target),
+			})
+			break
+		}
+	}
+	if kpi, ok := missedCommunityKPI(health, "issue_close_rate_pct"); ok {
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item:          "Regular issue grooming",
+			Why:           fmt.Sprintf("Only %.0f%% of the issues opened in the last %d days were closed", kpi.Value, health.PeriodDays),
+			SuccessMetric: fmt.Sprintf("Issue close rate ≥ %.0f%%", kpi.Target),
+		})
+	}
+	if kpi, ok := missedCommunityKPI(health, "labeled_issue_pct"); ok {
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item: "Issue templates that apply labels",
+			Why: fmt.Sprintf("%.0f%% of new issues are labeled and %d open issues have no label",
+				kpi.Value, health.UnlabeledOpenIssues),
+			SuccessMetric: fmt.Sprintf("Labeled issues ≥ %.0f%%", kpi.Target),
+		})
+	}
+
+	lowNewcomers := contributors != nil && contributors.ActiveContributors > 0 &&
+		contributors.NewContributorPercent < minNewContributorPercent
+	_, fewGoodFirstIssues := missedCommunityKPI(health, "good_first_issue_pct")
+	if scorecard.BusFactor == 1 || lowNewcomers || fewGoodFirstIssues {
+		why := "Attract new contributors"
+		switch {
+		case fewGoodFirstIssues:
+			why = fmt.Sprintf("Only %d of %d open issues are good first issues",
+				health.GoodFirstIssues, health.OpenIssues)
+		case lowNewcomers:
+			why = fmt.Sprintf("Only %d of %d active contributors are new",
+				contributors.NewContributors, contributors.ActiveContributors)
+		}
 		roadmap = append(roadmap, types.RoadmapItem{
 			Item:          "Create good-first-issues (5 issues)",
-			Why:           "Attract new contributors",
+			Why:           why,
 			SuccessMetric: "Bus factor ≥ 2",
 		})
 	}
 
+	if truck := scorecard.TruckFactor; truck != nil && len(truck.KeyAuthors) > 0 {
+		authors := make([]string, 0, len(truck.KeyAuthors))
+		for _, owner := range truck.KeyAuthors {
+			if owner.Name != "" {
+				authors = append(authors, owner.Name)
+			} else {
+				authors = append(authors, owner.Author)
+			}
+		}
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item: fmt.Sprintf("Knowledge transfer from %s", strings.Join(authors, ", ")),
+			Why: fmt.Sprintf("Their departure leaves %d files (%.0f%%) without an owner",
+				truck.OrphanedFiles, truck.OrphanedPercent),
+			SuccessMetric: fmt.Sprintf("Truck factor ≥ %d", truck.TruckFactor+1),
+		})
+	}
+
+	if quality := scorecard.DORA.ReviewQuality; quality != nil && len(quality.ReviewerLoad) > 0 &&
+		quality.TopReviewerSharePercent >= topReviewerShareThreshold {
+		roadmap = append(roadmap, types.RoadmapItem{
+			Item: "Reviewer rotation and CODEOWNERS",
+			Why: fmt.Sprintf("%s does %.0f%% of all reviews",
+				quality.ReviewerLoad[0].Reviewer, quality.TopReviewerSharePercent),
+			SuccessMetric: "Top reviewer share < 40%",
+		})
+	}
+
 	return roadmap
 }
 
 // generateVisibilityItems creates visibility improvement items
 func (e *Engine) generateVisibilityItems(scorecard *types.Scorecard) []types.VisibilityItem {
+	onboardingKPI := "Contributor onboarding time"
+	if contributors := scorecard.Contributors; contributors != nil && contributors.OnboardedContributors > 0 {
+		onboardingKPI = fmt.Sprintf("Contributor onboarding time (P50 %.0f days)", contributors.OnboardingP50Days)
+	}
+
 	return []types.VisibilityItem{
 		{
 			Asset:  "README with clear value proposition",
@@ -494,8 +1039,42 @@ func (e *Engine) generateVisibilityItems(scorecard *types.Scorecard) []types.Vis
 		},
 		{
 			Asset:  "Documentation site",
-			KPI:    "Contributor onboarding time",
+			KPI:    onboardingKPI,
 			Effort: "M",
 		},
 	}
 }
+
+// missedCommunityKPI returns a community KPI that misses its benchmark target
+func missedCommunityKPI(health *types.CommunityHealth, metric string) (types.CommunityKPI, bool) {
+	if health == nil {
+		return types.CommunityKPI{}, false
+	}
+	for _, kpi := range health.KPIs {
+		if kpi.Metric == metric && !kpi.MeetsTarget {
+			return kpi, true
+		}
+	}
+	return types.CommunityKPI{}, false
+}
+
+// retentionAt returns the overall contributor retention after days, if measured
+func retentionAt(contributors *types.ContributorMetrics, days int) (types.RetentionRate, bool) {
+	if contributors == nil {
+		return types.RetentionRate{}, false
+	}
+	for _, rate := range contributors.Retention {
+		if rate.Days == days && rate.Eligible > 0 {
+			return rate, true
+		}
+	}
+	return types.RetentionRate{}, false
+}
+
+// repositoryKey returns "owner/name" for a repository
+func repositoryKey(repo types.Repository) string {
+	if repo.FullName != "" {
+		return repo.FullName
+	}
+	return repo.Owner + "/" + repo.Name
+}
@@ -0,0 +1,181 @@
+package scorecard
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+var testRepo = types.Repository{Owner: "acme", Name: "api", FullName: "acme/api"}
+
+// emptyGitHub has no pull requests, deployments or workflow runs
+type emptyGitHub struct{}
+
+func (emptyGitHub) GetPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	return nil, nil
+}
+
+func (emptyGitHub) GetDeployments(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Deployment, error) {
+	return nil, nil
+}
+
+func (emptyGitHub) GetWorkflowRuns(ctx context.Context, owner, repo string, since time.Time) ([]metrics.WorkflowRun, error) {
+	return nil, nil
+}
+
+// fakeActivity serves coding time, AI assistance and commits
+type fakeActivity struct {
+	commits []metrics.Commit
+}
+
+func (f fakeActivity) GetCodingTime(ctx context.Context, user, repo string, since time.Time) (*metrics.CodingTime, error) {
+	return &metrics.CodingTime{CodingHours: 10}, nil
+}
+
+func (f fakeActivity) GetAIAssistData(ctx context.Context, user, repo string, since time.Time) (*metrics.AIAssistData, error) {
+	return &metrics.AIAssistData{}, nil
+}
+
+func (f fakeActivity) GetCommits(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Commit, error) {
+	return f.commits, nil
+}
+
+// fakeFileHistory serves file changes, or fails with err
+type fakeFileHistory struct {
+	changes []metrics.FileChange
+	err     error
+}
+
+func (f fakeFileHistory) GetFileHistory(ctx context.Context, owner, repo string) ([]metrics.FileChange, error) {
+	return f.changes, f.err
+}
+
+// newTestEngine builds an engine over an empty GitHub repository and a one-file clone
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	activity := fakeActivity{}
+	return NewEngine(
+		metrics.NewDORACalculator(emptyGitHub{}, nil),
+		metrics.NewCHICalculator(dir),
+		metrics.NewAIMetricsCalculator(activity, activity, activity),
+	)
+}
+
+func TestGenerateScorecardTruckFactor(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.SetFileHistoryClient(fakeFileHistory{changes: []metrics.FileChange{
+		{File: "a.go", Author: "alice@acme.io", Name: "Alice", Created: true},
+		{File: "b.go", Author: "alice@acme.io", Name: "Alice", Created: true},
+		{File: "c.go", Author: "bob@acme.io", Name: "Bob", Created: true},
+	}})
+
+	scorecard, err := engine.GenerateScorecard(context.Background(), testRepo, "", 30)
+	if err != nil {
+		t.Fatalf("GenerateScorecard failed: %v", err)
+	}
+	if scorecard.BusFactor != 1 || scorecard.TruckFactor == nil || scorecard.TruckFactor.KeyAuthors[0].Name != "Alice" {
+		t.Errorf("Expected a bus factor of 1 with alice as key author, got %d, %+v", scorecard.BusFactor, scorecard.TruckFactor)
+	}
+	if len(scorecard.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", scorecard.Warnings)
+	}
+
+	report, _ := engine.GenerateCommunityReport(context.Background(), scorecard)
+	var transfer string
+	for _, item := range report.Roadmap {
+		if strings.HasPrefix(item.Item, "Knowledge transfer") {
+			transfer = item.Item
+		}
+	}
+	if transfer != "Knowledge transfer from Alice" {
+		t.Errorf("Expected a knowledge transfer item naming Alice, got %q", transfer)
+	}
+}
+
+func TestGenerateScorecardWithoutFileHistory(t *testing.T) {
+	engine := newTestEngine(t)
+	engine.SetFileHistoryClient(fakeFileHistory{err: errors.New("not a git repository")})
+
+	scorecard, err := engine.GenerateScorecard(context.Background(), testRepo, "", 30)
+	if err != nil {
+		t.Fatalf("Expected the scorecard without the truck factor, got %v", err)
+	}
+	if scorecard.BusFactor != 0 || scorecard.TruckFactor != nil {
+		t.Errorf("Expected an unknown bus factor, got %d, %+v", scorecard.BusFactor, scorecard.TruckFactor)
+	}
+	if len(scorecard.Warnings) != 1 || !strings.Contains(scorecard.Warnings[0], "not a git repository") {
+		t.Errorf("Expected a warning about the truck factor, got %v", scorecard.Warnings)
+	}
+}
+
+// fakePullRequestList serves pull requests, or fails with err
+type fakePullRequestList struct {
+	prs []metrics.PullRequest
+	err error
+}
+
+func (f fakePullRequestList) ListPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	return f.prs, f.err
+}
+
+func TestGenerateScorecardWithoutContributorPullRequests(t *testing.T) {
+	engine := newTestEngine(t)
+	commits := fakeActivity{commits: []metrics.Commit{
+		{SHA: "a", Author: "Alice", Email: "alice@acme.io", Date: time.Now().AddDate(0, 0, -1)},
+	}}
+	engine.SetContributorHistory(commits, fakePullRequestList{err: errors.New("rate limited")}, metrics.ContributorConfig{})
+
+	scorecard, err := engine.GenerateScorecard(context.Background(), testRepo, "", 30)
+	if err != nil {
+		t.Fatalf("Expected the scorecard without pull requests, got %v", err)
+	}
+	if scorecard.Contributors == nil || scorecard.Contributors.Contributors != 1 {
+		t.Errorf("Expected contributors from commits alone, got %+v", scorecard.Contributors)
+	}
+	if len(scorecard.Warnings) != 1 || !strings.Contains(scorecard.Warnings[0], "rate limited") {
+		t.Errorf("Expected a warning about the pull requests, got %v", scorecard.Warnings)
+	}
+}
+
+// fakeCommunity serves community items, and fails to count open issues
+type fakeCommunity struct {
+	items metrics.CommunityItems
+}
+
+func (f fakeCommunity) GetCommunityItems(ctx context.Context, owner, repo string, since time.Time) (metrics.CommunityItems, error) {
+	return f.items, nil
+}
+
+func (f fakeCommunity) GetOpenIssueCounts(ctx context.Context, owner, repo string) (metrics.OpenIssueCounts, error) {
+	return metrics.OpenIssueCounts{}, errors.New("search unavailable")
+}
+
+func TestGenerateScorecardWithoutOpenIssueCounts(t *testing.T) {
+	engine := newTestEngine(t)
+	items := metrics.CommunityItems{Items: []metrics.CommunityItem{
+		{Number: 1, Author: "ana", AuthorAssociation: "NONE", CreatedAt: time.Now().AddDate(0, 0, -1)},
+	}}
+	engine.SetCommunityClient(fakeCommunity{items: items}, metrics.CommunityBenchmarks{})
+
+	scorecard, err := engine.GenerateScorecard(context.Background(), testRepo, "", 30)
+	if err != nil {
+		t.Fatalf("Expected the scorecard without open issue counts, got %v", err)
+	}
+	if community := scorecard.Community; community == nil || community.IssuesOpened != 1 || !community.Incomplete {
+		t.Errorf("Expected incomplete community health from the issues, got %+v", community)
+	}
+	if len(scorecard.Warnings) != 1 || !strings.Contains(scorecard.Warnings[0], "search unavailable") {
+		t.Errorf("Expected a warning about the open issue counts, got %v", scorecard.Warnings)
+	}
+}
@@ -0,0 +1,88 @@
+// Package bitbucket provides a Bitbucket Cloud and Server REST client with cache, retry, and rate limiting.
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kubex-ecosystem/analyzer/internal/services/restclient"
+	"golang.org/x/time/rate"
+)
+
+// Client provides a Bitbucket Cloud (2.0) and Server/Data Center (1.0) REST API client
+type Client struct {
+	*restclient.Client
+	config *Config
+}
+
+// NewClient creates a new Bitbucket client
+func NewClient(config *Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var rateLimit rate.Limit
+	if config.EnableRateLimit {
+		// Bitbucket Cloud allows 1000 API requests per hour for most resources,
+		// so spread requests out rather than bursting through the hourly budget
+		rateLimit = 1
+	}
+
+	return &Client{
+		Client: restclient.New(restclient.Options{
+			Name:           "Bitbucket",
+			BaseURL:        config.BaseURL,
+			UserAgent:      config.UserAgent,
+			Timeout:        config.Timeout,
+			MaxRetries:     config.MaxRetries,
+			RetryBackoff:   config.GetRetryBackoff(),
+			CacheTTL:       config.GetCacheTTL(),
+			RateLimit:      rateLimit,
+			RateLimitBurst: config.RateLimitBurst,
+			Authorize: func(req *http.Request) {
+				if config.Token != "" {
+					req.Header.Set("Authorization", "Bearer "+config.Token)
+				} else {
+					req.SetBasicAuth(config.Username, config.AppPassword)
+				}
+			},
+			ErrorMessage: errorMessage,
+		}),
+		config: config,
+	}, nil
+}
+
+// relativePath converts an absolute pagination link returned by the API into a
+// request path, refusing links to other hosts so credentials are never leaked
+func (c *Client) relativePath(link string) (string, error) {
+	base := c.BaseURL()
+	if !strings.HasPrefix(link, base+"/") {
+		return "", fmt.Errorf("unexpected pagination link: %s", link)
+	}
+	return strings.TrimPrefix(link, base), nil
+}
+
+// errorMessage extracts the message of a Bitbucket error response. Cloud returns
+// {"error": {"message": ...}}, Server returns {"errors": [{"message": ...}]}.
+func errorMessage(body []byte) string {
+	var apiError struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &apiError); err != nil {
+		return ""
+	}
+	if apiError.Error.Message != "" {
+		return apiError.Error.Message
+	}
+	if len(apiError.Errors) > 0 {
+		return apiError.Errors[0].Message
+	}
+	return ""
+}
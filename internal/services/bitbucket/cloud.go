@@ -0,0 +1,520 @@
+// Package bitbucket - Bitbucket Cloud (API 2.0) data access
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// getCloudPullRequests lists pull requests updated since the given time
+func (s *Service) getCloudPullRequests(ctx context.Context, workspace, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	query := url.QueryEscape(fmt.Sprintf("updated_on >= %s", since.UTC().Format("2006-01-02T15:04:05-07:00")))
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/pullrequests?state=OPEN&state=MERGED&state=DECLINED&state=SUPERSEDED&sort=-updated_on&pagelen=50&q=%s",
+		workspace, repo, query)
+
+	var allPRs []metrics.PullRequest
+	err := s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var cloudPRs []CloudPullRequest
+		if err := json.Unmarshal(values, &cloudPRs); err != nil {
+			return false, fmt.Errorf("failed to parse pull requests: %w", err)
+		}
+
+		for _, cpr := range cloudPRs {
+			pr := metrics.PullRequest{
+				Number:     cpr.ID,
+				Title:      cpr.Title,
+				State:      cloudPRState(cpr.State),
+				CreatedAt:  cpr.CreatedOn,
+				UpdatedAt:  cpr.UpdatedOn,
+				HeadBranch: cpr.Source.Branch.Name,
+			}
+			if cpr.State == "MERGED" && cpr.MergeCommit != nil {
+				pr.MergeCommitSHA = cpr.MergeCommit.Hash
+			}
+
+			// Review, approval, merge and close times only appear in the activity log
+			activity, err := s.getCloudActivity(ctx, workspace, repo, cpr.ID)
+			if err == nil {
+				applyCloudActivity(&pr, activity, cpr.Author.UUID)
+			}
+
+			commits, err := s.getCloudPullRequestCommits(ctx, workspace, repo, cpr.ID)
+			if err == nil {
+				pr.Commits = len(commits)
+				for _, commit := range commits {
+					pr.FirstCommitAt = earliest(pr.FirstCommitAt, commit.Date)
+				}
+			}
+
+			allPRs = append(allPRs, pr)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull requests: %w", err)
+	}
+
+	return allPRs, nil
+}
+
+// getCloudActivity lists the activity log of a pull request
+func (s *Service) getCloudActivity(ctx context.Context, workspace, repo string, id int) ([]CloudActivity, error) {
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/pullrequests/%d/activity?pagelen=50", workspace, repo, id)
+
+	var activity []CloudActivity
+	err := s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []CloudActivity
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, err
+		}
+		activity = append(activity, page...)
+		return true, nil
+	})
+	return activity, err
+}
+
+// applyCloudActivity derives review, approval, merge and close times from the activity log.
+// Comments, change requests and approvals by anyone but the author count as reviews.
+func applyCloudActivity(pr *metrics.PullRequest, activity []CloudActivity, authorUUID string) {
+	for _, entry := range activity {
+		switch {
+		case entry.Approval != nil:
+			if entry.Approval.User.UUID == authorUUID {
+				continue
+			}
+			pr.ApprovedAt = earliest(pr.ApprovedAt, entry.Approval.Date)
+			pr.FirstReviewAt = earliest(pr.FirstReviewAt, entry.Approval.Date)
+		case entry.ChangesRequested != nil:
+			if entry.ChangesRequested.User.UUID == authorUUID {
+				continue
+			}
+			pr.FirstReviewAt = earliest(pr.FirstReviewAt, entry.ChangesRequested.Date)
+		case entry.Comment != nil:
+			if entry.Comment.User.UUID == authorUUID {
+				continue
+			}
+			pr.FirstReviewAt = earliest(pr.FirstReviewAt, entry.Comment.CreatedOn)
+		case entry.Update != nil:
+			switch entry.Update.State {
+			case "MERGED":
+				pr.MergedAt = earliest(pr.MergedAt, entry.Update.Date)
+				pr.ClosedAt = earliest(pr.ClosedAt, entry.Update.Date)
+			case "DECLINED", "SUPERSEDED":
+				pr.ClosedAt = earliest(pr.ClosedAt, entry.Update.Date)
+			}
+		}
+	}
+
+	// Older activity logs may lack the final update; fall back to the last update time
+	if pr.State == "merged" && pr.MergedAt == nil {
+		updated := pr.UpdatedAt
+		pr.MergedAt = &updated
+		pr.ClosedAt = &updated
+	}
+	if pr.State == "closed" && pr.ClosedAt == nil {
+		updated := pr.UpdatedAt
+		pr.ClosedAt = &updated
+	}
+}
+
+// getCloudPullRequestCommits lists the commits of a pull request
+func (s *Service) getCloudPullRequestCommits(ctx context.Context, workspace, repo string, id int) ([]CloudCommit, error) {
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/pullrequests/%d/commits?pagelen=100", workspace, repo, id)
+
+	var commits []CloudCommit
+	err := s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []CloudCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, err
+		}
+		commits = append(commits, page...)
+		return true, nil
+	})
+	return commits, err
+}
+
+// getCloudDeployments lists deployments started since the given time
+func (s *Service) getCloudDeployments(ctx context.Context, workspace, repo string, since time.Time) ([]metrics.Deployment, error) {
+	environments, err := s.GetEnvironments(ctx, workspace, repo)
+	if err != nil {
+		return nil, err
+	}
+	environmentNames := make(map[string]string)
+	for _, environment := range environments {
+		environmentNames[environment.UUID] = environment.Name
+	}
+
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/deployments/?pagelen=100", workspace, repo)
+
+	var deployments []metrics.Deployment
+	err = s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var cloudDeployments []CloudDeployment
+		if err := json.Unmarshal(values, &cloudDeployments); err != nil {
+			return false, fmt.Errorf("failed to parse deployments: %w", err)
+		}
+
+		for _, cd := range cloudDeployments {
+			createdAt := cd.createdAt()
+			if createdAt.IsZero() || createdAt.Before(since) {
+				continue
+			}
+
+			updatedAt := createdAt
+			if cd.State.CompletedOn != nil {
+				updatedAt = *cd.State.CompletedOn
+			}
+
+			deployments = append(deployments, metrics.Deployment{
+				ID:          stableID(cd.UUID),
+				Environment: environmentNames[cd.Environment.UUID],
+				State:       cloudDeploymentState(cd.State.Name, cd.State.Status.Name),
+				CreatedAt:   createdAt,
+				UpdatedAt:   updatedAt,
+				SHA:         cd.sha(),
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployments: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// GetEnvironments lists the deployment environments of a Bitbucket Cloud repository
+func (s *Service) GetEnvironments(ctx context.Context, workspace, repo string) ([]CloudEnvironment, error) {
+	if s.server {
+		return nil, fmt.Errorf("deployment environments are only available on Bitbucket Cloud")
+	}
+
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/environments/?pagelen=100", workspace, repo)
+
+	var environments []CloudEnvironment
+	err := s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []CloudEnvironment
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, fmt.Errorf("failed to parse environments: %w", err)
+		}
+		environments = append(environments, page...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environments: %w", err)
+	}
+
+	return environments, nil
+}
+
+// getCloudPipelines lists Pipelines runs created since the given time
+func (s *Service) getCloudPipelines(ctx context.Context, workspace, repo string, since time.Time) ([]metrics.WorkflowRun, error) {
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/pipelines/?sort=-created_on&pagelen=100", workspace, repo)
+
+	var runs []metrics.WorkflowRun
+	err := s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var pipelines []CloudPipeline
+		if err := json.Unmarshal(values, &pipelines); err != nil {
+			return false, fmt.Errorf("failed to parse pipelines: %w", err)
+		}
+
+		for _, pipeline := range pipelines {
+			// Pipelines are returned newest first
+			if pipeline.CreatedOn.Before(since) {
+				return false, nil
+			}
+
+			status, conclusion := cloudPipelineStatus(pipeline.State.Name, pipeline.State.Result.Name)
+			updatedAt := pipeline.CreatedOn
+			if pipeline.CompletedOn != nil {
+				updatedAt = *pipeline.CompletedOn
+			}
+
+			runs = append(runs, metrics.WorkflowRun{
+				ID:         pipeline.BuildNumber,
+				Name:       pipeline.name(),
+				Status:     status,
+				Conclusion: conclusion,
+				Event:      pipeline.event(),
+				CreatedAt:  pipeline.CreatedOn,
+				UpdatedAt:  updatedAt,
+				SHA:        pipeline.Target.Commit.Hash,
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipelines: %w", err)
+	}
+
+	return runs, nil
+}
+
+// getCloudCommitsBetween lists commits reachable from head but not from base
+func (s *Service) getCloudCommitsBetween(ctx context.Context, workspace, repo, base, head string) ([]metrics.Commit, error) {
+	path := fmt.Sprintf("/2.0/repositories/%s/%s/commits/%s?exclude=%s&pagelen=100",
+		workspace, repo, url.PathEscape(head), url.QueryEscape(base))
+
+	var commits []metrics.Commit
+	err := s.cloudPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []CloudCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, fmt.Errorf("failed to parse commits: %w", err)
+		}
+
+		for _, commit := range page {
+			if len(commit.Parents) > 1 {
+				continue
+			}
+			commits = append(commits, metrics.Commit{
+				SHA:     commit.Hash,
+				Message: commit.Message,
+				Author:  commit.authorName(),
+				Date:    commit.Date,
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	return commits, nil
+}
+
+// cloudPRState maps Bitbucket Cloud pull request states to pull request states
+func cloudPRState(state string) string {
+	switch state {
+	case "OPEN":
+		return "open"
+	case "MERGED":
+		return "merged"
+	default: // DECLINED, SUPERSEDED
+		return "closed"
+	}
+}
+
+// cloudDeploymentState maps Bitbucket Cloud deployment states to deployment states
+func cloudDeploymentState(state, status string) string {
+	if state != "COMPLETED" {
+		return "pending" // UNDEPLOYED, IN_PROGRESS
+	}
+	switch status {
+	case "SUCCESSFUL":
+		return "success"
+	case "FAILED":
+		return "failure"
+	default: // STOPPED
+		return "error"
+	}
+}
+
+// cloudPipelineStatus maps Bitbucket Pipelines states to workflow run status and conclusion
+func cloudPipelineStatus(state, result string) (string, string) {
+	switch state {
+	case "COMPLETED":
+		switch result {
+		case "SUCCESSFUL":
+			return "completed", "success"
+		case "FAILED", "ERROR":
+			return "completed", "failure"
+		case "STOPPED":
+			return "completed", "cancelled"
+		default: // EXPIRED
+			return "completed", "skipped"
+		}
+	case "IN_PROGRESS":
+		return "in_progress", ""
+	default: // PENDING, PAUSED
+		return "queued", ""
+	}
+}
+
+// Bitbucket Cloud API types
+
+// CloudUser represents a Bitbucket Cloud account
+type CloudUser struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+}
+
+// CloudPullRequest represents a Bitbucket Cloud pull request
+type CloudPullRequest struct {
+	ID     int       `json:"id"`
+	Title  string    `json:"title"`
+	State  string    `json:"state"` // OPEN, MERGED, DECLINED, SUPERSEDED
+	Author CloudUser `json:"author"`
+	Source struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"source"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"destination"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit"`
+	CommentCount int       `json:"comment_count"`
+	CreatedOn    time.Time `json:"created_on"`
+	UpdatedOn    time.Time `json:"updated_on"`
+}
+
+// CloudActivity represents an entry of a pull request activity log; exactly one field is set
+type CloudActivity struct {
+	Approval *struct {
+		Date time.Time `json:"date"`
+		User CloudUser `json:"user"`
+	} `json:"approval,omitempty"`
+	ChangesRequested *struct {
+		Date time.Time `json:"date"`
+		User CloudUser `json:"user"`
+	} `json:"changes_requested,omitempty"`
+	Comment *struct {
+		ID        int       `json:"id"`
+		CreatedOn time.Time `json:"created_on"`
+		User      CloudUser `json:"user"`
+	} `json:"comment,omitempty"`
+	Update *struct {
+		State  string    `json:"state"`
+		Date   time.Time `json:"date"`
+		Author CloudUser `json:"author"`
+	} `json:"update,omitempty"`
+}
+
+// CloudCommit represents a Bitbucket Cloud commit
+type CloudCommit struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+	Author  struct {
+		Raw  string     `json:"raw"`
+		User *CloudUser `json:"user,omitempty"`
+	} `json:"author"`
+	Parents []struct {
+		Hash string `json:"hash"`
+	} `json:"parents"`
+}
+
+// authorName returns the linked account name, or the raw "Name <email>" author
+func (c CloudCommit) authorName() string {
+	if c.Author.User != nil && c.Author.User.DisplayName != "" {
+		return c.Author.User.DisplayName
+	}
+	return c.Author.Raw
+}
+
+// CloudEnvironment represents a Bitbucket Cloud deployment environment
+type CloudEnvironment struct {
+	UUID            string `json:"uuid"`
+	Name            string `json:"name"`
+	EnvironmentType struct {
+		Name string `json:"name"` // Test, Staging, Production
+	} `json:"environment_type"`
+}
+
+// CloudDeployment represents a Bitbucket Cloud deployment
+type CloudDeployment struct {
+	UUID  string `json:"uuid"`
+	State struct {
+		Name   string `json:"name"` // UNDEPLOYED, IN_PROGRESS, COMPLETED
+		Status struct {
+			Name string `json:"name"` // SUCCESSFUL, FAILED, STOPPED
+		} `json:"status"`
+		StartedOn   *time.Time `json:"started_on"`
+		CompletedOn *time.Time `json:"completed_on"`
+	} `json:"state"`
+	Environment struct {
+		UUID string `json:"uuid"`
+	} `json:"environment"`
+	Release *struct {
+		Name      string    `json:"name"`
+		CreatedOn time.Time `json:"created_on"`
+		Commit    struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+	} `json:"release"`
+	Deployable *struct {
+		Name      string    `json:"name"`
+		CreatedOn time.Time `json:"created_on"`
+		Commit    struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+	} `json:"deployable"`
+}
+
+// createdAt returns when the deployment started, falling back to its release time
+func (d CloudDeployment) createdAt() time.Time {
+	switch {
+	case d.State.StartedOn != nil:
+		return *d.State.StartedOn
+	case d.State.CompletedOn != nil:
+		return *d.State.CompletedOn
+	case d.Release != nil:
+		return d.Release.CreatedOn
+	default:
+		return time.Time{}
+	}
+}
+
+// sha returns the commit that was deployed
+func (d CloudDeployment) sha() string {
+	if d.Release != nil && d.Release.Commit.Hash != "" {
+		return d.Release.Commit.Hash
+	}
+	if d.Deployable != nil {
+		return d.Deployable.Commit.Hash
+	}
+	return ""
+}
+
+// CloudPipeline represents a Bitbucket Pipelines run
+type CloudPipeline struct {
+	UUID        string `json:"uuid"`
+	BuildNumber int    `json:"build_number"`
+	State       struct {
+		Name   string `json:"name"` // PENDING, IN_PROGRESS, PAUSED, COMPLETED
+		Result struct {
+			Name string `json:"name"` // SUCCESSFUL, FAILED, ERROR, STOPPED, EXPIRED
+		} `json:"result"`
+	} `json:"state"`
+	Target struct {
+		RefName  string `json:"ref_name"`
+		Selector struct {
+			Type    string `json:"type"` // default, branches, tags, custom, pull-requests
+			Pattern string `json:"pattern"`
+		} `json:"selector"`
+		Commit struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+	} `json:"target"`
+	CreatedOn   time.Time  `json:"created_on"`
+	CompletedOn *time.Time `json:"completed_on"`
+}
+
+// event is what triggered the pipeline, with pull request pipelines reported as metrics.RunEventPullRequest
+func (p CloudPipeline) event() string {
+	if p.Target.Selector.Type == "pull-requests" {
+		return metrics.RunEventPullRequest
+	}
+	return p.Target.Selector.Type
+}
+
+// name identifies the pipeline definition that ran, e.g. "branches: main" or "custom: deploy"
+func (p CloudPipeline) name() string {
+	switch {
+	case p.Target.Selector.Pattern != "":
+		return p.Target.Selector.Type + ": " + p.Target.Selector.Pattern
+	case p.Target.Selector.Type != "":
+		return p.Target.Selector.Type
+	default:
+		return "pipeline"
+	}
+}
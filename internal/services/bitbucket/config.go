@@ -0,0 +1,197 @@
+// Package bitbucket provides Bitbucket Cloud and Bitbucket Server/Data Center API integration.
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Bitbucket flavors
+const (
+	FlavorCloud  = "cloud"
+	FlavorServer = "server"
+)
+
+// cloudAPIHost is the API host of Bitbucket Cloud
+const cloudAPIHost = "api.bitbucket.org"
+
+// Config holds Bitbucket integration configuration
+type Config struct {
+	// Authentication: an access token (Bearer), or a username with an app password
+	Token         string `json:"token"`
+	Username      string `json:"username"`
+	AppPassword   string `json:"app_password"`
+	WebhookSecret string `json:"webhook_secret"`
+
+	// API configuration. BaseURL is the instance root: https://api.bitbucket.org for
+	// Cloud, or e.g. https://bitbucket.example.com for Server/Data Center
+	BaseURL         string        `json:"base_url"`
+	Flavor          string        `json:"flavor"` // cloud, server; derived from BaseURL when empty
+	UserAgent       string        `json:"user_agent"`
+	Timeout         time.Duration `json:"timeout"`
+	MaxRetries      int           `json:"max_retries"`
+	RetryBackoffMs  int           `json:"retry_backoff_ms"`
+	CacheTTLMinutes int           `json:"cache_ttl_minutes"`
+
+	// Rate limiting
+	EnableRateLimit bool `json:"enable_rate_limit"`
+	RateLimitBurst  int  `json:"rate_limit_burst"`
+}
+
+// LoadConfig loads Bitbucket configuration from environment variables
+func LoadConfig() (*Config, error) {
+	config := &Config{
+		// Default values
+		BaseURL:         "https://" + cloudAPIHost,
+		UserAgent:       "GemX-Analyzer/1.0.0",
+		Timeout:         30 * time.Second,
+		MaxRetries:      3,
+		RetryBackoffMs:  1000,
+		CacheTTLMinutes: 15,
+		EnableRateLimit: true,
+		RateLimitBurst:  100,
+	}
+
+	config.Token = os.Getenv("BITBUCKET_TOKEN")
+	config.Username = os.Getenv("BITBUCKET_USERNAME")
+	config.AppPassword = os.Getenv("BITBUCKET_APP_PASSWORD")
+	config.WebhookSecret = os.Getenv("BITBUCKET_WEBHOOK_SECRET")
+	config.Flavor = os.Getenv("BITBUCKET_FLAVOR")
+
+	// API configuration overrides
+	if baseURL := os.Getenv("BITBUCKET_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+
+	if userAgent := os.Getenv("BITBUCKET_USER_AGENT"); userAgent != "" {
+		config.UserAgent = userAgent
+	}
+
+	// Timeout configuration
+	if timeoutStr := os.Getenv("BITBUCKET_TIMEOUT_SECONDS"); timeoutStr != "" {
+		timeoutSec, err := strconv.Atoi(timeoutStr)
+		if err == nil && timeoutSec > 0 {
+			config.Timeout = time.Duration(timeoutSec) * time.Second
+		}
+	}
+
+	// Retry configuration
+	if retriesStr := os.Getenv("BITBUCKET_MAX_RETRIES"); retriesStr != "" {
+		retries, err := strconv.Atoi(retriesStr)
+		if err == nil && retries >= 0 {
+			config.MaxRetries = retries
+		}
+	}
+
+	if backoffStr := os.Getenv("BITBUCKET_RETRY_BACKOFF_MS"); backoffStr != "" {
+		backoff, err := strconv.Atoi(backoffStr)
+		if err == nil && backoff > 0 {
+			config.RetryBackoffMs = backoff
+		}
+	}
+
+	// Cache configuration
+	if ttlStr := os.Getenv("BITBUCKET_CACHE_TTL_MINUTES"); ttlStr != "" {
+		ttl, err := strconv.Atoi(ttlStr)
+		if err == nil && ttl > 0 {
+			config.CacheTTLMinutes = ttl
+		}
+	}
+
+	// Rate limiting configuration
+	if rateLimitStr := os.Getenv("BITBUCKET_ENABLE_RATE_LIMIT"); rateLimitStr != "" {
+		config.EnableRateLimit = rateLimitStr == "true"
+	}
+
+	if burstStr := os.Getenv("BITBUCKET_RATE_LIMIT_BURST"); burstStr != "" {
+		burst, err := strconv.Atoi(burstStr)
+		if err == nil && burst > 0 {
+			config.RateLimitBurst = burst
+		}
+	}
+
+	return config, nil
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	// Validate base URL
+	parsed, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid base URL: missing host")
+	}
+
+	if c.Flavor != "" && c.Flavor != FlavorCloud && c.Flavor != FlavorServer {
+		return fmt.Errorf("invalid flavor %q: must be %q or %q", c.Flavor, FlavorCloud, FlavorServer)
+	}
+
+	// Validate authentication
+	if c.Token == "" && (c.Username == "" || c.AppPassword == "") {
+		return fmt.Errorf("no authentication configured: need BITBUCKET_TOKEN or BITBUCKET_USERNAME with BITBUCKET_APP_PASSWORD")
+	}
+
+	// Validate timeout
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: must be positive")
+	}
+
+	// Validate retry configuration
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("invalid max retries: must be non-negative")
+	}
+	if c.RetryBackoffMs <= 0 {
+		return fmt.Errorf("invalid retry backoff: must be positive")
+	}
+
+	// Validate cache TTL
+	if c.CacheTTLMinutes <= 0 {
+		return fmt.Errorf("invalid cache TTL: must be positive")
+	}
+
+	// Validate rate limit configuration
+	if c.EnableRateLimit && c.RateLimitBurst <= 0 {
+		return fmt.Errorf("invalid rate limit burst: must be positive when rate limiting is enabled")
+	}
+
+	return nil
+}
+
+// IsServer returns true when talking to Bitbucket Server/Data Center rather than Cloud
+func (c *Config) IsServer() bool {
+	if c.Flavor != "" {
+		return c.Flavor == FlavorServer
+	}
+	parsed, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return false
+	}
+	return parsed.Hostname() != cloudAPIHost
+}
+
+// Host returns the host repositories are cloned from, used to route repositories to this backend
+func (c *Config) Host() string {
+	if !c.IsServer() {
+		return "bitbucket.org"
+	}
+	parsed, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// GetCacheTTL returns the cache TTL as a duration
+func (c *Config) GetCacheTTL() time.Duration {
+	return time.Duration(c.CacheTTLMinutes) * time.Minute
+}
+
+// GetRetryBackoff returns the retry backoff as a duration
+func (c *Config) GetRetryBackoff() time.Duration {
+	return time.Duration(c.RetryBackoffMs) * time.Millisecond
+}
@@ -0,0 +1,80 @@
+package bitbucket
+
+import (
+	"testing"
+)
+
+func TestLoadConfigCloud(t *testing.T) {
+	t.Setenv("BITBUCKET_USERNAME", "analyzer")
+	t.Setenv("BITBUCKET_APP_PASSWORD", "app-password")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if config.BaseURL != "https://api.bitbucket.org" {
+		t.Errorf("Expected default BaseURL to be 'https://api.bitbucket.org', got %s", config.BaseURL)
+	}
+
+	if config.IsServer() {
+		t.Error("Expected Bitbucket Cloud by default")
+	}
+
+	if config.Host() != "bitbucket.org" {
+		t.Errorf("Expected Cloud repositories to be routed by 'bitbucket.org', got %s", config.Host())
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
+
+func TestLoadConfigServer(t *testing.T) {
+	t.Setenv("BITBUCKET_TOKEN", "http-access-token")
+	t.Setenv("BITBUCKET_BASE_URL", "https://bitbucket.example.com")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if !config.IsServer() {
+		t.Error("Expected a self-hosted base URL to select Bitbucket Server")
+	}
+
+	if config.Host() != "bitbucket.example.com" {
+		t.Errorf("Expected Host to be 'bitbucket.example.com', got %s", config.Host())
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "token", env: map[string]string{"BITBUCKET_TOKEN": "t"}},
+		{name: "app password", env: map[string]string{"BITBUCKET_USERNAME": "u", "BITBUCKET_APP_PASSWORD": "p"}},
+		{name: "username without password", env: map[string]string{"BITBUCKET_USERNAME": "u"}, wantErr: true},
+		{name: "invalid flavor", env: map[string]string{"BITBUCKET_TOKEN": "t", "BITBUCKET_FLAVOR": "datacenter"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"BITBUCKET_TOKEN", "BITBUCKET_USERNAME", "BITBUCKET_APP_PASSWORD", "BITBUCKET_FLAVOR"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			config, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+
+			err = config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+// Package bitbucket - Bitbucket Server/Data Center (REST API 1.0) data access
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// getServerPullRequests lists pull requests updated since the given time
+func (s *Service) getServerPullRequests(ctx context.Context, project, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests?state=ALL&order=NEWEST&limit=100", project, repo)
+
+	var allPRs []metrics.PullRequest
+	err := s.serverPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var serverPRs []ServerPullRequest
+		if err := json.Unmarshal(values, &serverPRs); err != nil {
+			return false, fmt.Errorf("failed to parse pull requests: %w", err)
+		}
+
+		for _, spr := range serverPRs {
+			updatedAt := millis(spr.UpdatedDate)
+			if updatedAt.Before(since) {
+				continue
+			}
+
+			pr := metrics.PullRequest{
+				Number:     spr.ID,
+				Title:      spr.Title,
+				State:      serverPRState(spr.State),
+				CreatedAt:  millis(spr.CreatedDate),
+				UpdatedAt:  updatedAt,
+				ClosedAt:   millisPtr(spr.ClosedDate),
+				HeadBranch: spr.FromRef.DisplayID,
+			}
+			if spr.State == "MERGED" {
+				pr.MergedAt = pr.ClosedAt
+				if spr.Properties.MergeCommit != nil {
+					pr.MergeCommitSHA = spr.Properties.MergeCommit.ID
+				}
+			}
+
+			activities, err := s.getServerActivities(ctx, project, repo, spr.ID)
+			if err == nil {
+				applyServerActivities(&pr, activities, spr.Author.User.Name)
+			}
+
+			commits, err := s.getServerPullRequestCommits(ctx, project, repo, spr.ID)
+			if err == nil {
+				pr.Commits = len(commits)
+				for _, commit := range commits {
+					pr.FirstCommitAt = earliest(pr.FirstCommitAt, millis(commit.AuthorTimestamp))
+				}
+			}
+
+			allPRs = append(allPRs, pr)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull requests: %w", err)
+	}
+
+	return allPRs, nil
+}
+
+// getServerActivities lists the activities of a pull request
+func (s *Service) getServerActivities(ctx context.Context, project, repo string, id int) ([]ServerActivity, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/activities?limit=100", project, repo, id)
+
+	var activities []ServerActivity
+	err := s.serverPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []ServerActivity
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, err
+		}
+		activities = append(activities, page...)
+		return true, nil
+	})
+	return activities, err
+}
+
+// applyServerActivities derives review and approval times from pull request activities.
+// Comments, "needs work" reviews and approvals by anyone but the author count as reviews.
+func applyServerActivities(pr *metrics.PullRequest, activities []ServerActivity, author string) {
+	for _, activity := range activities {
+		at := millis(activity.CreatedDate)
+		switch activity.Action {
+		case "APPROVED":
+			if activity.User.Name == author {
+				continue
+			}
+			pr.ApprovedAt = earliest(pr.ApprovedAt, at)
+			pr.FirstReviewAt = earliest(pr.FirstReviewAt, at)
+		case "COMMENTED", "REVIEWED":
+			if activity.User.Name == author {
+				continue
+			}
+			pr.FirstReviewAt = earliest(pr.FirstReviewAt, at)
+		case "MERGED":
+			pr.MergedAt = earliest(pr.MergedAt, at)
+			if pr.MergeCommitSHA == "" && activity.Commit != nil {
+				pr.MergeCommitSHA = activity.Commit.ID
+			}
+		}
+	}
+}
+
+// getServerPullRequestCommits lists the commits of a pull request
+func (s *Service) getServerPullRequestCommits(ctx context.Context, project, repo string, id int) ([]ServerCommit, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d/commits?limit=100", project, repo, id)
+
+	var commits []ServerCommit
+	err := s.serverPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []ServerCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, err
+		}
+		commits = append(commits, page...)
+		return true, nil
+	})
+	return commits, err
+}
+
+// getServerBuilds collects build statuses reported against commits of the default
+// branch committed since the given time. Server has no repository-wide build listing.
+func (s *Service) getServerBuilds(ctx context.Context, project, repo string, since time.Time) ([]metrics.WorkflowRun, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/commits?limit=100", project, repo)
+
+	var commits []ServerCommit
+	err := s.serverPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []ServerCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, fmt.Errorf("failed to parse commits: %w", err)
+		}
+
+		for _, commit := range page {
+			// Commits are returned newest first
+			if millis(commit.CommitterTimestamp).Before(since) {
+				return false, nil
+			}
+			commits = append(commits, commit)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	var runs []metrics.WorkflowRun
+	for _, commit := range commits {
+		statusPath := fmt.Sprintf("/rest/build-status/1.0/commits/%s?limit=100", commit.ID)
+		err := s.serverPaginate(ctx, statusPath, func(values json.RawMessage) (bool, error) {
+			var statuses []ServerBuildStatus
+			if err := json.Unmarshal(values, &statuses); err != nil {
+				return false, fmt.Errorf("failed to parse build statuses: %w", err)
+			}
+
+			for _, build := range statuses {
+				status, conclusion := serverBuildStatus(build.State)
+				name := build.Name
+				if name == "" {
+					name = build.Key
+				}
+				runs = append(runs, metrics.WorkflowRun{
+					ID:         stableID(build.Key + "@" + commit.ID),
+					Name:       name,
+					Status:     status,
+					Conclusion: conclusion,
+					CreatedAt:  millis(build.DateAdded),
+					UpdatedAt:  millis(build.DateAdded),
+					SHA:        commit.ID,
+				})
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get build statuses: %w", err)
+		}
+	}
+
+	return runs, nil
+}
+
+// getServerTagReleases lists tags whose commit was made since the given time as successful
+// production deployments, dated by the commit since Server does not record tag dates
+func (s *Service) getServerTagReleases(ctx context.Context, project, repo string, since time.Time) ([]metrics.Deployment, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/tags?orderBy=MODIFICATION&limit=100", project, repo)
+
+	var deployments []metrics.Deployment
+	err := s.serverPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var tags []ServerRef
+		if err := json.Unmarshal(values, &tags); err != nil {
+			return false, fmt.Errorf("failed to parse tags: %w", err)
+		}
+
+		for _, tag := range tags {
+			data, err := s.client.Get(ctx, fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/commits/%s", project, repo, tag.LatestCommit))
+			if err != nil {
+				return false, fmt.Errorf("failed to get commit of tag %s: %w", tag.DisplayID, err)
+			}
+			var commit ServerCommit
+			if err := json.Unmarshal(data, &commit); err != nil {
+				return false, fmt.Errorf("failed to parse commit of tag %s: %w", tag.DisplayID, err)
+			}
+
+			// Tags are returned most recently modified first
+			taggedAt := millis(commit.CommitterTimestamp)
+			if taggedAt.Before(since) {
+				return false, nil
+			}
+			deployments = append(deployments, metrics.Deployment{
+				ID:          stableID("tag@" + tag.ID),
+				Environment: "production",
+				State:       "success",
+				CreatedAt:   taggedAt,
+				UpdatedAt:   taggedAt,
+				SHA:         tag.LatestCommit,
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release tags: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// getServerCommitsBetween lists non-merge commits reachable from head but not from base
+func (s *Service) getServerCommitsBetween(ctx context.Context, project, repo, base, head string) ([]metrics.Commit, error) {
+	path := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/commits?since=%s&until=%s&merges=exclude&limit=100",
+		project, repo, url.QueryEscape(base), url.QueryEscape(head))
+
+	var commits []metrics.Commit
+	err := s.serverPaginate(ctx, path, func(values json.RawMessage) (bool, error) {
+		var page []ServerCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return false, fmt.Errorf("failed to parse commits: %w", err)
+		}
+
+		for _, commit := range page {
+			if len(commit.Parents) > 1 {
+				continue
+			}
+			commits = append(commits, metrics.Commit{
+				SHA:     commit.ID,
+				Message: commit.Message,
+				Author:  commit.Author.Name,
+				Date:    millis(commit.AuthorTimestamp),
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	return commits, nil
+}
+
+// serverPRState maps Bitbucket Server pull request states to pull request states
+func serverPRState(state string) string {
+	switch state {
+	case "OPEN":
+		return "open"
+	case "MERGED":
+		return "merged"
+	default: // DECLINED
+		return "closed"
+	}
+}
+
+// serverBuildStatus maps Bitbucket Server build states to workflow run status and conclusion
+func serverBuildStatus(state string) (string, string) {
+	switch state {
+	case "SUCCESSFUL":
+		return "completed", "success"
+	case "FAILED":
+		return "completed", "failure"
+	case "CANCELLED":
+		return "completed", "cancelled"
+	case "INPROGRESS":
+		return "in_progress", ""
+	default: // UNKNOWN
+		return "queued", ""
+	}
+}
+
+// millis converts a Bitbucket Server epoch-milliseconds timestamp
+func millis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+// millisPtr converts an optional epoch-milliseconds timestamp
+func millisPtr(ms int64) *time.Time {
+	if ms == 0 {
+		return nil
+	}
+	t := millis(ms)
+	return &t
+}
+
+// Bitbucket Server API types
+
+// ServerUser represents a Bitbucket Server user
+type ServerUser struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	DisplayName string `json:"displayName"`
+}
+
+// ServerRef represents a branch reference of a pull request
+type ServerRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+// ServerPullRequest represents a Bitbucket Server pull request
+type ServerPullRequest struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	State  string `json:"state"` // OPEN, MERGED, DECLINED
+	Author struct {
+		User ServerUser `json:"user"`
+	} `json:"author"`
+	FromRef     ServerRef `json:"fromRef"`
+	ToRef       ServerRef `json:"toRef"`
+	CreatedDate int64     `json:"createdDate"`
+	UpdatedDate int64     `json:"updatedDate"`
+	ClosedDate  int64     `json:"closedDate"`
+	Properties  struct {
+		MergeCommit *struct {
+			ID string `json:"id"`
+		} `json:"mergeCommit"`
+	} `json:"properties"`
+}
+
+// ServerActivity represents a pull request activity
+type ServerActivity struct {
+	ID          int        `json:"id"`
+	CreatedDate int64      `json:"createdDate"`
+	User        ServerUser `json:"user"`
+	Action      string     `json:"action"` // OPENED, COMMENTED, APPROVED, REVIEWED, UNAPPROVED, MERGED, DECLINED, RESCOPED, UPDATED
+	Commit      *struct {
+		ID string `json:"id"`
+	} `json:"commit,omitempty"`
+}
+
+// ServerCommit represents a Bitbucket Server commit
+type ServerCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Author  struct {
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+	} `json:"author"`
+	AuthorTimestamp    int64 `json:"authorTimestamp"`
+	CommitterTimestamp int64 `json:"committerTimestamp"`
+	Parents            []struct {
+		ID string `json:"id"`
+	} `json:"parents"`
+}
+
+// ServerBuildStatus represents a build status reported against a commit
+type ServerBuildStatus struct {
+	State     string `json:"state"` // SUCCESSFUL, FAILED, INPROGRESS, CANCELLED, UNKNOWN
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	DateAdded int64  `json:"dateAdded"`
+}
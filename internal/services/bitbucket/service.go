@@ -0,0 +1,178 @@
+// Package bitbucket provides a service wrapper that implements the metrics data interfaces.
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// Service provides Bitbucket data access for DORA metrics. Owner is the workspace
+// on Bitbucket Cloud and the project key on Bitbucket Server; repo is the repository slug.
+type Service struct {
+	client *Client
+	host   string
+	server bool
+}
+
+// NewService creates a new Bitbucket service
+func NewService(config *Config) (*Service, error) {
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Bitbucket client: %w", err)
+	}
+
+	return &Service{
+		client: client,
+		host:   config.Host(),
+		server: config.IsServer(),
+	}, nil
+}
+
+// NewServiceFromEnv creates a new Bitbucket service using environment configuration
+func NewServiceFromEnv() (*Service, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return NewService(config)
+}
+
+// Host returns the host repositories served by this backend are cloned from
+func (s *Service) Host() string {
+	return s.host
+}
+
+// GetClient returns the underlying Bitbucket client
+func (s *Service) GetClient() *Client {
+	return s.client
+}
+
+// GetPullRequests implements the metrics.GitHubClient interface
+func (s *Service) GetPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	if s.server {
+		return s.getServerPullRequests(ctx, owner, repo, since)
+	}
+	return s.getCloudPullRequests(ctx, owner, repo, since)
+}
+
+// GetDeployments implements the metrics.GitHubClient interface
+func (s *Service) GetDeployments(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Deployment, error) {
+	if s.server {
+		// Bitbucket Server only exposes deployments per commit, so there is no way
+		// to list them for a period; release tags stand in for production deployments
+		return s.getServerTagReleases(ctx, owner, repo, since)
+	}
+	return s.getCloudDeployments(ctx, owner, repo, since)
+}
+
+// GetWorkflowRuns implements the metrics.GitHubClient interface using Pipelines on
+// Cloud and build statuses of recent commits on Server
+func (s *Service) GetWorkflowRuns(ctx context.Context, owner, repo string, since time.Time) ([]metrics.WorkflowRun, error) {
+	if s.server {
+		return s.getServerBuilds(ctx, owner, repo, since)
+	}
+	return s.getCloudPipelines(ctx, owner, repo, since)
+}
+
+// GetCommitsBetween implements the metrics.CommitRangeClient interface.
+// Merge commits are skipped so lead time reflects authored changes.
+func (s *Service) GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]metrics.Commit, error) {
+	if s.server {
+		return s.getServerCommitsBetween(ctx, owner, repo, base, head)
+	}
+	return s.getCloudCommitsBetween(ctx, owner, repo, base, head)
+}
+
+// cloudPage is a page of a Bitbucket Cloud collection
+type cloudPage struct {
+	Values  json.RawMessage `json:"values"`
+	Next    string          `json:"next"`
+	PageLen int             `json:"pagelen"`
+}
+
+// cloudPaginate follows "next" links of a Cloud collection, handing each page of values
+// to handle until it returns false or there are no more pages
+func (s *Service) cloudPaginate(ctx context.Context, path string, handle func(values json.RawMessage) (bool, error)) error {
+	for path != "" {
+		data, err := s.client.Get(ctx, path)
+		if err != nil {
+			return err
+		}
+
+		var page cloudPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to parse page: %w", err)
+		}
+
+		more, err := handle(page.Values)
+		if err != nil {
+			return err
+		}
+		if !more || page.Next == "" {
+			return nil
+		}
+
+		path, err = s.client.relativePath(page.Next)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serverPage is a page of a Bitbucket Server collection
+type serverPage struct {
+	Values        json.RawMessage `json:"values"`
+	IsLastPage    bool            `json:"isLastPage"`
+	NextPageStart int             `json:"nextPageStart"`
+}
+
+// serverPaginate follows start offsets of a Server collection, handing each page of values
+// to handle until it returns false or the last page is reached
+func (s *Service) serverPaginate(ctx context.Context, path string, handle func(values json.RawMessage) (bool, error)) error {
+	start := 0
+	for {
+		data, err := s.client.Get(ctx, fmt.Sprintf("%s&start=%d", path, start))
+		if err != nil {
+			return err
+		}
+
+		var page serverPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("failed to parse page: %w", err)
+		}
+
+		more, err := handle(page.Values)
+		if err != nil {
+			return err
+		}
+		if !more || page.IsLastPage {
+			return nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// stableID derives a numeric ID from a UUID or key for resources without integer IDs
+func stableID(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() & 0x7fffffff)
+}
+
+// earliest returns the earlier of a time pointer and a candidate time
+func earliest(current *time.Time, candidate time.Time) *time.Time {
+	if candidate.IsZero() {
+		return current
+	}
+	if current == nil || candidate.Before(*current) {
+		return &candidate
+	}
+	return current
+}
@@ -0,0 +1,344 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureServer serves recorded Bitbucket API responses from testdata. Fixtures are keyed by
+// request path, with "?page=N" or "?start=N" appended for later pages. An empty fixture name
+// serves an empty collection. {{BASE}} in fixtures is replaced with the server URL.
+func fixtureServer(t *testing.T, flavor string, fixtures map[string]string) *Service {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "analyzer" || password != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"type": "error", "error": {"message": "Unauthorized"}}`))
+			return
+		}
+
+		key := r.URL.Path
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+		if start := r.URL.Query().Get("start"); start != "" && start != "0" {
+			key += "?start=" + start
+		}
+
+		fixture, ok := fixtures[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": [{"message": "Resource not found: ` + key + `"}]}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if fixture == "" {
+			w.Write([]byte(`{"values": [], "pagelen": 50, "size": 0, "isLastPage": true}`))
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Errorf("failed to read fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(strings.ReplaceAll(string(data), "{{BASE}}", server.URL)))
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewService(&Config{
+		Username:        "analyzer",
+		AppPassword:     "app-password",
+		BaseURL:         server.URL,
+		Flavor:          flavor,
+		UserAgent:       "test-agent",
+		Timeout:         5 * time.Second,
+		MaxRetries:      0,
+		RetryBackoffMs:  1,
+		CacheTTLMinutes: 1,
+		EnableRateLimit: false,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+	return service
+}
+
+func TestCloudGetPullRequests(t *testing.T) {
+	service := fixtureServer(t, FlavorCloud, map[string]string{
+		"/2.0/repositories/acme/widgets/pullrequests":            "cloud_pullrequests.json",
+		"/2.0/repositories/acme/widgets/pullrequests?page=2":     "cloud_pullrequests_page2.json",
+		"/2.0/repositories/acme/widgets/pullrequests/7/activity": "cloud_pr_7_activity.json",
+		"/2.0/repositories/acme/widgets/pullrequests/7/commits":  "cloud_pr_7_commits.json",
+		"/2.0/repositories/acme/widgets/pullrequests/6/activity": "",
+		"/2.0/repositories/acme/widgets/pullrequests/6/commits":  "",
+	})
+
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	prs, err := service.GetPullRequests(context.Background(), "acme", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetPullRequests() failed: %v", err)
+	}
+
+	// Both pages are followed
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	merged := prs[0]
+	if merged.Number != 7 || merged.State != "merged" || merged.HeadBranch != "feature/search" {
+		t.Errorf("Unexpected pull request mapping: %+v", merged)
+	}
+	if merged.MergeCommitSHA != "e4d7c0a1b2c3d4e5f60718293a4b5c6d7e8f9012" {
+		t.Errorf("Unexpected merge commit SHA: %s", merged.MergeCommitSHA)
+	}
+	// The author's own comments are not reviews; Bob's change request is the first review
+	if merged.FirstReviewAt == nil || !merged.FirstReviewAt.Equal(time.Date(2024, 4, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first review at 2024-04-01 14:00, got %v", merged.FirstReviewAt)
+	}
+	if merged.ApprovedAt == nil || !merged.ApprovedAt.Equal(time.Date(2024, 4, 2, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected approval at 2024-04-02 11:00, got %v", merged.ApprovedAt)
+	}
+	if merged.MergedAt == nil || !merged.MergedAt.Equal(time.Date(2024, 4, 2, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected merge at 2024-04-02 15:30, got %v", merged.MergedAt)
+	}
+	if merged.FirstCommitAt == nil || !merged.FirstCommitAt.Equal(time.Date(2024, 3, 29, 17, 5, 0, 0, time.UTC)) {
+		t.Errorf("Expected first commit at 2024-03-29 17:05, got %v", merged.FirstCommitAt)
+	}
+	if merged.Commits != 2 {
+		t.Errorf("Expected 2 commits, got %d", merged.Commits)
+	}
+
+	declined := prs[1]
+	if declined.State != "closed" || declined.MergedAt != nil || declined.ClosedAt == nil {
+		t.Errorf("Expected declined PR to be closed without merge, got %+v", declined)
+	}
+}
+
+func TestCloudGetDeployments(t *testing.T) {
+	service := fixtureServer(t, FlavorCloud, map[string]string{
+		"/2.0/repositories/acme/widgets/environments/": "cloud_environments.json",
+		"/2.0/repositories/acme/widgets/deployments/":  "cloud_deployments.json",
+	})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	deployments, err := service.GetDeployments(context.Background(), "acme", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetDeployments() failed: %v", err)
+	}
+
+	if len(deployments) != 3 {
+		t.Fatalf("Expected 3 deployments, got %d", len(deployments))
+	}
+
+	production := deployments[0]
+	if production.Environment != "Production" || production.State != "success" {
+		t.Errorf("Unexpected production deployment: %+v", production)
+	}
+	if production.SHA != "e4d7c0a1b2c3d4e5f60718293a4b5c6d7e8f9012" {
+		t.Errorf("Unexpected deployed SHA: %s", production.SHA)
+	}
+	if !production.UpdatedAt.After(production.CreatedAt) {
+		t.Errorf("Expected completion after start, got %v and %v", production.CreatedAt, production.UpdatedAt)
+	}
+	if production.ID == 0 || production.ID == deployments[1].ID {
+		t.Errorf("Expected distinct non-zero IDs derived from UUIDs, got %d and %d", production.ID, deployments[1].ID)
+	}
+
+	if deployments[1].Environment != "Staging" || deployments[1].State != "failure" {
+		t.Errorf("Unexpected staging deployment: %+v", deployments[1])
+	}
+	if deployments[2].State != "pending" {
+		t.Errorf("Expected undeployed release to be pending, got %s", deployments[2].State)
+	}
+}
+
+func TestCloudGetWorkflowRuns(t *testing.T) {
+	service := fixtureServer(t, FlavorCloud, map[string]string{
+		"/2.0/repositories/acme/widgets/pipelines/": "cloud_pipelines.json",
+	})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	runs, err := service.GetWorkflowRuns(context.Background(), "acme", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() failed: %v", err)
+	}
+
+	if len(runs) != 3 {
+		t.Fatalf("Expected 3 pipelines, got %d", len(runs))
+	}
+	if runs[0].Status != "in_progress" || runs[0].ID != 59 {
+		t.Errorf("Unexpected running pipeline: %+v", runs[0])
+	}
+	if runs[1].Name != "branches: main" || runs[1].Conclusion != "success" {
+		t.Errorf("Unexpected successful pipeline: %+v", runs[1])
+	}
+	if runs[1].UpdatedAt.Sub(runs[1].CreatedAt) != 8*time.Minute {
+		t.Errorf("Expected 8 minute pipeline, got %v", runs[1].UpdatedAt.Sub(runs[1].CreatedAt))
+	}
+	if runs[2].Conclusion != "failure" || runs[2].Event != "pull_request" {
+		t.Errorf("Expected failed pull request pipeline, got %+v", runs[2])
+	}
+}
+
+func TestCloudGetCommitsBetween(t *testing.T) {
+	service := fixtureServer(t, FlavorCloud, map[string]string{
+		"/2.0/repositories/acme/widgets/commits/e4d7c0a1": "cloud_commits.json",
+	})
+
+	commits, err := service.GetCommitsBetween(context.Background(), "acme", "widgets", "7a0c1d2e", "e4d7c0a1")
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() failed: %v", err)
+	}
+
+	// The merge commit is skipped
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+	if commits[0].Author != "Alice" || commits[1].Author != "Alice <alice@example.com>" {
+		t.Errorf("Unexpected commit authors: %s, %s", commits[0].Author, commits[1].Author)
+	}
+}
+
+func TestServerGetPullRequests(t *testing.T) {
+	service := fixtureServer(t, FlavorServer, map[string]string{
+		"/rest/api/1.0/projects/ACME/repos/widgets/pull-requests":               "server_pull_requests.json",
+		"/rest/api/1.0/projects/ACME/repos/widgets/pull-requests/12/activities": "server_pr_12_activities.json",
+		"/rest/api/1.0/projects/ACME/repos/widgets/pull-requests/12/commits":    "server_pr_12_commits.json",
+	})
+
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	prs, err := service.GetPullRequests(context.Background(), "ACME", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetPullRequests() failed: %v", err)
+	}
+
+	// PR #3 was last updated before since
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.Number != 12 || pr.State != "merged" || pr.HeadBranch != "bugfix/retries" {
+		t.Errorf("Unexpected pull request mapping: %+v", pr)
+	}
+	if !pr.CreatedAt.Equal(time.Date(2024, 4, 4, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected creation at 2024-04-04 09:00, got %v", pr.CreatedAt)
+	}
+	if pr.MergedAt == nil || !pr.MergedAt.Equal(time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected merge at 2024-04-05 12:00, got %v", pr.MergedAt)
+	}
+	if pr.MergeCommitSHA != "c3c3c3c300000000000000000000000000000012" {
+		t.Errorf("Expected merge commit from the MERGED activity, got %s", pr.MergeCommitSHA)
+	}
+	if pr.FirstReviewAt == nil || !pr.FirstReviewAt.Equal(time.Date(2024, 4, 4, 19, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first review at 2024-04-04 19:00, got %v", pr.FirstReviewAt)
+	}
+	if pr.ApprovedAt == nil || !pr.ApprovedAt.Equal(time.Date(2024, 4, 5, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected approval at 2024-04-05 10:00, got %v", pr.ApprovedAt)
+	}
+	if pr.FirstCommitAt == nil || !pr.FirstCommitAt.Equal(time.Date(2024, 4, 4, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first commit at 2024-04-04 08:00, got %v", pr.FirstCommitAt)
+	}
+}
+
+func TestServerGetWorkflowRunsFromBuildStatuses(t *testing.T) {
+	service := fixtureServer(t, FlavorServer, map[string]string{
+		"/rest/api/1.0/projects/ACME/repos/widgets/commits":                       "server_commits.json",
+		"/rest/api/1.0/projects/ACME/repos/widgets/commits?start=2":               "server_commits_page2.json",
+		"/rest/build-status/1.0/commits/c3c3c3c300000000000000000000000000000012": "server_build_status_c3c3c3c3.json",
+		"/rest/build-status/1.0/commits/b0b0b0b000000000000000000000000000000012": "server_build_status_b0b0b0b0.json",
+		"/rest/api/1.0/projects/ACME/repos/widgets/tags":                          "",
+	})
+
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	runs, err := service.GetWorkflowRuns(context.Background(), "ACME", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() failed: %v", err)
+	}
+
+	if len(runs) != 3 {
+		t.Fatalf("Expected 3 builds, got %d", len(runs))
+	}
+	if runs[0].Conclusion != "success" || runs[0].SHA != "c3c3c3c300000000000000000000000000000012" {
+		t.Errorf("Unexpected build mapping: %+v", runs[0])
+	}
+	if runs[1].Name != "WIDGETS-MASTER-DEPLOY" || runs[1].Status != "in_progress" {
+		t.Errorf("Expected unnamed build to use its key, got %+v", runs[1])
+	}
+	if runs[2].Conclusion != "failure" {
+		t.Errorf("Expected failed build, got %+v", runs[2])
+	}
+
+	deployments, err := service.GetDeployments(context.Background(), "ACME", "widgets", since)
+	if err != nil || len(deployments) != 0 {
+		t.Errorf("Expected build statuses not to count as deployments, got %v, %v", deployments, err)
+	}
+}
+
+func TestServerGetDeploymentsFromReleaseTags(t *testing.T) {
+	const commits = "/rest/api/1.0/projects/ACME/repos/widgets/commits/"
+	service := fixtureServer(t, FlavorServer, map[string]string{
+		"/rest/api/1.0/projects/ACME/repos/widgets/tags":     "server_tags.json",
+		commits + "c3c3c3c300000000000000000000000000000012": "server_commit_c3c3c3c3.json",
+		commits + "b0b0b0b000000000000000000000000000000012": "server_commit_b0b0b0b0.json",
+		commits + "a0a0a0a000000000000000000000000000000000": "server_commit_a0a0a0a0.json",
+	})
+
+	deployments, err := service.GetDeployments(context.Background(), "ACME", "widgets", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetDeployments() failed: %v", err)
+	}
+
+	// v1.0.0 was tagged before the period
+	if len(deployments) != 2 {
+		t.Fatalf("Expected 2 release tags in the period, got %d", len(deployments))
+	}
+	release := deployments[0]
+	if release.Environment != "production" || release.State != "success" || release.SHA != "c3c3c3c300000000000000000000000000000012" {
+		t.Errorf("Unexpected release mapping: %+v", release)
+	}
+	if !release.CreatedAt.Equal(time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the release dated by its commit, got %v", release.CreatedAt)
+	}
+	if release.ID == deployments[1].ID {
+		t.Errorf("Expected distinct deployment IDs, got %d twice", release.ID)
+	}
+}
+
+func TestServerGetCommitsBetween(t *testing.T) {
+	service := fixtureServer(t, FlavorServer, map[string]string{
+		"/rest/api/1.0/projects/ACME/repos/widgets/commits":         "server_commits.json",
+		"/rest/api/1.0/projects/ACME/repos/widgets/commits?start=2": "server_commits_page2.json",
+	})
+
+	commits, err := service.GetCommitsBetween(context.Background(), "ACME", "widgets", "a0", "c3c3c3c3")
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() failed: %v", err)
+	}
+
+	// The merge commit is skipped and the second page is followed
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+	if commits[0].Author != "dave" || commits[0].Date.IsZero() {
+		t.Errorf("Unexpected commit mapping: %+v", commits[0])
+	}
+}
+
+func TestClientRejectsForeignPaginationLinks(t *testing.T) {
+	service := fixtureServer(t, FlavorCloud, nil)
+
+	if _, err := service.client.relativePath("https://evil.example.com/2.0/repositories"); err == nil {
+		t.Error("Expected pagination link to another host to be rejected")
+	}
+}
@@ -0,0 +1,124 @@
+// Package bitbucket provides webhook signature validation for Bitbucket Cloud and Server.
+package bitbucket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookValidator handles Bitbucket webhook signature validation and deduplication
+type WebhookValidator struct {
+	secret string
+
+	// Deduplication tracking
+	deliveryMutex sync.RWMutex
+	deliveries    map[string]time.Time
+	maxAge        time.Duration
+}
+
+// NewWebhookValidator creates a new webhook validator with the given secret
+func NewWebhookValidator(secret string) *WebhookValidator {
+	return &WebhookValidator{
+		secret:     secret,
+		deliveries: make(map[string]time.Time),
+		maxAge:     24 * time.Hour, // Keep delivery IDs for 24 hours
+	}
+}
+
+// ValidateSignature validates the webhook signature. Both Cloud and Server send
+// X-Hub-Signature as "sha256=<hex HMAC-SHA256 of the body>".
+func (wv *WebhookValidator) ValidateSignature(payload []byte, signature string) error {
+	if wv.secret == "" {
+		return fmt.Errorf("webhook secret not configured")
+	}
+
+	if signature == "" {
+		return fmt.Errorf("missing X-Hub-Signature header")
+	}
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		return fmt.Errorf("invalid signature format, expected sha256= prefix")
+	}
+
+	mac := hmac.New(sha256.New, []byte(wv.secret))
+	mac.Write(payload)
+	expectedSignature := hex.EncodeToString(mac.Sum(nil))
+
+	// Use constant-time comparison to prevent timing attacks
+	if !hmac.Equal([]byte(expectedSignature), []byte(strings.TrimPrefix(signature, "sha256="))) {
+		return fmt.Errorf("signature validation failed")
+	}
+
+	return nil
+}
+
+// IsDuplicate checks if a delivery ID has been processed before
+func (wv *WebhookValidator) IsDuplicate(deliveryID string) bool {
+	if deliveryID == "" {
+		return false
+	}
+
+	wv.deliveryMutex.RLock()
+	_, exists := wv.deliveries[deliveryID]
+	wv.deliveryMutex.RUnlock()
+
+	return exists
+}
+
+// MarkDelivery marks a delivery ID as processed
+func (wv *WebhookValidator) MarkDelivery(deliveryID string) {
+	if deliveryID == "" {
+		return
+	}
+
+	wv.deliveryMutex.Lock()
+	defer wv.deliveryMutex.Unlock()
+
+	now := time.Now()
+	wv.deliveries[deliveryID] = now
+
+	// Clean up old entries
+	cutoff := now.Add(-wv.maxAge)
+	for id, timestamp := range wv.deliveries {
+		if timestamp.Before(cutoff) {
+			delete(wv.deliveries, id)
+		}
+	}
+}
+
+// ValidateAndProcess validates webhook signature and checks for duplicates
+func (wv *WebhookValidator) ValidateAndProcess(r *http.Request, payload []byte) error {
+	signature := r.Header.Get("X-Hub-Signature")
+	deliveryID := DeliveryID(r)
+
+	if err := wv.ValidateSignature(payload, signature); err != nil {
+		return fmt.Errorf("signature validation failed: %w", err)
+	}
+
+	if wv.IsDuplicate(deliveryID) {
+		return fmt.Errorf("duplicate delivery ID: %s", deliveryID)
+	}
+
+	wv.MarkDelivery(deliveryID)
+
+	return nil
+}
+
+// EventKey returns the webhook event key, e.g. pullrequest:fulfilled (Cloud) or pr:merged (Server)
+func EventKey(r *http.Request) string {
+	return r.Header.Get("X-Event-Key")
+}
+
+// DeliveryID returns the webhook delivery ID (X-Request-UUID on Cloud, X-Request-Id on Server)
+func DeliveryID(r *http.Request) string {
+	if id := r.Header.Get("X-Request-UUID"); id != "" {
+		return id
+	}
+	return r.Header.Get("X-Request-Id")
+}
@@ -0,0 +1,68 @@
+package bitbucket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookValidatorValidateAndProcess(t *testing.T) {
+	secret := "test-secret"
+	payload := []byte(`{"pullrequest":{"id":7}}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	validator := NewWebhookValidator(secret)
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		expectErr string
+	}{
+		{
+			name:    "valid Cloud delivery",
+			headers: map[string]string{"X-Hub-Signature": signature, "X-Request-UUID": "cloud-1"},
+		},
+		{
+			name:    "valid Server delivery",
+			headers: map[string]string{"X-Hub-Signature": signature, "X-Request-Id": "server-1"},
+		},
+		{
+			name:      "duplicate delivery",
+			headers:   map[string]string{"X-Hub-Signature": signature, "X-Request-UUID": "cloud-1"},
+			expectErr: "duplicate delivery ID",
+		},
+		{
+			name:      "missing prefix",
+			headers:   map[string]string{"X-Hub-Signature": strings.TrimPrefix(signature, "sha256="), "X-Request-UUID": "cloud-2"},
+			expectErr: "expected sha256= prefix",
+		},
+		{
+			name:      "invalid signature",
+			headers:   map[string]string{"X-Hub-Signature": "sha256=deadbeef", "X-Request-UUID": "cloud-3"},
+			expectErr: "signature validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhook/bitbucket", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			err := validator.ValidateAndProcess(req, payload)
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ type DaemonService struct {
 	notificationSvc  *NotificationService
 	schedulerSvc     *SchedulerService
 	orchestrationSvc *OrchestrationService
+	stalePRs         *StalePRMonitor
 
 	// Internal state
 	running bool
@@ -149,6 +150,33 @@ func (d *DaemonService) SendNotification(event types.NotificationEvent) error {
 	}
 }
 
+// SetStalePRMonitor enables the scheduled stale pull request digest
+func (d *DaemonService) SetStalePRMonitor(monitor *StalePRMonitor) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stalePRs = monitor
+}
+
+// MaxSnoozeDuration is the longest a pull request can be left out of stale digests at once
+const MaxSnoozeDuration = 30 * 24 * time.Hour
+
+// SnoozePullRequest leaves a pull request out of stale digests for a while, up to
+// MaxSnoozeDuration
+func (d *DaemonService) SnoozePullRequest(repository string, number int, duration time.Duration) error {
+	if duration <= 0 || duration > MaxSnoozeDuration {
+		return fmt.Errorf("snooze duration must be positive and at most %s, got %s", MaxSnoozeDuration, duration)
+	}
+
+	d.mu.RLock()
+	monitor := d.stalePRs
+	d.mu.RUnlock()
+
+	if monitor == nil {
+		return fmt.Errorf("stale pull request digest is not enabled")
+	}
+	return monitor.Snooze(repository, number, time.Now().Add(duration))
+}
+
 // OrchestrateTool adds an orchestration task to the queue
 func (d *DaemonService) OrchestrateTool(task types.OrchestrationTask) error {
 	if task.ID == "" {
@@ -319,8 +347,14 @@ func (d *DaemonService) processAnalysisRequest(req types.AnalysisRequest) {
 func (d *DaemonService) processNotificationEvent(event types.NotificationEvent) {
 	log.Printf("📤 Sending %s notification: %s", event.Type, event.Subject)
 
-	// TODO: Implement actual notification sending
-	// This will use the notification service to send via Discord/WhatsApp/Email
+	if d.notificationSvc == nil {
+		log.Printf("⚠️ No notification service configured, dropping: %s", event.Subject)
+		return
+	}
+	if err := d.notificationSvc.SendNotification(d.ctx, event); err != nil {
+		log.Printf("❌ Failed to send %s notification: %v", event.Type, err)
+		return
+	}
 
 	log.Printf("✅ Notification sent: %s", event.Type)
 }
@@ -349,4 +383,34 @@ func (d *DaemonService) performHealthCheck() {
 func (d *DaemonService) checkScheduledTasks() {
 	// TODO: Implement scheduled task checking
 	// This will check the scheduler service for tasks that need to be executed
+
+	d.mu.RLock()
+	monitor := d.stalePRs
+	d.mu.RUnlock()
+
+	if monitor == nil {
+		return
+	}
+	due, err := monitor.Due(time.Now())
+	if err != nil {
+		log.Printf("⚠️ Failed to record stale PR digest time: %v", err)
+	}
+	if due {
+		d.sendStalePRDigest(monitor)
+	}
+}
+
+// sendStalePRDigest queues one stale pull request digest per team
+func (d *DaemonService) sendStalePRDigest(monitor *StalePRMonitor) {
+	events, err := monitor.Digest(d.ctx, time.Now())
+	if err != nil {
+		log.Printf("⚠️ Stale PR digest incomplete: %v", err)
+	}
+
+	for _, event := range events {
+		if err := d.SendNotification(event); err != nil {
+			log.Printf("❌ Failed to queue stale PR digest: %v", err)
+		}
+	}
+	log.Printf("📬 Stale PR digest: %d notifications queued", len(events))
 }
@@ -0,0 +1,62 @@
+// Package gitea provides a Gitea/Forgejo REST client with cache, retry, and rate limiting.
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kubex-ecosystem/analyzer/internal/services/restclient"
+	"golang.org/x/time/rate"
+)
+
+// Client provides a Gitea/Forgejo REST API v1 client
+type Client struct {
+	*restclient.Client
+	config *Config
+}
+
+// NewClient creates a new Gitea client
+func NewClient(config *Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var rateLimit rate.Limit
+	if config.EnableRateLimit {
+		// Gitea and Forgejo do not rate limit by default, but instances are usually
+		// small self-hosted servers, so keep the request rate modest
+		rateLimit = 10
+	}
+
+	return &Client{
+		Client: restclient.New(restclient.Options{
+			Name:           "Gitea",
+			BaseURL:        config.BaseURL,
+			UserAgent:      config.UserAgent,
+			Timeout:        config.Timeout,
+			MaxRetries:     config.MaxRetries,
+			RetryBackoff:   config.GetRetryBackoff(),
+			CacheTTL:       config.GetCacheTTL(),
+			RateLimit:      rateLimit,
+			RateLimitBurst: config.RateLimitBurst,
+			Authorize: func(req *http.Request) {
+				req.Header.Set("Authorization", "token "+config.Token)
+			},
+			ErrorMessage: errorMessage,
+		}),
+		config: config,
+	}, nil
+}
+
+// errorMessage extracts the message of a Gitea error response
+func errorMessage(body []byte) string {
+	var apiError struct {
+		Message string `json:"message"`
+		URL     string `json:"url"`
+	}
+	if err := json.Unmarshal(body, &apiError); err != nil {
+		return ""
+	}
+	return apiError.Message
+}
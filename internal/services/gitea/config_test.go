@@ -0,0 +1,51 @@
+package gitea
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "test-token")
+	t.Setenv("GITEA_BASE_URL", "https://git.example.com/api/v1")
+	t.Setenv("GITEA_PAGE_SIZE", "20")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if config.Token != "test-token" {
+		t.Errorf("Expected Token to be 'test-token', got %s", config.Token)
+	}
+
+	if config.Host() != "git.example.com" {
+		t.Errorf("Expected Host to be 'git.example.com', got %s", config.Host())
+	}
+
+	if config.PageSize != 20 {
+		t.Errorf("Expected PageSize to be 20, got %d", config.PageSize)
+	}
+
+	if config.Timeout != 30*time.Second {
+		t.Errorf("Expected default Timeout to be 30s, got %v", config.Timeout)
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
+
+func TestConfigValidateRequiresBaseURL(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "test-token")
+	t.Setenv("GITEA_BASE_URL", "")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if err := config.Validate(); err == nil {
+		t.Error("Expected error when GITEA_BASE_URL is not set")
+	}
+}
@@ -0,0 +1,546 @@
+// Package gitea provides a service wrapper that implements the metrics data interfaces.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// Service provides Gitea/Forgejo data access for DORA metrics
+type Service struct {
+	client   *Client
+	host     string
+	pageSize int
+}
+
+// NewService creates a new Gitea service
+func NewService(config *Config) (*Service, error) {
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gitea client: %w", err)
+	}
+
+	return &Service{
+		client:   client,
+		host:     config.Host(),
+		pageSize: config.PageSize,
+	}, nil
+}
+
+// NewServiceFromEnv creates a new Gitea service using environment configuration
+func NewServiceFromEnv() (*Service, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return NewService(config)
+}
+
+// Host returns the Gitea instance host this service talks to
+func (s *Service) Host() string {
+	return s.host
+}
+
+// GetClient returns the underlying Gitea client
+func (s *Service) GetClient() *Client {
+	return s.client
+}
+
+// GetPullRequests implements the metrics.GitHubClient interface
+func (s *Service) GetPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls?state=all&sort=recentupdate&limit=%d", owner, repo, s.pageSize)
+
+	var allPRs []metrics.PullRequest
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull requests: %w", err)
+		}
+
+		var giteaPRs []GiteaPullRequest
+		if err := json.Unmarshal(data, &giteaPRs); err != nil {
+			return nil, fmt.Errorf("failed to parse pull requests: %w", err)
+		}
+
+		if len(giteaPRs) == 0 {
+			break
+		}
+
+		reachedSince := false
+		for _, gpr := range giteaPRs {
+			// Results are sorted by most recently updated
+			if gpr.UpdatedAt.Before(since) {
+				reachedSince = true
+				break
+			}
+
+			pr := metrics.PullRequest{
+				Number:     gpr.Number,
+				Title:      gpr.Title,
+				State:      gpr.State,
+				CreatedAt:  gpr.CreatedAt,
+				UpdatedAt:  gpr.UpdatedAt,
+				MergedAt:   gpr.MergedAt,
+				ClosedAt:   gpr.ClosedAt,
+				HeadBranch: gpr.Head.Ref,
+			}
+			if gpr.Merged {
+				pr.State = "merged"
+				pr.MergeCommitSHA = gpr.MergeCommitSHA
+			}
+			for _, label := range gpr.Labels {
+				pr.Labels = append(pr.Labels, label.Name)
+			}
+
+			reviews, err := s.GetPullRequestReviews(ctx, owner, repo, gpr.Number)
+			if err == nil {
+				pr.FirstReviewAt, pr.ApprovedAt = reviewTimes(reviews, gpr.User.ID)
+			}
+
+			commits, err := s.getPullRequestCommits(ctx, owner, repo, gpr.Number)
+			if err == nil {
+				pr.Commits = len(commits)
+				for i := range commits {
+					authored := commits[i].Commit.Author.Date
+					if !authored.IsZero() && (pr.FirstCommitAt == nil || authored.Before(*pr.FirstCommitAt)) {
+						pr.FirstCommitAt = &commits[i].Commit.Author.Date
+					}
+				}
+			}
+
+			allPRs = append(allPRs, pr)
+		}
+
+		page++
+		if reachedSince || len(giteaPRs) < s.pageSize {
+			break
+		}
+	}
+
+	return allPRs, nil
+}
+
+// GetDeployments implements the metrics.GitHubClient interface. Gitea has no deployments
+// API, so published releases are treated as production deployments and pre-releases as
+// deployments to a "prerelease" environment.
+func (s *Service) GetDeployments(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Deployment, error) {
+	releases, err := s.GetReleases(ctx, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+
+	tagSHAs, err := s.getTagSHAs(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []metrics.Deployment
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+
+		environment := "production"
+		if release.Prerelease {
+			environment = "prerelease"
+		}
+
+		deployments = append(deployments, metrics.Deployment{
+			ID:          release.ID,
+			Environment: environment,
+			State:       "success",
+			CreatedAt:   release.PublishedAt,
+			UpdatedAt:   release.PublishedAt,
+			SHA:         tagSHAs[release.TagName],
+		})
+	}
+
+	return deployments, nil
+}
+
+// GetWorkflowRuns implements the metrics.GitHubClient interface using Gitea Actions tasks
+func (s *Service) GetWorkflowRuns(ctx context.Context, owner, repo string, since time.Time) ([]metrics.WorkflowRun, error) {
+	path := fmt.Sprintf("/repos/%s/%s/actions/tasks?limit=%d", owner, repo, s.pageSize)
+
+	var runs []metrics.WorkflowRun
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get action tasks: %w", err)
+		}
+
+		var response GiteaActionTaskResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse action tasks: %w", err)
+		}
+
+		if len(response.WorkflowRuns) == 0 {
+			break
+		}
+
+		reachedSince := false
+		for _, task := range response.WorkflowRuns {
+			// Tasks are returned newest first
+			if task.CreatedAt.Before(since) {
+				reachedSince = true
+				break
+			}
+
+			status, conclusion := taskStatus(task.Status)
+			run := metrics.WorkflowRun{
+				ID:         task.ID,
+				Name:       task.Name,
+				Status:     status,
+				Conclusion: conclusion,
+				Event:      task.Event,
+				CreatedAt:  task.CreatedAt,
+				UpdatedAt:  task.UpdatedAt,
+				SHA:        task.HeadSHA,
+			}
+			if !task.RunStartedAt.IsZero() {
+				startedAt := task.RunStartedAt
+				run.StartedAt = &startedAt
+			}
+			runs = append(runs, run)
+		}
+
+		page++
+		if reachedSince || len(response.WorkflowRuns) < s.pageSize {
+			break
+		}
+	}
+
+	return runs, nil
+}
+
+// GetCommitsBetween implements the metrics.CommitRangeClient interface using the compare API.
+// Merge commits are skipped so lead time reflects authored changes.
+func (s *Service) GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]metrics.Commit, error) {
+	path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", owner, repo, url.PathEscape(base), url.PathEscape(head))
+
+	data, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	var comparison GiteaComparison
+	if err := json.Unmarshal(data, &comparison); err != nil {
+		return nil, fmt.Errorf("failed to parse comparison: %w", err)
+	}
+
+	var commits []metrics.Commit
+	for _, gc := range comparison.Commits {
+		if len(gc.Parents) > 1 {
+			continue
+		}
+		commits = append(commits, metrics.Commit{
+			SHA:     gc.SHA,
+			Message: gc.Commit.Message,
+			Author:  gc.Commit.Author.Name,
+			Date:    gc.Commit.Author.Date,
+		})
+	}
+
+	return commits, nil
+}
+
+// GetPullRequestReviews lists the reviews of a pull request
+func (s *Service) GetPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]GiteaReview, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews?limit=%d", owner, repo, number, s.pageSize)
+
+	var reviews []GiteaReview
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request reviews: %w", err)
+		}
+
+		var pageReviews []GiteaReview
+		if err := json.Unmarshal(data, &pageReviews); err != nil {
+			return nil, fmt.Errorf("failed to parse pull request reviews: %w", err)
+		}
+
+		reviews = append(reviews, pageReviews...)
+
+		page++
+		if len(pageReviews) < s.pageSize {
+			break
+		}
+	}
+
+	return reviews, nil
+}
+
+// GetReleases lists the releases published since the given time
+func (s *Service) GetReleases(ctx context.Context, owner, repo string, since time.Time) ([]GiteaRelease, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases?limit=%d", owner, repo, s.pageSize)
+
+	var releases []GiteaRelease
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get releases: %w", err)
+		}
+
+		var pageReleases []GiteaRelease
+		if err := json.Unmarshal(data, &pageReleases); err != nil {
+			return nil, fmt.Errorf("failed to parse releases: %w", err)
+		}
+
+		reachedSince := false
+		for _, release := range pageReleases {
+			// Releases are returned newest first
+			if release.PublishedAt.Before(since) {
+				reachedSince = true
+				break
+			}
+			releases = append(releases, release)
+		}
+
+		page++
+		if reachedSince || len(pageReleases) < s.pageSize {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+// getPullRequestCommits lists the commits of a pull request
+func (s *Service) getPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]GiteaCommit, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/commits?stat=false&verification=false&files=false&limit=%d",
+		owner, repo, number, s.pageSize)
+
+	var commits []GiteaCommit
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageCommits []GiteaCommit
+		if err := json.Unmarshal(data, &pageCommits); err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, pageCommits...)
+
+		page++
+		if len(pageCommits) < s.pageSize {
+			break
+		}
+	}
+
+	return commits, nil
+}
+
+// getTagSHAs maps tag names to the commits they point at
+func (s *Service) getTagSHAs(ctx context.Context, owner, repo string) (map[string]string, error) {
+	path := fmt.Sprintf("/repos/%s/%s/tags?limit=%d", owner, repo, s.pageSize)
+
+	shas := make(map[string]string)
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags: %w", err)
+		}
+
+		var tags []GiteaTag
+		if err := json.Unmarshal(data, &tags); err != nil {
+			return nil, fmt.Errorf("failed to parse tags: %w", err)
+		}
+
+		for _, tag := range tags {
+			shas[tag.Name] = tag.Commit.SHA
+		}
+
+		page++
+		if len(tags) < s.pageSize {
+			break
+		}
+	}
+
+	return shas, nil
+}
+
+// reviewTimes finds the first review and first approval left by someone other than the author.
+// Pending reviews are drafts and review requests are not reviews, so both are skipped.
+func reviewTimes(reviews []GiteaReview, authorID int) (*time.Time, *time.Time) {
+	var firstReview, approved *time.Time
+	for i := range reviews {
+		review := reviews[i]
+		if review.User.ID == authorID || review.SubmittedAt.IsZero() {
+			continue
+		}
+		if review.State == "PENDING" || review.State == "REQUEST_REVIEW" {
+			continue
+		}
+
+		if firstReview == nil || review.SubmittedAt.Before(*firstReview) {
+			firstReview = &reviews[i].SubmittedAt
+		}
+		if review.State == "APPROVED" && (approved == nil || review.SubmittedAt.Before(*approved)) {
+			approved = &reviews[i].SubmittedAt
+		}
+	}
+	return firstReview, approved
+}
+
+// taskStatus maps Gitea Actions task statuses to workflow run status and conclusion
+func taskStatus(status string) (string, string) {
+	switch status {
+	case "success":
+		return "completed", "success"
+	case "failure":
+		return "completed", "failure"
+	case "cancelled":
+		return "completed", "cancelled"
+	case "skipped":
+		return "completed", "skipped"
+	case "running":
+		return "in_progress", ""
+	default: // waiting, blocked, unknown
+		return "queued", ""
+	}
+}
+
+// Gitea API types
+
+// GiteaUser represents a Gitea user
+type GiteaUser struct {
+	ID       int    `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+// GiteaLabel represents a Gitea label
+type GiteaLabel struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GiteaPullRequest represents a Gitea pull request
+type GiteaPullRequest struct {
+	ID     int          `json:"id"`
+	Number int          `json:"number"`
+	Title  string       `json:"title"`
+	State  string       `json:"state"` // open, closed
+	User   GiteaUser    `json:"user"`
+	Labels []GiteaLabel `json:"labels"`
+	Head   struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Merged         bool       `json:"merged"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	HTMLURL        string     `json:"html_url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	MergedAt       *time.Time `json:"merged_at"`
+	ClosedAt       *time.Time `json:"closed_at"`
+}
+
+// GiteaReview represents a pull request review
+type GiteaReview struct {
+	ID            int       `json:"id"`
+	User          GiteaUser `json:"user"`
+	State         string    `json:"state"` // APPROVED, PENDING, COMMENT, REQUEST_CHANGES, REQUEST_REVIEW
+	Body          string    `json:"body"`
+	CommentsCount int       `json:"comments_count"`
+	SubmittedAt   time.Time `json:"submitted_at"`
+}
+
+// GiteaCommit represents a Gitea commit
+type GiteaCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string    `json:"name"`
+			Email string    `json:"email"`
+			Date  time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+}
+
+// GiteaComparison represents the result of comparing two refs
+type GiteaComparison struct {
+	TotalCommits int           `json:"total_commits"`
+	Commits      []GiteaCommit `json:"commits"`
+}
+
+// GiteaRelease represents a Gitea release
+type GiteaRelease struct {
+	ID              int       `json:"id"`
+	TagName         string    `json:"tag_name"`
+	TargetCommitish string    `json:"target_commitish"`
+	Name            string    `json:"name"`
+	Draft           bool      `json:"draft"`
+	Prerelease      bool      `json:"prerelease"`
+	CreatedAt       time.Time `json:"created_at"`
+	PublishedAt     time.Time `json:"published_at"`
+}
+
+// GiteaTag represents a Gitea tag
+type GiteaTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA     string    `json:"sha"`
+		Created time.Time `json:"created"`
+	} `json:"commit"`
+}
+
+// GiteaActionTask represents a Gitea Actions task (a single job run)
+type GiteaActionTask struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	RunNumber    int       `json:"run_number"`
+	Event        string    `json:"event"`
+	DisplayTitle string    `json:"display_title"`
+	Status       string    `json:"status"`
+	WorkflowID   string    `json:"workflow_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	RunStartedAt time.Time `json:"run_started_at"`
+}
+
+// GiteaActionTaskResponse represents a page of Gitea Actions tasks
+type GiteaActionTaskResponse struct {
+	WorkflowRuns []GiteaActionTask `json:"workflow_runs"`
+	TotalCount   int               `json:"total_count"`
+}
@@ -0,0 +1,193 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixtureServer serves recorded Gitea API responses from testdata, keyed by request path
+func fixtureServer(t *testing.T, fixtures map[string]string) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "token is required", "url": "https://git.example.com/api/swagger"}`))
+			return
+		}
+
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "The target couldn't be found.", "url": "https://git.example.com/api/swagger"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if fixture == "" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Errorf("failed to read fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewService(&Config{
+		Token:           "test-token",
+		BaseURL:         server.URL + "/api/v1",
+		UserAgent:       "test-agent",
+		Timeout:         5 * time.Second,
+		MaxRetries:      0,
+		RetryBackoffMs:  1,
+		CacheTTLMinutes: 1,
+		PageSize:        50,
+		EnableRateLimit: false,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+	return service
+}
+
+func TestServiceGetPullRequests(t *testing.T) {
+	service := fixtureServer(t, map[string]string{
+		"/api/v1/repos/acme/widgets/pulls":            "pulls.json",
+		"/api/v1/repos/acme/widgets/pulls/42/reviews": "pull_42_reviews.json",
+		"/api/v1/repos/acme/widgets/pulls/42/commits": "pull_42_commits.json",
+		"/api/v1/repos/acme/widgets/pulls/41/reviews": "",
+		"/api/v1/repos/acme/widgets/pulls/41/commits": "",
+	})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prs, err := service.GetPullRequests(context.Background(), "acme", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetPullRequests() failed: %v", err)
+	}
+
+	// PR #30 was last updated before since and must be dropped
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	merged := prs[0]
+	if merged.Number != 42 || merged.State != "merged" {
+		t.Errorf("Expected merged PR #42, got #%d %s", merged.Number, merged.State)
+	}
+	if merged.MergeCommitSHA != "9f1c2ab7e0d35f8a1e3b6c4d2f0a9b8c7d6e5f40" {
+		t.Errorf("Unexpected merge commit SHA: %s", merged.MergeCommitSHA)
+	}
+	if merged.HeadBranch != "feature/widget-cache" || len(merged.Labels) != 1 || merged.Labels[0] != "enhancement" {
+		t.Errorf("Expected head branch and labels to be mapped, got %s %v", merged.HeadBranch, merged.Labels)
+	}
+	if merged.FirstReviewAt == nil || !merged.FirstReviewAt.Equal(time.Date(2024, 3, 4, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first review from bob at 13:00, got %v", merged.FirstReviewAt)
+	}
+	if merged.ApprovedAt == nil || !merged.ApprovedAt.Equal(time.Date(2024, 3, 5, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected approval at 2024-03-05 11:00, got %v", merged.ApprovedAt)
+	}
+	if merged.FirstCommitAt == nil || !merged.FirstCommitAt.Equal(time.Date(2024, 3, 3, 17, 45, 0, 0, time.UTC)) {
+		t.Errorf("Expected first commit at 2024-03-03 17:45, got %v", merged.FirstCommitAt)
+	}
+	if merged.Commits != 2 {
+		t.Errorf("Expected 2 commits, got %d", merged.Commits)
+	}
+
+	open := prs[1]
+	if open.State != "open" || open.MergedAt != nil || open.MergeCommitSHA != "" {
+		t.Errorf("Expected open PR #41 without merge data, got %+v", open)
+	}
+}
+
+func TestServiceGetDeploymentsFromReleases(t *testing.T) {
+	service := fixtureServer(t, map[string]string{
+		"/api/v1/repos/acme/widgets/releases": "releases.json",
+		"/api/v1/repos/acme/widgets/tags":     "tags.json",
+	})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	deployments, err := service.GetDeployments(context.Background(), "acme", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetDeployments() failed: %v", err)
+	}
+
+	// Drafts and releases before since are skipped
+	if len(deployments) != 2 {
+		t.Fatalf("Expected 2 deployments, got %d", len(deployments))
+	}
+
+	if deployments[0].Environment != "prerelease" {
+		t.Errorf("Expected pre-release environment, got %s", deployments[0].Environment)
+	}
+
+	production := deployments[1]
+	if production.Environment != "production" || production.State != "success" {
+		t.Errorf("Unexpected production deployment: %+v", production)
+	}
+	if production.SHA != "9f1c2ab7e0d35f8a1e3b6c4d2f0a9b8c7d6e5f40" {
+		t.Errorf("Expected release tag to resolve to its commit, got %s", production.SHA)
+	}
+}
+
+func TestServiceGetWorkflowRuns(t *testing.T) {
+	service := fixtureServer(t, map[string]string{
+		"/api/v1/repos/acme/widgets/actions/tasks": "action_tasks.json",
+	})
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	runs, err := service.GetWorkflowRuns(context.Background(), "acme", "widgets", since)
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() failed: %v", err)
+	}
+
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 workflow runs, got %d", len(runs))
+	}
+	if runs[0].Name != "test" || runs[0].Status != "completed" || runs[0].Conclusion != "failure" || runs[0].Event != "push" {
+		t.Errorf("Unexpected workflow run mapping: %+v", runs[0])
+	}
+	if runs[0].StartedAt == nil || !runs[0].StartedAt.Equal(time.Date(2024, 3, 5, 16, 0, 15, 0, time.UTC)) {
+		t.Errorf("Expected run started at 16:00:15, got %v", runs[0].StartedAt)
+	}
+	if runs[1].Status != "in_progress" || runs[1].Conclusion != "" {
+		t.Errorf("Expected running task to be in progress, got %+v", runs[1])
+	}
+}
+
+func TestServiceGetCommitsBetween(t *testing.T) {
+	service := fixtureServer(t, map[string]string{
+		"/api/v1/repos/acme/widgets/compare/v1.1.0...v1.2.0": "compare.json",
+	})
+
+	commits, err := service.GetCommitsBetween(context.Background(), "acme", "widgets", "v1.1.0", "v1.2.0")
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() failed: %v", err)
+	}
+
+	// The merge commit is skipped
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+	if commits[0].Author != "Alice" || commits[0].Date.IsZero() {
+		t.Errorf("Unexpected commit mapping: %+v", commits[0])
+	}
+}
+
+func TestServiceUnauthorized(t *testing.T) {
+	service := fixtureServer(t, map[string]string{})
+	service.client.config.Token = "wrong-token"
+
+	if _, err := service.GetPullRequests(context.Background(), "acme", "widgets", time.Now()); err == nil {
+		t.Fatal("Expected error for invalid token")
+	}
+}
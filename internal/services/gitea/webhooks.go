@@ -0,0 +1,132 @@
+// Package gitea provides webhook signature validation for Gitea and Forgejo.
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookValidator handles Gitea webhook signature validation and deduplication
+type WebhookValidator struct {
+	secret string
+
+	// Deduplication tracking
+	deliveryMutex sync.RWMutex
+	deliveries    map[string]time.Time
+	maxAge        time.Duration
+}
+
+// NewWebhookValidator creates a new webhook validator with the given secret
+func NewWebhookValidator(secret string) *WebhookValidator {
+	return &WebhookValidator{
+		secret:     secret,
+		deliveries: make(map[string]time.Time),
+		maxAge:     24 * time.Hour, // Keep delivery IDs for 24 hours
+	}
+}
+
+// ValidateSignature validates the webhook signature. Gitea and Forgejo send the
+// hex HMAC-SHA256 of the body without a prefix.
+func (wv *WebhookValidator) ValidateSignature(payload []byte, signature string) error {
+	if wv.secret == "" {
+		return fmt.Errorf("webhook secret not configured")
+	}
+
+	if signature == "" {
+		return fmt.Errorf("missing X-Gitea-Signature header")
+	}
+
+	mac := hmac.New(sha256.New, []byte(wv.secret))
+	mac.Write(payload)
+	expectedSignature := hex.EncodeToString(mac.Sum(nil))
+
+	// Use constant-time comparison to prevent timing attacks
+	if !hmac.Equal([]byte(expectedSignature), []byte(signature)) {
+		return fmt.Errorf("signature validation failed")
+	}
+
+	return nil
+}
+
+// IsDuplicate checks if a delivery ID has been processed before
+func (wv *WebhookValidator) IsDuplicate(deliveryID string) bool {
+	if deliveryID == "" {
+		return false
+	}
+
+	wv.deliveryMutex.RLock()
+	_, exists := wv.deliveries[deliveryID]
+	wv.deliveryMutex.RUnlock()
+
+	return exists
+}
+
+// MarkDelivery marks a delivery ID as processed
+func (wv *WebhookValidator) MarkDelivery(deliveryID string) {
+	if deliveryID == "" {
+		return
+	}
+
+	wv.deliveryMutex.Lock()
+	defer wv.deliveryMutex.Unlock()
+
+	now := time.Now()
+	wv.deliveries[deliveryID] = now
+
+	// Clean up old entries
+	cutoff := now.Add(-wv.maxAge)
+	for id, timestamp := range wv.deliveries {
+		if timestamp.Before(cutoff) {
+			delete(wv.deliveries, id)
+		}
+	}
+}
+
+// ValidateAndProcess validates webhook signature and checks for duplicates.
+// Forgejo sends X-Forgejo-* headers alongside the Gitea ones.
+func (wv *WebhookValidator) ValidateAndProcess(r *http.Request, payload []byte) error {
+	signature := firstHeader(r, "X-Gitea-Signature", "X-Forgejo-Signature")
+	deliveryID := DeliveryID(r)
+
+	if err := wv.ValidateSignature(payload, signature); err != nil {
+		return fmt.Errorf("signature validation failed: %w", err)
+	}
+
+	if wv.IsDuplicate(deliveryID) {
+		return fmt.Errorf("duplicate delivery ID: %s", deliveryID)
+	}
+
+	wv.MarkDelivery(deliveryID)
+
+	return nil
+}
+
+// EventName returns the webhook event name from Gitea or Forgejo headers
+func EventName(r *http.Request) string {
+	return firstHeader(r, "X-Gitea-Event", "X-Forgejo-Event", "X-Gogs-Event")
+}
+
+// EventType returns the detailed webhook event type (e.g. pull_request_review_approved)
+func EventType(r *http.Request) string {
+	return firstHeader(r, "X-Gitea-Event-Type", "X-Forgejo-Event-Type")
+}
+
+// DeliveryID returns the webhook delivery ID from Gitea or Forgejo headers
+func DeliveryID(r *http.Request) string {
+	return firstHeader(r, "X-Gitea-Delivery", "X-Forgejo-Delivery", "X-Gogs-Delivery")
+}
+
+// firstHeader returns the first non-empty header value
+func firstHeader(r *http.Request, names ...string) string {
+	for _, name := range names {
+		if value := r.Header.Get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
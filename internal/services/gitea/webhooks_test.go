@@ -0,0 +1,68 @@
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookValidatorValidateAndProcess(t *testing.T) {
+	secret := "test-secret"
+	payload := []byte(`{"action":"opened"}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	validator := NewWebhookValidator(secret)
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		expectErr string
+	}{
+		{
+			name:    "valid Gitea delivery",
+			headers: map[string]string{"X-Gitea-Signature": signature, "X-Gitea-Delivery": "d-1"},
+		},
+		{
+			name:    "valid Forgejo delivery",
+			headers: map[string]string{"X-Forgejo-Signature": signature, "X-Forgejo-Delivery": "d-2"},
+		},
+		{
+			name:      "duplicate delivery",
+			headers:   map[string]string{"X-Gitea-Signature": signature, "X-Gitea-Delivery": "d-1"},
+			expectErr: "duplicate delivery ID",
+		},
+		{
+			name:      "invalid signature",
+			headers:   map[string]string{"X-Gitea-Signature": "deadbeef", "X-Gitea-Delivery": "d-3"},
+			expectErr: "signature validation failed",
+		},
+		{
+			name:      "missing signature",
+			headers:   map[string]string{"X-Gitea-Delivery": "d-4"},
+			expectErr: "missing X-Gitea-Signature header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhook/gitea", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			err := validator.ValidateAndProcess(req, payload)
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -109,6 +109,49 @@ func (c *Client) Delete(ctx context.Context, path string, installationID int64)
 	return c.request(ctx, "DELETE", path, nil, installationID)
 }
 
+// GraphQL runs a GraphQL query with retries and rate limiting and decodes its data into result.
+// Partial data is decoded even when the response also carries errors.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, installationID int64, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL query: %w", err)
+	}
+
+	data, err := c.Post(ctx, c.graphQLURL(), body, installationID)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		if len(response.Errors) > 0 {
+			return fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+		}
+		return fmt.Errorf("GraphQL response has no data")
+	}
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		return fmt.Errorf("failed to parse GraphQL data: %w", err)
+	}
+	return nil
+}
+
+// graphQLURL returns the GraphQL endpoint, served at /api/graphql by GitHub Enterprise Server
+func (c *Client) graphQLURL() string {
+	base := strings.TrimSuffix(c.config.BaseURL, "/")
+	if strings.HasSuffix(base, "/api/v3") {
+		return strings.TrimSuffix(base, "/v3") + "/graphql"
+	}
+	return base + "/graphql"
+}
+
 // request performs an HTTP request with all the bells and whistles
 func (c *Client) request(ctx context.Context, method, path string, body []byte, installationID int64) ([]byte, error) {
 	// Check circuit breaker
@@ -164,6 +207,9 @@ func (c *Client) request(ctx context.Context, method, path string, body []byte,
 // doRequest performs a single HTTP request
 func (c *Client) doRequest(ctx context.Context, method, path string, body []byte, installationID int64) ([]byte, error) {
 	url := strings.TrimSuffix(c.config.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		url = path
+	}
 
 	var bodyReader io.Reader
 	if body != nil {
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/types"
 )
 
 func TestService_CreatePR(t *testing.T) {
@@ -566,6 +568,32 @@ func TestService_GetPR(t *testing.T) {
 	}
 }
 
+func TestPRValidationPolicy_WithRecommendedSize(t *testing.T) {
+	batch := &types.BatchSize{RecommendedMaxLines: 400, RecommendedMaxFiles: 12}
+
+	policy := PRValidationPolicy{RequiredReviewers: 1}.WithRecommendedSize(batch)
+	if policy.MaxPRSize != 400 || policy.MaxFilesChanged != 12 {
+		t.Errorf("Expected recommended limits 400 lines / 12 files, got %d / %d", policy.MaxPRSize, policy.MaxFilesChanged)
+	}
+	if policy.RequiredReviewers != 1 {
+		t.Errorf("Expected other rules to be kept, got %d required reviewers", policy.RequiredReviewers)
+	}
+
+	explicit := PRValidationPolicy{MaxPRSize: 250}.WithRecommendedSize(batch)
+	if explicit.MaxPRSize != 250 {
+		t.Errorf("Expected explicit max size to win, got %d", explicit.MaxPRSize)
+	}
+
+	unchanged := PRValidationPolicy{}.WithRecommendedSize(nil)
+	if unchanged.MaxPRSize != 0 || unchanged.MaxFilesChanged != 0 {
+		t.Errorf("Expected no limits without batch-size data, got %+v", unchanged)
+	}
+	unrecommended := PRValidationPolicy{}.WithRecommendedSize(&types.BatchSize{RecommendedMaxFiles: 3})
+	if unrecommended.MaxPRSize != 0 || unrecommended.MaxFilesChanged != 0 {
+		t.Errorf("Expected no limits without a recommendation, got %+v", unrecommended)
+	}
+}
+
 // Helper function for string pointers
 func stringPtr(s string) *string {
 	return &s
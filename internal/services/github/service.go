@@ -5,9 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
 )
 
 // Service provides GitHub integration using the new client architecture
@@ -51,61 +56,192 @@ func (s *Service) GetPullRequests(ctx context.Context, owner, repo string, since
 		}
 	}
 
-	path := fmt.Sprintf("/repos/%s/%s/pulls?state=all&since=%s&sort=updated&direction=desc&per_page=100",
-		owner, repo, since.Format(time.RFC3339))
-
 	var allPRs []metrics.PullRequest
-	page := 1
+	err := s.listPullRequests(ctx, owner, repo, since, installationID, func(recent []GitHubPullRequest) {
+		numbers := make([]int, 0, len(recent))
+		for _, gpr := range recent {
+			numbers = append(numbers, gpr.Number)
+		}
+		// Sizes, reviews and first commits are missing from the list, so they are looked up
+		// in batches rather than per PR. A failed batch leaves its PRs to the fallback below.
+		details, err := s.getPullRequestDetails(ctx, owner, repo, numbers, installationID)
+		if err != nil {
+			log.Printf("⚠️  %s/%s: %v; listing reviews per pull request instead", owner, repo, err)
+		}
 
-	for {
+		for _, gpr := range recent {
+			pr := toMetricsPullRequest(gpr)
+			if detail, ok := details[gpr.Number]; ok {
+				detail.apply(&pr)
+				allPRs = append(allPRs, pr)
+				continue
+			}
+
+			// Sizes, commit counts and first commit stay unknown
+			pr.Incomplete = true
+			if reviews, err := s.listReviews(ctx, owner, repo, gpr.Number, installationID); err == nil {
+				// Review times for cycle time stages
+				pr.FirstReviewAt, pr.ApprovedAt = reviewTimes(reviews)
+				// Without inline comments every approval would look like a rubber stamp
+				if reviewDetails, err := s.getReviewDetails(ctx, owner, repo, gpr.Number, reviews, installationID); err == nil {
+					pr.Reviews = reviewDetails
+				}
+			}
+			allPRs = append(allPRs, pr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allPRs, nil
+}
+
+// ListPullRequests lists the pull requests updated since, as the list endpoint reports them:
+// without sizes, reviews or first commits, for histories too long to look those up
+func (s *Service) ListPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	installationID := s.installationID
+	if installationID == 0 && s.client.auth.IsUsingAppAuth() {
+		var err error
+		installationID, err = s.client.auth.GetInstallationID(owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get installation ID: %w", err)
+		}
+	}
+
+	var prs []metrics.PullRequest
+	err := s.listPullRequests(ctx, owner, repo, since, installationID, func(recent []GitHubPullRequest) {
+		for _, gpr := range recent {
+			prs = append(prs, toMetricsPullRequest(gpr))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// listPullRequests hands visit each page of the pull requests updated since
+func (s *Service) listPullRequests(ctx context.Context, owner, repo string, since time.Time, installationID int64, visit func([]GitHubPullRequest)) error {
+	// The list endpoint has no since filter; pull requests come most recently updated first,
+	// so the first one updated before since ends the listing
+	path := fmt.Sprintf("/repos/%s/%s/pulls?state=all&sort=updated&direction=desc&per_page=100", owner, repo)
+
+	for page := 1; ; page++ {
 		pagePath := fmt.Sprintf("%s&page=%d", path, page)
 		data, err := s.client.Get(ctx, pagePath, installationID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get pull requests: %w", err)
+			return fmt.Errorf("failed to get pull requests: %w", err)
 		}
 
 		var githubPRs []GitHubPullRequest
 		if err := json.Unmarshal(data, &githubPRs); err != nil {
-			return nil, fmt.Errorf("failed to parse pull requests: %w", err)
+			return fmt.Errorf("failed to parse pull requests: %w", err)
 		}
 
-		if len(githubPRs) == 0 {
-			break
+		recent := githubPRs
+		for i, gpr := range githubPRs {
+			if gpr.UpdatedAt.Before(since) {
+				recent = githubPRs[:i]
+				break
+			}
+		}
+		visit(recent)
+
+		if len(recent) < len(githubPRs) || len(githubPRs) < 100 {
+			return nil
+		}
+	}
+}
+
+// GetOpenPullRequests lists open pull requests, including drafts, with their review times and
+// when drafts were marked ready for review
+func (s *Service) GetOpenPullRequests(ctx context.Context, owner, repo string) ([]metrics.PullRequest, error) {
+	installationID := s.installationID
+	if installationID == 0 && s.client.auth.IsUsingAppAuth() {
+		var err error
+		installationID, err = s.client.auth.GetInstallationID(owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get installation ID: %w", err)
 		}
+	}
 
+	path := fmt.Sprintf("/repos/%s/%s/pulls?state=open&sort=updated&direction=asc&per_page=100", owner, repo)
+
+	var openPRs []metrics.PullRequest
+	for page := 1; ; page++ {
+		data, err := s.client.Get(ctx, fmt.Sprintf("%s&page=%d", path, page), installationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open pull requests: %w", err)
+		}
+
+		var githubPRs []GitHubPullRequest
+		if err := json.Unmarshal(data, &githubPRs); err != nil {
+			return nil, fmt.Errorf("failed to parse pull requests: %w", err)
+		}
+
+		// Drafts are not up for review yet
+		var numbers []int
 		for _, gpr := range githubPRs {
-			pr := metrics.PullRequest{
-				Number:       gpr.Number,
-				Title:        gpr.Title,
-				State:        gpr.State,
-				CreatedAt:    gpr.CreatedAt,
-				UpdatedAt:    gpr.UpdatedAt,
-				MergedAt:     gpr.MergedAt,
-				ClosedAt:     gpr.ClosedAt,
-				Commits:      gpr.Commits,
-				Additions:    gpr.Additions,
-				Deletions:    gpr.Deletions,
-				ChangedFiles: gpr.ChangedFiles,
+			if !gpr.Draft {
+				numbers = append(numbers, gpr.Number)
 			}
+		}
+		details, err := s.getPullRequestDetails(ctx, owner, repo, numbers, installationID)
+		if err != nil {
+			log.Printf("⚠️  %s/%s: %v; listing reviews per pull request instead", owner, repo, err)
+		}
 
-			// Get first review time if there are review comments
-			if gpr.ReviewComments > 0 {
-				firstReview, err := s.getFirstReviewTime(ctx, owner, repo, gpr.Number, installationID)
-				if err == nil && firstReview != nil {
-					pr.FirstReviewAt = firstReview
+		for _, gpr := range githubPRs {
+			pr := toMetricsPullRequest(gpr)
+			if detail, ok := details[gpr.Number]; ok {
+				detail.apply(&pr)
+			} else if !pr.Draft {
+				pr.Incomplete = true
+				if reviews, err := s.listReviews(ctx, owner, repo, gpr.Number, installationID); err == nil {
+					pr.FirstReviewAt, pr.ApprovedAt = reviewTimes(reviews)
 				}
 			}
-
-			allPRs = append(allPRs, pr)
+			openPRs = append(openPRs, pr)
 		}
 
-		page++
 		if len(githubPRs) < 100 {
 			break
 		}
 	}
 
-	return allPRs, nil
+	return openPRs, nil
+}
+
+// toMetricsPullRequest converts a GitHub pull request to the metrics model
+func toMetricsPullRequest(gpr GitHubPullRequest) metrics.PullRequest {
+	pr := metrics.PullRequest{
+		Number:       gpr.Number,
+		Title:        gpr.Title,
+		State:        gpr.State,
+		CreatedAt:    gpr.CreatedAt,
+		UpdatedAt:    gpr.UpdatedAt,
+		MergedAt:     gpr.MergedAt,
+		ClosedAt:     gpr.ClosedAt,
+		Commits:      gpr.Commits,
+		Additions:    gpr.Additions,
+		Deletions:    gpr.Deletions,
+		ChangedFiles: gpr.ChangedFiles,
+		HeadBranch:   gpr.Head.Ref,
+		Author:       gpr.User.Login,
+		Draft:        gpr.Draft,
+		URL:          gpr.HTMLURL,
+	}
+	if gpr.MergedAt != nil {
+		pr.MergeCommitSHA = gpr.MergeCommitSHA
+	}
+	for _, label := range gpr.Labels {
+		pr.Labels = append(pr.Labels, label.Name)
+	}
+	for _, reviewer := range gpr.RequestedReviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, reviewer.Login)
+	}
+	return pr
 }
 
 // GetDeployments implements the metrics.GitHubClient interface
@@ -182,18 +318,164 @@ func (s *Service) GetWorkflowRuns(ctx context.Context, owner, repo string, since
 			Status:     gwr.Status,
 			Conclusion: gwr.Conclusion,
 			CreatedAt:  gwr.CreatedAt,
+			StartedAt:  gwr.RunStartedAt,
+			Attempt:    gwr.RunAttempt,
+			Event:      gwr.Event,
 			UpdatedAt:  gwr.UpdatedAt,
 			SHA:        gwr.HeadSHA,
 		}
+		// A rerun keeps the first attempt's creation time; its own jobs tell when it was queued.
+		// Without them the queue time of the rerun is left unknown.
+		if gwr.RunAttempt > 1 {
+			if queuedAt, err := s.getAttemptQueuedAt(ctx, owner, repo, gwr.ID, gwr.RunAttempt, installationID); err == nil {
+				run.QueuedAt = queuedAt
+			}
+		}
 		runs = append(runs, run)
 	}
 
 	return runs, nil
 }
 
-// getFirstReviewTime gets the first review time for a PR
-func (s *Service) getFirstReviewTime(ctx context.Context, owner, repo string, prNumber int, installationID int64) (*time.Time, error) {
-	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", owner, repo, prNumber)
+// getAttemptQueuedAt gets when an attempt of a workflow run was queued: the creation of its first job
+func (s *Service) getAttemptQueuedAt(ctx context.Context, owner, repo string, runID, attempt int, installationID int64) (*time.Time, error) {
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs/%d/attempts/%d/jobs?per_page=100", owner, repo, runID, attempt)
+	data, err := s.client.Get(ctx, path, installationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run attempt jobs: %w", err)
+	}
+
+	var response struct {
+		Jobs []struct {
+			CreatedAt time.Time `json:"created_at"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run attempt jobs: %w", err)
+	}
+
+	var queuedAt *time.Time
+	for _, job := range response.Jobs {
+		if job.CreatedAt.IsZero() {
+			continue
+		}
+		if queuedAt == nil || job.CreatedAt.Before(*queuedAt) {
+			created := job.CreatedAt
+			queuedAt = &created
+		}
+	}
+	if queuedAt == nil {
+		return nil, fmt.Errorf("no jobs in attempt %d of workflow run %d", attempt, runID)
+	}
+	return queuedAt, nil
+}
+
+// GetIssues lists issues (excluding pull requests) updated since the given time, optionally filtered by labels
+func (s *Service) GetIssues(ctx context.Context, owner, repo string, since time.Time, labels []string) ([]GitHubIssue, error) {
+	installationID := s.installationID
+	if installationID == 0 && s.client.auth.IsUsingAppAuth() {
+		var err error
+		installationID, err = s.client.auth.GetInstallationID(owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get installation ID: %w", err)
+		}
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s/issues?state=all&since=%s&per_page=100",
+		owner, repo, since.Format(time.RFC3339))
+	if len(labels) > 0 {
+		path += "&labels=" + url.QueryEscape(strings.Join(labels, ","))
+	}
+
+	var allIssues []GitHubIssue
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath, installationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issues: %w", err)
+		}
+
+		var issues []GitHubIssue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return nil, fmt.Errorf("failed to parse issues: %w", err)
+		}
+
+		if len(issues) == 0 {
+			break
+		}
+
+		for _, issue := range issues {
+			// The issues endpoint also returns pull requests
+			if issue.PullRequest != nil {
+				continue
+			}
+			allIssues = append(allIssues, issue)
+		}
+
+		page++
+		if len(issues) < 100 {
+			break
+		}
+	}
+
+	return allIssues, nil
+}
+
+// GetCommitsBetween implements the metrics.CommitRangeClient interface using the compare API.
+// Merge commits are skipped so lead time reflects authored changes.
+func (s *Service) GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]metrics.Commit, error) {
+	installationID := s.installationID
+	if installationID == 0 && s.client.auth.IsUsingAppAuth() {
+		var err error
+		installationID, err = s.client.auth.GetInstallationID(owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get installation ID: %w", err)
+		}
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s?per_page=100", owner, repo, base, head)
+
+	var commits []metrics.Commit
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath, installationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare commits: %w", err)
+		}
+
+		var comparison GitHubComparison
+		if err := json.Unmarshal(data, &comparison); err != nil {
+			return nil, fmt.Errorf("failed to parse comparison: %w", err)
+		}
+
+		for _, gc := range comparison.Commits {
+			if len(gc.Parents) > 1 {
+				continue
+			}
+			commits = append(commits, metrics.Commit{
+				SHA:     gc.SHA,
+				Message: gc.Commit.Message,
+				Author:  gc.Commit.Author.Name,
+				Date:    gc.Commit.Author.Date,
+			})
+		}
+
+		page++
+		if len(comparison.Commits) < 100 {
+			break
+		}
+	}
+
+	return commits, nil
+}
+
+// listReviews gets the submitted reviews of a PR. Pending reviews are left out.
+func (s *Service) listReviews(ctx context.Context, owner, repo string, prNumber int, installationID int64) ([]GitHubReview, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews?per_page=100", owner, repo, prNumber)
 	data, err := s.client.Get(ctx, path, installationID)
 	if err != nil {
 		return nil, err
@@ -204,11 +486,222 @@ func (s *Service) getFirstReviewTime(ctx context.Context, owner, repo string, pr
 		return nil, err
 	}
 
-	if len(reviews) > 0 {
-		return &reviews[0].SubmittedAt, nil
+	submitted := reviews[:0]
+	for _, review := range reviews {
+		if !review.SubmittedAt.IsZero() {
+			submitted = append(submitted, review)
+		}
+	}
+	return submitted, nil
+}
+
+// reviewTimes returns the first review and first approval times
+func reviewTimes(reviews []GitHubReview) (*time.Time, *time.Time) {
+	var firstReview, approved *time.Time
+	for i := range reviews {
+		review := reviews[i]
+		if firstReview == nil || review.SubmittedAt.Before(*firstReview) {
+			firstReview = &reviews[i].SubmittedAt
+		}
+		if review.State == "APPROVED" && (approved == nil || review.SubmittedAt.Before(*approved)) {
+			approved = &reviews[i].SubmittedAt
+		}
+	}
+	return firstReview, approved
+}
+
+// getReviewDetails attaches inline comment counts and the first comment time to each review
+func (s *Service) getReviewDetails(ctx context.Context, owner, repo string, prNumber int, reviews []GitHubReview, installationID int64) ([]metrics.PullRequestReview, error) {
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/comments?per_page=100", owner, repo, prNumber)
+	var comments []GitHubReviewComment
+	for page := 1; ; page++ {
+		data, err := s.client.Get(ctx, fmt.Sprintf("%s&page=%d", path, page), installationID)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageComments []GitHubReviewComment
+		if err := json.Unmarshal(data, &pageComments); err != nil {
+			return nil, err
+		}
+		comments = append(comments, pageComments...)
+
+		if len(pageComments) < 100 {
+			break
+		}
+	}
+
+	counts := make(map[int]int)
+	started := make(map[int]time.Time)
+	for _, comment := range comments {
+		counts[comment.PullRequestReviewID]++
+		if first, ok := started[comment.PullRequestReviewID]; !ok || comment.CreatedAt.Before(first) {
+			started[comment.PullRequestReviewID] = comment.CreatedAt
+		}
+	}
+
+	details := make([]metrics.PullRequestReview, 0, len(reviews))
+	for _, review := range reviews {
+		detail := metrics.PullRequestReview{
+			Reviewer:    review.User.Login,
+			State:       review.State,
+			SubmittedAt: review.SubmittedAt,
+			Comments:    counts[review.ID],
+			BodyLength:  len(strings.TrimSpace(review.Body)),
+		}
+		if first, ok := started[review.ID]; ok {
+			detail.StartedAt = &first
+		}
+		details = append(details, detail)
 	}
+	return details, nil
+}
+
+// pullRequestDetailsBatchSize is the number of pull requests looked up per GraphQL query
+const pullRequestDetailsBatchSize = 50
+
+// pullRequestDetailsFields are the GraphQL fields of a pull request that the REST list omits.
+// Commits and review comments are listed oldest first.
+const pullRequestDetailsFields = `additions deletions changedFiles
+commits(first: 1) { totalCount nodes { commit { authoredDate } } }
+reviews(first: 100) { nodes { databaseId state submittedAt body author { login } comments(first: 1) { totalCount nodes { createdAt } } } }
+timelineItems(itemTypes: [READY_FOR_REVIEW_EVENT], last: 1) { nodes { ... on ReadyForReviewEvent { createdAt } } }`
+
+// pullRequestDetails holds the sizes, submitted reviews, first commit and last ready for
+// review time of a pull request
+type pullRequestDetails struct {
+	additions        int
+	deletions        int
+	changedFiles     int
+	commits          int
+	firstCommitAt    *time.Time
+	readyForReviewAt *time.Time
+	reviews          []GitHubReview
+	reviewDetails    []metrics.PullRequestReview
+}
+
+// apply fills the fields of pr the REST list leaves out
+func (d pullRequestDetails) apply(pr *metrics.PullRequest) {
+	pr.Additions = d.additions
+	pr.Deletions = d.deletions
+	pr.ChangedFiles = d.changedFiles
+	pr.Commits = d.commits
+	pr.FirstCommitAt = d.firstCommitAt
+	pr.ReadyForReviewAt = d.readyForReviewAt
+	pr.FirstReviewAt, pr.ApprovedAt = reviewTimes(d.reviews)
+	pr.Reviews = d.reviewDetails
+}
+
+// getPullRequestDetails gets the sizes, submitted reviews and first commit of each pull request,
+// keyed by number. Partial results are returned along with the error of a failed batch.
+func (s *Service) getPullRequestDetails(ctx context.Context, owner, repo string, numbers []int, installationID int64) (map[int]pullRequestDetails, error) {
+	details := make(map[int]pullRequestDetails)
+	for start := 0; start < len(numbers); start += pullRequestDetailsBatchSize {
+		end := start + pullRequestDetailsBatchSize
+		if end > len(numbers) {
+			end = len(numbers)
+		}
+
+		// One aliased field per pull request
+		var fields strings.Builder
+		for _, number := range numbers[start:end] {
+			fmt.Fprintf(&fields, "pr%d: pullRequest(number: %d) { %s }\n", number, number, pullRequestDetailsFields)
+		}
+		query := "query($owner: String!, $name: String!) {\nrepository(owner: $owner, name: $name) {\n" + fields.String() + "}\n}"
+
+		var response struct {
+			Repository map[string]*struct {
+				Additions    int `json:"additions"`
+				Deletions    int `json:"deletions"`
+				ChangedFiles int `json:"changedFiles"`
+				Commits      struct {
+					TotalCount int `json:"totalCount"`
+					Nodes      []struct {
+						Commit struct {
+							AuthoredDate time.Time `json:"authoredDate"`
+						} `json:"commit"`
+					} `json:"nodes"`
+				} `json:"commits"`
+				Reviews struct {
+					Nodes []struct {
+						DatabaseID  int        `json:"databaseId"`
+						State       string     `json:"state"`
+						SubmittedAt *time.Time `json:"submittedAt"`
+						Body        string     `json:"body"`
+						Author      *struct {
+							Login string `json:"login"`
+						} `json:"author"`
+						Comments struct {
+							TotalCount int `json:"totalCount"`
+							Nodes      []struct {
+								CreatedAt time.Time `json:"createdAt"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviews"`
+				TimelineItems struct {
+					Nodes []struct {
+						CreatedAt *time.Time `json:"createdAt"`
+					} `json:"nodes"`
+				} `json:"timelineItems"`
+			} `json:"repository"`
+		}
+		variables := map[string]interface{}{"owner": owner, "name": repo}
+		if err := s.client.GraphQL(ctx, query, variables, installationID, &response); err != nil {
+			return details, fmt.Errorf("failed to get pull request details: %w", err)
+		}
+
+		for alias, pr := range response.Repository {
+			number, err := strconv.Atoi(strings.TrimPrefix(alias, "pr"))
+			if err != nil || pr == nil {
+				continue
+			}
+
+			detail := pullRequestDetails{
+				additions:    pr.Additions,
+				deletions:    pr.Deletions,
+				changedFiles: pr.ChangedFiles,
+				commits:      pr.Commits.TotalCount,
+			}
+			if len(pr.Commits.Nodes) > 0 && !pr.Commits.Nodes[0].Commit.AuthoredDate.IsZero() {
+				authored := pr.Commits.Nodes[0].Commit.AuthoredDate
+				detail.firstCommitAt = &authored
+			}
+			if nodes := pr.TimelineItems.Nodes; len(nodes) > 0 && nodes[0].CreatedAt != nil {
+				detail.readyForReviewAt = nodes[0].CreatedAt
+			}
 
-	return nil, nil
+			// Pending reviews have no submission time and are left out
+			for _, node := range pr.Reviews.Nodes {
+				if node.SubmittedAt == nil {
+					continue
+				}
+				review := GitHubReview{ID: node.DatabaseID, State: node.State, SubmittedAt: *node.SubmittedAt, Body: node.Body}
+				if node.Author != nil {
+					review.User.Login = node.Author.Login
+				}
+				reviewDetail := metrics.PullRequestReview{
+					Reviewer:    review.User.Login,
+					State:       review.State,
+					SubmittedAt: review.SubmittedAt,
+					Comments:    node.Comments.TotalCount,
+					BodyLength:  len(strings.TrimSpace(review.Body)),
+				}
+				if len(node.Comments.Nodes) > 0 {
+					started := node.Comments.Nodes[0].CreatedAt
+					reviewDetail.StartedAt = &started
+				}
+				detail.reviews = append(detail.reviews, review)
+				detail.reviewDetails = append(detail.reviewDetails, reviewDetail)
+			}
+			details[number] = detail
+		}
+	}
+	return details, nil
 }
 
 // GetRepository gets repository information
@@ -309,6 +802,17 @@ func (s *Service) SetInstallationID(id int64) {
 	s.installationID = id
 }
 
+// GetAuthToken returns the token of the configured installation, or the personal access token,
+// for clients calling the API on their own such as metrics.GraphQLClient
+func (s *Service) GetAuthToken() (string, error) {
+	return s.client.auth.GetAuthToken(s.installationID)
+}
+
+// GraphQLURL returns the GraphQL endpoint of the configured GitHub host
+func (s *Service) GraphQLURL() string {
+	return s.client.graphQLURL()
+}
+
 // PR Operations for Checkpoint 3
 
 // CreatePRRequest represents a request to create a pull request
@@ -353,6 +857,21 @@ type PRValidationPolicy struct {
 	BlockedLabels        []string `json:"blocked_labels"`
 }
 
+// WithRecommendedSize fills MaxPRSize and MaxFilesChanged, when unset, from the
+// size limits recommended by the repository's batch-size metrics, if any
+func (p PRValidationPolicy) WithRecommendedSize(batch *types.BatchSize) PRValidationPolicy {
+	if batch == nil || batch.RecommendedMaxLines <= 0 {
+		return p
+	}
+	if p.MaxPRSize == 0 {
+		p.MaxPRSize = batch.RecommendedMaxLines
+	}
+	if p.MaxFilesChanged == 0 {
+		p.MaxFilesChanged = batch.RecommendedMaxFiles
+	}
+	return p
+}
+
 // CreatePR creates a new pull request
 func (s *Service) CreatePR(ctx context.Context, owner, repo string, request CreatePRRequest) (*GitHubPullRequest, error) {
 	installationID := s.installationID
@@ -853,8 +1372,15 @@ type GitHubPullRequest struct {
 	Deletions      int        `json:"deletions"`
 	ChangedFiles   int        `json:"changed_files"`
 	ReviewComments int        `json:"review_comments"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	User           GitHubUser `json:"user"`
+	Draft          bool       `json:"draft"`
+	HTMLURL        string     `json:"html_url"`
+	RequestedReviewers []GitHubUser `json:"requested_reviewers"`
+	Labels         []GitHubLabel `json:"labels"`
 	Head           struct {
 		SHA string `json:"sha"`
+		Ref string `json:"ref"`
 	} `json:"head"`
 }
 
@@ -877,6 +1403,9 @@ type GitHubWorkflowRun struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	HeadSHA    string    `json:"head_sha"`
+	RunStartedAt *time.Time `json:"run_started_at"`
+	RunAttempt   int        `json:"run_attempt"`
+	Event        string     `json:"event"`
 }
 
 // GitHubWorkflowRunsResponse represents the response for workflow runs
@@ -887,9 +1416,19 @@ type GitHubWorkflowRunsResponse struct {
 
 // GitHubReview represents a GitHub pull request review
 type GitHubReview struct {
-	ID          int       `json:"id"`
-	State       string    `json:"state"`
-	SubmittedAt time.Time `json:"submitted_at"`
+	ID          int        `json:"id"`
+	State       string     `json:"state"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	Body        string     `json:"body"`
+	User        GitHubUser `json:"user"`
+}
+
+// GitHubReviewComment represents an inline comment of a pull request review
+type GitHubReviewComment struct {
+	ID                  int        `json:"id"`
+	PullRequestReviewID int        `json:"pull_request_review_id"`
+	User                GitHubUser `json:"user"`
+	CreatedAt           time.Time  `json:"created_at"`
 }
 
 // GitHubRepository represents a GitHub repository
@@ -935,6 +1474,47 @@ type GitHubStatusCheckItem struct {
 	Description string `json:"description"`
 }
 
+// GitHubIssue represents a GitHub issue
+type GitHubIssue struct {
+	Number      int           `json:"number"`
+	Title       string        `json:"title"`
+	State       string        `json:"state"`
+	HTMLURL     string        `json:"html_url"`
+	User        GitHubUser    `json:"user"`
+	Labels      []GitHubLabel `json:"labels"`
+	Comments    int           `json:"comments"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	ClosedAt    *time.Time    `json:"closed_at"`
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request,omitempty"`
+}
+
+// GitHubComparison represents the result of comparing two commits
+type GitHubComparison struct {
+	Status       string         `json:"status"` // ahead, behind, diverged, identical
+	AheadBy      int            `json:"ahead_by"`
+	TotalCommits int            `json:"total_commits"`
+	Commits      []GitHubCommit `json:"commits"`
+}
+
+// GitHubCommit represents a commit in the GitHub REST API
+type GitHubCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string    `json:"name"`
+			Email string    `json:"email"`
+			Date  time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+}
+
 // GitHubLabel represents a GitHub label
 type GitHubLabel struct {
 	ID          int    `json:"id"`
@@ -2,10 +2,16 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
 )
 
 func TestNewService(t *testing.T) {
@@ -139,13 +145,29 @@ func TestServiceGetPullRequestsWithMockServer(t *testing.T) {
 					"updated_at": "2023-01-02T00:00:00Z",
 					"merged_at": null,
 					"closed_at": null,
-					"commits": 3,
-					"additions": 100,
-					"deletions": 50,
-					"changed_files": 5,
-					"review_comments": 0
+					"review_comments": 0,
+					"labels": [{"id": 1, "name": "hotfix"}],
+					"head": {"sha": "abc123", "ref": "hotfix/login-crash"}
 				}
 			]`))
+		} else if r.URL.Path == "/graphql" && r.Method == http.MethodPost {
+			// The list omits sizes and reviews; one query returns them with the first commit
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": {"repository": {
+				"pr1": {
+					"additions": 100, "deletions": 50, "changedFiles": 5,
+					"commits": {"totalCount": 3, "nodes": [{"commit": {"authoredDate": "2022-12-31T09:00:00Z"}}]},
+					"reviews": {"nodes": [
+						{"databaseId": 1, "state": "COMMENTED", "submittedAt": "2023-01-01T06:00:00Z", "body": "", "author": {"login": "alice"},
+						 "comments": {"totalCount": 2, "nodes": [{"createdAt": "2023-01-01T05:00:00Z"}]}},
+						{"databaseId": 2, "state": "APPROVED", "submittedAt": "2023-01-01T12:00:00Z", "body": "LGTM", "author": null,
+						 "comments": {"totalCount": 0, "nodes": []}},
+						{"databaseId": 3, "state": "PENDING", "submittedAt": null, "author": {"login": "carol"},
+						 "comments": {"totalCount": 0, "nodes": []}}
+					]}
+				}
+			}}}`))
 		} else {
 			w.WriteHeader(http.StatusNotFound)
 		}
@@ -171,7 +193,7 @@ func TestServiceGetPullRequestsWithMockServer(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	since := time.Now().AddDate(0, 0, -30) // 30 days ago
+	since := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
 
 	prs, err := service.GetPullRequests(ctx, "test-owner", "test-repo", since)
 	if err != nil {
@@ -193,6 +215,295 @@ func TestServiceGetPullRequestsWithMockServer(t *testing.T) {
 		if pr.State != "open" {
 			t.Errorf("Expected PR state 'open', got %s", pr.State)
 		}
+		if pr.HeadBranch != "hotfix/login-crash" {
+			t.Errorf("Expected head branch 'hotfix/login-crash', got %s", pr.HeadBranch)
+		}
+		if len(pr.Labels) != 1 || pr.Labels[0] != "hotfix" {
+			t.Errorf("Expected labels [hotfix], got %v", pr.Labels)
+		}
+		if pr.FirstReviewAt == nil || !pr.FirstReviewAt.Equal(time.Date(2023, 1, 1, 6, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected first review at 06:00, got %v", pr.FirstReviewAt)
+		}
+		if pr.ApprovedAt == nil || !pr.ApprovedAt.Equal(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected approval at 12:00, got %v", pr.ApprovedAt)
+		}
+		if pr.FirstCommitAt == nil || !pr.FirstCommitAt.Equal(time.Date(2022, 12, 31, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected first commit on 2022-12-31 09:00, got %v", pr.FirstCommitAt)
+		}
+		if pr.Additions != 100 || pr.Deletions != 50 || pr.ChangedFiles != 5 || pr.Commits != 3 {
+			t.Errorf("Expected sizes from GraphQL, got +%d -%d in %d files and %d commits", pr.Additions, pr.Deletions, pr.ChangedFiles, pr.Commits)
+		}
+		if len(pr.Reviews) != 2 {
+			t.Fatalf("Expected 2 submitted reviews, got %+v", pr.Reviews)
+		}
+		if commented := pr.Reviews[0]; commented.Reviewer != "alice" || commented.Comments != 2 ||
+			commented.StartedAt == nil || !commented.StartedAt.Equal(time.Date(2023, 1, 1, 5, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected alice's review started at 05:00 with 2 comments, got %+v", commented)
+		}
+		if approved := pr.Reviews[1]; approved.Reviewer != "" || approved.BodyLength != 4 || approved.StartedAt != nil {
+			t.Errorf("Expected a ghost approval with a 4 character body, got %+v", approved)
+		}
+	}
+}
+
+func TestServiceGetPullRequestsStopsBeforeSince(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	var pages []string
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls":
+			pages = append(pages, r.URL.Query().Get("page"))
+			// A full page, most recently updated first; the last 10 predate since
+			var prs []map[string]interface{}
+			for i := 0; i < 100; i++ {
+				prs = append(prs, map[string]interface{}{
+					"number":     1000 - i,
+					"state":      "closed",
+					"created_at": since.AddDate(0, 0, -200),
+					"updated_at": since.AddDate(0, 0, 90-i),
+				})
+			}
+			json.NewEncoder(w).Encode(prs)
+		case "/graphql":
+			queries++
+			var request struct {
+				Query string `json:"query"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			repository := make(map[string]interface{})
+			for _, alias := range regexp.MustCompile(`pr\d+`).FindAllString(request.Query, -1) {
+				repository[alias] = map[string]interface{}{"additions": 10}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"repository": repository}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             5 * time.Second,
+		RetryBackoffMs:      10,
+		CacheTTLMinutes:     1,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	prs, err := service.GetPullRequests(context.Background(), "test-owner", "test-repo", since)
+	if err != nil {
+		t.Fatalf("GetPullRequests() failed: %v", err)
+	}
+	if len(prs) != 91 {
+		t.Errorf("Expected the 91 pull requests updated since %s, got %d", since.Format("2006-01-02"), len(prs))
+	}
+	if len(pages) != 1 {
+		t.Errorf("Expected paging to stop at the first pull request before since, got pages %v", pages)
+	}
+	for _, pr := range prs {
+		if pr.Additions != 10 {
+			t.Fatalf("Expected every pull request filled in from GraphQL, got %+v", pr)
+		}
+	}
+	if queries != 2 {
+		t.Errorf("Expected 91 pull requests looked up in 2 GraphQL queries, got %d", queries)
+	}
+}
+
+func TestServiceListPullRequests(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/repos/test-owner/test-repo/pulls" {
+			t.Errorf("Expected the list endpoint only, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"number": 2, "state": "open", "user": map[string]interface{}{"login": "alice"}, "updated_at": since.AddDate(0, 0, 1)},
+			{"number": 1, "state": "closed", "user": map[string]interface{}{"login": "bob"}, "updated_at": since.AddDate(0, 0, -1)},
+		})
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             5 * time.Second,
+		RetryBackoffMs:      10,
+		CacheTTLMinutes:     1,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	prs, err := service.ListPullRequests(context.Background(), "test-owner", "test-repo", since)
+	if err != nil {
+		t.Fatalf("ListPullRequests() failed: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 2 || prs[0].Author != "alice" {
+		t.Errorf("Expected alice's pull request updated since %s, got %+v", since.Format("2006-01-02"), prs)
+	}
+}
+
+func TestServiceGetPullRequestsReviewDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls":
+			w.Write([]byte(`[
+				{"number": 1, "title": "Big change", "state": "closed", "user": {"login": "author"},
+				 "created_at": "2023-01-01T00:00:00Z", "updated_at": "2023-01-02T00:00:00Z"}
+			]`))
+		case "/repos/test-owner/test-repo/pulls/1/reviews":
+			w.Write([]byte(`[
+				{"id": 10, "state": "COMMENTED", "submitted_at": "2023-01-01T06:00:00Z", "body": "", "user": {"login": "alice"}},
+				{"id": 11, "state": "APPROVED", "submitted_at": "2023-01-01T12:00:00Z", "body": "LGTM", "user": {"login": "bob"}},
+				{"id": 12, "state": "PENDING", "user": {"login": "carol"}}
+			]`))
+		case "/repos/test-owner/test-repo/pulls/1/comments":
+			// A full first page, so the earliest comment is only on the second
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`[{"id": 200, "pull_request_review_id": 10, "user": {"login": "alice"}, "created_at": "2023-01-01T05:00:00Z"}]`))
+				return
+			}
+			comments := make([]string, 100)
+			for i := range comments {
+				comments[i] = fmt.Sprintf(`{"id": %d, "pull_request_review_id": 10, "user": {"login": "alice"}, "created_at": "2023-01-01T05:30:00Z"}`, 100+i)
+			}
+			w.Write([]byte("[" + strings.Join(comments, ",") + "]"))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             30 * time.Second,
+		MaxRetries:          3,
+		RetryBackoffMs:      1000,
+		CacheTTLMinutes:     15,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	// The GraphQL lookup gets an unparsable body, so reviews fall back to the REST endpoints
+	since := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	prs, err := service.GetPullRequests(context.Background(), "test-owner", "test-repo", since)
+	if err != nil {
+		t.Fatalf("GetPullRequests() failed: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.Author != "author" {
+		t.Errorf("Expected author 'author', got %s", pr.Author)
+	}
+	if !pr.Incomplete {
+		t.Error("Expected the pull request marked incomplete without its sizes")
+	}
+	if len(pr.Reviews) != 2 {
+		t.Fatalf("Expected 2 submitted reviews, got %d", len(pr.Reviews))
+	}
+
+	commented := pr.Reviews[0]
+	if commented.Reviewer != "alice" || commented.Comments != 101 {
+		t.Errorf("Expected alice with 101 comments, got %s with %d", commented.Reviewer, commented.Comments)
+	}
+	if commented.StartedAt == nil || !commented.StartedAt.Equal(time.Date(2023, 1, 1, 5, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected review started at 05:00, got %v", commented.StartedAt)
+	}
+
+	approved := pr.Reviews[1]
+	if approved.Reviewer != "bob" || approved.Comments != 0 || approved.BodyLength != 4 {
+		t.Errorf("Expected bob with no comments and a 4 character body, got %+v", approved)
+	}
+}
+
+func TestServiceGetOpenPullRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/pulls":
+			if r.URL.Query().Get("state") != "open" {
+				t.Errorf("Expected state=open, got %s", r.URL.Query().Get("state"))
+			}
+			w.Write([]byte(`[
+				{"number": 1, "title": "Ready", "state": "open", "draft": false, "user": {"login": "author"},
+				 "html_url": "https://github.com/test-owner/test-repo/pull/1",
+				 "requested_reviewers": [{"login": "alice"}],
+				 "created_at": "2023-01-01T00:00:00Z", "updated_at": "2023-01-02T00:00:00Z"},
+				{"number": 2, "title": "WIP", "state": "open", "draft": true, "user": {"login": "author"},
+				 "created_at": "2023-01-01T00:00:00Z", "updated_at": "2023-01-01T00:00:00Z"}
+			]`))
+		case "/repos/test-owner/test-repo/pulls/1/reviews":
+			w.Write([]byte(`[
+				{"id": 10, "state": "COMMENTED", "submitted_at": "2023-01-01T06:00:00Z", "user": {"login": "alice"}}
+			]`))
+		case "/repos/test-owner/test-repo/pulls/2/reviews":
+			t.Error("Reviews of draft pull requests should not be fetched")
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             30 * time.Second,
+		MaxRetries:          3,
+		RetryBackoffMs:      1000,
+		CacheTTLMinutes:     15,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	prs, err := service.GetOpenPullRequests(context.Background(), "test-owner", "test-repo")
+	if err != nil {
+		t.Fatalf("GetOpenPullRequests() failed: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	ready := prs[0]
+	if ready.Draft || ready.URL != "https://github.com/test-owner/test-repo/pull/1" {
+		t.Errorf("Expected a non-draft pull request with its URL, got %+v", ready)
+	}
+	if len(ready.RequestedReviewers) != 1 || ready.RequestedReviewers[0] != "alice" {
+		t.Errorf("Expected requested reviewer alice, got %v", ready.RequestedReviewers)
+	}
+	if ready.FirstReviewAt == nil || !ready.FirstReviewAt.Equal(time.Date(2023, 1, 1, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first review at 06:00, got %v", ready.FirstReviewAt)
+	}
+
+	if !prs[1].Draft || prs[1].FirstReviewAt != nil {
+		t.Errorf("Expected an unreviewed draft, got %+v", prs[1])
 	}
 }
 
@@ -232,4 +543,196 @@ func TestServiceHelperMethods(t *testing.T) {
 	if client == nil {
 		t.Error("Expected client, got nil")
 	}
-}
\ No newline at end of file
+}
+func TestServiceGetCommitsBetweenWithMockServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/test-owner/test-repo/compare/aaa111...bbb222" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"status": "ahead",
+				"ahead_by": 3,
+				"total_commits": 3,
+				"commits": [
+					{"sha": "c1", "commit": {"message": "Add feature", "author": {"name": "dev", "date": "2023-01-01T10:00:00Z"}}, "parents": [{"sha": "aaa111"}]},
+					{"sha": "c2", "commit": {"message": "Fix tests", "author": {"name": "dev", "date": "2023-01-02T10:00:00Z"}}, "parents": [{"sha": "c1"}]},
+					{"sha": "bbb222", "commit": {"message": "Merge pull request #1", "author": {"name": "dev", "date": "2023-01-03T10:00:00Z"}}, "parents": [{"sha": "aaa111"}, {"sha": "c2"}]}
+				]
+			}`))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             30 * time.Second,
+		MaxRetries:          3,
+		RetryBackoffMs:      1000,
+		CacheTTLMinutes:     15,
+		EnableRateLimit:     false,
+		RateLimitBurst:      100,
+	}
+
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	commits, err := service.GetCommitsBetween(context.Background(), "test-owner", "test-repo", "aaa111", "bbb222")
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() failed: %v", err)
+	}
+
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits without the merge commit, got %d", len(commits))
+	}
+	if commits[0].SHA != "c1" || commits[0].Message != "Add feature" {
+		t.Errorf("Unexpected first commit: %+v", commits[0])
+	}
+	if want := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC); !commits[0].Date.Equal(want) {
+		t.Errorf("Expected author date %v, got %v", want, commits[0].Date)
+	}
+}
+
+func TestServiceGetPullRequestDetailsBatches(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			t.Errorf("Expected the Enterprise Server GraphQL endpoint, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		queries = append(queries, request.Query)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(queries) == 1 {
+			// Pull request 2 was deleted and comes back null with an error
+			w.Write([]byte(`{"data": {"repository": {
+				"pr1": {"additions": 700, "deletions": 20, "changedFiles": 9,
+					"commits": {"totalCount": 4, "nodes": [{"commit": {"authoredDate": "2024-05-01T10:00:00Z"}}]},
+					"reviews": {"nodes": [{"databaseId": 7, "state": "APPROVED", "submittedAt": "2024-05-02T10:00:00Z",
+						"body": "", "author": {"login": "bob"}, "comments": {"totalCount": 0, "nodes": []}}]},
+					"timelineItems": {"nodes": [{"createdAt": "2024-05-02T08:00:00Z"}]}},
+				"pr2": null
+			}}, "errors": [{"message": "Could not resolve to a PullRequest with the number of 2."}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {
+			"pr60": {"additions": 3, "commits": {"totalCount": 0, "nodes": []}, "reviews": {"nodes": []}}
+		}}}`))
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL + "/api/v3",
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             5 * time.Second,
+		RetryBackoffMs:      10,
+		CacheTTLMinutes:     1,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	numbers := make([]int, 60)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+	details, err := service.getPullRequestDetails(context.Background(), "test-owner", "test-repo", numbers, 0)
+	if err != nil {
+		t.Fatalf("getPullRequestDetails() failed: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("Expected 60 pull requests in 2 queries, got %d", len(queries))
+	}
+	if !strings.Contains(queries[0], "pr50: pullRequest(number: 50)") || strings.Contains(queries[0], "pr51:") {
+		t.Errorf("Expected the first query to cover pull requests 1-50, got %s", queries[0])
+	}
+	if _, ok := details[2]; ok || len(details) != 2 {
+		t.Fatalf("Expected details of pull requests 1 and 60 only, got %v", details)
+	}
+
+	var pr metrics.PullRequest
+	details[1].apply(&pr)
+	if pr.Additions != 700 || pr.Deletions != 20 || pr.ChangedFiles != 9 || pr.Commits != 4 {
+		t.Errorf("Unexpected sizes %+v", pr)
+	}
+	if pr.FirstCommitAt == nil || !pr.FirstCommitAt.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the first commit on 2024-05-01 10:00, got %v", pr.FirstCommitAt)
+	}
+	if pr.ApprovedAt == nil || len(pr.Reviews) != 1 || pr.Reviews[0].Reviewer != "bob" {
+		t.Errorf("Expected bob's approval, got %+v", pr.Reviews)
+	}
+	if pr.ReadyForReviewAt == nil || !pr.ReadyForReviewAt.Equal(time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the draft marked ready at 2024-05-02 08:00, got %v", pr.ReadyForReviewAt)
+	}
+	if details[60].firstCommitAt != nil || details[60].readyForReviewAt != nil || details[60].additions != 3 {
+		t.Errorf("Expected pull request 60 without a first commit, got %+v", details[60])
+	}
+}
+
+func TestServiceGetWorkflowRunsQueuedAtOfReruns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/test-owner/test-repo/actions/runs":
+			w.Write([]byte(`{"total_count": 2, "workflow_runs": [
+				{"id": 1, "name": "ci", "status": "completed", "conclusion": "success", "event": "pull_request",
+				 "run_attempt": 1, "created_at": "2024-05-01T10:00:00Z", "run_started_at": "2024-05-01T10:01:00Z",
+				 "updated_at": "2024-05-01T10:11:00Z", "head_sha": "abc"},
+				{"id": 2, "name": "ci", "status": "completed", "conclusion": "success", "event": "schedule",
+				 "run_attempt": 2, "created_at": "2024-05-01T10:00:00Z", "run_started_at": "2024-05-01T11:05:00Z",
+				 "updated_at": "2024-05-01T11:15:00Z", "head_sha": "def"}
+			]}`))
+		case "/repos/test-owner/test-repo/actions/runs/2/attempts/2/jobs":
+			w.Write([]byte(`{"total_count": 2, "jobs": [
+				{"id": 20, "created_at": "2024-05-01T11:02:00Z"},
+				{"id": 21, "created_at": "2024-05-01T11:00:00Z"}
+			]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		PersonalAccessToken: "test-token",
+		BaseURL:             server.URL,
+		APIVersion:          "2022-11-28",
+		UserAgent:           "test-agent",
+		Timeout:             5 * time.Second,
+		RetryBackoffMs:      10,
+		CacheTTLMinutes:     1,
+		RateLimitBurst:      100,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	runs, err := service.GetWorkflowRuns(context.Background(), "test-owner", "test-repo", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 workflow runs, got %d", len(runs))
+	}
+	if runs[0].Event != "pull_request" || runs[0].QueuedAt != nil {
+		t.Errorf("Expected a first attempt triggered by a pull request, got %+v", runs[0])
+	}
+	if runs[1].Attempt != 2 || runs[1].QueuedAt == nil || !runs[1].QueuedAt.Equal(time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the rerun queued with its first job at 11:00, got %v", runs[1].QueuedAt)
+	}
+}
@@ -0,0 +1,66 @@
+// Package gitlab provides a GitLab REST client with cache, retry, and rate limiting.
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kubex-ecosystem/analyzer/internal/services/restclient"
+	"golang.org/x/time/rate"
+)
+
+// Client provides a GitLab REST API v4 client
+type Client struct {
+	*restclient.Client
+	config *Config
+}
+
+// NewClient creates a new GitLab client
+func NewClient(config *Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var rateLimit rate.Limit
+	if config.EnableRateLimit {
+		// GitLab.com allows 2000 authenticated API requests per minute; self-managed
+		// instances are often configured lower, so stay well below that
+		rateLimit = 10
+	}
+
+	return &Client{
+		Client: restclient.New(restclient.Options{
+			Name:           "GitLab",
+			BaseURL:        config.BaseURL,
+			UserAgent:      config.UserAgent,
+			Timeout:        config.Timeout,
+			MaxRetries:     config.MaxRetries,
+			RetryBackoff:   config.GetRetryBackoff(),
+			CacheTTL:       config.GetCacheTTL(),
+			RateLimit:      rateLimit,
+			RateLimitBurst: config.RateLimitBurst,
+			Authorize: func(req *http.Request) {
+				req.Header.Set("PRIVATE-TOKEN", config.Token)
+			},
+			ErrorMessage: errorMessage,
+		}),
+		config: config,
+	}, nil
+}
+
+// errorMessage extracts the message of a GitLab error response, which is either
+// {"message": ...} (string or object) or {"error": ...}
+func errorMessage(body []byte) string {
+	var apiError struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+	if err := json.Unmarshal(body, &apiError); err != nil {
+		return ""
+	}
+	if apiError.Message != nil {
+		return fmt.Sprint(apiError.Message)
+	}
+	return apiError.Error
+}
@@ -0,0 +1,166 @@
+// Package gitlab provides GitLab API integration for GitLab.com and self-managed instances.
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds GitLab integration configuration
+type Config struct {
+	// Personal, project or group access token
+	Token         string `json:"token"`
+	WebhookSecret string `json:"webhook_secret"`
+
+	// API configuration
+	BaseURL         string        `json:"base_url"`
+	UserAgent       string        `json:"user_agent"`
+	Timeout         time.Duration `json:"timeout"`
+	MaxRetries      int           `json:"max_retries"`
+	RetryBackoffMs  int           `json:"retry_backoff_ms"`
+	CacheTTLMinutes int           `json:"cache_ttl_minutes"`
+
+	// Rate limiting
+	EnableRateLimit bool `json:"enable_rate_limit"`
+	RateLimitBurst  int  `json:"rate_limit_burst"`
+}
+
+// LoadConfig loads GitLab configuration from environment variables
+func LoadConfig() (*Config, error) {
+	config := &Config{
+		// Default values
+		BaseURL:         "https://gitlab.com/api/v4",
+		UserAgent:       "GemX-Analyzer/1.0.0",
+		Timeout:         30 * time.Second,
+		MaxRetries:      3,
+		RetryBackoffMs:  1000,
+		CacheTTLMinutes: 15,
+		EnableRateLimit: true,
+		RateLimitBurst:  100,
+	}
+
+	config.Token = os.Getenv("GITLAB_TOKEN")
+	config.WebhookSecret = os.Getenv("GITLAB_WEBHOOK_SECRET")
+
+	// API configuration overrides
+	if baseURL := os.Getenv("GITLAB_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+
+	if userAgent := os.Getenv("GITLAB_USER_AGENT"); userAgent != "" {
+		config.UserAgent = userAgent
+	}
+
+	// Timeout configuration
+	if timeoutStr := os.Getenv("GITLAB_TIMEOUT_SECONDS"); timeoutStr != "" {
+		timeoutSec, err := strconv.Atoi(timeoutStr)
+		if err == nil && timeoutSec > 0 {
+			config.Timeout = time.Duration(timeoutSec) * time.Second
+		}
+	}
+
+	// Retry configuration
+	if retriesStr := os.Getenv("GITLAB_MAX_RETRIES"); retriesStr != "" {
+		retries, err := strconv.Atoi(retriesStr)
+		if err == nil && retries >= 0 {
+			config.MaxRetries = retries
+		}
+	}
+
+	if backoffStr := os.Getenv("GITLAB_RETRY_BACKOFF_MS"); backoffStr != "" {
+		backoff, err := strconv.Atoi(backoffStr)
+		if err == nil && backoff > 0 {
+			config.RetryBackoffMs = backoff
+		}
+	}
+
+	// Cache configuration
+	if ttlStr := os.Getenv("GITLAB_CACHE_TTL_MINUTES"); ttlStr != "" {
+		ttl, err := strconv.Atoi(ttlStr)
+		if err == nil && ttl > 0 {
+			config.CacheTTLMinutes = ttl
+		}
+	}
+
+	// Rate limiting configuration
+	if rateLimitStr := os.Getenv("GITLAB_ENABLE_RATE_LIMIT"); rateLimitStr != "" {
+		config.EnableRateLimit = rateLimitStr == "true"
+	}
+
+	if burstStr := os.Getenv("GITLAB_RATE_LIMIT_BURST"); burstStr != "" {
+		burst, err := strconv.Atoi(burstStr)
+		if err == nil && burst > 0 {
+			config.RateLimitBurst = burst
+		}
+	}
+
+	return config, nil
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	// Validate base URL
+	parsed, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid base URL: missing host")
+	}
+
+	if c.Token == "" {
+		return fmt.Errorf("no authentication configured: need a GitLab access token (GITLAB_TOKEN)")
+	}
+
+	// Validate timeout
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: must be positive")
+	}
+
+	// Validate retry configuration
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("invalid max retries: must be non-negative")
+	}
+	if c.RetryBackoffMs <= 0 {
+		return fmt.Errorf("invalid retry backoff: must be positive")
+	}
+
+	// Validate cache TTL
+	if c.CacheTTLMinutes <= 0 {
+		return fmt.Errorf("invalid cache TTL: must be positive")
+	}
+
+	// Validate rate limit configuration
+	if c.EnableRateLimit && c.RateLimitBurst <= 0 {
+		return fmt.Errorf("invalid rate limit burst: must be positive when rate limiting is enabled")
+	}
+
+	return nil
+}
+
+// Host returns the host name of the GitLab instance, used to route repositories to this backend
+func (c *Config) Host() string {
+	parsed, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// IsSelfManaged returns true if this is a self-managed GitLab instance
+func (c *Config) IsSelfManaged() bool {
+	return c.Host() != "gitlab.com"
+}
+
+// GetCacheTTL returns the cache TTL as a duration
+func (c *Config) GetCacheTTL() time.Duration {
+	return time.Duration(c.CacheTTLMinutes) * time.Minute
+}
+
+// GetRetryBackoff returns the retry backoff as a duration
+func (c *Config) GetRetryBackoff() time.Duration {
+	return time.Duration(c.RetryBackoffMs) * time.Millisecond
+}
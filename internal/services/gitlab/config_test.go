@@ -0,0 +1,96 @@
+package gitlab
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "test-token")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if config.Token != "test-token" {
+		t.Errorf("Expected Token to be 'test-token', got %s", config.Token)
+	}
+
+	if config.BaseURL != "https://gitlab.com/api/v4" {
+		t.Errorf("Expected default BaseURL to be 'https://gitlab.com/api/v4', got %s", config.BaseURL)
+	}
+
+	if config.Timeout != 30*time.Second {
+		t.Errorf("Expected default Timeout to be 30s, got %v", config.Timeout)
+	}
+
+	if config.IsSelfManaged() {
+		t.Error("Expected gitlab.com not to be self-managed")
+	}
+}
+
+func TestLoadConfigSelfManaged(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "test-token")
+	t.Setenv("GITLAB_BASE_URL", "https://git.example.com/api/v4")
+	t.Setenv("GITLAB_MAX_RETRIES", "5")
+	t.Setenv("GITLAB_ENABLE_RATE_LIMIT", "false")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if config.Host() != "git.example.com" {
+		t.Errorf("Expected Host to be 'git.example.com', got %s", config.Host())
+	}
+
+	if !config.IsSelfManaged() {
+		t.Error("Expected self-managed instance")
+	}
+
+	if config.MaxRetries != 5 {
+		t.Errorf("Expected MaxRetries to be 5, got %d", config.MaxRetries)
+	}
+
+	if config.EnableRateLimit {
+		t.Error("Expected rate limiting to be disabled")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Token:           "test-token",
+		BaseURL:         "https://gitlab.com/api/v4",
+		Timeout:         30 * time.Second,
+		MaxRetries:      3,
+		RetryBackoffMs:  1000,
+		CacheTTLMinutes: 15,
+		RateLimitBurst:  100,
+		EnableRateLimit: true,
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(c *Config) {}},
+		{name: "missing token", mutate: func(c *Config) { c.Token = "" }, wantErr: true},
+		{name: "missing host", mutate: func(c *Config) { c.BaseURL = "/api/v4" }, wantErr: true},
+		{name: "invalid timeout", mutate: func(c *Config) { c.Timeout = 0 }, wantErr: true},
+		{name: "invalid burst", mutate: func(c *Config) { c.RateLimitBurst = 0 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.mutate(&config)
+
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
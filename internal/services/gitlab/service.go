@@ -0,0 +1,565 @@
+// Package gitlab provides a service wrapper that implements the metrics data interfaces.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// Service provides GitLab data access for DORA metrics
+type Service struct {
+	client *Client
+	host   string
+}
+
+// NewService creates a new GitLab service
+func NewService(config *Config) (*Service, error) {
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+
+	return &Service{
+		client: client,
+		host:   config.Host(),
+	}, nil
+}
+
+// NewServiceFromEnv creates a new GitLab service using environment configuration
+func NewServiceFromEnv() (*Service, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return NewService(config)
+}
+
+// Host returns the GitLab instance host this service talks to
+func (s *Service) Host() string {
+	return s.host
+}
+
+// GetClient returns the underlying GitLab client
+func (s *Service) GetClient() *Client {
+	return s.client
+}
+
+// GetPullRequests implements the metrics.GitHubClient interface using merge requests
+func (s *Service) GetPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]metrics.PullRequest, error) {
+	path := fmt.Sprintf("/projects/%s/merge_requests?state=all&updated_after=%s&order_by=updated_at&sort=desc&per_page=100",
+		projectID(owner, repo), url.QueryEscape(since.Format(time.RFC3339)))
+
+	var allPRs []metrics.PullRequest
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get merge requests: %w", err)
+		}
+
+		var mergeRequests []GitLabMergeRequest
+		if err := json.Unmarshal(data, &mergeRequests); err != nil {
+			return nil, fmt.Errorf("failed to parse merge requests: %w", err)
+		}
+
+		if len(mergeRequests) == 0 {
+			break
+		}
+
+		for _, mr := range mergeRequests {
+			pr := metrics.PullRequest{
+				Number:         mr.IID,
+				Title:          mr.Title,
+				State:          mrState(mr.State),
+				CreatedAt:      mr.CreatedAt,
+				UpdatedAt:      mr.UpdatedAt,
+				MergedAt:       mr.MergedAt,
+				ClosedAt:       mr.ClosedAt,
+				Labels:         mr.Labels,
+				HeadBranch:     mr.SourceBranch,
+				MergeCommitSHA: firstNonEmpty(mr.SquashCommitSHA, mr.MergeCommitSHA),
+				Author:         mr.Author.Username,
+				Draft:          mr.Draft,
+				URL:            mr.WebURL,
+			}
+			if pr.MergedAt != nil && pr.ClosedAt == nil {
+				pr.ClosedAt = pr.MergedAt
+			}
+
+			// Reviews, review and approval times come from MR notes
+			firstReview, approved, reviews, err := s.getReviews(ctx, owner, repo, mr.IID, mr.Author.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get reviews of merge request !%d: %w", mr.IID, err)
+			}
+			pr.FirstReviewAt = firstReview
+			pr.ApprovedAt = approved
+			pr.Reviews = reviews
+
+			commits, err := s.getMergeRequestCommits(ctx, owner, repo, mr.IID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get commits of merge request !%d: %w", mr.IID, err)
+			}
+			pr.Commits = len(commits)
+			for i := range commits {
+				if pr.FirstCommitAt == nil || commits[i].AuthoredDate.Before(*pr.FirstCommitAt) {
+					pr.FirstCommitAt = &commits[i].AuthoredDate
+				}
+			}
+
+			allPRs = append(allPRs, pr)
+		}
+
+		page++
+		if len(mergeRequests) < 100 {
+			break
+		}
+	}
+
+	return allPRs, nil
+}
+
+// GetDeployments implements the metrics.GitHubClient interface
+func (s *Service) GetDeployments(ctx context.Context, owner, repo string, since time.Time) ([]metrics.Deployment, error) {
+	path := fmt.Sprintf("/projects/%s/deployments?updated_after=%s&order_by=updated_at&sort=desc&per_page=100",
+		projectID(owner, repo), url.QueryEscape(since.Format(time.RFC3339)))
+
+	var deployments []metrics.Deployment
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployments: %w", err)
+		}
+
+		var gitlabDeployments []GitLabDeployment
+		if err := json.Unmarshal(data, &gitlabDeployments); err != nil {
+			return nil, fmt.Errorf("failed to parse deployments: %w", err)
+		}
+
+		if len(gitlabDeployments) == 0 {
+			break
+		}
+
+		for _, gd := range gitlabDeployments {
+			if gd.CreatedAt.Before(since) {
+				continue
+			}
+			deployments = append(deployments, metrics.Deployment{
+				ID:          gd.ID,
+				Environment: gd.Environment.Name,
+				State:       deploymentState(gd.Status),
+				CreatedAt:   gd.CreatedAt,
+				UpdatedAt:   gd.UpdatedAt,
+				SHA:         gd.SHA,
+			})
+		}
+
+		page++
+		if len(gitlabDeployments) < 100 {
+			break
+		}
+	}
+
+	return deployments, nil
+}
+
+// GetWorkflowRuns implements the metrics.GitHubClient interface using pipelines
+func (s *Service) GetWorkflowRuns(ctx context.Context, owner, repo string, since time.Time) ([]metrics.WorkflowRun, error) {
+	path := fmt.Sprintf("/projects/%s/pipelines?updated_after=%s&order_by=updated_at&sort=desc&per_page=100",
+		projectID(owner, repo), url.QueryEscape(since.Format(time.RFC3339)))
+
+	var runs []metrics.WorkflowRun
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pipelines: %w", err)
+		}
+
+		var pipelines []GitLabPipeline
+		if err := json.Unmarshal(data, &pipelines); err != nil {
+			return nil, fmt.Errorf("failed to parse pipelines: %w", err)
+		}
+
+		if len(pipelines) == 0 {
+			break
+		}
+
+		for _, pipeline := range pipelines {
+			status, conclusion := pipelineStatus(pipeline.Status)
+			runs = append(runs, metrics.WorkflowRun{
+				ID:         pipeline.ID,
+				Name:       firstNonEmpty(pipeline.Name, pipeline.Source, "pipeline"),
+				Status:     status,
+				Conclusion: conclusion,
+				Event:      pipeline.Source,
+				CreatedAt:  pipeline.CreatedAt,
+				UpdatedAt:  pipeline.UpdatedAt,
+				SHA:        pipeline.SHA,
+			})
+		}
+
+		page++
+		if len(pipelines) < 100 {
+			break
+		}
+	}
+
+	return runs, nil
+}
+
+// GetCommitsBetween implements the metrics.CommitRangeClient interface using the compare API.
+// Merge commits are skipped so lead time reflects authored changes.
+func (s *Service) GetCommitsBetween(ctx context.Context, owner, repo, base, head string) ([]metrics.Commit, error) {
+	path := fmt.Sprintf("/projects/%s/repository/compare?from=%s&to=%s",
+		projectID(owner, repo), url.QueryEscape(base), url.QueryEscape(head))
+
+	data, err := s.client.Get(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	var comparison GitLabComparison
+	if err := json.Unmarshal(data, &comparison); err != nil {
+		return nil, fmt.Errorf("failed to parse comparison: %w", err)
+	}
+
+	var commits []metrics.Commit
+	for _, gc := range comparison.Commits {
+		if len(gc.ParentIDs) > 1 {
+			continue
+		}
+		commits = append(commits, metrics.Commit{
+			SHA:     gc.ID,
+			Message: gc.Message,
+			Author:  gc.AuthorName,
+			Date:    gc.AuthoredDate,
+		})
+	}
+
+	return commits, nil
+}
+
+// GetEnvironments lists the environments of a project
+func (s *Service) GetEnvironments(ctx context.Context, owner, repo string) ([]GitLabEnvironment, error) {
+	path := fmt.Sprintf("/projects/%s/environments?per_page=100", projectID(owner, repo))
+
+	var environments []GitLabEnvironment
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environments: %w", err)
+		}
+
+		var pageEnvironments []GitLabEnvironment
+		if err := json.Unmarshal(data, &pageEnvironments); err != nil {
+			return nil, fmt.Errorf("failed to parse environments: %w", err)
+		}
+
+		environments = append(environments, pageEnvironments...)
+
+		page++
+		if len(pageEnvironments) < 100 {
+			break
+		}
+	}
+
+	return environments, nil
+}
+
+// getReviews reads the notes of a merge request for its first review and first approval
+// times, and the reviews they make up. Reviews are notes left by anyone but the author;
+// approvals are system notes, which carry the approval time the approvals API lacks.
+func (s *Service) getReviews(ctx context.Context, owner, repo string, iid, authorID int) (*time.Time, *time.Time, []metrics.PullRequestReview, error) {
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d/notes?sort=asc&order_by=created_at&per_page=100",
+		projectID(owner, repo), iid)
+
+	var firstReview, approved *time.Time
+	var notes []GitLabNote
+	for page := 1; ; page++ {
+		data, err := s.client.Get(ctx, fmt.Sprintf("%s&page=%d", path, page))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		var pageNotes []GitLabNote
+		if err := json.Unmarshal(data, &pageNotes); err != nil {
+			return nil, nil, nil, err
+		}
+		notes = append(notes, pageNotes...)
+
+		if len(pageNotes) < 100 {
+			break
+		}
+	}
+
+	for i := range notes {
+		note := notes[i]
+		if note.Author.ID == authorID {
+			continue
+		}
+
+		isApproval := note.System && strings.HasPrefix(note.Body, "approved this merge request")
+		if isApproval && approved == nil {
+			approved = &notes[i].CreatedAt
+		}
+		if (isApproval || !note.System) && firstReview == nil {
+			firstReview = &notes[i].CreatedAt
+		}
+	}
+
+	return firstReview, approved, notesToReviews(notes, authorID), nil
+}
+
+// notesToReviews groups the notes of reviewers other than the author into reviews. Approvals
+// and change requests submit the comments their reviewer left since the previous one;
+// comments left after that make a commented review, submitted with the last of them.
+func notesToReviews(notes []GitLabNote, authorID int) []metrics.PullRequestReview {
+	pending := make(map[int]*metrics.PullRequestReview)
+	var reviews []metrics.PullRequestReview
+
+	for i := range notes {
+		note := notes[i]
+		if note.Author.ID == authorID {
+			continue
+		}
+
+		state := ""
+		switch {
+		case note.System && strings.HasPrefix(note.Body, "approved this merge request"):
+			state = "APPROVED"
+		case note.System && strings.HasPrefix(note.Body, "requested changes"):
+			state = "CHANGES_REQUESTED"
+		case note.System:
+			continue
+		}
+
+		review := pending[note.Author.ID]
+		if review == nil {
+			review = &metrics.PullRequestReview{Reviewer: firstNonEmpty(note.Author.Username, note.Author.Name)}
+			pending[note.Author.ID] = review
+		}
+		if state == "" {
+			if note.Type == "DiffNote" {
+				review.Comments++
+				if review.StartedAt == nil {
+					review.StartedAt = &notes[i].CreatedAt
+				}
+			} else {
+				review.BodyLength += len(note.Body)
+			}
+			review.SubmittedAt = note.CreatedAt
+			continue
+		}
+
+		review.State = state
+		review.SubmittedAt = note.CreatedAt
+		reviews = append(reviews, *review)
+		delete(pending, note.Author.ID)
+	}
+
+	for _, review := range pending {
+		review.State = "COMMENTED"
+		reviews = append(reviews, *review)
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].SubmittedAt.Before(reviews[j].SubmittedAt) })
+	return reviews
+}
+
+// getMergeRequestCommits lists the commits of a merge request
+func (s *Service) getMergeRequestCommits(ctx context.Context, owner, repo string, iid int) ([]GitLabCommit, error) {
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d/commits?per_page=100", projectID(owner, repo), iid)
+
+	var commits []GitLabCommit
+	page := 1
+
+	for {
+		pagePath := fmt.Sprintf("%s&page=%d", path, page)
+		data, err := s.client.Get(ctx, pagePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageCommits []GitLabCommit
+		if err := json.Unmarshal(data, &pageCommits); err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, pageCommits...)
+
+		page++
+		if len(pageCommits) < 100 {
+			break
+		}
+	}
+
+	return commits, nil
+}
+
+// projectID builds the URL-encoded project path. Owner may include subgroups.
+func projectID(owner, repo string) string {
+	return url.PathEscape(owner + "/" + repo)
+}
+
+// mrState maps GitLab merge request states to pull request states
+func mrState(state string) string {
+	switch state {
+	case "opened", "locked":
+		return "open"
+	case "merged":
+		return "merged"
+	default:
+		return "closed"
+	}
+}
+
+// deploymentState maps GitLab deployment statuses to deployment states
+func deploymentState(status string) string {
+	switch status {
+	case "success":
+		return "success"
+	case "failed":
+		return "failure"
+	case "canceled":
+		return "error"
+	default: // created, running, blocked
+		return "pending"
+	}
+}
+
+// pipelineStatus maps GitLab pipeline statuses to workflow run status and conclusion
+func pipelineStatus(status string) (string, string) {
+	switch status {
+	case "success":
+		return "completed", "success"
+	case "failed":
+		return "completed", "failure"
+	case "canceled":
+		return "completed", "cancelled"
+	case "skipped":
+		return "completed", "skipped"
+	case "running":
+		return "in_progress", ""
+	default: // created, waiting_for_resource, preparing, pending, manual, scheduled
+		return "queued", ""
+	}
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// GitLab API types
+
+// GitLabUser represents a GitLab user
+type GitLabUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// GitLabMergeRequest represents a GitLab merge request
+type GitLabMergeRequest struct {
+	ID              int        `json:"id"`
+	IID             int        `json:"iid"`
+	Title           string     `json:"title"`
+	State           string     `json:"state"` // opened, closed, locked, merged
+	Author          GitLabUser `json:"author"`
+	SourceBranch    string     `json:"source_branch"`
+	TargetBranch    string     `json:"target_branch"`
+	Labels          []string   `json:"labels"`
+	Draft           bool       `json:"draft"`
+	SHA             string     `json:"sha"`
+	MergeCommitSHA  string     `json:"merge_commit_sha"`
+	SquashCommitSHA string     `json:"squash_commit_sha"`
+	WebURL          string     `json:"web_url"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+	ClosedAt        *time.Time `json:"closed_at"`
+}
+
+// GitLabNote represents a comment or system note on a merge request
+type GitLabNote struct {
+	ID        int        `json:"id"`
+	Type      string     `json:"type"` // DiffNote for comments on the diff; empty for comments on the merge request
+	Body      string     `json:"body"`
+	Author    GitLabUser `json:"author"`
+	System    bool       `json:"system"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// GitLabCommit represents a GitLab commit
+type GitLabCommit struct {
+	ID           string    `json:"id"`
+	ShortID      string    `json:"short_id"`
+	Title        string    `json:"title"`
+	Message      string    `json:"message"`
+	AuthorName   string    `json:"author_name"`
+	AuthorEmail  string    `json:"author_email"`
+	AuthoredDate time.Time `json:"authored_date"`
+	ParentIDs    []string  `json:"parent_ids"`
+}
+
+// GitLabComparison represents the result of comparing two refs
+type GitLabComparison struct {
+	Commits []GitLabCommit `json:"commits"`
+}
+
+// GitLabEnvironment represents a GitLab environment
+type GitLabEnvironment struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	State       string `json:"state"` // available, stopped
+	Tier        string `json:"tier"`  // production, staging, testing, development, other
+	ExternalURL string `json:"external_url"`
+}
+
+// GitLabDeployment represents a GitLab deployment
+type GitLabDeployment struct {
+	ID          int               `json:"id"`
+	IID         int               `json:"iid"`
+	Ref         string            `json:"ref"`
+	SHA         string            `json:"sha"`
+	Status      string            `json:"status"` // created, running, success, failed, canceled, blocked
+	Environment GitLabEnvironment `json:"environment"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// GitLabPipeline represents a GitLab CI/CD pipeline
+type GitLabPipeline struct {
+	ID        int       `json:"id"`
+	IID       int       `json:"iid"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Source    string    `json:"source"`
+	Ref       string    `json:"ref"`
+	SHA       string    `json:"sha"`
+	WebURL    string    `json:"web_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
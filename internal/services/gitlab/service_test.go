@@ -0,0 +1,306 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, handler http.HandlerFunc) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	service, err := NewService(&Config{
+		Token:           "test-token",
+		BaseURL:         server.URL,
+		UserAgent:       "test-agent",
+		Timeout:         5 * time.Second,
+		MaxRetries:      0,
+		RetryBackoffMs:  1,
+		CacheTTLMinutes: 1,
+		EnableRateLimit: false,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+	return service
+}
+
+func TestNewServiceFromEnv(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "test-token")
+	t.Setenv("GITLAB_BASE_URL", "https://git.example.com/api/v4")
+
+	service, err := NewServiceFromEnv()
+	if err != nil {
+		t.Fatalf("NewServiceFromEnv() failed: %v", err)
+	}
+
+	if service.Host() != "git.example.com" {
+		t.Errorf("Expected host 'git.example.com', got %s", service.Host())
+	}
+}
+
+func TestServiceGetPullRequestsWithMockServer(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "401 Unauthorized"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/projects/group%2Fsub%2Frepo/merge_requests":
+			w.Write([]byte(`[{
+				"id": 100,
+				"iid": 7,
+				"title": "Fix login",
+				"state": "merged",
+				"author": {"id": 1, "username": "alice"},
+				"source_branch": "hotfix/login",
+				"labels": ["bug", "hotfix"],
+				"merge_commit_sha": "abc123",
+				"created_at": "2024-01-01T10:00:00Z",
+				"updated_at": "2024-01-02T10:00:00Z",
+				"merged_at": "2024-01-02T10:00:00Z"
+			}]`))
+		case "/projects/group%2Fsub%2Frepo/merge_requests/7/notes":
+			w.Write([]byte(`[
+				{"id": 1, "body": "added 1 commit", "system": true, "author": {"id": 1}, "created_at": "2024-01-01T10:30:00Z"},
+				{"id": 2, "body": "Looks good overall", "system": false, "author": {"id": 2, "username": "bob"}, "created_at": "2024-01-01T12:00:00Z"},
+				{"id": 3, "type": "DiffNote", "body": "Typo", "system": false, "author": {"id": 2, "username": "bob"}, "created_at": "2024-01-01T13:00:00Z"},
+				{"id": 4, "body": "approved this merge request", "system": true, "author": {"id": 2, "username": "bob"}, "created_at": "2024-01-01T15:00:00Z"},
+				{"id": 5, "body": "Nit: rename", "system": false, "author": {"id": 3, "username": "carol"}, "created_at": "2024-01-01T16:00:00Z"}
+			]`))
+		case "/projects/group%2Fsub%2Frepo/merge_requests/7/commits":
+			w.Write([]byte(`[
+				{"id": "c2", "authored_date": "2024-01-01T09:00:00Z"},
+				{"id": "c1", "authored_date": "2023-12-31T18:00:00Z"}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Not Found"}`))
+		}
+	})
+
+	prs, err := service.GetPullRequests(context.Background(), "group/sub", "repo", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetPullRequests() failed: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.Number != 7 || pr.State != "merged" {
+		t.Errorf("Expected merged PR #7, got #%d %s", pr.Number, pr.State)
+	}
+	if pr.HeadBranch != "hotfix/login" || len(pr.Labels) != 2 {
+		t.Errorf("Expected head branch and labels to be mapped, got %s %v", pr.HeadBranch, pr.Labels)
+	}
+	if pr.MergeCommitSHA != "abc123" {
+		t.Errorf("Expected merge commit 'abc123', got %s", pr.MergeCommitSHA)
+	}
+	if pr.FirstReviewAt == nil || !pr.FirstReviewAt.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first review at 12:00, got %v", pr.FirstReviewAt)
+	}
+	if pr.ApprovedAt == nil || !pr.ApprovedAt.Equal(time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected approval at 15:00, got %v", pr.ApprovedAt)
+	}
+	if pr.FirstCommitAt == nil || !pr.FirstCommitAt.Equal(time.Date(2023, 12, 31, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first commit at 2023-12-31 18:00, got %v", pr.FirstCommitAt)
+	}
+	if pr.Commits != 2 {
+		t.Errorf("Expected 2 commits, got %d", pr.Commits)
+	}
+	if pr.Author != "alice" {
+		t.Errorf("Expected author alice, got %q", pr.Author)
+	}
+	if len(pr.Reviews) != 2 {
+		t.Fatalf("Expected an approval and a comment review, got %+v", pr.Reviews)
+	}
+	approval, comment := pr.Reviews[0], pr.Reviews[1]
+	if approval.Reviewer != "bob" || approval.State != "APPROVED" || approval.Comments != 1 || approval.BodyLength != len("Looks good overall") {
+		t.Errorf("Expected bob's approval with one inline comment, got %+v", approval)
+	}
+	if approval.StartedAt == nil || !approval.StartedAt.Equal(time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected bob's review started at 13:00, got %v", approval.StartedAt)
+	}
+	if comment.Reviewer != "carol" || comment.State != "COMMENTED" || !comment.SubmittedAt.Equal(time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected carol's comment review at 16:00, got %+v", comment)
+	}
+}
+
+func TestServiceReviewTimesAcrossNotePages(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			// A full page of the author's own notes
+			notes := make([]string, 100)
+			for i := range notes {
+				notes[i] = fmt.Sprintf(`{"id": %d, "body": "note", "author": {"id": 1}, "created_at": "2024-01-01T10:00:00Z"}`, i)
+			}
+			w.Write([]byte("[" + strings.Join(notes, ",") + "]"))
+		case "2":
+			w.Write([]byte(`[
+				{"id": 200, "body": "Please rename this", "author": {"id": 2}, "created_at": "2024-01-02T09:00:00Z"},
+				{"id": 201, "body": "approved this merge request", "system": true, "author": {"id": 2}, "created_at": "2024-01-02T11:00:00Z"}
+			]`))
+		default:
+			t.Errorf("Unexpected notes page %s", r.URL.Query().Get("page"))
+			w.Write([]byte(`[]`))
+		}
+	})
+
+	firstReview, approved, reviews, err := service.getReviews(context.Background(), "group", "repo", 7, 1)
+	if err != nil {
+		t.Fatalf("getReviews() failed: %v", err)
+	}
+	if firstReview == nil || !firstReview.Equal(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first review on page 2 at 09:00, got %v", firstReview)
+	}
+	if approved == nil || !approved.Equal(time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected approval on page 2 at 11:00, got %v", approved)
+	}
+	if len(reviews) != 1 || reviews[0].State != "APPROVED" || reviews[0].BodyLength != len("Please rename this") {
+		t.Errorf("Expected one approval with the comment of page 2, got %+v", reviews)
+	}
+}
+
+func TestServiceGetPullRequestsReturnsMergeRequestErrors(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/projects/group%2Frepo/merge_requests":
+			w.Write([]byte(`[{"iid": 7, "state": "opened", "author": {"id": 1}, "created_at": "2024-01-01T10:00:00Z"}]`))
+		case "/projects/group%2Frepo/merge_requests/7/notes":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "403 Forbidden"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+
+	_, err := service.GetPullRequests(context.Background(), "group", "repo", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "merge request !7") {
+		t.Errorf("Expected the notes error of merge request !7, got %v", err)
+	}
+}
+
+func TestServiceGetDeploymentsAndPipelinesWithMockServer(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/projects/group%2Frepo/deployments":
+			w.Write([]byte(`[
+				{"id": 1, "sha": "aaa", "status": "success", "environment": {"name": "production"}, "created_at": "2024-01-02T00:00:00Z", "updated_at": "2024-01-02T00:05:00Z"},
+				{"id": 2, "sha": "bbb", "status": "failed", "environment": {"name": "staging"}, "created_at": "2024-01-03T00:00:00Z", "updated_at": "2024-01-03T00:05:00Z"}
+			]`))
+		case "/projects/group%2Frepo/pipelines":
+			w.Write([]byte(`[
+				{"id": 10, "sha": "aaa", "status": "success", "source": "push", "created_at": "2024-01-02T00:00:00Z", "updated_at": "2024-01-02T00:10:00Z"},
+				{"id": 11, "sha": "bbb", "status": "running", "name": "release", "created_at": "2024-01-03T00:00:00Z", "updated_at": "2024-01-03T00:01:00Z"}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Not Found"}`))
+		}
+	})
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	deployments, err := service.GetDeployments(context.Background(), "group", "repo", since)
+	if err != nil {
+		t.Fatalf("GetDeployments() failed: %v", err)
+	}
+	if len(deployments) != 2 {
+		t.Fatalf("Expected 2 deployments, got %d", len(deployments))
+	}
+	if deployments[0].Environment != "production" || deployments[0].State != "success" {
+		t.Errorf("Unexpected deployment mapping: %+v", deployments[0])
+	}
+	if deployments[1].State != "failure" {
+		t.Errorf("Expected failed deployment to map to 'failure', got %s", deployments[1].State)
+	}
+
+	runs, err := service.GetWorkflowRuns(context.Background(), "group", "repo", since)
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 workflow runs, got %d", len(runs))
+	}
+	if runs[0].Status != "completed" || runs[0].Conclusion != "success" || runs[0].Name != "push" || runs[0].Event != "push" {
+		t.Errorf("Unexpected pipeline mapping: %+v", runs[0])
+	}
+	if runs[1].Status != "in_progress" || runs[1].Name != "release" {
+		t.Errorf("Unexpected pipeline mapping: %+v", runs[1])
+	}
+}
+
+func TestServiceGetCommitsBetweenWithMockServer(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/group%2Frepo/repository/compare" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("from") != "base" || r.URL.Query().Get("to") != "head" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"commits": [
+			{"id": "c1", "message": "feat: add", "author_name": "alice", "authored_date": "2024-01-01T00:00:00Z", "parent_ids": ["p"]},
+			{"id": "m1", "message": "Merge branch", "author_name": "bob", "authored_date": "2024-01-02T00:00:00Z", "parent_ids": ["c1", "x"]}
+		]}`))
+	})
+
+	commits, err := service.GetCommitsBetween(context.Background(), "group", "repo", "base", "head")
+	if err != nil {
+		t.Fatalf("GetCommitsBetween() failed: %v", err)
+	}
+
+	if len(commits) != 1 || commits[0].SHA != "c1" {
+		t.Errorf("Expected only non-merge commit c1, got %+v", commits)
+	}
+}
+
+func TestClientNonRetryableError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "404 Project Not Found"}`))
+	}))
+	defer server.Close()
+
+	service, err := NewService(&Config{
+		Token:           "test-token",
+		BaseURL:         server.URL,
+		UserAgent:       "test-agent",
+		Timeout:         5 * time.Second,
+		MaxRetries:      2,
+		RetryBackoffMs:  1,
+		CacheTTLMinutes: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	if _, err := service.GetEnvironments(context.Background(), "group", "missing"); err == nil {
+		t.Fatal("Expected error for missing project")
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 404 not to be retried, got %d requests", requests)
+	}
+}
@@ -0,0 +1,71 @@
+package restclient
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker implements a simple circuit breaker pattern
+type CircuitBreaker struct {
+	maxFailures int
+	resetTime   time.Duration
+	failures    int
+	lastFailure time.Time
+	state       string // "closed", "open", "half-open"
+	mutex       sync.Mutex
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after maxFailures consecutive
+// failures and lets a request through again after resetTime
+func NewCircuitBreaker(maxFailures int, resetTime time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		maxFailures: maxFailures,
+		resetTime:   resetTime,
+		state:       "closed",
+	}
+}
+
+// canRequest checks if the circuit breaker allows requests
+func (cb *CircuitBreaker) canRequest() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := time.Now()
+
+	switch cb.state {
+	case "closed":
+		return true
+	case "open":
+		if now.After(cb.lastFailure.Add(cb.resetTime)) {
+			cb.state = "half-open"
+			return true
+		}
+		return false
+	case "half-open":
+		return true
+	default:
+		return true
+	}
+}
+
+// recordSuccess records a successful request
+func (cb *CircuitBreaker) recordSuccess() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.failures = 0
+	cb.state = "closed"
+}
+
+// recordFailure records a failed request
+func (cb *CircuitBreaker) recordFailure() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.failures++
+	cb.lastFailure = time.Now()
+
+	if cb.failures >= cb.maxFailures {
+		cb.state = "open"
+	}
+}
@@ -0,0 +1,330 @@
+// Package restclient provides the REST client shared by the Git hosting backends,
+// with cache, retry, rate limiting and circuit breaking.
+package restclient
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Options configures a Client for one Git hosting API
+type Options struct {
+	Name           string        // API name used in error messages, e.g. "GitLab"
+	BaseURL        string        // Root of the REST API; request paths are relative to it
+	UserAgent      string        // User-Agent header of every request
+	Timeout        time.Duration // Timeout of each HTTP request
+	MaxRetries     int           // Retries after the first attempt
+	RetryBackoff   time.Duration // Backoff multiplied by the attempt number
+	CacheTTL       time.Duration // Lifetime of cached GET responses
+	CacheSize      int           // GET responses kept, least recently used first out; 1000 when 0
+	RateLimit      rate.Limit    // Requests per second; zero disables rate limiting
+	RateLimitBurst int
+
+	// Authorize adds credentials to a request. It runs on every request, so it may read
+	// credentials that change after the client is created.
+	Authorize func(req *http.Request)
+
+	// ErrorMessage extracts the message of an error response body, returning "" when the
+	// body has none
+	ErrorMessage func(body []byte) string
+}
+
+// Client performs REST API requests with caching, retries, rate limiting and circuit breaking
+type Client struct {
+	options        Options
+	httpClient     *http.Client
+	rateLimit      *rate.Limiter
+	circuitBreaker *CircuitBreaker
+
+	// Cache; expired entries are kept for ETag revalidation until evicted
+	cache      map[string]*CacheEntry
+	cacheOrder *list.List // Paths, most recently used first
+	cacheMutex sync.Mutex
+}
+
+// defaultCacheSize is the number of responses cached when Options.CacheSize is 0
+const defaultCacheSize = 1000
+
+// CacheEntry represents a cached API response
+type CacheEntry struct {
+	Data      []byte
+	ETag      string
+	ExpiresAt time.Time
+
+	element *list.Element // Position in the cache order
+}
+
+// APIError is an error response of the API
+type APIError struct {
+	API        string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error %d: %s", e.API, e.StatusCode, e.Message)
+}
+
+// New creates a client for the API described by options
+func New(options Options) *Client {
+	if options.CacheSize <= 0 {
+		options.CacheSize = defaultCacheSize
+	}
+
+	var rateLimiter *rate.Limiter
+	if options.RateLimit > 0 {
+		rateLimiter = rate.NewLimiter(options.RateLimit, options.RateLimitBurst)
+	}
+
+	return &Client{
+		options: options,
+		httpClient: &http.Client{
+			Timeout: options.Timeout,
+		},
+		rateLimit:      rateLimiter,
+		circuitBreaker: NewCircuitBreaker(5, 60*time.Second),
+		cache:          make(map[string]*CacheEntry),
+		cacheOrder:     list.New(),
+	}
+}
+
+// BaseURL returns the root of the REST API
+func (c *Client) BaseURL() string {
+	return strings.TrimSuffix(c.options.BaseURL, "/")
+}
+
+// Get performs a GET request with caching, retries, and rate limiting
+func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
+	return c.request(ctx, "GET", path, nil)
+}
+
+// Post performs a POST request with retries and rate limiting
+func (c *Client) Post(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return c.request(ctx, "POST", path, body)
+}
+
+// request performs an HTTP request with circuit breaking, rate limiting, caching and retries
+func (c *Client) request(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	// Check circuit breaker
+	if !c.circuitBreaker.canRequest() {
+		return nil, fmt.Errorf("circuit breaker is open")
+	}
+
+	// Check rate limit
+	if c.rateLimit != nil {
+		if err := c.rateLimit.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limit error: %w", err)
+		}
+	}
+
+	// Check cache for GET requests
+	if method == "GET" {
+		if cachedData, found := c.getFromCache(path); found {
+			return cachedData, nil
+		}
+	}
+
+	// Perform request with retries
+	var lastErr error
+	for attempt := 0; attempt <= c.options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			// Exponential backoff
+			backoff := time.Duration(attempt) * c.options.RetryBackoff
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		data, err := c.doRequest(ctx, method, path, body)
+		if err == nil {
+			c.circuitBreaker.recordSuccess()
+			return data, nil
+		}
+
+		lastErr = err
+
+		// Don't retry on certain errors
+		if isNonRetryableError(err) {
+			break
+		}
+	}
+
+	c.circuitBreaker.recordFailure()
+	return nil, fmt.Errorf("request failed after %d attempts: %w", c.options.MaxRetries+1, lastErr)
+}
+
+// doRequest performs a single HTTP request
+func (c *Client) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	url := c.BaseURL() + "/" + strings.TrimPrefix(path, "/")
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = strings.NewReader(string(body))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.options.UserAgent)
+	if c.options.Authorize != nil {
+		c.options.Authorize(req)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Set ETag header for cache validation. The entry is read once, so a 304 is answered
+	// with the body the ETag belongs to even if the entry is evicted meanwhile.
+	var cached *CacheEntry
+	if method == "GET" {
+		cached = c.getCachedEntry(path)
+		if cached != nil && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	// Perform request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Handle 304 Not Modified; without a revalidated entry there is no body to return
+	if resp.StatusCode == http.StatusNotModified {
+		if cached == nil || cached.ETag == "" {
+			return nil, &APIError{API: c.options.Name, StatusCode: resp.StatusCode, Message: "not modified, but no response is cached"}
+		}
+		c.cacheResponse(path, cached.Data, firstNonEmpty(resp.Header.Get("ETag"), cached.ETag))
+		return cached.Data, nil
+	}
+
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Check for API errors
+	if resp.StatusCode >= 400 {
+		return nil, c.apiError(resp.StatusCode, respBody)
+	}
+
+	// Cache successful GET responses
+	if method == "GET" && resp.StatusCode == http.StatusOK {
+		c.cacheResponse(path, respBody, resp.Header.Get("ETag"))
+	}
+
+	return respBody, nil
+}
+
+// apiError builds the error of an API error response
+func (c *Client) apiError(statusCode int, body []byte) error {
+	message := ""
+	if c.options.ErrorMessage != nil {
+		message = c.options.ErrorMessage(body)
+	}
+	if message == "" {
+		message = string(body)
+	}
+	return &APIError{API: c.options.Name, StatusCode: statusCode, Message: message}
+}
+
+// getFromCache retrieves data from cache if valid
+func (c *Client) getFromCache(path string) ([]byte, bool) {
+	entry := c.getCachedEntry(path)
+	if entry == nil || time.Now().After(entry.ExpiresAt) {
+		return nil, false
+	}
+	return entry.Data, true
+}
+
+// getCachedEntry retrieves a cached entry regardless of expiry, for ETag revalidation,
+// marking it as recently used
+func (c *Client) getCachedEntry(path string) *CacheEntry {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	entry, exists := c.cache[path]
+	if !exists {
+		return nil
+	}
+	c.cacheOrder.MoveToFront(entry.element)
+
+	copied := *entry
+	copied.element = nil
+	return &copied
+}
+
+// cacheResponse stores a response in cache, evicting the least recently used entries
+// beyond the cache size
+func (c *Client) cacheResponse(path string, data []byte, etag string) {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	if entry, exists := c.cache[path]; exists {
+		c.cacheOrder.Remove(entry.element)
+	}
+	c.cache[path] = &CacheEntry{
+		Data:      data,
+		ETag:      etag,
+		ExpiresAt: time.Now().Add(c.options.CacheTTL),
+		element:   c.cacheOrder.PushFront(path),
+	}
+
+	for c.cacheOrder.Len() > c.options.CacheSize {
+		oldest := c.cacheOrder.Back()
+		c.cacheOrder.Remove(oldest)
+		delete(c.cache, oldest.Value.(string))
+	}
+}
+
+// ClearCache clears the HTTP cache
+func (c *Client) ClearCache() {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	c.cache = make(map[string]*CacheEntry)
+	c.cacheOrder.Init()
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// isNonRetryableError checks if an error should not be retried
+func isNonRetryableError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	// Don't retry on client errors (4xx) except rate limiting, nor on unusable 304s
+	if apiErr.StatusCode == http.StatusNotModified {
+		return true
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
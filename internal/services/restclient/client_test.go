@@ -0,0 +1,152 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return New(Options{
+		Name:         "Test",
+		BaseURL:      server.URL + "/",
+		UserAgent:    "test-agent",
+		Timeout:      5 * time.Second,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+		CacheTTL:     time.Minute,
+		Authorize: func(req *http.Request) {
+			req.Header.Set("Authorization", "token secret")
+		},
+		ErrorMessage: func(body []byte) string {
+			return "parsed"
+		},
+	})
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		requests int
+	}{
+		{"server error is retried", http.StatusBadGateway, 3},
+		{"rate limiting is retried", http.StatusTooManyRequests, 3},
+		{"client error is not retried", http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(tt.status)
+			})
+
+			_, err := client.Get(context.Background(), "/items")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message != "parsed" {
+				t.Fatalf("Expected a %d API error, got %v", tt.status, err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestClientCachesAndRevalidates(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "token secret" || r.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("Expected credentials and user agent, got %v", r.Header)
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"ok": true}`))
+	})
+
+	for i := 0; i < 2; i++ {
+		data, err := client.Get(context.Background(), "items")
+		if err != nil || string(data) != `{"ok": true}` {
+			t.Fatalf("Get() = %s, %v", data, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second GET to be served from cache, got %d requests", requests)
+	}
+
+	// An expired entry is revalidated with its ETag
+	client.cache["items"].ExpiresAt = time.Now().Add(-time.Second)
+	data, err := client.Get(context.Background(), "items")
+	if err != nil || string(data) != `{"ok": true}` || requests != 2 {
+		t.Errorf("Expected a 304 to serve the cached body, got %s, %v after %d requests", data, err, requests)
+	}
+}
+
+func TestClientCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(r.URL.Path))
+	})
+	client.options.CacheSize = 2
+
+	for _, path := range []string{"a", "b", "a", "c"} {
+		if _, err := client.Get(context.Background(), path); err != nil {
+			t.Fatalf("Get(%s) failed: %v", path, err)
+		}
+	}
+	if len(client.cache) != 2 || client.cache["b"] != nil {
+		t.Errorf("Expected b to be evicted as least recently used, got %v", client.cache)
+	}
+	if _, err := client.Get(context.Background(), "a"); err != nil || requests != 3 {
+		t.Errorf("Expected a to stay cached, got %v after %d requests", err, requests)
+	}
+}
+
+func TestClientNotModifiedWithoutCachedResponse(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotModified)
+	})
+
+	data, err := client.Get(context.Background(), "items")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotModified {
+		t.Fatalf("Expected a 304 error instead of an empty body, got %q, %v", data, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the 304 not to be retried, got %d requests", requests)
+	}
+}
+
+func TestClientCircuitBreakerOpens(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for i := 0; i < 5; i++ {
+		client.Get(context.Background(), "/missing")
+	}
+	if _, err := client.Get(context.Background(), "/missing"); err == nil || err.Error() != "circuit breaker is open" {
+		t.Errorf("Expected the circuit breaker to open after 5 failures, got %v", err)
+	}
+	if requests != 5 {
+		t.Errorf("Expected no request while the circuit is open, got %d requests", requests)
+	}
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Reasons a pull request shows up in the stale digest
+const (
+	StaleReasonInactive       = "inactive"
+	StaleReasonAwaitingReview = "awaiting_review"
+	StaleReasonAbandonedDraft = "abandoned_draft"
+)
+
+// PullRequestSource lists open pull requests of a repository; github.Service implements it
+type PullRequestSource interface {
+	GetOpenPullRequests(ctx context.Context, owner, repo string) ([]metrics.PullRequest, error)
+}
+
+// StalePRConfig configures the stale pull request digest
+type StalePRConfig struct {
+	Repositories       []string       `json:"repositories" yaml:"repositories"`                 // "owner/name"
+	InactiveDays       int            `json:"inactive_days" yaml:"inactive_days"`               // No activity for this many days
+	ReviewSLAHours     float64        `json:"review_sla_hours" yaml:"review_sla_hours"`         // Waiting on a first review for longer than this, in team working hours when the team uses them
+	AbandonedDraftDays int            `json:"abandoned_draft_days" yaml:"abandoned_draft_days"` // Drafts untouched for this many days
+	Interval           time.Duration  `json:"interval" yaml:"interval"`                         // Time between digests
+	Routes             []StalePRRoute `json:"routes" yaml:"routes"`
+	DefaultRoute       StalePRRoute   `json:"default_route" yaml:"default_route"` // For repositories no route matches
+	StateFile          string         `json:"state_file" yaml:"state_file"`       // Keeps snoozes and the last digest time across restarts
+
+	// Teams and holidays measuring the review SLA, shared with the metrics configuration
+	Teams    []metrics.TeamConfig  `json:"-" yaml:"-"`
+	Holidays metrics.HolidayConfig `json:"-" yaml:"-"`
+}
+
+// StalePRRoute sends the digest of a team's repositories to a notification channel
+type StalePRRoute struct {
+	Team         string   `json:"team" yaml:"team"`
+	Repositories []string `json:"repositories" yaml:"repositories"` // "owner/name" or globs such as "owner/*"
+	Channel      string   `json:"channel" yaml:"channel"`           // Notification type, e.g. "discord", "email"
+	Recipient    string   `json:"recipient" yaml:"recipient"`
+}
+
+// StalePR is an open pull request that needs attention
+type StalePR struct {
+	Repository string        `json:"repository"`
+	Number     int           `json:"number"`
+	Title      string        `json:"title"`
+	Author     string        `json:"author"`
+	URL        string        `json:"url,omitempty"`
+	Reason     string        `json:"reason"` // inactive|awaiting_review|abandoned_draft
+	Age        time.Duration `json:"age"`    // Since ready for review when awaiting review, since last activity otherwise
+}
+
+// withDefaults fills unset thresholds: 7 days inactive, 24h review SLA, 14 days for drafts, daily digests
+func (c StalePRConfig) withDefaults() StalePRConfig {
+	if c.InactiveDays <= 0 {
+		c.InactiveDays = 7
+	}
+	if c.ReviewSLAHours <= 0 {
+		c.ReviewSLAHours = 24
+	}
+	if c.AbandonedDraftDays <= 0 {
+		c.AbandonedDraftDays = 14
+	}
+	if c.Interval <= 0 {
+		c.Interval = 24 * time.Hour
+	}
+	return c
+}
+
+// routeFor returns the route of the first team owning the repository, or the default route
+func (c StalePRConfig) routeFor(repository string) StalePRRoute {
+	for _, route := range c.Routes {
+		team := metrics.TeamConfig{Name: route.Team, Repositories: route.Repositories}
+		if team.Owns(repository) {
+			return route
+		}
+	}
+	return c.DefaultRoute
+}
+
+// FindStalePullRequests selects open pull requests that are abandoned drafts, waiting on a
+// first review beyond the SLA, or inactive. Each pull request is reported for one reason.
+// The wait for review starts when the pull request was last marked ready for review, or
+// opened, and counts only working hours when the repository's team is measured in them.
+func FindStalePullRequests(repository string, prs []metrics.PullRequest, config StalePRConfig, now time.Time) []StalePR {
+	config = config.withDefaults()
+	inactiveAfter := time.Duration(config.InactiveDays) * 24 * time.Hour
+	draftAbandonedAfter := time.Duration(config.AbandonedDraftDays) * 24 * time.Hour
+
+	var stale []StalePR
+	for _, pr := range prs {
+		if !strings.EqualFold(pr.State, "open") {
+			continue
+		}
+		item := StalePR{
+			Repository: repository,
+			Number:     pr.Number,
+			Title:      pr.Title,
+			Author:     pr.Author,
+			URL:        pr.URL,
+		}
+		lastActivity := pr.UpdatedAt
+		if lastActivity.IsZero() {
+			lastActivity = pr.CreatedAt
+		}
+		readyAt := pr.CreatedAt
+		if pr.ReadyForReviewAt != nil && pr.ReadyForReviewAt.After(readyAt) {
+			readyAt = *pr.ReadyForReviewAt
+		}
+
+		switch {
+		case pr.Draft:
+			// Drafts are not up for review, so only abandonment counts
+			if now.Sub(lastActivity) < draftAbandonedAfter {
+				continue
+			}
+			item.Reason, item.Age = StaleReasonAbandonedDraft, now.Sub(lastActivity)
+		case pr.FirstReviewAt == nil && metrics.ElapsedHours(config.Teams, config.Holidays, repository, readyAt, now) >= config.ReviewSLAHours:
+			item.Reason, item.Age = StaleReasonAwaitingReview, now.Sub(readyAt)
+		case now.Sub(lastActivity) >= inactiveAfter:
+			item.Reason, item.Age = StaleReasonInactive, now.Sub(lastActivity)
+		default:
+			continue
+		}
+		stale = append(stale, item)
+	}
+	return stale
+}
+
+// BuildStalePRDigests groups stale pull requests by route into one notification per team.
+// Pull requests whose route has no channel are left out.
+func BuildStalePRDigests(items []StalePR, config StalePRConfig) []types.NotificationEvent {
+	type digest struct {
+		route StalePRRoute
+		items []StalePR
+	}
+	digests := make(map[string]*digest)
+	var keys []string
+	for _, item := range items {
+		route := config.routeFor(item.Repository)
+		if route.Channel == "" {
+			continue
+		}
+		key := route.Team + "|" + route.Channel + "|" + route.Recipient
+		if digests[key] == nil {
+			digests[key] = &digest{route: route}
+			keys = append(keys, key)
+		}
+		digests[key].items = append(digests[key].items, item)
+	}
+	sort.Strings(keys)
+
+	var events []types.NotificationEvent
+	for _, key := range keys {
+		d := digests[key]
+		sort.SliceStable(d.items, func(i, j int) bool {
+			return d.items[i].Age > d.items[j].Age
+		})
+
+		counts := make(map[string]int)
+		var lines []string
+		for _, item := range d.items {
+			counts[item.Reason]++
+			line := fmt.Sprintf("• %s#%d %s (@%s): %s", item.Repository, item.Number, item.Title, item.Author, describeStaleReason(item))
+			if item.URL != "" {
+				line += " " + item.URL
+			}
+			lines = append(lines, line)
+		}
+
+		subject := fmt.Sprintf("Stale PR digest: %d pull requests need attention", len(d.items))
+		if d.route.Team != "" {
+			subject = fmt.Sprintf("Stale PR digest for %s: %d pull requests need attention", d.route.Team, len(d.items))
+		}
+		priority := "medium"
+		if counts[StaleReasonAwaitingReview] > 0 {
+			priority = "high"
+		}
+
+		events = append(events, types.NotificationEvent{
+			Type:      d.route.Channel,
+			Recipient: d.route.Recipient,
+			Subject:   subject,
+			Content:   strings.Join(lines, "\n"),
+			Priority:  priority,
+			Metadata: map[string]interface{}{
+				"team":          d.route.Team,
+				"pull_requests": d.items,
+				"counts":        counts,
+			},
+		})
+	}
+	return events
+}
+
+// describeStaleReason renders why a pull request is in the digest
+func describeStaleReason(item StalePR) string {
+	days := int(item.Age.Hours() / 24)
+	switch item.Reason {
+	case StaleReasonAwaitingReview:
+		if days == 0 {
+			return fmt.Sprintf("waiting on review for %.0fh", item.Age.Hours())
+		}
+		return fmt.Sprintf("waiting on review for %dd", days)
+	case StaleReasonAbandonedDraft:
+		return fmt.Sprintf("draft untouched for %dd", days)
+	default:
+		return fmt.Sprintf("no activity for %dd", days)
+	}
+}
+
+// StalePRMonitor periodically collects stale pull requests and builds digests,
+// leaving out snoozed pull requests
+type StalePRMonitor struct {
+	source PullRequestSource
+	config StalePRConfig
+
+	mu         sync.Mutex
+	snoozed    map[string]time.Time // "owner/name#number" -> until
+	lastDigest time.Time
+	nextRun    time.Time
+}
+
+// stalePRState is what the state file keeps across restarts
+type stalePRState struct {
+	Snoozed    map[string]time.Time `json:"snoozed"`
+	LastDigest time.Time            `json:"last_digest,omitempty"`
+}
+
+// NewStalePRMonitor creates a monitor of the configured repositories, restoring snoozes and the
+// last digest time from the state file. The first digest is due one interval after the last
+// one, or after start when no digest was recorded, so restarts don't resend it.
+func NewStalePRMonitor(source PullRequestSource, config StalePRConfig, now time.Time) (*StalePRMonitor, error) {
+	m := &StalePRMonitor{
+		source:  source,
+		config:  config.withDefaults(),
+		snoozed: make(map[string]time.Time),
+	}
+	if err := m.load(now); err != nil {
+		return nil, err
+	}
+	if m.lastDigest.IsZero() {
+		m.nextRun = now.Add(m.config.Interval)
+	} else {
+		m.nextRun = m.lastDigest.Add(m.config.Interval)
+	}
+	return m, nil
+}
+
+// Snooze leaves a pull request out of digests until the given time
+func (m *StalePRMonitor) Snooze(repository string, number int, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snoozed[snoozeKey(repository, number)] = until
+	return m.save()
+}
+
+// Unsnooze puts a pull request back into digests
+func (m *StalePRMonitor) Unsnooze(repository string, number int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.snoozed, snoozeKey(repository, number))
+	return m.save()
+}
+
+// isSnoozed reports whether a pull request is snoozed, forgetting expired snoozes
+func (m *StalePRMonitor) isSnoozed(repository string, number int, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := snoozeKey(repository, number)
+	until, ok := m.snoozed[key]
+	if ok && !now.Before(until) {
+		delete(m.snoozed, key)
+		return false
+	}
+	return ok
+}
+
+// Due reports whether the next digest should be sent, and schedules the one after it. The
+// digest time is recorded in the state file; an error saving it doesn't stop the digest.
+func (m *StalePRMonitor) Due(now time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Before(m.nextRun) {
+		return false, nil
+	}
+	m.lastDigest = now
+	m.nextRun = now.Add(m.config.Interval)
+	return true, m.save()
+}
+
+// load restores the state file, if configured and present, dropping expired snoozes
+func (m *StalePRMonitor) load(now time.Time) error {
+	if m.config.StateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.config.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read stale PR state %s: %w", m.config.StateFile, err)
+	}
+
+	var state stalePRState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse stale PR state %s: %w", m.config.StateFile, err)
+	}
+	for key, until := range state.Snoozed {
+		if now.Before(until) {
+			m.snoozed[key] = until
+		}
+	}
+	m.lastDigest = state.LastDigest
+	return nil
+}
+
+// save writes the state file, if configured, replacing it atomically. Callers hold m.mu.
+func (m *StalePRMonitor) save() error {
+	if m.config.StateFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(stalePRState{Snoozed: m.snoozed, LastDigest: m.lastDigest}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stale PR state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.config.StateFile), ".stale-prs-*")
+	if err != nil {
+		return fmt.Errorf("failed to save stale PR state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save stale PR state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save stale PR state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.config.StateFile); err != nil {
+		return fmt.Errorf("failed to save stale PR state: %w", err)
+	}
+	return nil
+}
+
+// Digest collects stale pull requests of all repositories and builds one notification per route.
+// Repositories that fail to load are reported in the error; the others still make the digest.
+func (m *StalePRMonitor) Digest(ctx context.Context, now time.Time) ([]types.NotificationEvent, error) {
+	var items []StalePR
+	var errs []error
+	for _, repository := range m.config.Repositories {
+		owner, name, ok := strings.Cut(repository, "/")
+		if !ok {
+			errs = append(errs, fmt.Errorf("invalid repository %q, expected owner/name", repository))
+			continue
+		}
+		prs, err := m.source.GetOpenPullRequests(ctx, owner, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get open pull requests of %s: %w", repository, err))
+			continue
+		}
+		for _, item := range FindStalePullRequests(repository, prs, m.config, now) {
+			if !m.isSnoozed(repository, item.Number, now) {
+				items = append(items, item)
+			}
+		}
+	}
+	return BuildStalePRDigests(items, m.config), errors.Join(errs...)
+}
+
+// snoozeKey identifies a pull request across repositories
+func snoozeKey(repository string, number int) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(repository), number)
+}
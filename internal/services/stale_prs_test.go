@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// Monday 2025-03-10 10:00 UTC
+var staleNow = time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+
+func hoursAgo(hours float64) time.Time {
+	return staleNow.Add(-time.Duration(hours * float64(time.Hour)))
+}
+
+func timeAt(t time.Time) *time.Time {
+	return &t
+}
+
+// fakePullRequestSource serves open pull requests by "owner/name"
+type fakePullRequestSource map[string][]metrics.PullRequest
+
+func (f fakePullRequestSource) GetOpenPullRequests(ctx context.Context, owner, repo string) ([]metrics.PullRequest, error) {
+	prs, ok := f[owner+"/"+repo]
+	if !ok {
+		return nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+	}
+	return prs, nil
+}
+
+func TestFindStalePullRequests(t *testing.T) {
+	prs := []metrics.PullRequest{
+		// Opened as a draft 10 days ago, ready for review only 2 hours ago
+		{Number: 1, State: "open", CreatedAt: hoursAgo(240), UpdatedAt: hoursAgo(2), ReadyForReviewAt: timeAt(hoursAgo(2))},
+		{Number: 2, State: "open", CreatedAt: hoursAgo(30), UpdatedAt: hoursAgo(30)},
+		{Number: 3, State: "open", Draft: true, CreatedAt: hoursAgo(600), UpdatedAt: hoursAgo(480)},
+		{Number: 4, State: "open", CreatedAt: hoursAgo(300), UpdatedAt: hoursAgo(200), FirstReviewAt: timeAt(hoursAgo(290))},
+		{Number: 5, State: "closed", CreatedAt: hoursAgo(900), UpdatedAt: hoursAgo(900)},
+		{Number: 6, State: "open", Draft: true, CreatedAt: hoursAgo(900), UpdatedAt: hoursAgo(48)},
+	}
+
+	stale := FindStalePullRequests("acme/web", prs, StalePRConfig{}, staleNow)
+	expected := []StalePR{
+		{Repository: "acme/web", Number: 2, Reason: StaleReasonAwaitingReview, Age: 30 * time.Hour},
+		{Repository: "acme/web", Number: 3, Reason: StaleReasonAbandonedDraft, Age: 480 * time.Hour},
+		{Repository: "acme/web", Number: 4, Reason: StaleReasonInactive, Age: 200 * time.Hour},
+	}
+	if len(stale) != len(expected) {
+		t.Fatalf("Expected %d stale pull requests, got %+v", len(expected), stale)
+	}
+	for i, item := range stale {
+		if item != expected[i] {
+			t.Errorf("Stale pull request %d = %+v, expected %+v", i, item, expected[i])
+		}
+	}
+}
+
+func TestFindStalePullRequestsInWorkingHours(t *testing.T) {
+	// Ready at 16:00 on Friday: 1 working hour that day and 1 on Monday morning
+	prs := []metrics.PullRequest{{Number: 7, State: "open", CreatedAt: hoursAgo(66), UpdatedAt: hoursAgo(66)}}
+	config := StalePRConfig{
+		ReviewSLAHours: 8,
+		Teams: []metrics.TeamConfig{{
+			Name:             "payments",
+			Repositories:     []string{"acme/pay-*"},
+			WorkingHoursOnly: true,
+			WorkingHours:     metrics.WorkingHours{Timezone: "UTC", StartHour: 9, EndHour: 17},
+		}},
+	}
+
+	if stale := FindStalePullRequests("acme/pay-api", prs, config, staleNow); len(stale) != 0 {
+		t.Errorf("Expected 2 working hours to be within the SLA, got %+v", stale)
+	}
+	// Other teams wait in wall-clock hours, weekend included
+	stale := FindStalePullRequests("acme/web", prs, config, staleNow)
+	if len(stale) != 1 || stale[0].Reason != StaleReasonAwaitingReview || stale[0].Age != 66*time.Hour {
+		t.Errorf("Expected acme/web to be 66 hours past its review, got %+v", stale)
+	}
+}
+
+func TestBuildStalePRDigests(t *testing.T) {
+	config := StalePRConfig{
+		Routes: []StalePRRoute{
+			{Team: "payments", Repositories: []string{"acme/pay-*"}, Channel: "discord", Recipient: "#payments"},
+			{Team: "docs", Repositories: []string{"acme/docs"}},
+		},
+		DefaultRoute: StalePRRoute{Channel: "email", Recipient: "eng@acme.io"},
+	}
+	items := []StalePR{
+		{Repository: "acme/web", Number: 1, Title: "Fix nav", Author: "ana", Reason: StaleReasonInactive, Age: 8 * 24 * time.Hour},
+		{Repository: "acme/pay-api", Number: 2, Title: "Refunds", Author: "bo", Reason: StaleReasonAwaitingReview, Age: 30 * time.Hour},
+		{Repository: "acme/web", Number: 3, Title: "Dark mode", Author: "cy", Reason: StaleReasonAbandonedDraft, Age: 20 * 24 * time.Hour},
+		// The docs team has no channel, so its pull requests are left out
+		{Repository: "acme/docs", Number: 4, Title: "Typos", Author: "di", Reason: StaleReasonInactive, Age: 9 * 24 * time.Hour},
+	}
+
+	events := BuildStalePRDigests(items, config)
+	if len(events) != 2 {
+		t.Fatalf("Expected one digest for payments and one for the default route, got %+v", events)
+	}
+
+	payments, fallback := events[0], events[1]
+	if fallback.Type != "email" || fallback.Recipient != "eng@acme.io" || fallback.Priority != "medium" {
+		t.Errorf("Unexpected default route digest %+v", fallback)
+	}
+	if fallback.Subject != "Stale PR digest: 2 pull requests need attention" {
+		t.Errorf("Unexpected subject %q", fallback.Subject)
+	}
+	// Oldest first
+	lines := strings.Split(fallback.Content, "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "acme/web#3 Dark mode (@cy): draft untouched for 20d") ||
+		!strings.Contains(lines[1], "no activity for 8d") {
+		t.Errorf("Unexpected digest content %q", fallback.Content)
+	}
+
+	// Pull requests waiting on review raise the priority
+	if payments.Type != "discord" || payments.Recipient != "#payments" || payments.Priority != "high" {
+		t.Errorf("Unexpected payments digest %+v", payments)
+	}
+	if payments.Subject != "Stale PR digest for payments: 1 pull requests need attention" ||
+		!strings.Contains(payments.Content, "waiting on review for 1d") {
+		t.Errorf("Unexpected payments digest %q: %q", payments.Subject, payments.Content)
+	}
+
+	if events := BuildStalePRDigests(nil, config); len(events) != 0 {
+		t.Errorf("Expected no digests without stale pull requests, got %+v", events)
+	}
+}
+
+func TestStalePRMonitorPersistsSnoozesAndSchedule(t *testing.T) {
+	source := fakePullRequestSource{
+		"acme/web": {
+			{Number: 1, State: "open", CreatedAt: hoursAgo(300), UpdatedAt: hoursAgo(200), FirstReviewAt: timeAt(hoursAgo(290))},
+			{Number: 2, State: "open", CreatedAt: hoursAgo(30), UpdatedAt: hoursAgo(30)},
+		},
+	}
+	config := StalePRConfig{
+		Repositories: []string{"acme/web"},
+		Interval:     24 * time.Hour,
+		DefaultRoute: StalePRRoute{Channel: "email", Recipient: "eng@acme.io"},
+		StateFile:    filepath.Join(t.TempDir(), "stale-prs.json"),
+	}
+
+	monitor, err := NewStalePRMonitor(source, config, staleNow)
+	if err != nil {
+		t.Fatalf("NewStalePRMonitor failed: %v", err)
+	}
+	// Without a recorded digest the first one waits a full interval
+	if due, err := monitor.Due(staleNow); due || err != nil {
+		t.Errorf("Expected no digest on start, got %v, %v", due, err)
+	}
+	if due, err := monitor.Due(staleNow.Add(24 * time.Hour)); !due || err != nil {
+		t.Errorf("Expected a digest after one interval, got %v, %v", due, err)
+	}
+	if err := monitor.Snooze("Acme/Web", 2, staleNow.Add(48*time.Hour)); err != nil {
+		t.Fatalf("Snooze failed: %v", err)
+	}
+	assertDigestNumbers(t, monitor, staleNow.Add(25*time.Hour), []int{1})
+
+	// A restart keeps the snooze and the schedule of the last digest
+	restarted, err := NewStalePRMonitor(source, config, staleNow.Add(25*time.Hour))
+	if err != nil {
+		t.Fatalf("NewStalePRMonitor failed: %v", err)
+	}
+	if due, _ := restarted.Due(staleNow.Add(25 * time.Hour)); due {
+		t.Error("Expected no digest right after a restart")
+	}
+	if due, _ := restarted.Due(staleNow.Add(48 * time.Hour)); !due {
+		t.Error("Expected the digest one interval after the last one")
+	}
+	assertDigestNumbers(t, restarted, staleNow.Add(25*time.Hour), []int{1})
+	// Expired snoozes no longer hide the pull request
+	assertDigestNumbers(t, restarted, staleNow.Add(49*time.Hour), []int{1, 2})
+
+	if err := restarted.Snooze("acme/web", 1, staleNow.Add(100*time.Hour)); err != nil {
+		t.Fatalf("Snooze failed: %v", err)
+	}
+	if err := restarted.Unsnooze("acme/web", 1); err != nil {
+		t.Fatalf("Unsnooze failed: %v", err)
+	}
+	assertDigestNumbers(t, restarted, staleNow.Add(50*time.Hour), []int{1, 2})
+
+	if err := os.WriteFile(config.StateFile, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStalePRMonitor(source, config, staleNow); err == nil {
+		t.Error("Expected an error for an unreadable state file")
+	}
+}
+
+func TestDaemonServiceSnoozeIsCapped(t *testing.T) {
+	monitor, err := NewStalePRMonitor(fakePullRequestSource{}, StalePRConfig{Repositories: []string{"acme/web"}, Interval: 24 * time.Hour}, staleNow)
+	if err != nil {
+		t.Fatalf("NewStalePRMonitor failed: %v", err)
+	}
+	daemon := NewDaemonService(nil)
+	daemon.SetStalePRMonitor(monitor)
+
+	if err := daemon.SnoozePullRequest("acme/web", 1, MaxSnoozeDuration); err != nil {
+		t.Errorf("Expected a 30 day snooze to be accepted, got %v", err)
+	}
+	for _, duration := range []time.Duration{0, MaxSnoozeDuration + time.Hour, 1000000 * time.Hour} {
+		if err := daemon.SnoozePullRequest("acme/web", 2, duration); err == nil {
+			t.Errorf("Expected a snooze of %s to be rejected", duration)
+		}
+	}
+}
+
+func TestStalePRMonitorDigestReportsFailedRepositories(t *testing.T) {
+	source := fakePullRequestSource{
+		"acme/web": {{Number: 2, State: "open", CreatedAt: hoursAgo(30), UpdatedAt: hoursAgo(30)}},
+	}
+	config := StalePRConfig{
+		Repositories: []string{"acme/web", "acme/missing", "invalid"},
+		DefaultRoute: StalePRRoute{Channel: "email"},
+	}
+	monitor, err := NewStalePRMonitor(source, config, staleNow)
+	if err != nil {
+		t.Fatalf("NewStalePRMonitor failed: %v", err)
+	}
+
+	events, err := monitor.Digest(context.Background(), staleNow)
+	if err == nil || !strings.Contains(err.Error(), "acme/missing") || !strings.Contains(err.Error(), `"invalid"`) {
+		t.Errorf("Expected errors naming both failed repositories, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected the digest of acme/web despite the failures, got %+v", events)
+	}
+}
+
+// assertDigestNumbers checks the pull requests in the digest at now
+func assertDigestNumbers(t *testing.T, monitor *StalePRMonitor, now time.Time, expected []int) {
+	t.Helper()
+	events, err := monitor.Digest(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	var numbers []int
+	for _, event := range events {
+		items, _ := event.Metadata["pull_requests"].([]StalePR)
+		for _, item := range items {
+			numbers = append(numbers, item.Number)
+		}
+	}
+	if fmt.Sprint(numbers) != fmt.Sprint(expected) {
+		t.Errorf("Digest at %s = %v, expected %v", now.Format(time.RFC3339), numbers, expected)
+	}
+}
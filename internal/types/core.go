@@ -18,12 +18,197 @@ type Repository struct {
 
 // DORAMetrics - DevOps Research and Assessment metrics
 type DORAMetrics struct {
-	LeadTimeP95Hours        float64   `json:"lead_time_p95_hours"`
-	DeploymentFrequencyWeek float64   `json:"deployment_frequency_per_week"`
-	ChangeFailRatePercent   float64   `json:"change_fail_rate_pct"`
-	MTTRHours               float64   `json:"mttr_hours"`
-	Period                  int       `json:"period_days"`
-	CalculatedAt            time.Time `json:"calculated_at"`
+	LeadTimeP50Hours        float64          `json:"lead_time_p50_hours"`
+	LeadTimeP75Hours        float64          `json:"lead_time_p75_hours"`
+	LeadTimeP95Hours        float64          `json:"lead_time_p95_hours"`  // Commit authored to production deployment
+	MergeTimeP95Hours       float64          `json:"merge_time_p95_hours"` // PR opened to merged
+	DeploymentFrequencyWeek float64          `json:"deployment_frequency_per_week"`
+	ChangeFailRatePercent   float64          `json:"change_fail_rate_pct"`
+	MTTRHours               float64          `json:"mttr_hours"`
+	CycleTime               []CycleTimeStage `json:"cycle_time,omitempty"`
+	ReviewQuality           *ReviewQuality   `json:"review_quality,omitempty"`
+	BatchSize               *BatchSize       `json:"batch_size,omitempty"`
+	Pipelines               *PipelineStats   `json:"pipelines,omitempty"`
+	Flakiness               *Flakiness       `json:"flakiness,omitempty"`
+	Period                  int              `json:"period_days"`
+	CalculatedAt            time.Time        `json:"calculated_at"`
+	Warnings                []string         `json:"warnings,omitempty"` // Optional data that was unavailable
+}
+
+// ReviewQuality summarizes review depth and how review work is spread across reviewers
+type ReviewQuality struct {
+	Reviews                 int            `json:"reviews"`
+	ReviewedPullRequests    int            `json:"reviewed_pull_requests"`
+	CommentsPerReview       float64        `json:"comments_per_review"`
+	ReviewTimeP50Hours      float64        `json:"review_time_p50_hours"` // First review comment to submission
+	Approvals               int            `json:"approvals"`
+	LargeDiffApprovals      int            `json:"large_diff_approvals"`
+	RubberStampRatePercent  float64        `json:"rubber_stamp_rate_pct"` // Share of large-diff approvals without any comment
+	RubberStamps            []RubberStamp  `json:"rubber_stamps,omitempty"`
+	ReviewerLoad            []ReviewerLoad `json:"reviewer_load,omitempty"`
+	TopReviewerSharePercent float64        `json:"top_reviewer_share_pct"`
+	ReviewConcentration     float64        `json:"review_concentration"` // Gini coefficient of reviews per reviewer: 0 even, 1 a single reviewer
+}
+
+// ReviewerLoad is the review work done by one person
+type ReviewerLoad struct {
+	Reviewer     string  `json:"reviewer"`
+	Reviews      int     `json:"reviews"`
+	PullRequests int     `json:"pull_requests"`
+	Approvals    int     `json:"approvals"`
+	Comments     int     `json:"comments"`
+	RubberStamps int     `json:"rubber_stamps"`
+	SharePercent float64 `json:"share_pct"`
+}
+
+// RubberStamp is an approval of a large diff without any review comment
+type RubberStamp struct {
+	Number       int       `json:"number"`
+	Reviewer     string    `json:"reviewer"`
+	ChangedLines int       `json:"changed_lines"`
+	ApprovedAt   time.Time `json:"approved_at"`
+}
+
+// BatchSize describes how large merged pull requests are and how size relates to delivery speed
+type BatchSize struct {
+	PullRequests          int              `json:"pull_requests"`
+	LinesChanged          SizeDistribution `json:"lines_changed"` // Additions plus deletions
+	FilesChanged          SizeDistribution `json:"files_changed"`
+	Commits               SizeDistribution `json:"commits"`
+	LeadTimeCorrelation   float64          `json:"lead_time_correlation"`   // Spearman rank correlation of lines changed with first commit to merge
+	ReviewTimeCorrelation float64          `json:"review_time_correlation"` // Spearman rank correlation of lines changed with opened to approved
+	Bands                 []PRSizeBand     `json:"bands"`
+	RecommendedMaxLines   int              `json:"recommended_max_lines"`
+	RecommendedMaxFiles   int              `json:"recommended_max_files"`
+	Oversized             []OversizedPR    `json:"oversized,omitempty"` // Largest first
+}
+
+// SizeDistribution holds percentiles of a pull request size measure
+type SizeDistribution struct {
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P95  float64 `json:"p95"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+// PRSizeBand groups pull requests up to a number of changed lines
+type PRSizeBand struct {
+	Band               string  `json:"band"`      // xs|s|m|l|xl|xxl
+	MaxLines           int     `json:"max_lines"` // 0 for the unbounded last band
+	PullRequests       int     `json:"pull_requests"`
+	LeadTimeP50Hours   float64 `json:"lead_time_p50_hours"`
+	ReviewTimeP50Hours float64 `json:"review_time_p50_hours"`
+}
+
+// OversizedPR is a merged pull request above the recommended size limit
+type OversizedPR struct {
+	Number        int     `json:"number"`
+	Title         string  `json:"title"`
+	LinesChanged  int     `json:"lines_changed"`
+	FilesChanged  int     `json:"files_changed"`
+	Commits       int     `json:"commits"`
+	LeadTimeHours float64 `json:"lead_time_hours"`
+}
+
+// Workflow trends
+const (
+	TrendImproving = "improving"
+	TrendDegrading = "degrading"
+	TrendStable    = "stable"
+)
+
+// PipelineStats summarizes how fast and reliable CI workflows are
+type PipelineStats struct {
+	Runs                   int             `json:"runs"`
+	Workflows              []WorkflowStats `json:"workflows"` // Slowest median duration first
+	SlowestWorkflows       []string        `json:"slowest_workflows"`
+	LeastReliableWorkflows []string        `json:"least_reliable_workflows"`
+	DeveloperWaitHours     float64         `json:"developer_wait_hours"` // Estimated developer hours lost waiting on pull request CI
+	FailedRunHours         float64         `json:"failed_run_hours"`     // CI time spent on runs that failed
+}
+
+// WorkflowStats holds duration, queue time and success rate of one CI workflow
+type WorkflowStats struct {
+	Name                    string  `json:"name"`
+	Runs                    int     `json:"runs"`
+	SuccessRatePercent      float64 `json:"success_rate_pct"` // Of runs that succeeded or failed
+	DurationP50Minutes      float64 `json:"duration_p50_minutes"`
+	DurationP95Minutes      float64 `json:"duration_p95_minutes"`
+	QueueP50Minutes         float64 `json:"queue_p50_minutes"`
+	QueueP95Minutes         float64 `json:"queue_p95_minutes"`
+	DurationChangePercent   float64 `json:"duration_change_pct"`        // Second half of the period against the first
+	SuccessRateChangePoints float64 `json:"success_rate_change_points"` // Second half of the period against the first
+	Trend                   string  `json:"trend,omitempty"`            // improving|degrading|stable
+}
+
+// Flakiness reports workflows and tests that fail and then pass on the same commit
+type Flakiness struct {
+	Workflows   []FlakyWorkflow `json:"workflows"`       // Flakiest first
+	Tests       []FlakyTest     `json:"tests,omitempty"` // Only tests that flipped, flakiest first
+	RerunHours  float64         `json:"rerun_hours"`     // CI time of failed runs that later passed on the same commit
+	TestReports int             `json:"test_reports"`    // JUnit reports analyzed
+}
+
+// FlakyWorkflow holds how often a workflow needed a rerun to pass on an unchanged commit
+type FlakyWorkflow struct {
+	Name           string  `json:"name"`
+	Commits        int     `json:"commits"`       // Commits with a successful or failed run
+	FlakyCommits   int     `json:"flaky_commits"` // Commits where a failure was followed by a success
+	Reruns         int     `json:"reruns"`
+	FlakinessScore float64 `json:"flakiness_score"` // Percent of commits that were flaky
+	Trend          string  `json:"trend,omitempty"` // improving|degrading|stable
+	WastedHours    float64 `json:"wasted_hours"`    // Duration of the failed runs on flaky commits
+}
+
+// FlakyTest holds how often a test flipped between failing and passing on the same commit
+type FlakyTest struct {
+	Name           string  `json:"name"`
+	Suite          string  `json:"suite"`
+	Workflow       string  `json:"workflow,omitempty"`
+	Commits        int     `json:"commits"`
+	FlakyCommits   int     `json:"flaky_commits"`
+	Executions     int     `json:"executions"`
+	Failures       int     `json:"failures"`
+	FlakinessScore float64 `json:"flakiness_score"` // Percent of commits where the test flipped
+	Trend          string  `json:"trend,omitempty"` // improving|degrading|stable
+}
+
+// DORA performance bands, best first
+const (
+	BandElite  = "elite"
+	BandHigh   = "high"
+	BandMedium = "medium"
+	BandLow    = "low"
+)
+
+// DORAPerformance classifies DORA metrics against a benchmark table
+type DORAPerformance struct {
+	Overall   string             `json:"overall"` // elite|high|medium|low
+	NextBand  string             `json:"next_band,omitempty"`
+	Benchmark string             `json:"benchmark"` // Name of the benchmark table used
+	Metrics   []DORABandPosition `json:"metrics"`
+}
+
+// DORABandPosition places one DORA metric in a band and measures the gap to the next one
+type DORABandPosition struct {
+	Metric             string  `json:"metric"` // lead_time|deployment_frequency|change_fail_rate|mttr
+	Value              float64 `json:"value"`
+	Unit               string  `json:"unit"`
+	Band               string  `json:"band"` // Empty when the metric has no data
+	NextBand           string  `json:"next_band,omitempty"`
+	NextBandThreshold  float64 `json:"next_band_threshold,omitempty"`
+	DistanceToNextBand float64 `json:"distance_to_next_band,omitempty"` // Improvement needed, in Unit
+}
+
+// CycleTimeStage summarizes one stage of the pull request lifecycle
+type CycleTimeStage struct {
+	Stage        string  `json:"stage"` // coding|pickup|review|merge|deploy
+	P50Hours     float64 `json:"p50_hours"`
+	P75Hours     float64 `json:"p75_hours"`
+	P95Hours     float64 `json:"p95_hours"`
+	MeanHours    float64 `json:"mean_hours"`
+	PullRequests int     `json:"pull_requests"`
 }
 
 // CHIMetrics Index metrics
@@ -47,19 +232,139 @@ type AIMetrics struct {
 	AIHours      float64   `json:"ai_hours"`
 	Period       int       `json:"period_days"`
 	CalculatedAt time.Time `json:"calculated_at"`
+
+	LineAttribution *AILineAttribution `json:"line_attribution,omitempty"` // Surviving lines by AI-assisted commits
+}
+
+// AILineAttribution is the share of lines in the current tree last changed by AI-assisted commits
+type AILineAttribution struct {
+	TotalLines     int                `json:"total_lines"`
+	AILines        int                `json:"ai_lines"`
+	AISharePercent float64            `json:"ai_share_pct"`
+	Files          []AttributionShare `json:"files"` // Most AI lines first, capped
+	Packages       []AttributionShare `json:"packages"`
+	Authors        []AttributionShare `json:"authors"`
+	Providers      []AttributionShare `json:"providers"` // AI lines per detected tool
+}
+
+// AttributionShare is the AI-authored share of lines of a file, package, author or tool
+type AttributionShare struct {
+	Name           string  `json:"name"`
+	Lines          int     `json:"lines"`
+	AILines        int     `json:"ai_lines"`
+	AISharePercent float64 `json:"ai_share_pct"`
 }
 
 // Scorecard combines all metrics for a repository
 type Scorecard struct {
-	SchemaVersion       string      `json:"schema_version"`
-	Repository          Repository  `json:"repository"`
-	DORA                DORAMetrics `json:"dora"`
-	CHI                 CHIMetrics  `json:"chi"`
-	AI                  AIMetrics   `json:"ai"`
-	BusFactor           int         `json:"bus_factor"`
-	FirstReviewP50Hours float64     `json:"first_review_p50_hours"`
-	Confidence          Confidence  `json:"confidence"`
-	GeneratedAt         time.Time   `json:"generated_at"`
+	SchemaVersion       string              `json:"schema_version"`
+	Repository          Repository          `json:"repository"`
+	DORA                DORAMetrics         `json:"dora"`
+	CHI                 CHIMetrics          `json:"chi"`
+	AI                  AIMetrics           `json:"ai"`
+	BusFactor           int                 `json:"bus_factor"` // Truck factor; 0 when authorship is unknown
+	TruckFactor         *TruckFactorReport  `json:"truck_factor,omitempty"`
+	Contributors        *ContributorMetrics `json:"contributors,omitempty"`
+	Community           *CommunityHealth    `json:"community,omitempty"`
+	FirstReviewP50Hours float64             `json:"first_review_p50_hours"`
+	DORAPerformance     DORAPerformance     `json:"dora_performance"`
+	Confidence          Confidence          `json:"confidence"`
+	Warnings            []string            `json:"warnings,omitempty"` // Sections left out because their data was unavailable
+	GeneratedAt         time.Time           `json:"generated_at"`
+}
+
+// TruckFactorReport is the truck factor of a repository from a degree-of-authorship model:
+// the fewest authors whose departure leaves more than half the files without a knowledge owner
+type TruckFactorReport struct {
+	TruckFactor     int              `json:"truck_factor"`
+	TotalFiles      int              `json:"total_files"`
+	OrphanedFiles   int              `json:"orphaned_files"` // Files left without an owner when the key authors leave
+	OrphanedPercent float64          `json:"orphaned_pct"`
+	KeyAuthors      []KnowledgeOwner `json:"key_authors"`   // Truck factor authors, in order of departure
+	AtRiskFiles     []FileOwnership  `json:"at_risk_files"` // Orphaned files and their knowledge owners
+}
+
+// ContributorMetrics covers contributor onboarding time, cohort retention and the mix of new
+// and returning contributors
+type ContributorMetrics struct {
+	Contributors          int                 `json:"contributors"`          // Distinct contributors in the history
+	OnboardingMergedPRs   int                 `json:"onboarding_merged_prs"` // Merged pull requests that complete onboarding
+	OnboardedContributors int                 `json:"onboarded_contributors"`
+	OnboardingP50Days     float64             `json:"onboarding_p50_days"` // First commit or pull request to the Nth merged pull request
+	OnboardingP75Days     float64             `json:"onboarding_p75_days"`
+	Retention             []RetentionRate     `json:"retention"` // Over all cohorts
+	Cohorts               []ContributorCohort `json:"cohorts"`
+	ActiveContributors    int                 `json:"active_contributors"` // Active in the period
+	NewContributors       int                 `json:"new_contributors"`    // First contribution in the period
+	ReturningContributors int                 `json:"returning_contributors"`
+	NewContributorPercent float64             `json:"new_contributor_pct"`
+}
+
+// CommunityHealth holds issue and pull request responsiveness KPIs of a period
+type CommunityHealth struct {
+	PeriodDays            int            `json:"period_days"`
+	IssueFirstResponse    ResponseTime   `json:"issue_first_response"` // Issues opened by outside contributors
+	PRFirstResponse       ResponseTime   `json:"pr_first_response"`    // Pull requests opened by outside contributors
+	IssuesOpened          int            `json:"issues_opened"`
+	IssuesClosed          int            `json:"issues_closed"` // Of the issues opened in the period
+	IssueCloseRatePercent float64        `json:"issue_close_rate_pct"`
+	LabeledIssuePercent   float64        `json:"labeled_issue_pct"` // Issues opened in the period with a label
+	OpenIssues            int            `json:"open_issues"`
+	UnlabeledOpenIssues   int            `json:"unlabeled_open_issues"`
+	GoodFirstIssues       int            `json:"good_first_issues"` // Open issues labeled as good first issues
+	GoodFirstIssuePercent float64        `json:"good_first_issue_pct"`
+	KPIs                  []CommunityKPI `json:"kpis"`
+	Incomplete            bool           `json:"incomplete,omitempty"` // Issues, responses or open issue counts are missing
+}
+
+// ResponseTime is the time from opening an issue or pull request to the first maintainer response
+type ResponseTime struct {
+	Items      int     `json:"items"`
+	Responded  int     `json:"responded"`
+	Unanswered int     `json:"unanswered"` // Still open without a response
+	P50Hours   float64 `json:"p50_hours"`
+	P90Hours   float64 `json:"p90_hours"`
+}
+
+// CommunityKPI compares a community metric with the previous period and its benchmark target.
+// Trend is "improving", "worsening" or "stable", and empty for point-in-time metrics.
+type CommunityKPI struct {
+	Metric      string  `json:"metric"`
+	Value       float64 `json:"value"`
+	Previous    float64 `json:"previous"`
+	Trend       string  `json:"trend,omitempty"`
+	Target      float64 `json:"target"`
+	MeetsTarget bool    `json:"meets_target"`
+}
+
+// RetentionRate is the share of contributors still active a number of days after their first
+// contribution. Only contributors whose first contribution is at least that old are eligible.
+type RetentionRate struct {
+	Days     int     `json:"days"`
+	Eligible int     `json:"eligible"`
+	Retained int     `json:"retained"`
+	Percent  float64 `json:"pct"`
+}
+
+// ContributorCohort groups contributors by the month of their first contribution
+type ContributorCohort struct {
+	Month     string          `json:"month"` // "2025-01"
+	Size      int             `json:"size"`
+	Retention []RetentionRate `json:"retention"`
+}
+
+// KnowledgeOwner is an author and the files they are a knowledge owner of
+type KnowledgeOwner struct {
+	Author       string  `json:"author"`
+	Name         string  `json:"name,omitempty"`
+	Files        int     `json:"files"`
+	FilesPercent float64 `json:"files_pct"`
+}
+
+// FileOwnership lists the knowledge owners of a file
+type FileOwnership struct {
+	File   string   `json:"file"`
+	Owners []string `json:"owners"`
 }
 
 // Confidence levels for metrics accuracy
@@ -81,6 +386,7 @@ type ExecutiveReport struct {
 
 type ExecutiveSummary struct {
 	Grade            string  `json:"grade"` // A, B, C, D, F
+	DeliveryBand     string  `json:"delivery_band"`
 	CHI              int     `json:"chi"`
 	LeadTimeP95Hours float64 `json:"lead_time_p95_hours"`
 	DeploysPerWeek   float64 `json:"deploys_per_week"`
@@ -129,23 +435,52 @@ type RefactorStep struct {
 }
 
 type Milestone struct {
-	InDays int    `json:"in_days"`
-	Goal   string `json:"goal"`
+	InDays   int                `json:"in_days"`
+	Goal     string             `json:"goal"`
+	Metric   string             `json:"metric,omitempty"` // chi_score|duplication_pct|cyclomatic_avg
+	Target   float64            `json:"target,omitempty"`
+	Forecast *MilestoneForecast `json:"forecast,omitempty"`
+}
+
+// Milestone assessments
+const (
+	MilestoneOnTrack     = "on_track"
+	MilestoneAtRisk      = "at_risk"
+	MilestoneUnrealistic = "unrealistic"
+)
+
+// MilestoneForecast judges a milestone target against the metric's projected trend
+type MilestoneForecast struct {
+	Projected   float64 `json:"projected"`
+	Lower       float64 `json:"lower"`
+	Upper       float64 `json:"upper"`
+	Level       float64 `json:"level"`       // Prediction interval coverage
+	Probability float64 `json:"probability"` // Chance the target is met on time
+	Assessment  string  `json:"assessment"`  // on_track|at_risk|unrealistic
 }
 
 // DORAReport DORA & Ops report
 type DORAReport struct {
-	LeadTimeP95Hours        float64        `json:"lead_time_p95_hours"`
-	DeploymentFrequencyWeek float64        `json:"deployment_frequency_per_week"`
-	ChangeFailRatePercent   float64        `json:"change_fail_rate_pct"`
-	MTTRHours               float64        `json:"mttr_hours"`
-	Bottlenecks             []Bottleneck   `json:"bottlenecks"`
-	Playbook                []PlaybookItem `json:"playbook"`
-	Experiments             []Experiment   `json:"experiments"`
+	LeadTimeP50Hours        float64          `json:"lead_time_p50_hours"`
+	LeadTimeP75Hours        float64          `json:"lead_time_p75_hours"`
+	LeadTimeP95Hours        float64          `json:"lead_time_p95_hours"`
+	MergeTimeP95Hours       float64          `json:"merge_time_p95_hours"`
+	DeploymentFrequencyWeek float64          `json:"deployment_frequency_per_week"`
+	ChangeFailRatePercent   float64          `json:"change_fail_rate_pct"`
+	MTTRHours               float64          `json:"mttr_hours"`
+	CycleTime               []CycleTimeStage `json:"cycle_time,omitempty"`
+	ReviewQuality           *ReviewQuality   `json:"review_quality,omitempty"`
+	BatchSize               *BatchSize       `json:"batch_size,omitempty"`
+	Pipelines               *PipelineStats   `json:"pipelines,omitempty"`
+	Flakiness               *Flakiness       `json:"flakiness,omitempty"`
+	Performance             DORAPerformance  `json:"performance"`
+	Bottlenecks             []Bottleneck     `json:"bottlenecks"`
+	Playbook                []PlaybookItem   `json:"playbook"`
+	Experiments             []Experiment     `json:"experiments"`
 }
 
 type Bottleneck struct {
-	Area     string `json:"area"` // review|pipeline|batch_size|release
+	Area     string `json:"area"` // coding|pickup|review|merge|deploy|pipeline|batch_size|release
 	Evidence string `json:"evidence"`
 }
 
@@ -163,10 +498,15 @@ type Experiment struct {
 
 // CommunityReport Community & Bus Factor report
 type CommunityReport struct {
-	BusFactor         int              `json:"bus_factor"`
-	OnboardingP50Days int              `json:"onboarding_p50_days"`
-	Roadmap           []RoadmapItem    `json:"roadmap"`
-	Visibility        []VisibilityItem `json:"visibility"`
+	BusFactor           int                 `json:"bus_factor"`
+	TruckFactor         *TruckFactorReport  `json:"truck_factor,omitempty"`
+	OnboardingP50Days   int                 `json:"onboarding_p50_days"`
+	Contributors        *ContributorMetrics `json:"contributors,omitempty"`
+	Health              *CommunityHealth    `json:"health,omitempty"`
+	ReviewerLoad        []ReviewerLoad      `json:"reviewer_load,omitempty"`
+	ReviewConcentration float64             `json:"review_concentration"`
+	Roadmap             []RoadmapItem       `json:"roadmap"`
+	Visibility          []VisibilityItem    `json:"visibility"`
 }
 
 type RoadmapItem struct {
@@ -0,0 +1,165 @@
+// Package webhook - Analyzer that scores events against repository metrics.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// DORASource calculates DORA metrics with a time series; metrics.EnhancedDORACalculator satisfies it
+type DORASource interface {
+	Calculate(ctx context.Context, request metrics.MetricsRequest) (*metrics.EnhancedDORAMetrics, error)
+}
+
+// CHISource calculates the code health of a repository; metrics.CHICalculator satisfies it
+type CHISource interface {
+	Calculate(ctx context.Context, repo types.Repository) (*types.CHIMetrics, error)
+}
+
+// MetricsAnalyzer is the AnalyzerActor that detects anomalies in the DORA time series and
+// the CHI history of the event's repository and reports them as "anomaly" insights
+type MetricsAnalyzer struct {
+	dora       DORASource
+	chi        CHISource
+	chiHistory *metrics.CHIHistoryStore
+	chiRepo    string                // "owner/name" whose code the CHI source measures
+	sources    map[string]DORASource // DORA sources of events by their source, e.g. "gitlab"
+	anomaly    metrics.AnomalyConfig
+	periodDays int
+}
+
+// NewMetricsAnalyzer creates an analyzer over the last 90 days of DORA metrics. Each CHI
+// calculation is recorded in chiHistory, whose history is scanned for level shifts; CHI is
+// only analyzed once SetCHIRepository names the repository it measures.
+// Either source may be nil to skip it.
+func NewMetricsAnalyzer(dora DORASource, chi CHISource, chiHistory *metrics.CHIHistoryStore) *MetricsAnalyzer {
+	return &MetricsAnalyzer{
+		dora:       dora,
+		chi:        chi,
+		chiHistory: chiHistory,
+		anomaly:    metrics.DefaultAnomalyConfig(),
+		periodDays: 90,
+	}
+}
+
+// SetCHIRepository names the "owner/name" repository whose code the CHI source measures, such
+// as the local clone. Only events of that repository get a CHI analysis, so the history of
+// other repositories never holds measurements of a different codebase.
+func (a *MetricsAnalyzer) SetCHIRepository(repository string) {
+	a.chiRepo = repository
+}
+
+// RegisterSource analyzes DORA metrics of events from source, such as "gitlab", with a
+// dedicated calculator
+func (a *MetricsAnalyzer) RegisterSource(source string, dora DORASource) {
+	if a.sources == nil {
+		a.sources = make(map[string]DORASource)
+	}
+	a.sources[source] = dora
+}
+
+// doraSourceFor selects the DORA source for the repository of event by the event source
+func (a *MetricsAnalyzer) doraSourceFor(event Event) DORASource {
+	if dora, ok := a.sources[event.Source]; ok {
+		return dora
+	}
+	return a.dora
+}
+
+// TriggerAnalysis detects anomalies for the repository of event. A source that fails is left
+// out and lowers the completeness of the result; the analysis fails only when every source does.
+func (a *MetricsAnalyzer) TriggerAnalysis(ctx context.Context, event Event) (*AnalysisResult, error) {
+	started := time.Now()
+
+	parts := strings.Split(event.Repository, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid repository %q: expected owner/name", event.Repository)
+	}
+	repo := types.Repository{Owner: parts[0], Name: parts[1], FullName: event.Repository}
+
+	var insights []AnalysisInsight
+	var sources []string
+	var failures []error
+	attempted := 0
+
+	if dora := a.doraSourceFor(event); dora != nil && wantsAnalysis(event, "dora") {
+		attempted++
+		anomalies, err := a.doraAnomalies(ctx, dora, repo, started)
+		if err != nil {
+			failures = append(failures, err)
+		} else {
+			sources = append(sources, "dora")
+			insights = append(insights, AnomalyInsights(anomalies)...)
+		}
+	}
+
+	measured := a.chiRepo != "" && strings.EqualFold(a.chiRepo, event.Repository)
+	if a.chi != nil && a.chiHistory != nil && measured && wantsAnalysis(event, "chi") {
+		attempted++
+		chi, err := a.chi.Calculate(ctx, repo)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("failed to calculate CHI metrics: %w", err))
+		} else {
+			a.chiHistory.Record(event.Repository, *chi)
+			sources = append(sources, "chi")
+			insights = append(insights, AnomalyInsights(metrics.DetectCHIAnomalies(a.chiHistory.History(event.Repository), a.anomaly))...)
+		}
+	}
+
+	if attempted > 0 && len(failures) == attempted {
+		return nil, fmt.Errorf("analysis of %s failed: %w", event.Repository, failures[0])
+	}
+
+	completeness := 1.0
+	if attempted > 0 {
+		completeness = float64(len(sources)) / float64(attempted)
+	}
+	return &AnalysisResult{
+		EventID:    event.ID,
+		Repository: event.Repository,
+		Insights:   insights,
+		Metadata: AnalysisMetadata{
+			ProcessingTimeMs: int(time.Since(started).Milliseconds()),
+			DataSources:      sources,
+			Confidence:       completeness,
+			Completeness:     completeness,
+		},
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// doraAnomalies calculates the daily DORA series of the analysis period and scans it for anomalies
+func (a *MetricsAnalyzer) doraAnomalies(ctx context.Context, source DORASource, repo types.Repository, now time.Time) ([]metrics.Anomaly, error) {
+	dora, err := source.Calculate(ctx, metrics.MetricsRequest{
+		Repository: repo,
+		TimeRange: metrics.TimeRange{
+			Start:    now.AddDate(0, 0, -a.periodDays),
+			End:      now,
+			Timezone: "UTC",
+		},
+		Granularity: "day",
+		UseCache:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate DORA metrics: %w", err)
+	}
+	return metrics.DetectDORAAnomalies(dora.TimeSeries, a.anomaly), nil
+}
+
+// wantsAnalysis reports whether event asks for the given analysis; events that name none get all
+func wantsAnalysis(event Event, analysis string) bool {
+	if len(event.Metadata.AnalysisTypes) == 0 {
+		return true
+	}
+	for _, requested := range event.Metadata.AnalysisTypes {
+		if requested == analysis {
+			return true
+		}
+	}
+	return false
+}
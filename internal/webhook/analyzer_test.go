@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+type stubDORASource struct {
+	series  []metrics.DORATimeSeriesPoint
+	err     error
+	request metrics.MetricsRequest
+}
+
+func (s *stubDORASource) Calculate(ctx context.Context, request metrics.MetricsRequest) (*metrics.EnhancedDORAMetrics, error) {
+	s.request = request
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &metrics.EnhancedDORAMetrics{TimeSeries: s.series}, nil
+}
+
+// stubCHISource returns the next coverage value on every calculation
+type stubCHISource struct {
+	coverage []float64
+	calls    int
+	err      error
+}
+
+func (s *stubCHISource) Calculate(ctx context.Context, repo types.Repository) (*types.CHIMetrics, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	coverage := s.coverage[s.calls%len(s.coverage)]
+	s.calls++
+	return &types.CHIMetrics{
+		Score:        75,
+		TestCoverage: coverage,
+		CalculatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, s.calls),
+	}, nil
+}
+
+// leadTimeSpikeSeries is a steady daily lead time with a spike on day 20
+func leadTimeSpikeSeries() []metrics.DORATimeSeriesPoint {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var points []metrics.DORATimeSeriesPoint
+	for i := 0; i < 30; i++ {
+		leadTime := 10.0 + float64(i%3)
+		if i == 20 {
+			leadTime = 80
+		}
+		points = append(points, metrics.DORATimeSeriesPoint{
+			Timestamp:       start.AddDate(0, 0, i),
+			LeadTimeHours:   leadTime,
+			DeploymentCount: 3,
+		})
+	}
+	return points
+}
+
+func hasAnomalyInsight(insights []AnalysisInsight, category, severity string) bool {
+	for _, insight := range insights {
+		if insight.Type == "anomaly" && insight.Category == category && insight.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMetricsAnalyzerDetectsDORAAnomalies(t *testing.T) {
+	dora := &stubDORASource{series: leadTimeSpikeSeries()}
+	analyzer := NewMetricsAnalyzer(dora, nil, nil)
+
+	result, err := analyzer.TriggerAnalysis(context.Background(), Event{ID: "evt-1", Repository: "acme/api"})
+	if err != nil {
+		t.Fatalf("TriggerAnalysis failed: %v", err)
+	}
+
+	if dora.request.Repository.FullName != "acme/api" || dora.request.Granularity != "day" {
+		t.Errorf("Expected a daily series of acme/api, got %+v", dora.request)
+	}
+	if days := dora.request.TimeRange.End.Sub(dora.request.TimeRange.Start).Hours() / 24; days != 90 {
+		t.Errorf("Expected a 90 day analysis period, got %.1f days", days)
+	}
+	if result.EventID != "evt-1" || result.Repository != "acme/api" {
+		t.Errorf("Unexpected result identity %q %q", result.EventID, result.Repository)
+	}
+	if !hasAnomalyInsight(result.Insights, "performance", metrics.SeverityCritical) {
+		t.Errorf("Expected a critical lead time anomaly insight, got %+v", result.Insights)
+	}
+	if result.Metadata.Completeness != 1 || len(result.Metadata.DataSources) != 1 {
+		t.Errorf("Expected DORA as the only complete source, got %+v", result.Metadata)
+	}
+}
+
+func TestMetricsAnalyzerRoutesDORABySource(t *testing.T) {
+	github := &stubDORASource{err: errors.New("not found")}
+	gitlab := &stubDORASource{series: leadTimeSpikeSeries()}
+	analyzer := NewMetricsAnalyzer(github, nil, nil)
+	analyzer.RegisterSource("gitlab", gitlab)
+
+	result, err := analyzer.TriggerAnalysis(context.Background(), Event{Source: "gitlab", Repository: "group/api"})
+	if err != nil {
+		t.Fatalf("Expected the GitLab calculator to serve GitLab events, got %v", err)
+	}
+	if gitlab.request.Repository.FullName != "group/api" || !hasAnomalyInsight(result.Insights, "performance", metrics.SeverityCritical) {
+		t.Errorf("Expected the lead time anomaly of group/api, got %+v", result.Insights)
+	}
+	if _, err := analyzer.TriggerAnalysis(context.Background(), Event{Source: "github", Repository: "acme/api"}); err == nil {
+		t.Error("Expected GitHub events to keep the default calculator")
+	}
+}
+
+func TestMetricsAnalyzerDetectsCHILevelShift(t *testing.T) {
+	coverage := make([]float64, 0, 20)
+	for i := 0; i < 20; i++ {
+		value := 80.0 + float64(i%2)
+		if i >= 10 {
+			value = 35.0 + float64(i%2)
+		}
+		coverage = append(coverage, value)
+	}
+	chi := &stubCHISource{coverage: coverage}
+	history := metrics.NewCHIHistoryStore(0)
+	analyzer := NewMetricsAnalyzer(nil, chi, history)
+	analyzer.SetCHIRepository("acme/api")
+
+	// Each event records one CHI calculation; the shift shows once enough history builds up
+	var result *AnalysisResult
+	for i := range coverage {
+		var err error
+		result, err = analyzer.TriggerAnalysis(context.Background(), Event{ID: "evt", Repository: "acme/api"})
+		if err != nil {
+			t.Fatalf("TriggerAnalysis %d failed: %v", i, err)
+		}
+	}
+
+	if got := len(history.History("acme/api")); got != len(coverage) {
+		t.Errorf("Expected %d recorded CHI calculations, got %d", len(coverage), got)
+	}
+	if !hasAnomalyInsight(result.Insights, "quality", metrics.SeverityCritical) {
+		t.Errorf("Expected a critical test coverage anomaly insight, got %+v", result.Insights)
+	}
+
+	// The clone measures acme/api only, so other repositories get no CHI analysis
+	other, err := analyzer.TriggerAnalysis(context.Background(), Event{ID: "evt", Repository: "acme/web"})
+	if err != nil {
+		t.Fatalf("TriggerAnalysis of another repository failed: %v", err)
+	}
+	if len(history.History("acme/web")) != 0 || len(other.Metadata.DataSources) != 0 {
+		t.Errorf("Expected no CHI analysis of acme/web, got %+v", other.Metadata)
+	}
+}
+
+func TestMetricsAnalyzerDegradesOnSourceErrors(t *testing.T) {
+	dora := &stubDORASource{series: leadTimeSpikeSeries()}
+	chi := &stubCHISource{err: errors.New("clone missing")}
+	analyzer := NewMetricsAnalyzer(dora, chi, metrics.NewCHIHistoryStore(0))
+	analyzer.SetCHIRepository("acme/api")
+
+	result, err := analyzer.TriggerAnalysis(context.Background(), Event{Repository: "acme/api"})
+	if err != nil {
+		t.Fatalf("Expected a partial result when only CHI fails, got %v", err)
+	}
+	if result.Metadata.Completeness != 0.5 || len(result.Metadata.DataSources) != 1 || result.Metadata.DataSources[0] != "dora" {
+		t.Errorf("Expected half completeness from DORA alone, got %+v", result.Metadata)
+	}
+
+	// Analysis types restrict the sources; when every requested source fails the analysis fails
+	event := Event{Repository: "acme/api", Metadata: EventMetadata{AnalysisTypes: []string{"chi"}}}
+	if _, err := analyzer.TriggerAnalysis(context.Background(), event); err == nil {
+		t.Error("Expected an error when the only requested source fails")
+	}
+	if _, err := analyzer.TriggerAnalysis(context.Background(), Event{Repository: "acme"}); err == nil {
+		t.Error("Expected an error for a repository without an owner")
+	}
+}
+
+func TestHandler_ProcessEventNotifiesMetricsAnalyzerAnomalies(t *testing.T) {
+	analyzer := NewMetricsAnalyzer(&stubDORASource{series: leadTimeSpikeSeries()}, nil, nil)
+	notifier := &recordingNotifier{}
+	handler := NewHandler(nil, analyzer, nil, nil)
+	handler.SetNotifier(notifier, "discord", "#delivery")
+
+	if err := handler.ProcessEvent(context.Background(), Event{ID: "evt-1", Repository: "acme/api"}); err != nil {
+		t.Fatalf("ProcessEvent failed: %v", err)
+	}
+
+	if len(notifier.events) == 0 {
+		t.Fatal("Expected the lead time spike to be notified")
+	}
+	if notifier.events[0].Priority != "critical" || notifier.events[0].Metadata["repository"] != "acme/api" {
+		t.Errorf("Unexpected notification %+v", notifier.events[0])
+	}
+
+	// The series still holds the spike on the next event, but it was already notified
+	notified := len(notifier.events)
+	if err := handler.ProcessEvent(context.Background(), Event{ID: "evt-2", Repository: "acme/api"}); err != nil {
+		t.Fatalf("ProcessEvent failed: %v", err)
+	}
+	if len(notifier.events) != notified {
+		t.Errorf("Expected no new notifications, got %+v", notifier.events[notified:])
+	}
+}
@@ -0,0 +1,168 @@
+// Package webhook - Anomaly insights and their routing to notifications.
+package webhook
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+// Notifier delivers notification events; services.DaemonService satisfies it
+type Notifier interface {
+	SendNotification(event types.NotificationEvent) error
+}
+
+// anomalyMetricNames are readable names for anomaly metrics
+var anomalyMetricNames = map[string]string{
+	"lead_time_hours":       "Lead time",
+	"deployment_count":      "Deployments",
+	"failure_count":         "Failures",
+	"change_failure_rate":   "Change failure rate",
+	"recovery_time_hours":   "Recovery time",
+	"chi_score":             "CHI score",
+	"duplication_pct":       "Duplication",
+	"cyclomatic_avg":        "Cyclomatic complexity",
+	"test_coverage_pct":     "Test coverage",
+	"maintainability_index": "Maintainability index",
+	"technical_debt_hours":  "Technical debt",
+}
+
+// AnomalyInsight converts a detected anomaly into an analysis insight of type "anomaly"
+func AnomalyInsight(anomaly metrics.Anomaly) AnalysisInsight {
+	name := anomalyMetricNames[anomaly.Metric]
+	if name == "" {
+		name = anomaly.Metric
+	}
+
+	category := "performance"
+	if anomaly.Source == "chi" {
+		category = "quality"
+	}
+
+	var title, description string
+	date := anomaly.Timestamp.Format("2006-01-02")
+	switch anomaly.Kind {
+	case metrics.AnomalyLevelShift:
+		title = fmt.Sprintf("%s shifted from %.2f to %.2f", name, anomaly.Expected, anomaly.Value)
+		description = fmt.Sprintf("%s changed level on %s: mean %.2f before, %.2f after (t=%.1f)",
+			name, date, anomaly.Expected, anomaly.Value, anomaly.Score)
+	default:
+		title = fmt.Sprintf("%s %s: %.2f (expected %.2f)", name, anomaly.Kind, anomaly.Value, anomaly.Expected)
+		description = fmt.Sprintf("%s on %s was %.2f against a baseline of %.2f (z=%.1f)",
+			name, date, anomaly.Value, anomaly.Expected, anomaly.Score)
+	}
+	if anomaly.Regression {
+		description += "; this is a regression"
+	}
+
+	impact := "low"
+	switch anomaly.Severity {
+	case metrics.SeverityCritical:
+		impact = "high"
+	case metrics.SeverityWarning:
+		impact = "medium"
+	}
+
+	return AnalysisInsight{
+		Key:         "anomaly:" + anomaly.Metric + ":" + date,
+		Type:        "anomaly",
+		Severity:    anomaly.Severity,
+		Category:    category,
+		Title:       title,
+		Description: description,
+		Confidence:  anomaly.Confidence,
+		Impact:      impact,
+		Effort:      "S",
+	}
+}
+
+// AnomalyInsights converts detected anomalies into analysis insights
+func AnomalyInsights(anomalies []metrics.Anomaly) []AnalysisInsight {
+	insights := make([]AnalysisInsight, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		insights = append(insights, AnomalyInsight(anomaly))
+	}
+	return insights
+}
+
+// SetNotifier routes warning and critical anomaly insights of every processed event
+// to notifier, using the given channel ("discord", "whatsapp", "email") and recipient
+func (h *Handler) SetNotifier(notifier Notifier, channel, recipient string) {
+	h.notifier = notifier
+	h.notifyChannel = channel
+	h.notifyRecipient = recipient
+}
+
+// notifiedRetention is how long an anomaly is remembered as notified; longer than the period
+// analyzed, so an anomaly has left the series before it is forgotten
+const notifiedRetention = 180 * 24 * time.Hour
+
+// notifyAnomalies sends one notification per warning or critical anomaly insight not notified
+// before for the repository. Delivery failures are logged so they never break the analysis
+// loop, and are retried with the next analysis.
+func (h *Handler) notifyAnomalies(result *AnalysisResult) {
+	if h.notifier == nil || result == nil {
+		return
+	}
+
+	h.notifiedMu.Lock()
+	defer h.notifiedMu.Unlock()
+	notified := h.notified[result.Repository]
+	if notified == nil {
+		notified = make(map[string]time.Time)
+		h.notified[result.Repository] = notified
+	}
+	now := time.Now()
+	for key, at := range notified {
+		if now.Sub(at) > notifiedRetention {
+			delete(notified, key)
+		}
+	}
+
+	for _, insight := range result.Insights {
+		if insight.Type != "anomaly" || insight.Severity == metrics.SeverityInfo {
+			continue
+		}
+		if _, ok := notified[insight.Key]; ok && insight.Key != "" {
+			continue
+		}
+
+		event := types.NotificationEvent{
+			Type:      h.notifyChannel,
+			Recipient: h.notifyRecipient,
+			Subject:   fmt.Sprintf("[%s] %s: %s", strings.ToUpper(insight.Severity), result.Repository, insight.Title),
+			Content:   insight.Description,
+			Priority:  notificationPriority(insight.Severity),
+			Metadata: map[string]interface{}{
+				"event_id":   result.EventID,
+				"repository": result.Repository,
+				"category":   insight.Category,
+				"confidence": insight.Confidence,
+			},
+			CreatedAt: time.Now(),
+		}
+		if err := h.notifier.SendNotification(event); err != nil {
+			log.Printf("failed to send anomaly notification for %s: %v", result.Repository, err)
+			continue
+		}
+		if insight.Key != "" {
+			notified[insight.Key] = now
+		}
+	}
+}
+
+// notificationPriority maps insight severities to notification priorities
+func notificationPriority(severity string) string {
+	switch severity {
+	case metrics.SeverityCritical:
+		return "critical"
+	case metrics.SeverityWarning:
+		return "high"
+	default:
+		return "low"
+	}
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/types"
+)
+
+type stubAnalyzer struct {
+	result *AnalysisResult
+}
+
+func (s *stubAnalyzer) TriggerAnalysis(ctx context.Context, event Event) (*AnalysisResult, error) {
+	return s.result, nil
+}
+
+type stubRecommender struct{}
+
+func (s *stubRecommender) GenerateRecommendations(ctx context.Context, analysis AnalysisResult) (*RecommendationSet, error) {
+	return &RecommendationSet{EventID: analysis.EventID}, nil
+}
+
+type recordingNotifier struct {
+	events []types.NotificationEvent
+}
+
+func (r *recordingNotifier) SendNotification(event types.NotificationEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestAnomalyInsight(t *testing.T) {
+	insight := AnomalyInsight(metrics.Anomaly{
+		Source:     "chi",
+		Metric:     "test_coverage_pct",
+		Kind:       metrics.AnomalyLevelShift,
+		Timestamp:  time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+		Value:      35,
+		Expected:   80,
+		Score:      12,
+		Regression: true,
+		Severity:   metrics.SeverityCritical,
+		Confidence: 0.99,
+	})
+
+	if insight.Type != "anomaly" {
+		t.Errorf("expected type anomaly, got %s", insight.Type)
+	}
+	if insight.Category != "quality" {
+		t.Errorf("expected category quality, got %s", insight.Category)
+	}
+	if insight.Severity != "critical" || insight.Impact != "high" {
+		t.Errorf("expected critical/high, got %s/%s", insight.Severity, insight.Impact)
+	}
+	if insight.Confidence != 0.99 {
+		t.Errorf("expected confidence 0.99, got %.2f", insight.Confidence)
+	}
+}
+
+func TestHandler_ProcessEventNotifiesAnomalies(t *testing.T) {
+	result := &AnalysisResult{
+		EventID:    "evt-1",
+		Repository: "test-owner/test-repo",
+		Insights: []AnalysisInsight{
+			{Type: "anomaly", Severity: "critical", Title: "Lead time spike"},
+			{Type: "anomaly", Severity: "warning", Title: "Deployments drop"},
+			{Type: "anomaly", Severity: "info", Title: "Coverage improved"},
+			{Type: "trend", Severity: "critical", Title: "Not an anomaly"},
+		},
+	}
+	notifier := &recordingNotifier{}
+	handler := NewHandler(nil, &stubAnalyzer{result: result}, &stubRecommender{}, nil)
+	handler.SetNotifier(notifier, "discord", "#delivery")
+
+	if err := handler.ProcessEvent(context.Background(), Event{ID: "evt-1", Source: "github"}); err != nil {
+		t.Fatalf("ProcessEvent failed: %v", err)
+	}
+
+	if len(notifier.events) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifier.events))
+	}
+	if notifier.events[0].Priority != "critical" || notifier.events[1].Priority != "high" {
+		t.Errorf("unexpected priorities %s, %s", notifier.events[0].Priority, notifier.events[1].Priority)
+	}
+	if notifier.events[0].Type != "discord" || notifier.events[0].Recipient != "#delivery" {
+		t.Errorf("unexpected routing %s -> %s", notifier.events[0].Type, notifier.events[0].Recipient)
+	}
+}
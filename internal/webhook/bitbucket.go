@@ -0,0 +1,163 @@
+// Package webhook provides Bitbucket Cloud and Server webhook handling with signature validation.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/services/bitbucket"
+)
+
+// BitbucketHandler handles Bitbucket Cloud and Server webhooks with signature validation and deduplication
+type BitbucketHandler struct {
+	validator *bitbucket.WebhookValidator
+	handler   EventHandler
+}
+
+// NewBitbucketHandler creates a new Bitbucket webhook handler
+func NewBitbucketHandler(webhookSecret string, handler EventHandler) *BitbucketHandler {
+	return &BitbucketHandler{
+		validator: bitbucket.NewWebhookValidator(webhookSecret),
+		handler:   handler,
+	}
+}
+
+// HandleBitbucketWebhook processes incoming Bitbucket webhooks with security validation
+func (bh *BitbucketHandler) HandleBitbucketWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	// Bitbucket Server sends an unsigned ping when a webhook is tested
+	if bitbucket.EventKey(r) == "diagnostics:ping" {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "ok",
+			"message": "pong",
+		})
+		return
+	}
+
+	// Validate signature and check for duplicates
+	if err := bh.validator.ValidateAndProcess(r, body); err != nil {
+		if strings.Contains(err.Error(), "duplicate delivery ID") {
+			// For duplicates, return 200 to avoid redelivery
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":  "ignored",
+				"reason":  "duplicate",
+				"message": "Webhook delivery already processed",
+			})
+			return
+		}
+
+		http.Error(w, fmt.Sprintf("Webhook validation failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	var rawEvent map[string]interface{}
+	if err := json.Unmarshal(body, &rawEvent); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	event := bh.convertBitbucketEvent(r, rawEvent)
+
+	if err := bh.handler.HandleEvent(r.Context(), event); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to process event: %v", err), eventErrorStatus(err))
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":     "accepted",
+		"event_id":   event.ID,
+		"delivery":   bitbucket.DeliveryID(r),
+		"event_type": event.Type,
+		"message":    "Bitbucket webhook processed successfully",
+		"timestamp":  time.Now().UTC(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// convertBitbucketEvent converts a Bitbucket webhook payload to internal Event format
+func (bh *BitbucketHandler) convertBitbucketEvent(r *http.Request, payload map[string]interface{}) Event {
+	eventID := bitbucket.DeliveryID(r)
+	if eventID == "" {
+		eventID = fmt.Sprintf("bitbucket-%d", time.Now().UnixNano())
+	}
+
+	eventType := mapBitbucketEventType(bitbucket.EventKey(r))
+	analysisTypes, priority, expectedLatency := forgeAnalysisConfig(eventType, bitbucketEventFailed(payload))
+
+	return Event{
+		ID:         eventID,
+		Type:       eventType,
+		Source:     "bitbucket",
+		Repository: extractForgeRepository(payload),
+		Timestamp:  time.Now().UTC(),
+		Payload:    payload,
+		Metadata: EventMetadata{
+			TriggerLevel:    1, // First level trigger
+			AnalysisTypes:   analysisTypes,
+			Priority:        priority,
+			RecursionDepth:  0,  // Starting depth
+			ParentEventID:   "", // No parent for external events
+			ExpectedLatency: expectedLatency,
+		},
+	}
+}
+
+// mapBitbucketEventType maps Bitbucket Cloud and Server event keys to internal event types
+func mapBitbucketEventType(eventKey string) string {
+	switch eventKey {
+	case "repo:push", "repo:refs_changed":
+		return "push"
+	case "pullrequest:created", "pr:opened":
+		return "pull_request_opened"
+	case "pullrequest:updated", "pr:from_ref_updated":
+		return "pull_request_synchronize"
+	case "pullrequest:approved", "pullrequest:unapproved", "pullrequest:changes_request_created",
+		"pr:reviewer:approved", "pr:reviewer:unapproved", "pr:reviewer:needs_work":
+		return "pull_request_review"
+	case "pullrequest:fulfilled", "pr:merged":
+		return "pull_request_merged"
+	case "pullrequest:rejected", "pr:declined", "pr:deleted":
+		return "pull_request_closed"
+	case "pullrequest:comment_created", "pr:comment:added":
+		return "pull_request_comment"
+	case "repo:commit_status_created", "repo:commit_status_updated":
+		// Pipelines and external CI report results as commit statuses
+		return "workflow_run"
+	case "issue:created":
+		return "issue_opened"
+	case "issue:comment_created":
+		return "issue_comment"
+	default:
+		return "bitbucket_" + strings.ReplaceAll(eventKey, ":", "_")
+	}
+}
+
+// bitbucketEventFailed reports whether a commit status payload is a failure
+func bitbucketEventFailed(payload map[string]interface{}) bool {
+	if status, ok := payload["commit_status"].(map[string]interface{}); ok {
+		if state, ok := status["state"].(string); ok {
+			return state == "FAILED"
+		}
+	}
+	return false
+}
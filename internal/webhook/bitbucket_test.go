@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBitbucketHandler_HandleBitbucketWebhook(t *testing.T) {
+	secret := "test-webhook-secret"
+
+	sign := func(payload []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name               string
+		fixture            string
+		headers            map[string]string
+		expectedType       string
+		expectedRepository string
+		expectedAnalysis   string
+	}{
+		{
+			name:               "Cloud pull request fulfilled",
+			fixture:            "bitbucket_cloud_pullrequest_fulfilled.json",
+			headers:            map[string]string{"X-Event-Key": "pullrequest:fulfilled", "X-Request-UUID": "cloud-delivery-1"},
+			expectedType:       "pull_request_merged",
+			expectedRepository: "acme/widgets",
+			expectedAnalysis:   "dora",
+		},
+		{
+			name:               "Cloud failed commit status",
+			fixture:            "bitbucket_cloud_commit_status_failed.json",
+			headers:            map[string]string{"X-Event-Key": "repo:commit_status_updated", "X-Request-UUID": "cloud-delivery-2"},
+			expectedType:       "workflow_run",
+			expectedRepository: "acme/widgets",
+			expectedAnalysis:   "failure_analysis",
+		},
+		{
+			name:               "Server pull request merged",
+			fixture:            "bitbucket_server_pr_merged.json",
+			headers:            map[string]string{"X-Event-Key": "pr:merged", "X-Request-Id": "server-delivery-1"},
+			expectedType:       "pull_request_merged",
+			expectedRepository: "ACME/widgets",
+			expectedAnalysis:   "dora",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &MockHandler{}
+			bitbucketHandler := NewBitbucketHandler(secret, mockHandler)
+
+			payload := loadFixture(t, tt.fixture)
+			req := httptest.NewRequest("POST", "/webhook/bitbucket", bytes.NewReader(payload))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			req.Header.Set("X-Hub-Signature", sign(payload))
+
+			rr := httptest.NewRecorder()
+			bitbucketHandler.HandleBitbucketWebhook(rr, req)
+
+			if rr.Code != 200 {
+				t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+
+			event := mockHandler.lastEvent
+			if event.Source != "bitbucket" {
+				t.Errorf("Expected source bitbucket, got %s", event.Source)
+			}
+			if event.Type != tt.expectedType {
+				t.Errorf("Expected type %s, got %s", tt.expectedType, event.Type)
+			}
+			if event.Repository != tt.expectedRepository {
+				t.Errorf("Expected repository %s, got %s", tt.expectedRepository, event.Repository)
+			}
+
+			found := false
+			for _, analysis := range event.Metadata.AnalysisTypes {
+				if analysis == tt.expectedAnalysis {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected analysis %s in %v", tt.expectedAnalysis, event.Metadata.AnalysisTypes)
+			}
+		})
+	}
+}
+
+func TestBitbucketHandler_Ping(t *testing.T) {
+	mockHandler := &MockHandler{}
+	bitbucketHandler := NewBitbucketHandler("test-webhook-secret", mockHandler)
+
+	req := httptest.NewRequest("POST", "/webhook/bitbucket", bytes.NewReader([]byte(`{"test": true}`)))
+	req.Header.Set("X-Event-Key", "diagnostics:ping")
+
+	rr := httptest.NewRecorder()
+	bitbucketHandler.HandleBitbucketWebhook(rr, req)
+
+	if rr.Code != 200 {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if mockHandler.lastEvent.ID != "" {
+		t.Error("Expected ping not to be processed as an event")
+	}
+}
+
+func TestHTTPHandler_DetectForgeWebhookSource(t *testing.T) {
+	handler := NewHTTPHandler(nil)
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		fixture        string
+		expectedSource string
+		expectedType   string
+	}{
+		{
+			name:           "Gitea",
+			headers:        map[string]string{"X-Gitea-Event": "pull_request"},
+			fixture:        "gitea_pull_request_merged.json",
+			expectedSource: "gitea",
+			expectedType:   "pull_request_merged",
+		},
+		{
+			name:           "Bitbucket Server",
+			headers:        map[string]string{"X-Event-Key": "pr:merged"},
+			fixture:        "bitbucket_server_pr_merged.json",
+			expectedSource: "bitbucket",
+			expectedType:   "pull_request_merged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/webhooks", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			var payload map[string]interface{}
+			if err := json.Unmarshal(loadFixture(t, tt.fixture), &payload); err != nil {
+				t.Fatalf("failed to parse fixture: %v", err)
+			}
+
+			event, err := handler.parseWebhookEvent(req, payload)
+			if err != nil {
+				t.Fatalf("parseWebhookEvent() failed: %v", err)
+			}
+			if event.Source != tt.expectedSource || event.Type != tt.expectedType {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.expectedSource, tt.expectedType, event.Source, event.Type)
+			}
+			if event.Repository == "unknown/repository" {
+				t.Error("Expected repository to be extracted")
+			}
+		})
+	}
+}
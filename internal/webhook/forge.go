@@ -0,0 +1,78 @@
+// Package webhook - Shared event handling for Gitea and Bitbucket webhooks
+package webhook
+
+import "fmt"
+
+// forgeAnalysisConfig determines what analysis should be performed for normalized forge
+// events. failed marks deployments and CI runs that did not succeed.
+func forgeAnalysisConfig(eventType string, failed bool) ([]string, string, string) {
+	switch eventType {
+	case "push":
+		return []string{"chi", "incremental_dora", "ai"}, "normal", "minutes"
+	case "pull_request_opened", "pull_request_synchronize":
+		return []string{"chi", "ai"}, "normal", "minutes"
+	case "pull_request_merged":
+		return []string{"dora", "chi", "ai"}, "high", "minutes"
+	case "pull_request_review":
+		return []string{"dora"}, "normal", "instant"
+	case "deployment", "deployment_status":
+		if failed {
+			return []string{"dora", "incident_analysis"}, "critical", "instant"
+		}
+		return []string{"dora"}, "normal", "minutes"
+	case "workflow_run":
+		if failed {
+			return []string{"dora", "failure_analysis"}, "high", "instant"
+		}
+		return []string{"incremental_dora"}, "low", "minutes"
+	case "release":
+		return []string{"dora", "chi", "ai", "executive"}, "high", "minutes"
+	case "issue_opened", "issue_closed", "issue_comment":
+		return []string{"community"}, "low", "hours"
+	default:
+		// Generic analysis for unknown events
+		return []string{"chi"}, "low", "hours"
+	}
+}
+
+// extractForgeRepository extracts "owner/repo" from Gitea, Bitbucket Cloud and Bitbucket Server payloads
+func extractForgeRepository(payload map[string]interface{}) string {
+	repo, ok := payload["repository"].(map[string]interface{})
+	if !ok {
+		// Bitbucket Server pull request events only carry the target repository
+		repo, ok = bitbucketServerTargetRepository(payload)
+		if !ok {
+			return "unknown/repository"
+		}
+	}
+
+	// Gitea and Bitbucket Cloud
+	if fullName, ok := repo["full_name"].(string); ok && fullName != "" {
+		return fullName
+	}
+
+	// Bitbucket Server: project key and repository slug
+	if project, ok := repo["project"].(map[string]interface{}); ok {
+		if key, ok := project["key"].(string); ok {
+			if slug, ok := repo["slug"].(string); ok {
+				return fmt.Sprintf("%s/%s", key, slug)
+			}
+		}
+	}
+
+	return "unknown/repository"
+}
+
+// bitbucketServerTargetRepository returns pullRequest.toRef.repository from a Bitbucket Server payload
+func bitbucketServerTargetRepository(payload map[string]interface{}) (map[string]interface{}, bool) {
+	pr, ok := payload["pullRequest"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	toRef, ok := pr["toRef"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	repo, ok := toRef["repository"].(map[string]interface{})
+	return repo, ok
+}
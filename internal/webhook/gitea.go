@@ -0,0 +1,170 @@
+// Package webhook provides Gitea/Forgejo webhook handling with signature validation.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/services/gitea"
+)
+
+// GiteaHandler handles Gitea and Forgejo webhooks with signature validation and deduplication
+type GiteaHandler struct {
+	validator *gitea.WebhookValidator
+	handler   EventHandler
+}
+
+// NewGiteaHandler creates a new Gitea webhook handler
+func NewGiteaHandler(webhookSecret string, handler EventHandler) *GiteaHandler {
+	return &GiteaHandler{
+		validator: gitea.NewWebhookValidator(webhookSecret),
+		handler:   handler,
+	}
+}
+
+// HandleGiteaWebhook processes incoming Gitea and Forgejo webhooks with security validation
+func (gh *GiteaHandler) HandleGiteaWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	// Validate signature and check for duplicates
+	if err := gh.validator.ValidateAndProcess(r, body); err != nil {
+		if strings.Contains(err.Error(), "duplicate delivery ID") {
+			// For duplicates, return 200 to avoid redelivery
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":  "ignored",
+				"reason":  "duplicate",
+				"message": "Webhook delivery already processed",
+			})
+			return
+		}
+
+		http.Error(w, fmt.Sprintf("Webhook validation failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	var rawEvent map[string]interface{}
+	if err := json.Unmarshal(body, &rawEvent); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	event := gh.convertGiteaEvent(r, rawEvent)
+
+	if err := gh.handler.HandleEvent(r.Context(), event); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to process event: %v", err), eventErrorStatus(err))
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":     "accepted",
+		"event_id":   event.ID,
+		"delivery":   gitea.DeliveryID(r),
+		"event_type": event.Type,
+		"message":    "Gitea webhook processed successfully",
+		"timestamp":  time.Now().UTC(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// convertGiteaEvent converts a Gitea webhook payload to internal Event format
+func (gh *GiteaHandler) convertGiteaEvent(r *http.Request, payload map[string]interface{}) Event {
+	eventID := gitea.DeliveryID(r)
+	if eventID == "" {
+		eventID = fmt.Sprintf("gitea-%d", time.Now().UnixNano())
+	}
+
+	eventType := mapGiteaEventType(gitea.EventName(r), gitea.EventType(r), payload)
+	analysisTypes, priority, expectedLatency := forgeAnalysisConfig(eventType, giteaEventFailed(payload))
+
+	return Event{
+		ID:         eventID,
+		Type:       eventType,
+		Source:     "gitea",
+		Repository: extractForgeRepository(payload),
+		Timestamp:  time.Now().UTC(),
+		Payload:    payload,
+		Metadata: EventMetadata{
+			TriggerLevel:    1, // First level trigger
+			AnalysisTypes:   analysisTypes,
+			Priority:        priority,
+			RecursionDepth:  0,  // Starting depth
+			ParentEventID:   "", // No parent for external events
+			ExpectedLatency: expectedLatency,
+		},
+	}
+}
+
+// mapGiteaEventType maps Gitea webhook events to internal event types. detailedType is the
+// X-Gitea-Event-Type header, which distinguishes review events sent as pull_request_*.
+func mapGiteaEventType(giteaEvent, detailedType string, payload map[string]interface{}) string {
+	if strings.HasPrefix(detailedType, "pull_request_review") {
+		return "pull_request_review"
+	}
+
+	switch giteaEvent {
+	case "push":
+		return "push"
+	case "pull_request":
+		if pr, ok := payload["pull_request"].(map[string]interface{}); ok {
+			if merged, ok := pr["merged"].(bool); ok && merged {
+				return "pull_request_merged"
+			}
+		}
+		if action, ok := payload["action"].(string); ok {
+			// Gitea reports new commits as "synchronized"
+			if action == "synchronized" {
+				action = "synchronize"
+			}
+			return fmt.Sprintf("pull_request_%s", action)
+		}
+		return "pull_request"
+	case "pull_request_approved", "pull_request_rejected", "pull_request_comment":
+		return "pull_request_review"
+	case "release":
+		return "release"
+	case "workflow_run", "status":
+		// Commit statuses carry CI results on instances without Actions
+		return "workflow_run"
+	case "issues":
+		if action, ok := payload["action"].(string); ok {
+			return fmt.Sprintf("issue_%s", action)
+		}
+		return "issue"
+	case "issue_comment":
+		return "issue_comment"
+	default:
+		return fmt.Sprintf("gitea_%s", giteaEvent)
+	}
+}
+
+// giteaEventFailed reports whether a workflow run or commit status payload is a failure
+func giteaEventFailed(payload map[string]interface{}) bool {
+	if workflowRun, ok := payload["workflow_run"].(map[string]interface{}); ok {
+		if conclusion, ok := workflowRun["conclusion"].(string); ok {
+			return conclusion == "failure"
+		}
+	}
+	// Commit status events carry the state at the top level
+	if state, ok := payload["state"].(string); ok {
+		return state == "failure" || state == "error"
+	}
+	return false
+}
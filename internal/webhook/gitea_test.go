@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadFixture reads a recorded webhook payload from testdata
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestGiteaHandler_HandleGiteaWebhook(t *testing.T) {
+	secret := "test-webhook-secret"
+
+	sign := func(payload []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name             string
+		fixture          string
+		headers          map[string]string
+		expectedStatus   int
+		expectedType     string
+		expectedPriority string
+	}{
+		{
+			name:    "merged pull request",
+			fixture: "gitea_pull_request_merged.json",
+			headers: map[string]string{
+				"X-Gitea-Event":      "pull_request",
+				"X-Gitea-Event-Type": "pull_request",
+				"X-Gitea-Delivery":   "gitea-delivery-1",
+			},
+			expectedStatus:   200,
+			expectedType:     "pull_request_merged",
+			expectedPriority: "high",
+		},
+		{
+			name:    "failed commit status from Forgejo",
+			fixture: "gitea_status_failure.json",
+			headers: map[string]string{
+				"X-Forgejo-Event":    "status",
+				"X-Forgejo-Delivery": "forgejo-delivery-1",
+			},
+			expectedStatus:   200,
+			expectedType:     "workflow_run",
+			expectedPriority: "high",
+		},
+		{
+			name:    "review approval",
+			fixture: "gitea_pull_request_merged.json",
+			headers: map[string]string{
+				"X-Gitea-Event":      "pull_request_approved",
+				"X-Gitea-Event-Type": "pull_request_review_approved",
+				"X-Gitea-Delivery":   "gitea-delivery-2",
+			},
+			expectedStatus:   200,
+			expectedType:     "pull_request_review",
+			expectedPriority: "normal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &MockHandler{}
+			giteaHandler := NewGiteaHandler(secret, mockHandler)
+
+			payload := loadFixture(t, tt.fixture)
+			req := httptest.NewRequest("POST", "/webhook/gitea", bytes.NewReader(payload))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			req.Header.Set("X-Gitea-Signature", sign(payload))
+
+			rr := httptest.NewRecorder()
+			giteaHandler.HandleGiteaWebhook(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+
+			event := mockHandler.lastEvent
+			if event.Source != "gitea" || event.Repository != "acme/widgets" {
+				t.Errorf("Expected gitea event for acme/widgets, got %s %s", event.Source, event.Repository)
+			}
+			if event.Type != tt.expectedType {
+				t.Errorf("Expected type %s, got %s", tt.expectedType, event.Type)
+			}
+			if event.Metadata.Priority != tt.expectedPriority {
+				t.Errorf("Expected priority %s, got %s", tt.expectedPriority, event.Metadata.Priority)
+			}
+		})
+	}
+}
+
+func TestGiteaHandler_RejectsInvalidSignature(t *testing.T) {
+	mockHandler := &MockHandler{}
+	giteaHandler := NewGiteaHandler("test-webhook-secret", mockHandler)
+
+	req := httptest.NewRequest("POST", "/webhook/gitea", bytes.NewReader(loadFixture(t, "gitea_pull_request_merged.json")))
+	req.Header.Set("X-Gitea-Event", "pull_request")
+	req.Header.Set("X-Gitea-Delivery", "gitea-delivery-3")
+	req.Header.Set("X-Gitea-Signature", "deadbeef")
+
+	rr := httptest.NewRecorder()
+	giteaHandler.HandleGiteaWebhook(rr, req)
+
+	if rr.Code != 401 {
+		t.Errorf("Expected status 401, got %d", rr.Code)
+	}
+	if mockHandler.lastEvent.ID != "" {
+		t.Error("Expected no event to be processed")
+	}
+}
+
+func TestMapGiteaEventType(t *testing.T) {
+	tests := []struct {
+		event    string
+		payload  map[string]interface{}
+		expected string
+	}{
+		{event: "push", expected: "push"},
+		{event: "pull_request", payload: map[string]interface{}{"action": "opened"}, expected: "pull_request_opened"},
+		{event: "pull_request", payload: map[string]interface{}{"action": "synchronized"}, expected: "pull_request_synchronize"},
+		{event: "pull_request", payload: map[string]interface{}{"action": "closed", "pull_request": map[string]interface{}{"merged": false}}, expected: "pull_request_closed"},
+		{event: "release", expected: "release"},
+		{event: "issues", payload: map[string]interface{}{"action": "opened"}, expected: "issue_opened"},
+		{event: "wiki", expected: "gitea_wiki"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := mapGiteaEventType(tt.event, "", tt.payload); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
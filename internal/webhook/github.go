@@ -81,7 +81,7 @@ func (gh *GitHubHandler) HandleGitHubWebhook(w http.ResponseWriter, r *http.Requ
 
 	// Process the event using the existing handler
 	if err := gh.handler.HandleEvent(r.Context(), event); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to process event: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Failed to process event: %v", err), eventErrorStatus(err))
 		return
 	}
 
@@ -3,7 +3,10 @@ package webhook
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/kubex-ecosystem/analyzer/internal/types"
@@ -36,8 +39,31 @@ type Handler struct {
 	analyzer    AnalyzerActor
 	recommender RecommenderActor
 	executor    ExecutorActor
+
+	// Optional delivery of anomaly insights, once per anomaly and repository
+	notifier        Notifier
+	notifyChannel   string
+	notifyRecipient string
+	notifiedMu      sync.Mutex
+	notified        map[string]map[string]time.Time // Repository -> anomaly key -> notified at
+
+	// Background processing without a queue
+	workers  chan struct{}
+	debounce time.Duration
+	mu       sync.Mutex
+	pending  map[string]Event // Repository -> coalesced event waiting to be processed
 }
 
+// Limits of background processing without a queue
+const (
+	defaultWorkers  = 2
+	defaultDebounce = 30 * time.Second
+	maxPending      = 100 // Repositories waiting for an analysis
+)
+
+// ErrTooManyPending is returned by HandleEvent when too many repositories wait for an analysis
+var ErrTooManyPending = errors.New("too many events waiting for analysis")
+
 // EventQueue interface for background job processing
 type EventQueue interface {
 	Enqueue(ctx context.Context, event Event, priority int) error
@@ -71,9 +97,10 @@ type AnalysisResult struct {
 
 // AnalysisInsight represents a specific insight from analysis
 type AnalysisInsight struct {
-	Type        string  `json:"type"`     // "trend", "anomaly", "recommendation"
-	Severity    string  `json:"severity"` // "info", "warning", "critical"
-	Category    string  `json:"category"` // "performance", "quality", "security"
+	Key         string  `json:"key,omitempty"` // Identifies the finding across analyses, e.g. "anomaly:lead_time_hours:2025-01-06"
+	Type        string  `json:"type"`          // "trend", "anomaly", "recommendation"
+	Severity    string  `json:"severity"`      // "info", "warning", "critical"
+	Category    string  `json:"category"`      // "performance", "quality", "security"
 	Title       string  `json:"title"`
 	Description string  `json:"description"`
 	Confidence  float64 `json:"confidence"` // 0.0-1.0
@@ -171,11 +198,30 @@ func NewHandler(queue EventQueue, analyzer AnalyzerActor, recommender Recommende
 		analyzer:    analyzer,
 		recommender: recommender,
 		executor:    executor,
+		notified:    make(map[string]map[string]time.Time),
+		workers:     make(chan struct{}, defaultWorkers),
+		debounce:    defaultDebounce,
+		pending:     make(map[string]Event),
 	}
 }
 
-// HandleEvent processes an incoming webhook event
+// SetBackgroundLimits sets how many events are processed at once without a queue, and how
+// long events of a repository are collected before its analysis starts
+func (h *Handler) SetBackgroundLimits(workers int, debounce time.Duration) {
+	if workers < 1 {
+		workers = 1
+	}
+	h.workers = make(chan struct{}, workers)
+	h.debounce = debounce
+}
+
+// HandleEvent processes an incoming webhook event. Without a queue the event is processed in
+// the background, see schedule.
 func (h *Handler) HandleEvent(ctx context.Context, event Event) error {
+	if h.eventQueue == nil {
+		return h.schedule(event)
+	}
+
 	// Determine priority based on event metadata
 	priority := h.calculatePriority(event)
 
@@ -187,6 +233,56 @@ func (h *Handler) HandleEvent(ctx context.Context, event Event) error {
 	return nil
 }
 
+// schedule processes event in the background once the debounce delay has passed and a worker
+// is free. Events of a repository already waiting are coalesced into one analysis that runs
+// every analysis any of them asked for.
+func (h *Handler) schedule(event Event) error {
+	h.mu.Lock()
+	if waiting, ok := h.pending[event.Repository]; ok {
+		event.Metadata.AnalysisTypes = mergeAnalysisTypes(waiting.Metadata.AnalysisTypes, event.Metadata.AnalysisTypes)
+		h.pending[event.Repository] = event
+		h.mu.Unlock()
+		return nil
+	}
+	if len(h.pending) >= maxPending {
+		h.mu.Unlock()
+		return ErrTooManyPending
+	}
+	h.pending[event.Repository] = event
+	workers, debounce := h.workers, h.debounce
+	h.mu.Unlock()
+
+	go func() {
+		time.Sleep(debounce)
+		workers <- struct{}{}
+		defer func() { <-workers }()
+
+		h.mu.Lock()
+		latest := h.pending[event.Repository]
+		delete(h.pending, event.Repository)
+		h.mu.Unlock()
+
+		if err := h.ProcessEvent(context.Background(), latest); err != nil {
+			log.Printf("failed to process webhook event %s: %v", latest.ID, err)
+		}
+	}()
+	return nil
+}
+
+// mergeAnalysisTypes combines the analyses asked for by two events; none means all
+func mergeAnalysisTypes(a, b []string) []string {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	merged := append([]string{}, a...)
+	for _, analysis := range b {
+		if !wantsAnalysis(Event{Metadata: EventMetadata{AnalysisTypes: merged}}, analysis) {
+			merged = append(merged, analysis)
+		}
+	}
+	return merged
+}
+
 // ProcessEvent executes the meta-recursive analysis loop
 func (h *Handler) ProcessEvent(ctx context.Context, event Event) error {
 	// Step 1: Trigger Analysis
@@ -194,6 +290,10 @@ func (h *Handler) ProcessEvent(ctx context.Context, event Event) error {
 	if err != nil {
 		return fmt.Errorf("analysis failed: %w", err)
 	}
+	h.notifyAnomalies(analysisResult)
+	if h.recommender == nil {
+		return nil
+	}
 
 	// Step 2: Generate Recommendations
 	recommendations, err := h.recommender.GenerateRecommendations(ctx, *analysisResult)
@@ -202,7 +302,7 @@ func (h *Handler) ProcessEvent(ctx context.Context, event Event) error {
 	}
 
 	// Step 3: Execute Recommendations (if auto-execution is enabled)
-	if h.shouldAutoExecute(event, *recommendations) {
+	if h.executor != nil && h.shouldAutoExecute(event, *recommendations) {
 		executionResult, err := h.executor.ExecuteRecommendations(ctx, *recommendations)
 		if err != nil {
 			return fmt.Errorf("recommendation execution failed: %w", err)
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingAnalyzer records the events it analyzes and blocks until release is closed
+type countingAnalyzer struct {
+	mu      sync.Mutex
+	events  []Event
+	release chan struct{}
+}
+
+func (c *countingAnalyzer) TriggerAnalysis(ctx context.Context, event Event) (*AnalysisResult, error) {
+	c.mu.Lock()
+	c.events = append(c.events, event)
+	c.mu.Unlock()
+	<-c.release
+	return &AnalysisResult{EventID: event.ID, Repository: event.Repository}, nil
+}
+
+func (c *countingAnalyzer) analyzed() []Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Event{}, c.events...)
+}
+
+func TestHandleEventCoalescesAndBoundsBackgroundAnalyses(t *testing.T) {
+	analyzer := &countingAnalyzer{release: make(chan struct{})}
+	handler := NewHandler(nil, analyzer, nil, nil)
+	handler.SetBackgroundLimits(1, 20*time.Millisecond)
+
+	// Events of a repository arriving within the debounce delay run one analysis
+	for i, analysis := range []string{"dora", "chi", "dora"} {
+		event := Event{ID: fmt.Sprintf("evt-%d", i), Repository: "acme/api", Metadata: EventMetadata{AnalysisTypes: []string{analysis}}}
+		if err := handler.HandleEvent(context.Background(), event); err != nil {
+			t.Fatalf("HandleEvent %d failed: %v", i, err)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(analyzer.analyzed()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The single worker is busy, so other repositories wait up to the pending limit
+	for i := 0; i < maxPending; i++ {
+		if err := handler.HandleEvent(context.Background(), Event{Repository: fmt.Sprintf("acme/repo-%d", i)}); err != nil {
+			t.Fatalf("HandleEvent of repository %d failed: %v", i, err)
+		}
+	}
+	if err := handler.HandleEvent(context.Background(), Event{Repository: "acme/one-too-many"}); !errors.Is(err, ErrTooManyPending) {
+		t.Errorf("Expected ErrTooManyPending, got %v", err)
+	}
+
+	analyzed := analyzer.analyzed()
+	close(analyzer.release)
+	if len(analyzed) != 1 {
+		t.Fatalf("Expected one analysis while the worker is busy, got %d", len(analyzed))
+	}
+	if analyzed[0].ID != "evt-2" || len(analyzed[0].Metadata.AnalysisTypes) != 2 {
+		t.Errorf("Expected the latest event asking for dora and chi, got %+v", analyzed[0])
+	}
+}
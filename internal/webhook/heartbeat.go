@@ -0,0 +1,267 @@
+// Package webhook provides a WakaTime-compatible heartbeat receiver for editor plugins.
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// HeartbeatTeamUser is the user of team API keys in HEARTBEAT_API_KEYS ("key:*"). A team key
+// sends no heartbeats but reads the coding time of any user, and of every user as "current".
+const HeartbeatTeamUser = "*"
+
+// HeartbeatStore stores editor heartbeats and aggregates them into coding time
+type HeartbeatStore interface {
+	Add(user string, heartbeats ...metrics.Heartbeat) error
+	CodingTime(user, repo string, since, until time.Time) metrics.CodingTime
+}
+
+// HeartbeatHandler implements the parts of the WakaTime API used by editor plugins, so they
+// can send heartbeats to the gateway instead of wakatime.com. Point a plugin at it with
+// api_url = https://<gateway>/api/v1 in ~/.wakatime.cfg.
+type HeartbeatHandler struct {
+	store   HeartbeatStore
+	apiKeys map[string]string // API key -> user
+}
+
+// NewHeartbeatHandler creates a heartbeat receiver. apiKeys maps each accepted API key to
+// its user; requests with other keys are rejected.
+func NewHeartbeatHandler(store HeartbeatStore, apiKeys map[string]string) *HeartbeatHandler {
+	return &HeartbeatHandler{
+		store:   store,
+		apiKeys: apiKeys,
+	}
+}
+
+// ParseHeartbeatAPIKeys parses "key:user" pairs separated by commas, as in HEARTBEAT_API_KEYS.
+// A user of "*" makes a team key.
+func ParseHeartbeatAPIKeys(value string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, user, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && key != "" && user != "" {
+			keys[key] = user
+		}
+	}
+	return keys
+}
+
+// HandleUsers serves /api/v1/users/{user}/..., where user is "current" or the API key's user:
+// heartbeats and heartbeats.bulk (POST), statusbar/today and summaries (GET). Team keys read
+// the summaries of any user, and of the whole team as "current".
+func (hh *HeartbeatHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	user, ok := hh.authenticate(r)
+	if !ok {
+		http.Error(w, "Invalid API key", http.StatusUnauthorized)
+		return
+	}
+
+	pathUser, endpoint, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/users/"), "/")
+	if user == HeartbeatTeamUser {
+		if strings.HasPrefix(endpoint, "heartbeats") {
+			http.Error(w, "Team keys cannot send heartbeats", http.StatusForbidden)
+			return
+		}
+		// An empty user covers every user of the store
+		user = ""
+		if pathUser != "current" {
+			user = pathUser
+		}
+	} else if pathUser != "current" && !strings.EqualFold(pathUser, user) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch endpoint {
+	case "heartbeats":
+		hh.handleHeartbeats(w, r, user, false)
+	case "heartbeats.bulk":
+		hh.handleHeartbeats(w, r, user, true)
+	case "statusbar/today":
+		hh.handleStatusBar(w, r, user)
+	case "summaries":
+		hh.handleSummaries(w, r, user)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authenticate resolves the user of the request's API key
+func (hh *HeartbeatHandler) authenticate(r *http.Request) (string, bool) {
+	return AuthenticateAPIKey(r, hh.apiKeys)
+}
+
+// AuthenticateAPIKey resolves the user of the API key in apiKeys, sent the way WakaTime accepts
+// it: Basic auth with the base64 key, a Bearer token, or the api_key query parameter
+func AuthenticateAPIKey(r *http.Request, apiKeys map[string]string) (string, bool) {
+	key := r.URL.Query().Get("api_key")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, credentials, _ := strings.Cut(auth, " ")
+		switch strings.ToLower(scheme) {
+		case "basic":
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(credentials))
+			if err != nil {
+				return "", false
+			}
+			// Some clients send "key:" as user and password
+			key = strings.TrimSuffix(string(decoded), ":")
+		case "bearer":
+			key = strings.TrimSpace(credentials)
+		}
+	}
+	if key == "" {
+		return "", false
+	}
+
+	for candidate, user := range apiKeys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			return user, true
+		}
+	}
+	return "", false
+}
+
+// handleHeartbeats stores one heartbeat, or an array of them for the bulk endpoint
+func (hh *HeartbeatHandler) handleHeartbeats(w http.ResponseWriter, r *http.Request, user string, bulk bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 8<<20))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var heartbeats []metrics.Heartbeat
+	if bulk {
+		err = json.Unmarshal(body, &heartbeats)
+	} else {
+		var heartbeat metrics.Heartbeat
+		err = json.Unmarshal(body, &heartbeat)
+		heartbeats = append(heartbeats, heartbeat)
+	}
+	if err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := hh.store.Add(user, heartbeats...); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid heartbeat: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if !bulk {
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": heartbeats[0]})
+		return
+	}
+	// The bulk response pairs each heartbeat with its status, as plugins expect
+	responses := make([][]interface{}, len(heartbeats))
+	for i, heartbeat := range heartbeats {
+		responses[i] = []interface{}{map[string]interface{}{"data": heartbeat}, http.StatusCreated}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+}
+
+// handleStatusBar returns today's coding time shown in editor status bars
+func (hh *HeartbeatHandler) handleStatusBar(w http.ResponseWriter, r *http.Request, user string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	summary := wakaTimeSummary(today, hh.store.CodingTime(user, "", today, today.Add(24*time.Hour)))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": summary})
+}
+
+// handleSummaries returns daily summaries between the start and end dates (YYYY-MM-DD,
+// inclusive), optionally for one project, in the format of WakaTime's summaries endpoint
+func (hh *HeartbeatHandler) handleSummaries(w http.ResponseWriter, r *http.Request, user string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	start, err := time.Parse("2006-01-02", query.Get("start"))
+	if err != nil {
+		http.Error(w, "start parameter (YYYY-MM-DD) is required", http.StatusBadRequest)
+		return
+	}
+	end, err := time.Parse("2006-01-02", query.Get("end"))
+	if err != nil {
+		http.Error(w, "end parameter (YYYY-MM-DD) is required", http.StatusBadRequest)
+		return
+	}
+	if end.Before(start) || end.Sub(start) > 366*24*time.Hour {
+		http.Error(w, "end must be after start and within a year", http.StatusBadRequest)
+		return
+	}
+
+	var days []map[string]interface{}
+	for day := start; !day.After(end); day = day.Add(24 * time.Hour) {
+		days = append(days, wakaTimeSummary(day, hh.store.CodingTime(user, query.Get("project"), day, day.Add(24*time.Hour))))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  days,
+		"start": start,
+		"end":   end.Add(24*time.Hour - time.Second),
+	})
+}
+
+// wakaTimeSummary renders a day's coding time as a WakaTime summary
+func wakaTimeSummary(day time.Time, codingTime metrics.CodingTime) map[string]interface{} {
+	languages := make([]map[string]interface{}, 0, len(codingTime.Languages))
+	for _, language := range codingTime.Languages {
+		languages = append(languages, wakaTimeDuration(language.Name, language.Hours))
+	}
+	projects := make([]map[string]interface{}, 0, len(codingTime.Projects))
+	for _, project := range codingTime.Projects {
+		projects = append(projects, wakaTimeDuration(project.Name, project.Hours))
+	}
+
+	return map[string]interface{}{
+		"grand_total": wakaTimeDuration("", codingTime.TotalHours),
+		"languages":   languages,
+		"projects":    projects,
+		"range": map[string]interface{}{
+			"date":  day.Format("2006-01-02"),
+			"start": day,
+			"end":   day.Add(24*time.Hour - time.Second),
+		},
+	}
+}
+
+// wakaTimeDuration renders hours the way WakaTime reports durations
+func wakaTimeDuration(name string, hours float64) map[string]interface{} {
+	seconds := hours * 3600
+	whole := int(seconds)
+	duration := map[string]interface{}{
+		"total_seconds": seconds,
+		"hours":         whole / 3600,
+		"minutes":       whole % 3600 / 60,
+		"digital":       fmt.Sprintf("%d:%02d", whole/3600, whole%3600/60),
+		"text":          fmt.Sprintf("%d hrs %d mins", whole/3600, whole%3600/60),
+	}
+	if name != "" {
+		duration["name"] = name
+	}
+	return duration
+}
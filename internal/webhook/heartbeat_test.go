@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+	"github.com/kubex-ecosystem/analyzer/internal/repositories"
+)
+
+func TestHeartbeatHandler_HandleUsers(t *testing.T) {
+	store := metrics.NewHeartbeatStore(0)
+	handler := NewHeartbeatHandler(store, ParseHeartbeatAPIKeys("alice-key:alice, bob-key:bob, team-key:*"))
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("alice-key"))
+
+	// 10:00, 10:05, 10:10 coding in Go, then 11:00 after an idle gap
+	bulk := `[
+		{"entity":"/src/api/main.go","type":"file","category":"coding","time":1736157600,"project":"api","language":"Go"},
+		{"entity":"/src/api/main.go","type":"file","category":"coding","time":1736157900,"project":"api","language":"Go","is_write":true},
+		{"entity":"/src/api/README.md","type":"file","category":"coding","time":1736158200,"project":"api","language":"Markdown"},
+		{"entity":"/src/api/main.go","type":"file","category":"coding","time":1736161200,"project":"api","language":"Go"}
+	]`
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		auth           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "bulk heartbeats",
+			method:         http.MethodPost,
+			path:           "/api/v1/users/current/heartbeats.bulk",
+			auth:           basic,
+			body:           bulk,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "single heartbeat with bearer token",
+			method:         http.MethodPost,
+			path:           "/api/v1/users/bob/heartbeats",
+			auth:           "Bearer bob-key",
+			body:           `{"entity":"/src/web/app.ts","type":"file","time":1736157600,"project":"web","language":"TypeScript"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid api key",
+			method:         http.MethodPost,
+			path:           "/api/v1/users/current/heartbeats",
+			auth:           "Bearer wrong",
+			body:           `{"entity":"main.go","time":1736157600}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "other user",
+			method:         http.MethodPost,
+			path:           "/api/v1/users/bob/heartbeats",
+			auth:           basic,
+			body:           `{"entity":"main.go","time":1736157600}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "team key sending heartbeats",
+			method:         http.MethodPost,
+			path:           "/api/v1/users/current/heartbeats",
+			auth:           "Bearer team-key",
+			body:           `{"entity":"main.go","time":1736157600}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing entity",
+			method:         http.MethodPost,
+			path:           "/api/v1/users/current/heartbeats",
+			auth:           basic,
+			body:           `{"time":1736157600}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown endpoint",
+			method:         http.MethodGet,
+			path:           "/api/v1/users/current/goals",
+			auth:           basic,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Authorization", tt.auth)
+			w := httptest.NewRecorder()
+
+			handler.HandleUsers(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	since := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	codingTime, err := store.GetCodingTime(context.Background(), "alice", "acme/api", since)
+	if err != nil {
+		t.Fatalf("GetCodingTime() failed: %v", err)
+	}
+	// Two 5 minute gaps count, the 50 minute idle gap does not
+	if math.Abs(codingTime.CodingHours-10.0/60) > 1e-9 {
+		t.Errorf("Expected 10 minutes of coding, got %.4f hours", codingTime.CodingHours)
+	}
+	if len(codingTime.Languages) != 1 || codingTime.Languages[0].Name != "Go" {
+		t.Errorf("Expected all time credited to Go, got %+v", codingTime.Languages)
+	}
+
+	everyone, _ := store.GetCodingTime(context.Background(), "", "", since)
+	if len(everyone.Projects) != 1 || everyone.Projects[0].Name != "api" {
+		t.Errorf("Expected a single heartbeat of bob to add no time, got %+v", everyone.Projects)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/current/summaries?start=2025-01-06&end=2025-01-07&project=api", nil)
+	req.Header.Set("Authorization", basic)
+	w := httptest.NewRecorder()
+	handler.HandleUsers(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var summaries struct {
+		Data []struct {
+			GrandTotal struct {
+				TotalSeconds float64 `json:"total_seconds"`
+			} `json:"grand_total"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&summaries); err != nil {
+		t.Fatalf("Failed to decode summaries: %v", err)
+	}
+	if len(summaries.Data) != 2 {
+		t.Fatalf("Expected 2 daily summaries, got %d", len(summaries.Data))
+	}
+	if summaries.Data[0].GrandTotal.TotalSeconds != 600 || summaries.Data[1].GrandTotal.TotalSeconds != 0 {
+		t.Errorf("Expected 600 seconds on the first day only, got %+v", summaries.Data)
+	}
+}
+
+func TestHeartbeatHandler_TeamCodingTimeOverHTTP(t *testing.T) {
+	store := metrics.NewHeartbeatStore(0)
+	now := time.Now().Truncate(time.Second)
+	for user, minutes := range map[string]int{"alice": 10, "bob": 12} {
+		heartbeats := []metrics.Heartbeat{
+			{Entity: "main.go", Time: float64(now.Add(-time.Duration(minutes) * time.Minute).Unix()), Project: "api", Language: "Go"},
+			{Entity: "main.go", Time: float64(now.Unix()), Project: "api", Language: "Go"},
+		}
+		if err := store.Add(user, heartbeats...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := NewHeartbeatHandler(store, ParseHeartbeatAPIKeys("alice-key:alice,team-key:*"))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/users/", handler.HandleUsers)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	since := now.Add(-24 * time.Hour)
+	tests := []struct {
+		name    string
+		key     string
+		user    string
+		minutes float64
+	}{
+		{"team key without user", "team-key", "", 22},
+		{"team key for one user", "team-key", "bob", 12},
+		{"user key without user", "alice-key", "", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := repositories.NewWakaTimeClientWithBaseURL(tt.key, server.URL+"/api/v1")
+			codingTime, err := client.GetCodingTime(context.Background(), tt.user, "api", since)
+			if err != nil {
+				t.Fatalf("GetCodingTime() failed: %v", err)
+			}
+			if math.Abs(codingTime.TotalHours*60-tt.minutes) > 1e-6 {
+				t.Errorf("Expected %.0f minutes, got %.2f", tt.minutes, codingTime.TotalHours*60)
+			}
+		})
+	}
+}
@@ -3,12 +3,15 @@ package webhook
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kubex-ecosystem/analyzer/internal/services/bitbucket"
+	"github.com/kubex-ecosystem/analyzer/internal/services/gitea"
 )
 
 // HTTPHandler handles HTTP webhook requests
@@ -54,7 +57,7 @@ func (h *HTTPHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 
 	// Process event asynchronously
 	if err := h.handler.HandleEvent(r.Context(), event); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to process event: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Failed to process event: %v", err), eventErrorStatus(err))
 		return
 	}
 
@@ -71,6 +74,14 @@ func (h *HTTPHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// eventErrorStatus is the HTTP status of a HandleEvent error: 503 while too many analyses wait
+func eventErrorStatus(err error) int {
+	if errors.Is(err, ErrTooManyPending) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
 // parseWebhookEvent converts raw webhook data to internal Event format
 func (h *HTTPHandler) parseWebhookEvent(r *http.Request, rawEvent map[string]interface{}) (Event, error) {
 	// Generate unique event ID
@@ -115,6 +126,16 @@ func (h *HTTPHandler) detectWebhookSource(r *http.Request, payload map[string]in
 		return "gitlab", h.mapGitLabEvent(gitlabEvent, payload)
 	}
 
+	// Gitea and Forgejo webhook detection (Forgejo also sends the Gitea headers)
+	if giteaEvent := gitea.EventName(r); giteaEvent != "" {
+		return "gitea", mapGiteaEventType(giteaEvent, gitea.EventType(r), payload)
+	}
+
+	// Bitbucket Cloud and Server webhook detection
+	if eventKey := bitbucket.EventKey(r); eventKey != "" {
+		return "bitbucket", mapBitbucketEventType(eventKey)
+	}
+
 	// Jenkins webhook detection
 	if userAgent := r.Header.Get("User-Agent"); userAgent == "Jenkins" {
 		return "jenkins", "deployment"
@@ -201,6 +222,11 @@ func (h *HTTPHandler) extractRepository(payload map[string]interface{}) string {
 		}
 	}
 
+	// Bitbucket Server format
+	if repo := extractForgeRepository(payload); repo != "unknown/repository" {
+		return repo
+	}
+
 	// Jenkins or custom format
 	if repo, ok := payload["repository"].(string); ok {
 		return repo
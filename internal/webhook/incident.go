@@ -0,0 +1,144 @@
+// Package webhook provides a generic incident webhook feeding MTTR and change failure rate.
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+// IncidentRecorder stores incidents received from external systems
+type IncidentRecorder interface {
+	Record(incident metrics.Incident) error
+}
+
+// IncidentHandler receives incident open/resolve notifications from any tool
+type IncidentHandler struct {
+	recorder IncidentRecorder
+	token    string
+}
+
+// IncidentPayload is the generic incident webhook body
+type IncidentPayload struct {
+	ID            string     `json:"id"`
+	Source        string     `json:"source,omitempty"`
+	Repository    string     `json:"repository"` // "owner/repo"
+	Title         string     `json:"title"`
+	Severity      string     `json:"severity"`
+	Status        string     `json:"status"` // "open", "resolved"
+	Environment   string     `json:"environment,omitempty"`
+	DeploymentSHA string     `json:"deployment_sha,omitempty"`
+	URL           string     `json:"url,omitempty"`
+	OpenedAt      *time.Time `json:"opened_at,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+}
+
+// NewIncidentHandler creates a new incident webhook handler.
+// Requests must carry token in the X-Incident-Token header; without a token every request is
+// rejected, since incidents change MTTR and change failure rate.
+func NewIncidentHandler(recorder IncidentRecorder, token string) *IncidentHandler {
+	return &IncidentHandler{
+		recorder: recorder,
+		token:    token,
+	}
+}
+
+// HandleIncidentWebhook processes incoming incident webhooks
+func (ih *IncidentHandler) HandleIncidentWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	provided := r.Header.Get("X-Incident-Token")
+	if ih.token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(ih.token)) != 1 {
+		http.Error(w, "Invalid incident token", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var payload IncidentPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	incident, err := payload.toIncident(time.Now().UTC())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid incident: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := ih.recorder.Record(incident); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to record incident: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":      "recorded",
+		"incident_id": incident.ID,
+		"resolved":    incident.ResolvedAt != nil,
+		"timestamp":   time.Now().UTC(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// toIncident converts the webhook payload to a metrics incident
+func (p IncidentPayload) toIncident(now time.Time) (metrics.Incident, error) {
+	if p.ID == "" {
+		return metrics.Incident{}, fmt.Errorf("id is required")
+	}
+
+	incident := metrics.Incident{
+		ID:            p.ID,
+		Source:        p.Source,
+		Repository:    p.Repository,
+		Title:         p.Title,
+		Severity:      p.Severity,
+		Environment:   p.Environment,
+		DeploymentSHA: p.DeploymentSHA,
+		URL:           p.URL,
+		OpenedAt:      now,
+		ResolvedAt:    p.ResolvedAt,
+	}
+	if incident.Source == "" {
+		incident.Source = "webhook"
+	}
+
+	switch p.Status {
+	case "", "open", "triggered", "acknowledged":
+	case "resolved", "closed":
+		if incident.ResolvedAt == nil {
+			incident.ResolvedAt = &now
+		}
+	default:
+		return metrics.Incident{}, fmt.Errorf("unsupported status %q", p.Status)
+	}
+
+	// Resolve-only updates omit opened_at; the store keeps the original open time
+	if p.OpenedAt != nil {
+		incident.OpenedAt = *p.OpenedAt
+	} else if incident.ResolvedAt != nil {
+		incident.OpenedAt = *incident.ResolvedAt
+	}
+
+	if incident.ResolvedAt != nil && incident.ResolvedAt.Before(incident.OpenedAt) {
+		return metrics.Incident{}, fmt.Errorf("resolved_at is before opened_at")
+	}
+
+	return incident, nil
+}
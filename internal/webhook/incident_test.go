@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubex-ecosystem/analyzer/internal/metrics"
+)
+
+func TestIncidentHandler_HandleIncidentWebhook(t *testing.T) {
+	store := metrics.NewIncidentStore()
+	handler := NewIncidentHandler(store, "secret-token")
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "open incident",
+			method:         http.MethodPost,
+			token:          "secret-token",
+			body:           `{"id":"INC-1","repository":"test-owner/test-repo","title":"API down","severity":"critical","status":"open","opened_at":"2025-01-10T10:00:00Z"}`,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "resolve incident",
+			method:         http.MethodPost,
+			token:          "secret-token",
+			body:           `{"id":"INC-1","repository":"test-owner/test-repo","status":"resolved","resolved_at":"2025-01-10T12:30:00Z"}`,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "invalid token",
+			method:         http.MethodPost,
+			token:          "wrong",
+			body:           `{"id":"INC-2","status":"open"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing id",
+			method:         http.MethodPost,
+			token:          "secret-token",
+			body:           `{"status":"open"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported status",
+			method:         http.MethodPost,
+			token:          "secret-token",
+			body:           `{"id":"INC-3","status":"exploded"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "wrong method",
+			method:         http.MethodGet,
+			token:          "secret-token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/webhooks/incidents", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("X-Incident-Token", tt.token)
+			w := httptest.NewRecorder()
+
+			handler.HandleIncidentWebhook(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	incidents, err := store.GetIncidents(context.Background(), "test-owner", "test-repo", since)
+	if err != nil {
+		t.Fatalf("GetIncidents() failed: %v", err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("Expected 1 incident, got %d", len(incidents))
+	}
+
+	incident := incidents[0]
+	if incident.Severity != "critical" {
+		t.Errorf("Expected severity to survive the resolve update, got %q", incident.Severity)
+	}
+	if got := incident.ResolutionTime(); got != 150*time.Minute {
+		t.Errorf("Expected resolution time 2h30m, got %v", got)
+	}
+}
+
+func TestIncidentHandlerWithoutToken(t *testing.T) {
+	store := metrics.NewIncidentStore()
+	handler := NewIncidentHandler(store, "")
+
+	body := `{"id":"INC-1","repository":"test-owner/test-repo","status":"open","opened_at":"2025-01-10T10:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/webhooks/incidents", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+	handler.HandleIncidentWebhook(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without a configured token, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if store.HasIncidents("test-owner", "test-repo") {
+		t.Error("Expected the incident to be rejected")
+	}
+}